@@ -76,3 +76,53 @@ const RequestCursorColor = "\x1b]12;?\x07"
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
 const ResetCursorColor = "\x1b]112\x07"
+
+// SetHighlightBackgroundColor returns a sequence that sets the terminal
+// highlight (selection) background color.
+//
+//	OSC 17 ; color ST
+//	OSC 17 ; color BEL
+//
+// Where color is the encoded color number.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+func SetHighlightBackgroundColor(c color.Color) string {
+	return "\x1b]17;" + colorToHexString(c) + "\x07"
+}
+
+// RequestHighlightBackgroundColor is a sequence that requests the current
+// terminal highlight (selection) background color.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+const RequestHighlightBackgroundColor = "\x1b]17;?\x07"
+
+// ResetHighlightBackgroundColor is a sequence that resets the terminal
+// highlight (selection) background color.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+const ResetHighlightBackgroundColor = "\x1b]117\x07"
+
+// SetHighlightForegroundColor returns a sequence that sets the terminal
+// highlight (selection) foreground color.
+//
+//	OSC 19 ; color ST
+//	OSC 19 ; color BEL
+//
+// Where color is the encoded color number.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+func SetHighlightForegroundColor(c color.Color) string {
+	return "\x1b]19;" + colorToHexString(c) + "\x07"
+}
+
+// RequestHighlightForegroundColor is a sequence that requests the current
+// terminal highlight (selection) foreground color.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+const RequestHighlightForegroundColor = "\x1b]19;?\x07"
+
+// ResetHighlightForegroundColor is a sequence that resets the terminal
+// highlight (selection) foreground color.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+const ResetHighlightForegroundColor = "\x1b]119\x07"
@@ -31,3 +31,23 @@ func TestStringImplementations(t *testing.T) {
 			cursorColor)
 	}
 }
+
+func TestHighlightColors(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"SetHighlightBackgroundColor", ansi.SetHighlightBackgroundColor(ansi.TrueColor(0x112233)), "\x1b]17;#112233\x07"},
+		{"SetHighlightForegroundColor", ansi.SetHighlightForegroundColor(ansi.TrueColor(0xaabbcc)), "\x1b]19;#aabbcc\x07"},
+		{"RequestHighlightBackgroundColor", ansi.RequestHighlightBackgroundColor, "\x1b]17;?\x07"},
+		{"ResetHighlightForegroundColor", ansi.ResetHighlightForegroundColor, "\x1b]119\x07"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package ansi
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// ReorderLine returns the line s in display order, reordered with the
+// Unicode Bidirectional Algorithm (UAX #9), so text written right to left,
+// like Hebrew and Arabic, shows right to left on a terminal that doesn't
+// reorder text itself. The paragraph direction is the direction of the first
+// strong character of the line, left to right when there's none. Mirrored
+// characters, like brackets, are mirrored in right to left text.
+//
+// Escape sequences are kept: SGR styles and hyperlinks follow the text they
+// apply to, and other sequences stay before the character they preceded. A
+// line without right to left text is returned as is. The width of the line
+// doesn't change.
+//
+// Lines must be reordered after they're wrapped, since the algorithm reorders
+// whole lines, see [ReorderLines] and [WrapOptions.Reorder].
+//
+// Explicit directional embeddings, overrides, and isolates aren't supported,
+// and paired brackets resolve like other neutral characters.
+func ReorderLine(s string) string {
+	if !hasRTL(s) {
+		return s
+	}
+
+	var (
+		cells  []bidiCell
+		style  sgrTracker
+		link   string
+		seqs   strings.Builder
+		pstate byte
+		b      = s
+	)
+	for len(b) > 0 {
+		seq, width, n, newState := DecodeSequence(b, pstate, nil)
+		b = b[n:]
+		pstate = newState
+		if width == 0 && isEscape(seq) {
+			style.trackSequence(seq)
+			if l, ok := hyperlinkSeq(seq); ok {
+				link = l
+			} else if !isSgr(seq) {
+				seqs.WriteString(seq)
+			}
+			continue
+		}
+
+		p, _ := bidi.LookupString(seq)
+		cells = append(cells, bidiCell{
+			text:  seq,
+			seqs:  seqs.String(),
+			style: styleString(style.style),
+			link:  link,
+			class: p.Class(),
+		})
+		seqs.Reset()
+	}
+
+	levels := bidiLevels(cells)
+
+	// Reverse each run of cells at the given level or higher, from the
+	// highest level down to the lowest odd level.
+	order := make([]int, len(cells))
+	var highest, lowestOdd uint8 = 0, 0xff
+	for i, l := range levels {
+		order[i] = i
+		if l > highest {
+			highest = l
+		}
+		if l%2 == 1 && l < lowestOdd {
+			lowestOdd = l
+		}
+	}
+	for l := highest; l >= lowestOdd && l > 0; l-- {
+		for i := 0; i < len(order); {
+			if levels[order[i]] < l {
+				i++
+				continue
+			}
+			j := i
+			for j < len(order) && levels[order[j]] >= l {
+				j++
+			}
+			for a, z := i, j-1; a < z; a, z = a+1, z-1 {
+				order[a], order[z] = order[z], order[a]
+			}
+			i = j
+		}
+	}
+
+	var (
+		buf     strings.Builder
+		cur     string
+		curLink string
+	)
+	for _, i := range order {
+		c := cells[i]
+		buf.WriteString(c.seqs)
+		if c.link != curLink {
+			if curLink != "" {
+				buf.WriteString(ResetHyperlink())
+			}
+			buf.WriteString(c.link)
+			curLink = c.link
+		}
+		if c.style != cur {
+			if cur != "" {
+				buf.WriteString(ResetStyle)
+			}
+			buf.WriteString(c.style)
+			cur = c.style
+		}
+		if levels[i]%2 == 1 {
+			if r, ok := bidiMirrors[c.text]; ok {
+				buf.WriteString(r)
+				continue
+			}
+		}
+		buf.WriteString(c.text)
+	}
+
+	// The sequences after the last character, and the style and hyperlink
+	// the line ends with.
+	buf.WriteString(seqs.String())
+	if link != curLink {
+		if curLink != "" {
+			buf.WriteString(ResetHyperlink())
+		}
+		buf.WriteString(link)
+	}
+	if end := styleString(style.style); end != cur {
+		if cur != "" {
+			buf.WriteString(ResetStyle)
+		}
+		buf.WriteString(end)
+	}
+
+	return buf.String()
+}
+
+// ReorderLines reorders each line of s with [ReorderLine]. Use it on wrapped
+// text:
+//
+//	ansi.ReorderLines(ansi.Wordwrap(s, 40, ""))
+func ReorderLines(s string) string {
+	if !hasRTL(s) {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = ReorderLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bidiCell is a grapheme cluster of a line being reordered.
+type bidiCell struct {
+	text  string     // text is the grapheme cluster, or a control character.
+	seqs  string     // seqs are the non-SGR sequences before the text.
+	style string     // style is the SGR sequence of the style of the text.
+	link  string     // link is the OSC 8 sequence of the hyperlink of the text.
+	class bidi.Class // class is the bidi class of the first rune of text.
+}
+
+// hasRTL reports whether s has right to left text, or Arabic numbers, that
+// need reordering.
+func hasRTL(s string) bool {
+	for i := 0; i < len(s); {
+		if s[i] < 0x80 {
+			i++
+			continue
+		}
+		p, n := bidi.LookupString(s[i:])
+		switch p.Class() {
+		case bidi.R, bidi.AL, bidi.AN:
+			return true
+		}
+		if n == 0 {
+			n = 1
+		}
+		i += n
+	}
+	return false
+}
+
+// bidiLevels returns the embedding levels of the cells, resolved with the
+// weak, neutral, and implicit rules of the Unicode Bidirectional Algorithm.
+func bidiLevels(cells []bidiCell) []uint8 {
+	// The paragraph level, rules P2 and P3.
+	var level uint8
+	for _, c := range cells {
+		if c.class == bidi.L {
+			break
+		}
+		if c.class == bidi.R || c.class == bidi.AL {
+			level = 1
+			break
+		}
+	}
+	sos := bidi.L
+	if level == 1 {
+		sos = bidi.R
+	}
+
+	types := make([]bidi.Class, len(cells))
+	for i, c := range cells {
+		switch c.class {
+		case bidi.NSM:
+			// W1, a mark that isn't part of a cluster takes the type of
+			// the character before it.
+			if i > 0 {
+				types[i] = types[i-1]
+			} else {
+				types[i] = sos
+			}
+		case bidi.L, bidi.R, bidi.AL, bidi.EN, bidi.ES, bidi.ET, bidi.AN,
+			bidi.CS, bidi.B, bidi.S, bidi.WS:
+			types[i] = c.class
+		default:
+			types[i] = bidi.ON
+		}
+	}
+
+	// W2 and W3, European numbers after Arabic letters are Arabic numbers,
+	// and Arabic letters are right to left.
+	last := sos
+	for i, t := range types {
+		switch t {
+		case bidi.L, bidi.R:
+			last = t
+		case bidi.AL:
+			last = t
+			types[i] = bidi.R
+		case bidi.EN:
+			if last == bidi.AL {
+				types[i] = bidi.AN
+			}
+		}
+	}
+
+	// W4, a single separator between two numbers of the same type takes
+	// their type.
+	for i := 1; i+1 < len(types); i++ {
+		prev, next := types[i-1], types[i+1]
+		switch {
+		case types[i] == bidi.ES && prev == bidi.EN && next == bidi.EN,
+			types[i] == bidi.CS && prev == bidi.EN && next == bidi.EN:
+			types[i] = bidi.EN
+		case types[i] == bidi.CS && prev == bidi.AN && next == bidi.AN:
+			types[i] = bidi.AN
+		}
+	}
+
+	// W5, terminators next to European numbers are European numbers.
+	for i := 0; i < len(types); {
+		if types[i] != bidi.ET {
+			i++
+			continue
+		}
+		j := i
+		for j < len(types) && types[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && types[i-1] == bidi.EN) || (j < len(types) && types[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				types[k] = bidi.EN
+			}
+		}
+		i = j
+	}
+
+	// W6 and W7, other separators and terminators are neutral, and European
+	// numbers in left to right text are left to right.
+	last = sos
+	for i, t := range types {
+		switch t {
+		case bidi.ES, bidi.ET, bidi.CS:
+			types[i] = bidi.ON
+		case bidi.L, bidi.R:
+			last = t
+		case bidi.EN:
+			if last == bidi.L {
+				types[i] = bidi.L
+			}
+		}
+	}
+
+	// N1 and N2, neutrals between characters of the same direction take
+	// their direction, other neutrals take the paragraph direction.
+	strong := func(t bidi.Class) bidi.Class {
+		if t == bidi.EN || t == bidi.AN {
+			return bidi.R
+		}
+		return t
+	}
+	for i := 0; i < len(types); {
+		if !isNeutral(types[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(types) && isNeutral(types[j]) {
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = strong(types[i-1])
+		}
+		if j < len(types) {
+			after = strong(types[j])
+		}
+		dir := sos
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			types[k] = dir
+		}
+		i = j
+	}
+
+	// I1 and I2, the implicit levels.
+	levels := make([]uint8, len(types))
+	for i, t := range types {
+		levels[i] = level
+		switch {
+		case level == 0 && t == bidi.R:
+			levels[i]++
+		case level == 0 && (t == bidi.EN || t == bidi.AN):
+			levels[i] += 2
+		case level == 1 && (t == bidi.L || t == bidi.EN || t == bidi.AN):
+			levels[i]++
+		}
+	}
+
+	// L1, tabs, and the whitespace before them and at the end of the line,
+	// are at the paragraph level.
+	trailing := true
+	for i := len(cells) - 1; i >= 0; i-- {
+		switch cells[i].class {
+		case bidi.S:
+			levels[i] = level
+			trailing = true
+		case bidi.WS:
+			if trailing {
+				levels[i] = level
+			}
+		default:
+			trailing = false
+		}
+	}
+
+	return levels
+}
+
+// isNeutral reports whether the bidi type t is neutral.
+func isNeutral(t bidi.Class) bool {
+	switch t {
+	case bidi.B, bidi.S, bidi.WS, bidi.ON:
+		return true
+	}
+	return false
+}
+
+// isEscape reports whether seq is an escape sequence.
+func isEscape(seq string) bool {
+	switch seq[0] {
+	case ESC, CSI, DCS, OSC, APC, SOS, PM:
+		return len(seq) > 1 || seq[0] != ESC
+	}
+	return false
+}
+
+// isSgr reports whether seq is a SGR sequence.
+func isSgr(seq string) bool {
+	if !HasCsiPrefix(seq) || seq[len(seq)-1] != 'm' {
+		return false
+	}
+	params := seq[1:]
+	if seq[0] == ESC {
+		params = seq[2:]
+	}
+	for i := 0; i < len(params)-1; i++ {
+		if c := params[i]; (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// hyperlinkSeq reports whether seq is a OSC 8 hyperlink sequence, and
+// returns it when it opens a link, or an empty string when it closes one.
+func hyperlinkSeq(seq string) (string, bool) {
+	url, ok := hyperlinkURL(seq)
+	if !ok || url == "" {
+		return "", ok
+	}
+	return seq, true
+}
+
+// hyperlinkURL reports whether seq is a OSC 8 hyperlink sequence, and
+// returns the URL of the link, empty when it closes one.
+func hyperlinkURL(seq string) (string, bool) {
+	if !HasOscPrefix(seq) {
+		return "", false
+	}
+	data := seq[1:]
+	if seq[0] == ESC {
+		data = seq[2:]
+	}
+	if !strings.HasPrefix(data, "8;") {
+		return "", false
+	}
+	parts := strings.SplitN(data, ";", 3)
+	if len(parts) < 3 {
+		return "", true
+	}
+	url := parts[2]
+	switch {
+	case strings.HasSuffix(url, "\x1b\\"):
+		url = url[:len(url)-2]
+	case strings.HasSuffix(url, "\x07"), seq[0] == OSC && strings.HasSuffix(url, "\x9c"):
+		url = url[:len(url)-1]
+	}
+	return url, true
+}
+
+// styleString returns the SGR sequence of the style s, or an empty string
+// when s is the default style.
+func styleString(s Style) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s.String()
+}
+
+// bidiMirrors are the mirrored glyphs of the characters with the
+// Bidi_Mirrored property, shown in right to left text.
+var bidiMirrors = map[string]string{
+	"(": ")", ")": "(",
+	"<": ">", ">": "<",
+	"[": "]", "]": "[",
+	"{": "}", "}": "{",
+	"«": "»", "»": "«",
+	"‹": "›", "›": "‹",
+	"⁅": "⁆", "⁆": "⁅",
+	"≤": "≥", "≥": "≤",
+	"≪": "≫", "≫": "≪",
+	"⟨": "⟩", "⟩": "⟨",
+	"〈": "〉", "〉": "〈",
+	"《": "》", "》": "《",
+	"「": "」", "」": "「",
+	"『": "』", "』": "『",
+	"【": "】", "】": "【",
+	"〔": "〕", "〕": "〔",
+	"（": "）", "）": "（",
+	"［": "］", "］": "［",
+	"｛": "｝", "｝": "｛",
+}
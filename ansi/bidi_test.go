@@ -0,0 +1,38 @@
+package ansi
+
+import "testing"
+
+func TestReorderLine(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"ltr", "hello world", "hello world"},
+		{"rtl word", "hello שלום world", "hello םולש world"},
+		{"rtl paragraph", "שלום 123 עולם!", "!םלוע 123 םולש"},
+		{"arabic numbers", "مرحبا بالعالم 2024", "2024 ملاعلاب ابحرم"},
+		{"european numbers", "price: 1,234.50 ש\"ח", "price: 1,234.50 ח\"ש"},
+		{"mirrored", "שלום (עולם)", "(םלוע) םולש"},
+		{"trailing spaces", "שלום  ", "  םולש"},
+		{"styled", "\x1b[1mשלום\x1b[m world", "world \x1b[1mםולש\x1b[m"},
+		{"hyperlink end", "\x1b]8;;https://charm.sh\x1b\\של\x1b]8;;\x1b\\ום", "םו\x1b]8;;https://charm.sh\x1b\\לש\x1b]8;;\x07"},
+		{"styles", "a \x1b[31mשל\x1b[32mום\x1b[m b", "a \x1b[32mםו\x1b[m\x1b[31mלש\x1b[m b"},
+		{"hyperlink", "\x1b]8;;https://charm.sh\x07שלום\x1b]8;;\x07", "\x1b]8;;https://charm.sh\x07םולש\x1b]8;;\x07"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ReorderLine(c.input); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestReorderLines(t *testing.T) {
+	input := Wordwrap("שלום עולם hello", 10, "")
+	expect := "םלוע םולש\nhello"
+	if got := ReorderLines(input); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
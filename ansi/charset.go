@@ -0,0 +1,92 @@
+package ansi
+
+// Character sets, by the final byte that designates them with
+// [SelectCharacterSet].
+const (
+	AsciiCharset              = 'B'
+	BritishCharset            = 'A'
+	DecSpecialGraphicsCharset = '0'
+)
+
+// SelectCharacterSet (SCS) returns a sequence that designates the character
+// set cs, like [DecSpecialGraphicsCharset], as the G0 to G3 character set g.
+// G0 is used by default, and G1 after a shift out (SO) control character,
+// until a shift in (SI). It returns an empty string when g isn't between 0
+// and 3.
+//
+//	ESC ( Ps
+//	ESC ) Ps
+//	ESC * Ps
+//	ESC + Ps
+//
+// See: https://vt100.net/docs/vt510-rm/SCS.html
+func SelectCharacterSet(g int, cs byte) string {
+	if g < 0 || g > 3 {
+		return ""
+	}
+	return "\x1b" + string("()*+"[g]) + string(cs)
+}
+
+// SelectDecSpecialGraphics is a sequence that designates the DEC special
+// graphics character set as G0, to draw lines and boxes with ASCII letters.
+// Use [SelectAsciiCharset] to go back to ASCII.
+//
+// This is equivalent to SelectCharacterSet(0, DecSpecialGraphicsCharset).
+const SelectDecSpecialGraphics = "\x1b(0"
+
+// SelectAsciiCharset is a sequence that designates the ASCII character set
+// as G0, the default.
+//
+// This is equivalent to SelectCharacterSet(0, AsciiCharset).
+const SelectAsciiCharset = "\x1b(B"
+
+// decSpecialGraphics are the characters of the DEC special graphics
+// character set from 0x5f to 0x7e.
+var decSpecialGraphics = [...]rune{
+	' ',                                    // _ blank
+	'◆', '▒', '␉', '␌', '␍', '␊', '°', '±', // ` a b c d e f g
+	'␤', '␋', '┘', '┐', '┌', '└', '┼', '⎺', // h i j k l m n o
+	'⎻', '─', '⎼', '⎽', '├', '┤', '┴', '┬', // p q r s t u v w
+	'│', '≤', '≥', 'π', '≠', '£', '·', // x y z { | } ~
+}
+
+// DecSpecialGraphicsRune returns the Unicode character that r stands for in
+// the DEC special graphics character set, like '─' for 'q'. Other
+// characters are returned as is.
+func DecSpecialGraphicsRune(r rune) rune {
+	if r >= 0x5f && r <= 0x7e {
+		return decSpecialGraphics[r-0x5f]
+	}
+	return r
+}
+
+// charsetState keeps track of the character sets designated with SCS, and
+// of the one in use.
+type charsetState struct {
+	g  [4]byte // g are the designated G0 to G3 sets, zero for ASCII.
+	gl int     // gl is the set in use, G0 or G1.
+}
+
+// designate handles an escape sequence with the given intermediate and
+// final bytes.
+func (c *charsetState) designate(intermed, final byte) {
+	switch intermed {
+	case '(', ')', '*', '+':
+		c.g[intermed-'('] = final
+	}
+}
+
+// shift handles the SO and SI control characters.
+func (c *charsetState) shift(b byte) {
+	switch b {
+	case SO:
+		c.gl = 1
+	case SI:
+		c.gl = 0
+	}
+}
+
+// graphics reports whether the DEC special graphics set is in use.
+func (c *charsetState) graphics() bool {
+	return c.g[c.gl] == DecSpecialGraphicsCharset
+}
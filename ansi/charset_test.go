@@ -0,0 +1,38 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSelectCharacterSet(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"SelectCharacterSet(0, '0')", ansi.SelectCharacterSet(0, ansi.DecSpecialGraphicsCharset), ansi.SelectDecSpecialGraphics},
+		{"SelectCharacterSet(0, 'B')", ansi.SelectCharacterSet(0, ansi.AsciiCharset), ansi.SelectAsciiCharset},
+		{"SelectCharacterSet(1, '0')", ansi.SelectCharacterSet(1, '0'), "\x1b)0"},
+		{"SelectCharacterSet(3, 'A')", ansi.SelectCharacterSet(3, ansi.BritishCharset), "\x1b+A"},
+		{"SelectCharacterSet(4, '0')", ansi.SelectCharacterSet(4, '0'), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
+
+func TestDecSpecialGraphicsRune(t *testing.T) {
+	for r, expect := range map[rune]rune{
+		'q': '─', 'x': '│', 'l': '┌', 'j': '┘', 'n': '┼', '~': '·', 'A': 'A', '^': '^',
+	} {
+		if got := ansi.DecSpecialGraphicsRune(r); got != expect {
+			t.Errorf("%q: expected %q, got %q", r, expect, got)
+		}
+	}
+}
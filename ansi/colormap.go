@@ -0,0 +1,160 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ColorTarget is what the color of a SGR sequence applies to.
+type ColorTarget uint8
+
+// Color targets.
+const (
+	// ForegroundColorTarget is the color of the text.
+	ForegroundColorTarget ColorTarget = iota
+
+	// BackgroundColorTarget is the color behind the text.
+	BackgroundColorTarget
+
+	// UnderlineColorTarget is the color of the underline.
+	UnderlineColorTarget
+)
+
+// MapColors rewrites the colors of the SGR sequences in s with fn, to give
+// styled output a new theme, like mapping the 16 basic colors to the colors
+// of a palette, or clamping the brightness of true colors. fn is called with
+// each foreground, background, and underline color, and returns the color
+// to use instead, or nil for the default color. Other attributes, text, and
+// sequences are kept as they are.
+//
+//	theme := map[ansi.Color]ansi.Color{
+//		ansi.Red:   ansi.TrueColor(0xff5f87),
+//		ansi.Green: ansi.TrueColor(0x5fd787),
+//	}
+//	s = ansi.MapColors(s, func(_ ansi.ColorTarget, c ansi.Color) ansi.Color {
+//		if t, ok := theme[c]; ok {
+//			return t
+//		}
+//		return c
+//	})
+//
+// Colors are written in their shortest form, 256 colors and true colors
+// use semicolons, like "38;5;n" and "38;2;r;g;b". The default colors, like
+// 39, aren't passed to fn.
+func MapColors(s string, fn func(target ColorTarget, c Color) Color) string {
+	if isPlainASCII(s) {
+		// No sequences.
+		return s
+	}
+
+	var (
+		b  strings.Builder
+		sc = NewScanner(s)
+	)
+	b.Grow(len(s))
+	for sc.Scan() {
+		tok := sc.Token()
+		if sc.Kind() != SequenceToken || !isSgr(tok) {
+			b.WriteString(tok)
+			continue
+		}
+
+		intro := 1
+		if tok[0] == ESC {
+			intro = 2
+		}
+		b.WriteString(tok[:intro])
+		mapSgrColors(&b, tok[intro:len(tok)-1], fn)
+		b.WriteByte('m')
+	}
+	return b.String()
+}
+
+// mapSgrColors writes the SGR parameters params to b, with their colors
+// rewritten with fn.
+func mapSgrColors(b *strings.Builder, params string, fn func(ColorTarget, Color) Color) {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+
+		f := fields[i]
+		n, err := strconv.Atoi(f)
+		switch {
+		case err != nil && !strings.Contains(f, ":"):
+			b.WriteString(f)
+		case n >= 30 && n <= 37:
+			b.WriteString(foregroundColorString(fn(ForegroundColorTarget, BasicColor(n-30))))
+		case n >= 90 && n <= 97:
+			b.WriteString(foregroundColorString(fn(ForegroundColorTarget, BasicColor(n-90+8))))
+		case n >= 40 && n <= 47:
+			b.WriteString(backgroundColorString(fn(BackgroundColorTarget, BasicColor(n-40))))
+		case n >= 100 && n <= 107:
+			b.WriteString(backgroundColorString(fn(BackgroundColorTarget, BasicColor(n-100+8))))
+		default:
+			c, size, ok := readSgrColor(fields[i:])
+			if !ok {
+				// Keep what we don't understand.
+				b.WriteString(f)
+				continue
+			}
+			i += size - 1
+			switch strings.SplitN(f, ":", 2)[0] {
+			case "38":
+				b.WriteString(foregroundColorString(fn(ForegroundColorTarget, c)))
+			case "48":
+				b.WriteString(backgroundColorString(fn(BackgroundColorTarget, c)))
+			case "58":
+				b.WriteString(underlineColorString(fn(UnderlineColorTarget, c)))
+			}
+		}
+	}
+}
+
+// readSgrColor reads the color of a 38, 48, or 58 SGR parameter at the
+// start of fields. It returns the color, and the number of fields it spans.
+func readSgrColor(fields []string) (Color, int, bool) {
+	sub := strings.Split(fields[0], ":")
+	switch sub[0] {
+	case "38", "48", "58":
+	default:
+		return nil, 1, false
+	}
+
+	if len(sub) > 1 {
+		// The colon form, 38:5:n or 38:2:[colorspace:]r:g:b, fits in one
+		// field.
+		switch {
+		case sub[1] == "5" && len(sub) >= 3:
+			return ExtendedColor(atoiByte(sub[2])), 1, true
+		case sub[1] == "2" && len(sub) >= 5:
+			rgb := sub[len(sub)-3:]
+			return rgbColor(rgb[0], rgb[1], rgb[2]), 1, true
+		}
+		return nil, 1, false
+	}
+
+	// The semicolon form, 38;5;n or 38;2;r;g;b.
+	args := fields[1:]
+	switch {
+	case len(args) >= 2 && args[0] == "5":
+		return ExtendedColor(atoiByte(args[1])), 3, true
+	case len(args) >= 4 && args[0] == "2":
+		return rgbColor(args[1], args[2], args[3]), 5, true
+	}
+	return nil, 1, false
+}
+
+// rgbColor returns the true color with the red, green, and blue components
+// r, g, and b.
+func rgbColor(r, g, b string) TrueColor {
+	return TrueColor(uint32(atoiByte(r))<<16 | uint32(atoiByte(g))<<8 | uint32(atoiByte(b)))
+}
+
+// atoiByte returns the number in s, truncated to a byte, or 0 when s isn't
+// a number.
+func atoiByte(s string) uint8 {
+	n, _ := strconv.Atoi(s)
+	return uint8(n)
+}
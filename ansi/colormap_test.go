@@ -0,0 +1,55 @@
+package ansi
+
+import "testing"
+
+func TestMapColors(t *testing.T) {
+	theme := map[Color]Color{
+		Red:              TrueColor(0xff5f87),
+		BrightGreen:      ExtendedColor(120),
+		ExtendedColor(1): Blue,
+		TrueColor(0):     nil,
+	}
+	remap := func(_ ColorTarget, c Color) Color {
+		if t, ok := theme[c]; ok {
+			return t
+		}
+		return c
+	}
+
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"plain", "hello", "hello"},
+		{"basic", "\x1b[31mred\x1b[m", "\x1b[38;2;255;95;135mred\x1b[m"},
+		{"bright", "\x1b[1;92mgreen\x1b[0m", "\x1b[1;38;5;120mgreen\x1b[0m"},
+		{"background", "\x1b[41;4mx", "\x1b[48;2;255;95;135;4mx"},
+		{"extended", "\x1b[38;5;1mx", "\x1b[34mx"},
+		{"extended colon", "\x1b[48:5:1mx", "\x1b[44mx"},
+		{"true color", "\x1b[38;2;0;0;0;1mx", "\x1b[39;1mx"},
+		{"true color colon", "\x1b[38:2::0:0:0mx", "\x1b[39mx"},
+		{"underline", "\x1b[4:3;58;5;1mx", "\x1b[4:3;58;5;4mx"},
+		{"unchanged", "\x1b[33;44mx", "\x1b[33;44mx"},
+		{"8-bit", "\x9b31mx", "\x9b38;2;255;95;135mx"},
+		{"other sequences", "\x1b[31Hx\x1b]11;rgb:0/0/0\x07", "\x1b[31Hx\x1b]11;rgb:0/0/0\x07"},
+		{"incomplete", "\x1b[38;5mx", "\x1b[38;5mx"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := MapColors(c.input, remap); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+
+	var targets []ColorTarget
+	MapColors("\x1b[31;42;58:5:3m", func(target ColorTarget, c Color) Color {
+		targets = append(targets, target)
+		return c
+	})
+	if len(targets) != 3 || targets[0] != ForegroundColorTarget ||
+		targets[1] != BackgroundColorTarget || targets[2] != UnderlineColorTarget {
+		t.Errorf("unexpected targets %v", targets)
+	}
+}
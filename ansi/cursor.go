@@ -212,6 +212,54 @@ func SetCursorStyle(style int) string {
 	return "\x1b[" + strconv.Itoa(style) + " q"
 }
 
+// CursorShape is the shape of the cursor, see [SetCursorShape].
+type CursorShape int
+
+// Cursor shapes.
+const (
+	CursorBlock CursorShape = iota
+	CursorUnderline
+	CursorBar
+)
+
+// CursorStyle returns the DECSCUSR cursor style of the given shape, blinking
+// or steady, to use with [SetCursorStyle].
+func CursorStyle(shape CursorShape, blink bool) int {
+	style := int(shape)*2 + 1
+	if !blink {
+		style++
+	}
+	return style
+}
+
+// ParseCursorStyle returns the shape of the DECSCUSR cursor style, and
+// whether it blinks. It returns false when the style isn't valid.
+func ParseCursorStyle(style int) (shape CursorShape, blink bool, ok bool) {
+	switch {
+	case style == 0:
+		return CursorBlock, true, true
+	case style < 0 || style > 6:
+		return 0, false, false
+	}
+	return CursorShape((style - 1) / 2), style%2 == 1, true
+}
+
+// SetCursorShape returns a sequence for changing the cursor shape, blinking
+// or steady. It's equivalent to SetCursorStyle(CursorStyle(shape, blink)).
+//
+// Use [RequestCursorStyle] to query the current style, to restore it later.
+func SetCursorShape(shape CursorShape, blink bool) string {
+	return SetCursorStyle(CursorStyle(shape, blink))
+}
+
+// ResetCursorStyle is a sequence for resetting the cursor style to the
+// terminal default, usually the one configured by the user.
+//
+//	CSI 0 SP q
+//
+// See: https://vt100.net/docs/vt510-rm/DECSCUSR.html
+const ResetCursorStyle = "\x1b[0 q"
+
 // SetPointerShape returns a sequence for changing the mouse pointer cursor
 // shape. Use "default" for the default pointer shape.
 //
@@ -229,7 +277,57 @@ func SetCursorStyle(style int) string {
 //   - text
 //   - wait
 //
+// See the Pointer* constants for the names most terminals know.
+//
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Operating-System-Commands
 func SetPointerShape(shape string) string {
 	return "\x1b]22;" + shape + "\x07"
 }
+
+// Pointer shape names, from the CSS cursor names, that xterm and kitty know.
+// Use them with [SetPointerShape].
+//
+// See: https://sw.kovidgoyal.net/kitty/pointer-shapes/
+const (
+	PointerDefault    = "default"
+	PointerText       = "text"
+	PointerHand       = "pointer"
+	PointerHelp       = "help"
+	PointerWait       = "wait"
+	PointerProgress   = "progress"
+	PointerCrosshair  = "crosshair"
+	PointerMove       = "move"
+	PointerNotAllowed = "not-allowed"
+	PointerGrab       = "grab"
+	PointerGrabbing   = "grabbing"
+	PointerEWResize   = "ew-resize"
+	PointerNSResize   = "ns-resize"
+)
+
+// ResetPointerShape is a sequence for resetting the mouse pointer shape to
+// the default.
+//
+// This is equivalent to SetPointerShape(PointerDefault).
+const ResetPointerShape = "\x1b]22;default\x07"
+
+// PushPointerShape returns a sequence that changes the mouse pointer shape,
+// and saves the current one on a stack, to restore it with
+// [PopPointerShape]. This is a kitty extension, other terminals might set
+// the shape or ignore the sequence.
+//
+//	OSC 22 ; > Pt ST
+//	OSC 22 ; > Pt BEL
+//
+// See: https://sw.kovidgoyal.net/kitty/pointer-shapes/
+func PushPointerShape(shape string) string {
+	return "\x1b]22;>" + shape + "\x07"
+}
+
+// PopPointerShape is a sequence that restores the mouse pointer shape saved
+// by [PushPointerShape].
+//
+//	OSC 22 ; < ST
+//	OSC 22 ; < BEL
+//
+// See: https://sw.kovidgoyal.net/kitty/pointer-shapes/
+const PopPointerShape = "\x1b]22;<\x07"
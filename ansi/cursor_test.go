@@ -0,0 +1,59 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSetCursorShape(t *testing.T) {
+	cases := []struct {
+		shape  ansi.CursorShape
+		blink  bool
+		expect string
+	}{
+		{ansi.CursorBlock, true, "\x1b[1 q"},
+		{ansi.CursorBlock, false, "\x1b[2 q"},
+		{ansi.CursorUnderline, true, "\x1b[3 q"},
+		{ansi.CursorUnderline, false, "\x1b[4 q"},
+		{ansi.CursorBar, true, "\x1b[5 q"},
+		{ansi.CursorBar, false, "\x1b[6 q"},
+	}
+	for _, tc := range cases {
+		if got := ansi.SetCursorShape(tc.shape, tc.blink); got != tc.expect {
+			t.Errorf("expected: %q, got: %q", tc.expect, got)
+		}
+		shape, blink, ok := ansi.ParseCursorStyle(ansi.CursorStyle(tc.shape, tc.blink))
+		if !ok || shape != tc.shape || blink != tc.blink {
+			t.Errorf("expected %v %v, got %v %v %v", tc.shape, tc.blink, shape, blink, ok)
+		}
+	}
+}
+
+func TestParseCursorStyle(t *testing.T) {
+	if shape, blink, ok := ansi.ParseCursorStyle(0); !ok || shape != ansi.CursorBlock || !blink {
+		t.Errorf("expected the default style to be a blinking block, got %v %v %v", shape, blink, ok)
+	}
+	if _, _, ok := ansi.ParseCursorStyle(7); ok {
+		t.Error("expected style 7 to be invalid")
+	}
+}
+
+func TestPointerShape(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"SetPointerShape(PointerText)", ansi.SetPointerShape(ansi.PointerText), "\x1b]22;text\x07"},
+		{"SetPointerShape(PointerDefault)", ansi.SetPointerShape(ansi.PointerDefault), ansi.ResetPointerShape},
+		{"PushPointerShape(PointerHand)", ansi.PushPointerShape(ansi.PointerHand), "\x1b]22;>pointer\x07"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package ansi
+
+import "strings"
+
+// CutAt splits a string at the given width, and returns the text of the
+// first width cells and the rest of the text. The SGR style and the
+// hyperlink in effect where it's cut are closed at the end of head and opened
+// again at the start of tail, so that both halves can be printed on their
+// own, like the pages of a text or the frames of a marquee.
+//
+//	ansi.CutAt("\x1b[1mhello world\x1b[m", 5) // "\x1b[1mhello\x1b[m", "\x1b[1m world\x1b[m"
+//
+// A wide character that doesn't fit in head goes to tail, and the escape
+// sequences right before the cut go to tail, with the text they apply to. A
+// string that fits is returned as is, with an empty tail.
+func CutAt(s string, w int) (head, tail string) {
+	var (
+		cut   int // cut is where s is split.
+		width int
+		pos   int
+		over  bool
+		sc    = NewScanner(s)
+	)
+	for sc.Scan() {
+		if sc.Kind() == SequenceToken {
+			pos += len(sc.Token())
+			continue
+		}
+		if width+sc.Width() > w {
+			over = true
+			break
+		}
+		width += sc.Width()
+		pos += len(sc.Token())
+		cut = pos
+	}
+	if !over {
+		return s, ""
+	}
+
+	var (
+		style sgrTracker
+		link  string // link is the sequence opening the hyperlink, if any.
+	)
+	sc.Reset(s[:cut])
+	for sc.Scan() {
+		if sc.Kind() != SequenceToken {
+			continue
+		}
+		seq := sc.Token()
+		if uri, ok := hyperlinkURI(seq); ok {
+			link = ""
+			if uri != "" {
+				link = seq
+			}
+			continue
+		}
+		style.trackSequence(seq)
+	}
+
+	head, tail = s[:cut], s[cut:]
+	if link != "" {
+		head += ResetHyperlink()
+		tail = link + tail
+	}
+	if len(style.style) > 0 {
+		head += ResetStyle
+		tail = style.style.String() + tail
+	}
+	return head, tail
+}
+
+// hyperlinkURI returns the URI of the OSC 8 hyperlink sequence seq, empty
+// when it closes the hyperlink, and whether seq is one.
+func hyperlinkURI(seq string) (string, bool) {
+	var body string
+	switch {
+	case strings.HasPrefix(seq, "\x1b]8;"):
+		body = seq[4:]
+	case strings.HasPrefix(seq, "\x9d8;"):
+		body = seq[3:]
+	default:
+		return "", false
+	}
+	for _, st := range []string{"\x1b\\", "\x07", "\x9c"} {
+		if strings.HasSuffix(body, st) {
+			body = body[:len(body)-len(st)]
+			break
+		}
+	}
+	i := strings.IndexByte(body, ';')
+	if i < 0 {
+		return "", false
+	}
+	return body[i+1:], true
+}
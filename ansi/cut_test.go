@@ -0,0 +1,42 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCutAt(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		width int
+		head  string
+		tail  string
+	}{
+		{"empty", "", 3, "", ""},
+		{"fits", "hello", 5, "hello", ""},
+		{"fits_with_sequences", "hello\x1b[m", 5, "hello\x1b[m", ""},
+		{"zero", "abc", 0, "", "abc"},
+		{"plain", "hello world", 5, "hello", " world"},
+		{"style", "\x1b[1mhello world\x1b[m", 5, "\x1b[1mhello\x1b[m", "\x1b[1m world\x1b[m"},
+		{"style_change", "\x1b[1;31mab\x1b[22mcd", 3, "\x1b[1;31mab\x1b[22mc\x1b[m", "\x1b[31md"},
+		{"sequence_at_cut", "ab\x1b[31mcd", 2, "ab", "\x1b[31mcd"},
+		{"wide", "a日本", 2, "a", "日本"},
+		{
+			"hyperlink",
+			"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\ text",
+			2,
+			"\x1b]8;;https://example.com\x1b\\li\x1b]8;;\x07",
+			"\x1b]8;;https://example.com\x1b\\nk\x1b]8;;\x1b\\ text",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			head, tail := ansi.CutAt(c.input, c.width)
+			if head != c.head || tail != c.tail {
+				t.Errorf("expected %q, %q, got %q, %q", c.head, c.tail, head, tail)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package ansi
+
+import "strings"
+
+// DiffStyles are the styles [DiffStyles.Diff] highlights changes with.
+type DiffStyles struct {
+	// Insert is the style of the text only in the new string. It's applied
+	// on top of the style of the text.
+	Insert Style
+
+	// Delete is the style of the text only in the old string. It replaces
+	// the style of the text.
+	Delete Style
+}
+
+// DefaultDiffStyles are the styles [Diff] highlights changes with, green
+// insertions and red, struck through deletions.
+var DefaultDiffStyles = DiffStyles{
+	Insert: Style{}.BackgroundColor(Green),
+	Delete: Style{}.BackgroundColor(Red).Strikethrough(),
+}
+
+// Diff compares the visible text of the styled strings a and b, and returns
+// b with the text inserted since a, and the text deleted from a, highlighted
+// with [DefaultDiffStyles].
+//
+//	ansi.Diff("\x1b[1mhello\x1b[m world", "\x1b[1mhello\x1b[m there")
+func Diff(a, b string) string {
+	return DefaultDiffStyles.Diff(a, b)
+}
+
+// Diff compares the visible text of the styled strings a and b, grapheme
+// cluster by grapheme cluster, and returns b with the text inserted since a,
+// and the text deleted from a, highlighted with the styles. The escape
+// sequences of b are kept, and deleted text comes before the text inserted
+// in its place.
+//
+// Comparing strings takes time and memory proportional to the product of
+// their lengths, it's meant for lines and short texts.
+func (ds DiffStyles) Diff(a, b string) string {
+	ca, _ := diffCells(a)
+	cb, end := diffCells(b)
+
+	// The longest common subsequence of the cells, lcs[i][j] is the length
+	// of the one of ca[i:] and cb[j:].
+	lcs := make([][]int, len(ca)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(cb)+1)
+	}
+	for i := len(ca) - 1; i >= 0; i-- {
+		for j := len(cb) - 1; j >= 0; j-- {
+			switch {
+			case ca[i].text == cb[j].text:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var (
+		buf   strings.Builder
+		style sgrTracker // style is the style of b where it's written.
+		op    byte       // op is the change being highlighted, if any.
+	)
+
+	// writeSeqs writes the sequences of b, and keeps track of its style.
+	writeSeqs := func(seqs string) {
+		buf.WriteString(seqs)
+		style.trackSequence(seqs)
+	}
+
+	// highlight starts highlighting the change op, or stops highlighting
+	// when op is zero.
+	highlight := func(next byte) {
+		if next == op {
+			return
+		}
+		switch {
+		case next == '-':
+			// Deleted text doesn't have the style of b.
+			if op != 0 || len(style.style) > 0 {
+				buf.WriteString(ResetStyle)
+			}
+			buf.WriteString(styleString(ds.Delete))
+		case op != 0:
+			// Back to the style of b.
+			buf.WriteString(ResetStyle)
+			buf.WriteString(styleString(style.style))
+		}
+		if next == '+' {
+			buf.WriteString(styleString(ds.Insert))
+		}
+		op = next
+	}
+
+	i, j := 0, 0
+	for i < len(ca) || j < len(cb) {
+		switch {
+		case i < len(ca) && j < len(cb) && ca[i].text == cb[j].text:
+			highlight(0)
+			writeSeqs(cb[j].seqs)
+			buf.WriteString(cb[j].text)
+			i++
+			j++
+		case i < len(ca) && (j == len(cb) || lcs[i+1][j] >= lcs[i][j+1]):
+			highlight('-')
+			buf.WriteString(ca[i].text)
+			i++
+		default:
+			if op == '+' {
+				if cb[j].seqs != "" {
+					// The sequences of b can change the style, apply the
+					// highlight on top of it again.
+					writeSeqs(cb[j].seqs)
+					buf.WriteString(styleString(ds.Insert))
+				}
+			} else {
+				highlight(0)
+				writeSeqs(cb[j].seqs)
+				highlight('+')
+			}
+			buf.WriteString(cb[j].text)
+			j++
+		}
+	}
+	highlight(0)
+	buf.WriteString(end)
+
+	return buf.String()
+}
+
+// diffCell is a grapheme cluster of a string being compared.
+type diffCell struct {
+	text string // text is the grapheme cluster, or a control character.
+	seqs string // seqs are the escape sequences before the text.
+}
+
+// diffCells returns the cells of s, and the escape sequences after the last
+// one.
+func diffCells(s string) ([]diffCell, string) {
+	var (
+		cells []diffCell
+		seqs  strings.Builder
+		sc    = NewScanner(s)
+	)
+	for sc.Scan() {
+		if sc.Kind() == SequenceToken {
+			seqs.WriteString(sc.Token())
+			continue
+		}
+		cells = append(cells, diffCell{text: sc.Token(), seqs: seqs.String()})
+		seqs.Reset()
+	}
+	return cells, seqs.String()
+}
@@ -0,0 +1,39 @@
+package ansi
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	ds := DiffStyles{
+		Insert: Style{}.Underline(),
+		Delete: Style{}.Strikethrough(),
+	}
+	cases := []struct {
+		name   string
+		a, b   string
+		expect string
+	}{
+		{"equal", "abc", "abc", "abc"},
+		{"styled equal", "\x1b[1mabc\x1b[m", "a\x1b[31mbc", "a\x1b[31mbc"},
+		{"insert", "", "new", "\x1b[4mnew\x1b[m"},
+		{"delete", "old", "", "\x1b[9mold\x1b[m"},
+		{"replace", "hello world", "hello word", "hello wor\x1b[9ml\x1b[md"},
+		{"replace word", "a cat", "a dog", "a \x1b[9mcat\x1b[m\x1b[4mdog\x1b[m"},
+		{"kept sequences", "\x1b[1mhello\x1b[m cat", "\x1b[1mhello\x1b[m dog", "\x1b[1mhello\x1b[m \x1b[9mcat\x1b[m\x1b[4mdog\x1b[m"},
+		{"styled delete", "\x1b[1mab\x1b[m", "\x1b[1mb\x1b[m", "\x1b[9ma\x1b[m\x1b[1mb\x1b[m"},
+		{"styled insert", "ac", "a\x1b[32mb\x1b[mc", "a\x1b[32m\x1b[4mb\x1b[m\x1b[32m\x1b[mc"},
+		{"insert restyled", "", "a\x1b[32mb", "\x1b[4ma\x1b[32m\x1b[4mb\x1b[m\x1b[32m"},
+		{"clusters", "héllo", "hello", "h\x1b[9mé\x1b[m\x1b[4me\x1b[mllo"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ds.Diff(c.a, c.b); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+
+	expect := "a\x1b[41;9mb\x1b[m\x1b[42mc\x1b[m"
+	if got := Diff("ab", "ac"); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
@@ -0,0 +1,771 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// Dumper writes a human-readable listing of the output written to it, with
+// one line per escape sequence, control character, or run of text, and what
+// it does:
+//
+//	CSI 1;31 m — SGR: bold, red foreground
+//	"hello" — text, 5 cells
+//	CSI m — SGR: reset
+//	CR — carriage return
+//	LF — line feed
+//
+// Use it to debug the output of a program, or input sequences a terminal
+// sends. Sequences can be split across writes. Text is listed when a
+// sequence follows it, or when the dumper is flushed.
+type Dumper struct {
+	w    io.Writer
+	p    *Parser
+	text []rune
+	buf  bytes.Buffer
+
+	// str reports whether the last sequence was a string, like an OSC
+	// sequence, that an ST might terminate.
+	str bool
+}
+
+// NewDumper returns a new dumper that writes the listing to w.
+func NewDumper(w io.Writer) *Dumper {
+	return &Dumper{
+		w: w,
+		p: NewParser(32, 0),
+	}
+}
+
+// Dump returns the listing of s, see [Dumper].
+func Dump(s string) string {
+	var b strings.Builder
+	d := NewDumper(&b)
+	d.Write([]byte(s)) //nolint:errcheck
+	d.Flush()          //nolint:errcheck
+	return b.String()
+}
+
+// Write implements io.Writer.
+func (d *Dumper) Write(p []byte) (int, error) {
+	d.buf.Reset()
+	for _, b := range p {
+		// There's always more to come, the rest of a sequence can be in the
+		// next write.
+		d.p.Advance(d.dump, b, true)
+	}
+	_, err := d.w.Write(d.buf.Bytes())
+	return len(p), err
+}
+
+// Flush lists the pending text, and the sequence being written, if any, as
+// incomplete.
+func (d *Dumper) Flush() error {
+	d.buf.Reset()
+	d.flushText()
+	if d.p.State != parser.GroundState {
+		d.line(stateNotation(d.p.State), "incomplete sequence")
+		d.p.Reset()
+	}
+	_, err := d.w.Write(d.buf.Bytes())
+	return err
+}
+
+// dump lists the sequence seq.
+func (d *Dumper) dump(seq Sequence) {
+	if r, ok := seq.(Rune); ok {
+		d.text = append(d.text, rune(r))
+		return
+	}
+	d.flushText()
+
+	str := d.str
+	d.str = false
+	switch seq := seq.(type) {
+	case ControlCode:
+		d.line(ControlName(byte(seq)), controlDescription(byte(seq)))
+	case EscSequence:
+		if str && seq == '\\' {
+			// The terminator of the string.
+			return
+		}
+		d.line(escNotation(seq), named(SequenceName(seq), describeEsc(seq)))
+	case CsiSequence:
+		d.line(csiNotation(seq), named(CsiNames[Cmd(seq.Cmd)], describeCsi(seq)))
+	case OscSequence:
+		d.str = true
+		d.line("OSC "+strconv.Quote(string(seq.Data)), describeOsc(seq))
+	case DcsSequence:
+		d.str = true
+		d.line(dcsNotation(seq), named(DcsNames[Cmd(seq.Cmd)], describeDcs(seq)))
+	case ApcSequence:
+		d.str = true
+		desc := "application program command"
+		if name := SequenceName(seq); name != "" {
+			desc = name
+		}
+		d.line("APC "+strconv.Quote(string(seq.Data)), desc)
+	case SosSequence:
+		d.str = true
+		d.line("SOS "+strconv.Quote(string(seq.Data)), "start of string")
+	case PmSequence:
+		d.str = true
+		d.line("PM "+strconv.Quote(string(seq.Data)), "privacy message")
+	}
+}
+
+// flushText lists the pending text.
+func (d *Dumper) flushText() {
+	if len(d.text) == 0 {
+		return
+	}
+	s := string(d.text)
+	d.text = d.text[:0]
+	d.line(strconv.Quote(s), "text, "+plural(StringWidth(s), "cell"))
+}
+
+// line writes a line of the listing.
+func (d *Dumper) line(notation, desc string) {
+	d.buf.WriteString(notation)
+	if desc != "" {
+		d.buf.WriteString(" — ")
+		d.buf.WriteString(desc)
+	}
+	d.buf.WriteByte('\n')
+}
+
+// named returns the description desc of a sequence, after its name.
+func named(name, desc string) string {
+	switch {
+	case name == "":
+		return desc
+	case desc == "":
+		return name
+	}
+	return name + ": " + desc
+}
+
+// stateNotation returns the introducer of the sequences parsed in the
+// parser state s.
+func stateNotation(s parser.State) string {
+	switch s {
+	case parser.CsiEntryState, parser.CsiIntermediateState, parser.CsiParamState:
+		return "CSI"
+	case parser.DcsEntryState, parser.DcsIntermediateState, parser.DcsParamState, parser.DcsStringState:
+		return "DCS"
+	case parser.OscStringState:
+		return "OSC"
+	case parser.SosStringState:
+		return "SOS"
+	case parser.PmStringState:
+		return "PM"
+	case parser.ApcStringState:
+		return "APC"
+	case parser.Utf8State:
+		return "UTF-8"
+	}
+	return "ESC"
+}
+
+// controlDescription returns what the control character c does.
+func controlDescription(c byte) string {
+	switch c {
+	case NUL:
+		return "null"
+	case BEL:
+		return "bell"
+	case BS:
+		return "backspace"
+	case HT:
+		return "horizontal tab"
+	case LF:
+		return "line feed"
+	case VT:
+		return "vertical tab"
+	case FF:
+		return "form feed"
+	case CR:
+		return "carriage return"
+	case SO:
+		return "shift out, use the G1 character set"
+	case SI:
+		return "shift in, use the G0 character set"
+	case CAN:
+		return "cancel the sequence"
+	case SUB:
+		return "cancel the sequence"
+	case DEL:
+		return "delete"
+	case IND:
+		return "index"
+	case NEL:
+		return "next line"
+	case HTS:
+		return "set a tab stop"
+	case RI:
+		return "reverse index"
+	case ST:
+		return "string terminator"
+	}
+	return ""
+}
+
+// escNotation returns the notation of the escape sequence seq.
+func escNotation(seq EscSequence) string {
+	s := "ESC "
+	if i := seq.Intermediate(); i != 0 {
+		s += string(rune(i)) + " "
+	}
+	return s + string(rune(seq.Command()))
+}
+
+// describeEsc returns what the escape sequence seq does.
+func describeEsc(seq EscSequence) string {
+	cmd := seq.Command()
+	switch seq.Intermediate() {
+	case 0:
+		switch cmd {
+		case '7':
+			return "save cursor"
+		case '8':
+			return "restore cursor"
+		case '=':
+			return "application keypad"
+		case '>':
+			return "normal keypad"
+		case 'D':
+			return "index"
+		case 'E':
+			return "next line"
+		case 'H':
+			return "set a tab stop"
+		case 'M':
+			return "reverse index"
+		case 'N':
+			return "single shift 2"
+		case 'O':
+			return "single shift 3"
+		case '\\':
+			return "string terminator"
+		case 'c':
+			return "reset to initial state"
+		}
+	case '(', ')', '*', '+':
+		g := strings.IndexByte("()*+", byte(seq.Intermediate()))
+		return fmt.Sprintf("designate the %s character set as G%d", charsetName(cmd), g)
+	case '#':
+		if cmd == '8' {
+			return "screen alignment test"
+		}
+	}
+	return ""
+}
+
+// charsetName returns the name of the character set designated by the
+// final byte c.
+func charsetName(c int) string {
+	switch c {
+	case 'B':
+		return "ASCII"
+	case '0':
+		return "DEC special graphics"
+	case 'A':
+		return "British"
+	}
+	return strconv.Quote(string(rune(c)))
+}
+
+// paramsNotation returns the parameters as written in a sequence, with the
+// sub-parameters separated by colons.
+func paramsNotation(params []int) string {
+	var b strings.Builder
+	for i, p := range params {
+		if v := p & parser.ParamMask; v != parser.MissingParam {
+			b.WriteString(strconv.Itoa(v))
+		}
+		if i < len(params)-1 {
+			if p&parser.HasMoreFlag != 0 {
+				b.WriteByte(':')
+			} else {
+				b.WriteByte(';')
+			}
+		}
+	}
+	return b.String()
+}
+
+// csiNotation returns the notation of the control sequence seq.
+func csiNotation(seq CsiSequence) string {
+	return seqNotation("CSI", seq.Marker(), seq.Params, seq.Intermediate(), seq.Command())
+}
+
+// dcsNotation returns the notation of the device control string seq.
+func dcsNotation(seq DcsSequence) string {
+	s := seqNotation("DCS", seq.Marker(), seq.Params, seq.Intermediate(), seq.Command())
+	return s + " " + strconv.Quote(string(seq.Data))
+}
+
+func seqNotation(intro string, marker int, params []int, intermed, cmd int) string {
+	s := intro + " "
+	if marker != 0 {
+		s += string(rune(marker))
+	}
+	if p := paramsNotation(params); p != "" {
+		s += p + " "
+	} else if marker != 0 {
+		s += " "
+	}
+	if intermed != 0 {
+		s += string(rune(intermed)) + " "
+	}
+	return s + string(rune(cmd))
+}
+
+// describeCsi returns what the control sequence seq does.
+func describeCsi(seq CsiSequence) string {
+	// n returns the ith parameter, or def when missing.
+	n := func(i, def int) int {
+		if p := seq.Param(i); p >= 0 {
+			return p
+		}
+		return def
+	}
+
+	cmd := seq.Command()
+	switch seq.Marker() {
+	case 0:
+	case '?':
+		switch {
+		case seq.Intermediate() == '$' && cmd == 'p':
+			return "request mode " + modeNames(seq, true)
+		case seq.Intermediate() == '$' && cmd == 'y':
+			return fmt.Sprintf("mode %s is %s", privateModeName(n(0, 0)), modeSetting(n(1, 0)))
+		case cmd == 'h':
+			return "enable " + modeNames(seq, true)
+		case cmd == 'l':
+			return "disable " + modeNames(seq, true)
+		case cmd == 'u':
+			return "query the flags"
+		case cmd == 'c':
+			return ""
+		case cmd == 'n':
+			return "device status report"
+		}
+		return ""
+	case '>':
+		switch cmd {
+		case 'c':
+			return "secondary device attributes"
+		case 'q':
+			return "request the terminal name and version"
+		case 'u':
+			return "push flags"
+		case 'm':
+			return "set key modifier options"
+		}
+		return ""
+	case '<':
+		switch cmd {
+		case 'u':
+			return "pop flags"
+		case 'M':
+			return ""
+		case 'm':
+			return ""
+		}
+		return ""
+	case '=':
+		switch cmd {
+		case 'c':
+			return "tertiary device attributes"
+		case 'u':
+			return "set flags"
+		}
+		return ""
+	}
+
+	switch seq.Intermediate() {
+	case 0:
+	case ' ':
+		if cmd == 'q' {
+			return "set cursor style " + strconv.Itoa(n(0, 0))
+		}
+		return ""
+	case '$':
+		if cmd == 'p' {
+			return "request mode " + modeNames(seq, false)
+		}
+		if cmd == 'y' {
+			return fmt.Sprintf("mode %s is %s", ansiModeName(n(0, 0)), modeSetting(n(1, 0)))
+		}
+		return ""
+	case '!':
+		if cmd == 'p' {
+			return "soft reset"
+		}
+		return ""
+	default:
+		return ""
+	}
+
+	switch cmd {
+	case 'A':
+		return fmt.Sprintf("cursor up %d", max1(n(0, 1)))
+	case 'B':
+		return fmt.Sprintf("cursor down %d", max1(n(0, 1)))
+	case 'C':
+		return fmt.Sprintf("cursor forward %d", max1(n(0, 1)))
+	case 'D':
+		return fmt.Sprintf("cursor backward %d", max1(n(0, 1)))
+	case 'E':
+		return "cursor down " + plural(max1(n(0, 1)), "line") + ", to the first column"
+	case 'F':
+		return "cursor up " + plural(max1(n(0, 1)), "line") + ", to the first column"
+	case 'G':
+		return fmt.Sprintf("cursor to column %d", max1(n(0, 1)))
+	case 'H':
+		return fmt.Sprintf("cursor to row %d, column %d", max1(n(0, 1)), max1(n(1, 1)))
+	case 'f':
+		return fmt.Sprintf("cursor to row %d, column %d", max1(n(0, 1)), max1(n(1, 1)))
+	case 'd':
+		return fmt.Sprintf("cursor to row %d", max1(n(0, 1)))
+	case 'I':
+		return "cursor forward " + plural(max1(n(0, 1)), "tab stop")
+	case 'Z':
+		return "cursor backward " + plural(max1(n(0, 1)), "tab stop")
+	case 'J':
+		return "erase " + pick(n(0, 0), "below", "above", "the screen", "the scrollback")
+	case 'K':
+		return "erase " + pick(n(0, 0), "to the right", "to the left", "the line")
+	case 'X':
+		return "erase " + plural(max1(n(0, 1)), "character")
+	case '@':
+		return "insert " + plural(max1(n(0, 1)), "character")
+	case 'P':
+		return "delete " + plural(max1(n(0, 1)), "character")
+	case 'L':
+		return "insert " + plural(max1(n(0, 1)), "line")
+	case 'M':
+		return "delete " + plural(max1(n(0, 1)), "line")
+	case 'S':
+		return "scroll up " + plural(max1(n(0, 1)), "line")
+	case 'T':
+		return "scroll down " + plural(max1(n(0, 1)), "line")
+	case 'b':
+		return "repeat the last character " + plural(max1(n(0, 1)), "time")
+	case 'g':
+		return "clear tab stops"
+	case 'h':
+		return "set mode " + modeNames(seq, false)
+	case 'l':
+		return "reset mode " + modeNames(seq, false)
+	case 'm':
+		return describeSgr(seq)
+	case 'n':
+		switch n(0, 0) {
+		case 5:
+			return "request the terminal status"
+		case 6:
+			return "request the cursor position"
+		}
+		return "device status report"
+	case 'R':
+		return fmt.Sprintf("cursor at row %d, column %d", n(0, 1), n(1, 1))
+	case 'c':
+		return "request the primary device attributes"
+	case 'r':
+		return "set the scroll region"
+	case 's':
+		return "save cursor"
+	case 'u':
+		return "restore cursor"
+	case 't':
+		return "window operation " + strconv.Itoa(n(0, 0))
+	case '~':
+		return ""
+	}
+	return ""
+}
+
+// modeNames returns the names of the modes of the SM, RM, DECSET, DECRST,
+// and DECRQM sequence seq.
+func modeNames(seq CsiSequence, private bool) string {
+	names := make([]string, 0, len(seq.Params))
+	for i := range seq.Params {
+		if private {
+			names = append(names, privateModeName(seq.Param(i)))
+		} else {
+			names = append(names, ansiModeName(seq.Param(i)))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// privateModeNames are the names of the well-known DEC private modes.
+var privateModeNames = map[int]string{
+	1:    "cursor keys (DECCKM)",
+	5:    "reverse video (DECSCNM)",
+	6:    "origin (DECOM)",
+	7:    "autowrap (DECAWM)",
+	9:    "X10 mouse",
+	12:   "cursor blinking",
+	25:   "cursor visibility (DECTCEM)",
+	47:   "alternate screen",
+	66:   "numeric keypad (DECNKM)",
+	67:   "backarrow key (DECBKM)",
+	69:   "left and right margins (DECLRMM)",
+	1000: "normal mouse",
+	1001: "highlight mouse",
+	1002: "button event mouse",
+	1003: "any event mouse",
+	1004: "focus events",
+	1005: "UTF-8 mouse",
+	1006: "SGR mouse",
+	1015: "urxvt mouse",
+	1016: "SGR pixel mouse",
+	1047: "alternate screen",
+	1048: "save cursor",
+	1049: "alternate screen, saving the cursor",
+	2004: "bracketed paste",
+	2026: "synchronized output",
+	2027: "grapheme clustering",
+	2031: "light and dark mode reports",
+	2048: "in-band resize",
+}
+
+// privateModeName returns the name of the DEC private mode m.
+func privateModeName(m int) string {
+	if name, ok := privateModeNames[m]; ok {
+		return fmt.Sprintf("%d (%s)", m, name)
+	}
+	return strconv.Itoa(m)
+}
+
+// ansiModeName returns the name of the ANSI mode m.
+func ansiModeName(m int) string {
+	switch m {
+	case 2:
+		return "2 (keyboard action, KAM)"
+	case 4:
+		return "4 (insert/replace, IRM)"
+	case 12:
+		return "12 (send/receive, SRM)"
+	case 20:
+		return "20 (line feed/new line, LNM)"
+	}
+	return strconv.Itoa(m)
+}
+
+// modeSetting returns the name of the setting of a DECRPM report.
+func modeSetting(v int) string {
+	return pick(v, "not recognized", "set", "reset", "permanently set", "permanently reset")
+}
+
+// colorNames are the names of the 16 basic colors.
+var colorNames = [...]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright black", "bright red", "bright green", "bright yellow",
+	"bright blue", "bright magenta", "bright cyan", "bright white",
+}
+
+// underlineStyleNames are the names of the underline styles.
+var underlineStyleNames = [...]string{"no underline", "underline", "double underline", "curly underline", "dotted underline", "dashed underline"}
+
+// sgrNames are the names of the SGR attributes without arguments.
+var sgrNames = map[int]string{
+	0:  "reset",
+	1:  "bold",
+	2:  "faint",
+	3:  "italic",
+	4:  "underline",
+	5:  "slow blink",
+	6:  "rapid blink",
+	7:  "reverse",
+	8:  "conceal",
+	9:  "strikethrough",
+	21: "double underline",
+	22: "normal intensity",
+	23: "no italic",
+	24: "no underline",
+	25: "no blink",
+	27: "no reverse",
+	28: "no conceal",
+	29: "no strikethrough",
+	39: "default foreground",
+	49: "default background",
+	53: "overline",
+	55: "no overline",
+	59: "default underline color",
+}
+
+// describeSgr returns the attributes set by the SGR sequence seq.
+func describeSgr(seq CsiSequence) string {
+	if len(seq.Params) == 0 {
+		return "reset"
+	}
+
+	var attrs []string
+	for i := 0; i < len(seq.Params); i++ {
+		// The parameter and its sub-parameters.
+		group := []int{seq.Param(i)}
+		for seq.HasMore(i) && i < len(seq.Params)-1 {
+			i++
+			group = append(group, seq.Param(i))
+		}
+
+		p := group[0]
+		if p < 0 {
+			p = 0
+		}
+		switch {
+		case p == 4 && len(group) > 1:
+			attrs = append(attrs, pick(group[1], underlineStyleNames[:]...))
+		case p >= 30 && p <= 37:
+			attrs = append(attrs, colorNames[p-30]+" foreground")
+		case p >= 40 && p <= 47:
+			attrs = append(attrs, colorNames[p-40]+" background")
+		case p >= 90 && p <= 97:
+			attrs = append(attrs, colorNames[p-90+8]+" foreground")
+		case p >= 100 && p <= 107:
+			attrs = append(attrs, colorNames[p-100+8]+" background")
+		case p == 38, p == 48, p == 58:
+			args := group[1:]
+			if len(args) == 0 {
+				// The semicolon form, 38;5;n or 38;2;r;g;b.
+				n := 0
+				switch seq.Param(i + 1) {
+				case 5:
+					n = 2
+				case 2:
+					n = 4
+				}
+				for ; n > 0 && i+1 < len(seq.Params); n-- {
+					i++
+					args = append(args, seq.Param(i))
+				}
+			}
+			what := map[int]string{38: "foreground", 48: "background", 58: "underline color"}[p]
+			attrs = append(attrs, sgrColorName(args)+" "+what)
+		default:
+			if name, ok := sgrNames[p]; ok {
+				attrs = append(attrs, name)
+			} else {
+				attrs = append(attrs, "unknown "+strconv.Itoa(p))
+			}
+		}
+	}
+	return strings.Join(attrs, ", ")
+}
+
+// sgrColorName returns the name of the color of the arguments of a 38, 48,
+// or 58 SGR parameter, 5;n or 2;[colorspace;]r;g;b.
+func sgrColorName(args []int) string {
+	for i, a := range args {
+		if a < 0 {
+			args[i] = 0
+		}
+	}
+	switch {
+	case len(args) >= 2 && args[0] == 5:
+		if args[1] < 16 {
+			return colorNames[args[1]]
+		}
+		return "color " + strconv.Itoa(args[1])
+	case len(args) >= 4 && args[0] == 2:
+		rgb := args[len(args)-3:]
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0]&0xff, rgb[1]&0xff, rgb[2]&0xff)
+	}
+	return "invalid"
+}
+
+// describeOsc returns what the operating system command seq does.
+func describeOsc(seq OscSequence) string {
+	var data string
+	if i := bytes.IndexByte(seq.Data, ';'); i >= 0 {
+		data = string(seq.Data[i+1:])
+	}
+	query := data == "?"
+
+	// The parser only reads the command when it's followed by arguments,
+	// like in OSC 104 ; 1 ST, but not OSC 104 ST.
+	cmd := seq.Command()
+	if cmd == parser.MissingCommand {
+		if n, err := strconv.Atoi(string(seq.Data)); err == nil {
+			cmd = n
+		}
+	}
+
+	switch cmd {
+	case 0:
+		return fmt.Sprintf("set the window title and icon name to %q", data)
+	case 1:
+		return fmt.Sprintf("set the icon name to %q", data)
+	case 2:
+		return fmt.Sprintf("set the window title to %q", data)
+	case 4:
+		return "set or query palette colors"
+	case 7:
+		return "set the working directory to " + data
+	case 8:
+		parts := strings.SplitN(data, ";", 2)
+		if len(parts) < 2 || parts[1] == "" {
+			return "hyperlink: end"
+		}
+		return "hyperlink: " + parts[1]
+	case 9:
+		return fmt.Sprintf("notification %q", data)
+	case 10, 11, 12:
+		what := [...]string{"foreground", "background", "cursor"}[cmd-10]
+		if query {
+			return "query the " + what + " color"
+		}
+		return "set the " + what + " color to " + data
+	case 52:
+		return "clipboard"
+	case 104:
+		return "reset palette colors"
+	case 110, 111, 112:
+		return "reset the " + [...]string{"foreground", "background", "cursor"}[cmd-110] + " color"
+	}
+	return OscNames[cmd]
+}
+
+// describeDcs returns what the device control string seq does.
+func describeDcs(seq DcsSequence) string {
+	switch {
+	case seq.Intermediate() == '+' && seq.Command() == 'q':
+		return "request termcap capabilities"
+	case seq.Intermediate() == '$' && seq.Command() == 'q':
+		return "request a setting"
+	}
+	return ""
+}
+
+// plural returns n followed by noun, in the plural unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+func max1(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
+
+// pick returns the ith name, or "unknown" followed by i when there is no
+// such name.
+func pick(i int, names ...string) string {
+	if i < 0 || i >= len(names) {
+		return "unknown " + strconv.Itoa(i)
+	}
+	return names[i]
+}
@@ -0,0 +1,382 @@
+package ansi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{"empty", "", nil},
+		{"text", "hello 你", []string{`"hello 你" — text, 8 cells`}},
+		{"single_cell", "é", []string{`"é" — text, 1 cell`}},
+		{"controls", "a\r\n\x07", []string{`"a" — text, 1 cell`, "CR — carriage return", "LF — line feed", "BEL — bell"}},
+		{"sgr", "\x1b[1;31mhi\x1b[m", []string{"CSI 1;31 m — SGR: bold, red foreground", `"hi" — text, 2 cells`, "CSI m — SGR: reset"}},
+		{"sgr_colors", "\x1b[38;5;200;48;2;1;2;3;97m", []string{"CSI 38;5;200;48;2;1;2;3;97 m — SGR: color 200 foreground, #010203 background, bright white foreground"}},
+		{"sgr_subparams", "\x1b[4:3;58:2::255:0:0m", []string{"CSI 4:3;58:2::255:0:0 m — SGR: curly underline, #ff0000 underline color"}},
+		{"cursor", "\x1b[H\x1b[5;10H\x1b[3A", []string{"CSI H — CUP: cursor to row 1, column 1", "CSI 5;10 H — CUP: cursor to row 5, column 10", "CSI 3 A — CUU: cursor up 3"}},
+		{"decset", "\x1b[?1049;2004h", []string{"CSI ?1049;2004 h — DECSET: enable 1049 (alternate screen, saving the cursor), 2004 (bracketed paste)"}},
+		{"decrpm", "\x1b[?2026;2$y", []string{"CSI ?2026;2 $ y — DECRPM: mode 2026 (synchronized output) is reset"}},
+		{"esc", "\x1b7\x1b(0", []string{"ESC 7 — DECSC: save cursor", "ESC ( 0 — SCS: designate the DEC special graphics character set as G0"}},
+		{"osc_st", "\x1b]2;title\x1b\\", []string{`OSC "2;title" — set the window title to "title"`}},
+		{"osc_bel", "\x1b]8;;https://charm.sh\x07", []string{`OSC "8;;https://charm.sh" — hyperlink: https://charm.sh`}},
+		{"dcs", "\x1bP>|xterm(1)\x1b\\", []string{`DCS > | "xterm(1)" — XTVERSION reply`}},
+		{"unknown", "\x1b[5z", []string{"CSI 5 z"}},
+		{"incomplete", "hi\x1b[1;3", []string{`"hi" — text, 2 cells`, "CSI — incomplete sequence"}},
+		{"incomplete_esc", "\x1b", []string{"ESC — incomplete sequence"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var expect string
+			if len(c.expect) > 0 {
+				expect = strings.Join(c.expect, "\n") + "\n"
+			}
+			if got := Dump(c.input); got != expect {
+				t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+			}
+		})
+	}
+}
+
+func TestDumperSplitWrites(t *testing.T) {
+	input := "\x1b[1;31mhello\x1b]2;title\x1b\\"
+	var b strings.Builder
+	d := NewDumper(&b)
+	for i := 0; i < len(input); i++ {
+		if _, err := d.Write([]byte{input[i]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, expect := b.String(), Dump(input); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+// dumpCase is an input and its listing, one line per element.
+type dumpCase struct {
+	name   string
+	input  string
+	expect []string
+}
+
+func testDump(t *testing.T, cases []dumpCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var expect string
+			if len(c.expect) > 0 {
+				expect = strings.Join(c.expect, "\n") + "\n"
+			}
+			if got := Dump(c.input); got != expect {
+				t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+			}
+		})
+	}
+}
+
+func TestDumpControls(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"c0", "\x00\b\t\v\f", []string{"NUL — null", "BS — backspace", "HT — horizontal tab", "VT — vertical tab", "FF — form feed"}},
+		{"shifts", "\x0e\x0f", []string{"SO — shift out, use the G1 character set", "SI — shift in, use the G0 character set"}},
+		{"no_description", "\x1c", []string{"FS"}},
+		{"del", "\x7f", []string{"DEL — delete"}},
+		{"c1", "\x84\x85\x88\x8d\x9c", []string{"IND — index", "NEL — next line", "HTS — set a tab stop", "RI — reverse index", "ST — string terminator"}},
+		{"between_text", "a\x18b", []string{`"a" — text, 1 cell`, "CAN — cancel the sequence", `"b" — text, 1 cell`}},
+	})
+}
+
+func TestDumpEsc(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"cursor", "\x1b8", []string{"ESC 8 — DECRC: restore cursor"}},
+		{"keypad", "\x1b=\x1b>", []string{"ESC = — DECKPAM: application keypad", "ESC > — DECKPNM: normal keypad"}},
+		{"index", "\x1bD\x1bE\x1bM", []string{"ESC D — IND: index", "ESC E — NEL: next line", "ESC M — RI: reverse index"}},
+		{"tab_stop", "\x1bH", []string{"ESC H — HTS: set a tab stop"}},
+		{"single_shifts", "\x1bN\x1bO", []string{"ESC N — SS2: single shift 2", "ESC O — SS3: single shift 3"}},
+		{"reset", "\x1bc", []string{"ESC c — RIS: reset to initial state"}},
+		{"name_only", "\x1bn\x1b#3", []string{"ESC n — LS2", "ESC # 3 — DECDHL top"}},
+		{"alignment", "\x1b#8", []string{"ESC # 8 — DECALN: screen alignment test"}},
+		{"charsets", "\x1b)B\x1b+A\x1b*<", []string{
+			"ESC ) B — SCS: designate the ASCII character set as G1",
+			"ESC + A — SCS: designate the British character set as G3",
+			`ESC * < — SCS: designate the "<" character set as G2`,
+		}},
+		{"space_intermediate", "\x1b F", []string{"ESC   F — S7C1T"}},
+		{"unknown", "\x1bz", []string{"ESC z"}},
+		{"lone_st", "\x1b\\", []string{`ESC \ — ST: string terminator`}},
+	})
+}
+
+func TestDumpCsi(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"cursor_moves", "\x1b[B\x1b[2C\x1b[0D", []string{"CSI B — CUD: cursor down 1", "CSI 2 C — CUF: cursor forward 2", "CSI 0 D — CUB: cursor backward 1"}},
+		{"cursor_lines", "\x1b[3E\x1b[F", []string{"CSI 3 E — CNL: cursor down 3 lines, to the first column", "CSI F — CPL: cursor up 1 line, to the first column"}},
+		{"cursor_position", "\x1b[7G\x1b[2;3f\x1b[4d\x1b[0;0H", []string{
+			"CSI 7 G — CHA: cursor to column 7",
+			"CSI 2;3 f — HVP: cursor to row 2, column 3",
+			"CSI 4 d — VPA: cursor to row 4",
+			"CSI 0;0 H — CUP: cursor to row 1, column 1",
+		}},
+		{"tabs", "\x1b[2I\x1b[Z\x1b[3g", []string{"CSI 2 I — CHT: cursor forward 2 tab stops", "CSI Z — CBT: cursor backward 1 tab stop", "CSI 3 g — TBC: clear tab stops"}},
+		{"erase_display", "\x1b[J\x1b[1J\x1b[2J\x1b[3J\x1b[9J", []string{
+			"CSI J — ED: erase below",
+			"CSI 1 J — ED: erase above",
+			"CSI 2 J — ED: erase the screen",
+			"CSI 3 J — ED: erase the scrollback",
+			"CSI 9 J — ED: erase unknown 9",
+		}},
+		{"erase_line", "\x1b[K\x1b[1K\x1b[2K\x1b[?2K", []string{"CSI K — EL: erase to the right", "CSI 1 K — EL: erase to the left", "CSI 2 K — EL: erase the line", "CSI ?2 K — DECSEL"}},
+		{"editing", "\x1b[3X\x1b[@\x1b[2P\x1b[L\x1b[4M", []string{
+			"CSI 3 X — ECH: erase 3 characters",
+			"CSI @ — ICH: insert 1 character",
+			"CSI 2 P — DCH: delete 2 characters",
+			"CSI L — IL: insert 1 line",
+			"CSI 4 M — DL: delete 4 lines",
+		}},
+		{"scroll", "\x1b[S\x1b[2T\x1b[2;20r", []string{"CSI S — SU: scroll up 1 line", "CSI 2 T — SD: scroll down 2 lines", "CSI 2;20 r — DECSTBM: set the scroll region"}},
+		{"repeat", "\x1b[5b", []string{"CSI 5 b — REP: repeat the last character 5 times"}},
+		{"ansi_modes", "\x1b[4h\x1b[20;99l", []string{"CSI 4 h — SM: set mode 4 (insert/replace, IRM)", "CSI 20;99 l — RM: reset mode 20 (line feed/new line, LNM), 99"}},
+		{"private_modes", "\x1b[?25l\x1b[?9999h", []string{"CSI ?25 l — DECRST: disable 25 (cursor visibility (DECTCEM))", "CSI ?9999 h — DECSET: enable 9999"}},
+		{"mode_requests", "\x1b[?1$p\x1b[2$p", []string{"CSI ?1 $ p — DECRQM: request mode 1 (cursor keys (DECCKM))", "CSI 2 $ p — DECRQM: request mode 2 (keyboard action, KAM)"}},
+		{"mode_reports", "\x1b[4;1$y\x1b[?25;0$y\x1b[?1;9$y", []string{
+			"CSI 4;1 $ y — DECRPM: mode 4 (insert/replace, IRM) is set",
+			"CSI ?25;0 $ y — DECRPM: mode 25 (cursor visibility (DECTCEM)) is not recognized",
+			"CSI ?1;9 $ y — DECRPM: mode 1 (cursor keys (DECCKM)) is unknown 9",
+		}},
+		{"status", "\x1b[5n\x1b[6n\x1b[9n\x1b[?6n\x1b[12;40R", []string{
+			"CSI 5 n — DSR: request the terminal status",
+			"CSI 6 n — DSR: request the cursor position",
+			"CSI 9 n — DSR: device status report",
+			"CSI ?6 n — DECDSR: device status report",
+			"CSI 12;40 R — CPR: cursor at row 12, column 40",
+		}},
+		{"device_attributes", "\x1b[c\x1b[?62;4c\x1b[>c\x1b[=c\x1b[>q", []string{
+			"CSI c — DA1: request the primary device attributes",
+			"CSI ?62;4 c — DA1 reply",
+			"CSI > c — DA2: secondary device attributes",
+			"CSI = c — DA3: tertiary device attributes",
+			"CSI > q — XTVERSION: request the terminal name and version",
+		}},
+		{"save_restore", "\x1b[s\x1b[u", []string{"CSI s — SCOSC: save cursor", "CSI u — SCORC: restore cursor"}},
+		{"window", "\x1b[14t", []string{"CSI 14 t — XTWINOPS: window operation 14"}},
+		{"kitty_keyboard", "\x1b[?u\x1b[>1u\x1b[<u\x1b[=3;2u", []string{
+			"CSI ? u — Kitty keyboard flags: query the flags",
+			"CSI >1 u — Kitty keyboard push: push flags",
+			"CSI < u — Kitty keyboard pop: pop flags",
+			"CSI =3;2 u — Kitty keyboard set: set flags",
+		}},
+		{"key_modifiers", "\x1b[>4;1m", []string{"CSI >4;1 m — XTMODKEYS: set key modifier options"}},
+		{"mouse", "\x1b[<0;10;5M\x1b[<0;10;5m", []string{"CSI <0;10;5 M — SGR mouse press", "CSI <0;10;5 m — SGR mouse release"}},
+		{"intermediates", "\x1b[2 q\x1b[!p\x1b[\"p\x1b[1$z", []string{
+			"CSI 2   q — DECSCUSR: set cursor style 2",
+			"CSI ! p — DECSTR: soft reset",
+			`CSI " p — DECSCL`,
+			"CSI 1 $ z — DECERA",
+		}},
+		{"unknown", "\x1b[2~\x1b[>5z\x1b[#p\x1b[?5$z", []string{"CSI 2 ~", "CSI >5 z", "CSI # p", "CSI ?5 $ z"}},
+		{"c1", "\x9b1m", []string{"CSI 1 m — SGR: bold"}},
+	})
+}
+
+func TestDumpSgr(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"attributes", "\x1b[2;3;5;6;7;8;9;53m", []string{"CSI 2;3;5;6;7;8;9;53 m — SGR: faint, italic, slow blink, rapid blink, reverse, conceal, strikethrough, overline"}},
+		{"resets", "\x1b[22;23;24;25;27;28;29;55m", []string{"CSI 22;23;24;25;27;28;29;55 m — SGR: normal intensity, no italic, no underline, no blink, no reverse, no conceal, no strikethrough, no overline"}},
+		{"default_colors", "\x1b[39;49;59m", []string{"CSI 39;49;59 m — SGR: default foreground, default background, default underline color"}},
+		{"basic_colors", "\x1b[30;47;90;100m", []string{"CSI 30;47;90;100 m — SGR: black foreground, white background, bright black foreground, bright black background"}},
+		{"indexed", "\x1b[38;5;3;48;5;17m", []string{"CSI 38;5;3;48;5;17 m — SGR: yellow foreground, color 17 background"}},
+		{"indexed_colons", "\x1b[38:5:17m", []string{"CSI 38:5:17 m — SGR: color 17 foreground"}},
+		{"rgb", "\x1b[38;2;255;128;0m", []string{"CSI 38;2;255;128;0 m — SGR: #ff8000 foreground"}},
+		{"rgb_colorspace", "\x1b[38:2:0:1:2:3m", []string{"CSI 38:2:0:1:2:3 m — SGR: #010203 foreground"}},
+		{"underline_color", "\x1b[58;5;9m", []string{"CSI 58;5;9 m — SGR: bright red underline color"}},
+		{"underline_styles", "\x1b[4:0m\x1b[4:1m\x1b[4:2m\x1b[4:4m\x1b[4:5m", []string{
+			"CSI 4:0 m — SGR: no underline",
+			"CSI 4:1 m — SGR: underline",
+			"CSI 4:2 m — SGR: double underline",
+			"CSI 4:4 m — SGR: dotted underline",
+			"CSI 4:5 m — SGR: dashed underline",
+		}},
+		{"missing_param", "\x1b[;1m", []string{"CSI ;1 m — SGR: reset, bold"}},
+		{"unknown", "\x1b[4;77m", []string{"CSI 4;77 m — SGR: underline, unknown 77"}},
+		{"unknown_underline_style", "\x1b[4:9m", []string{"CSI 4:9 m — SGR: unknown 9"}},
+		{"color_missing_args", "\x1b[38m\x1b[38;2;1m", []string{"CSI 38 m — SGR: invalid foreground", "CSI 38;2;1 m — SGR: invalid foreground"}},
+		{"color_unknown_kind", "\x1b[38;7m", []string{"CSI 38;7 m — SGR: invalid foreground, reverse"}},
+	})
+}
+
+func TestDumpOsc(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"titles", "\x1b]0;both\a\x1b]1;icon\a", []string{`OSC "0;both" — set the window title and icon name to "both"`, `OSC "1;icon" — set the icon name to "icon"`}},
+		{"palette", "\x1b]4;1;?\a\x1b]104\a", []string{`OSC "4;1;?" — set or query palette colors`, `OSC "104" — reset palette colors`}},
+		{"working_directory", "\x1b]7;file://host/tmp\a", []string{`OSC "7;file://host/tmp" — set the working directory to file://host/tmp`}},
+		{"hyperlink_end", "\x1b]8;;\a", []string{`OSC "8;;" — hyperlink: end`}},
+		{"notification", "\x1b]9;hi\a", []string{`OSC "9;hi" — notification "hi"`}},
+		{"colors", "\x1b]10;?\a\x1b]11;#000000\a\x1b]12;?\a", []string{
+			`OSC "10;?" — query the foreground color`,
+			`OSC "11;#000000" — set the background color to #000000`,
+			`OSC "12;?" — query the cursor color`,
+		}},
+		{"color_resets", "\x1b]110\a\x1b]111\a\x1b]112\a", []string{
+			`OSC "110" — reset the foreground color`,
+			`OSC "111" — reset the background color`,
+			`OSC "112" — reset the cursor color`,
+		}},
+		{"clipboard", "\x1b]52;c;?\a", []string{`OSC "52;c;?" — clipboard`}},
+		{"names", "\x1b]133;A\a\x1b]1337;x\a", []string{`OSC "133;A" — semantic prompt`, `OSC "1337;x" — iTerm2`}},
+		{"unknown", "\x1b]999;x\a\x1b]x\a", []string{`OSC "999;x"`, `OSC "x"`}},
+		{"c1", "\x9d2;t\x9c", []string{`OSC "2;t" — set the window title to "t"`}},
+	})
+}
+
+func TestDumpDcs(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"xtgettcap", "\x1bP+q544e\x1b\\\x1bP1+r544e=78\x1b\\", []string{`DCS + q "544e" — XTGETTCAP: request termcap capabilities`, `DCS 1 + r "544e=78" — XTGETTCAP reply`}},
+		{"decrqss", "\x1bP$qm\x1b\\\x1bP1$r0m\x1b\\", []string{`DCS $ q "m" — DECRQSS: request a setting`, `DCS 1 $ r "0m" — DECRQSS reply`}},
+		{"names", "\x1bP!|00000000\x1b\\\x1bPq#0\x1b\\\x1bPtmux;x\x1b\\", []string{`DCS ! | "00000000" — DA3 reply`, `DCS q "#0" — sixel`, `DCS t "mux;x" — tmux passthrough`}},
+		{"unknown", "\x1bPz\x1b\\", []string{`DCS z ""`}},
+		{"c1", "\x90$qm\x9c", []string{`DCS $ q "m" — DECRQSS: request a setting`}},
+	})
+}
+
+func TestDumpStrings(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"kitty_graphics", "\x1b_Gi=1\x1b\\", []string{`APC "Gi=1" — Kitty graphics`}},
+		{"apc", "\x1b_x\x1b\\", []string{`APC "x" — application program command`}},
+		{"sos", "\x1bXsos\x1b\\", []string{`SOS "sos" — start of string`}},
+		{"pm", "\x1b^pm\x1b\\", []string{`PM "pm" — privacy message`}},
+	})
+}
+
+func TestDumpMalformed(t *testing.T) {
+	testDump(t, []dumpCase{
+		{"canceled_csi", "\x1b[1\x18x", []string{"CAN — cancel the sequence", `"x" — text, 1 cell`}},
+		{"substituted_csi", "\x1b[1\x1ax", []string{"SUB — cancel the sequence", `"x" — text, 1 cell`}},
+		{"string_interrupted", "\x1b]2;a\x1bx", []string{`OSC "2;a" — set the window title to "a"`, "ESC x"}},
+		{"truncated_csi_marker", "\x1b[?", []string{"CSI — incomplete sequence"}},
+		{"truncated_csi_intermediate", "\x1b[1$", []string{"CSI — incomplete sequence"}},
+		{"truncated_esc_intermediate", "\x1b(", []string{"ESC — incomplete sequence"}},
+		{"truncated_dcs", "\x1bP", []string{"DCS — incomplete sequence"}},
+		{"truncated_dcs_intermediate", "\x1bP1$", []string{"DCS — incomplete sequence"}},
+		{"truncated_dcs_data", "\x1bPx", []string{"DCS — incomplete sequence"}},
+		{"truncated_osc", "\x1b]2;ti", []string{"OSC — incomplete sequence"}},
+		{"truncated_st", "\x1b]2;a\x1b", []string{`OSC "2;a" — set the window title to "a"`, "ESC — incomplete sequence"}},
+		{"truncated_apc", "\x1b_x", []string{"APC — incomplete sequence"}},
+		{"truncated_sos", "\x1bXx", []string{"SOS — incomplete sequence"}},
+		{"truncated_pm", "\x1b^x", []string{"PM — incomplete sequence"}},
+		{"truncated_utf8", "a\xe4\xb8", []string{`"a" — text, 1 cell`, "UTF-8 — incomplete sequence"}},
+	})
+}
+
+// TestDumpNames checks that the dumper lists the names of the sequences in
+// the registry.
+func TestDumpNames(t *testing.T) {
+	seq := func(intro string, cmd Cmd) string {
+		var s string
+		if m := cmd.Marker(); m != 0 {
+			s += string(rune(m))
+		}
+		if i := cmd.Intermediate(); i != 0 {
+			s += string(rune(i))
+		}
+		return intro + s + string(rune(cmd.Command()))
+	}
+	check := func(input, name string) {
+		t.Helper()
+		got := Dump(input)
+		if strings.Count(got, "\n") != 1 || !strings.Contains(got, " — "+name) {
+			t.Errorf("%q: expected a single line named %q, got %q", input, name, got)
+		}
+	}
+
+	for cmd, name := range CsiNames {
+		check(seq("\x1b[", cmd), name)
+	}
+	for cmd, name := range EscNames {
+		check(seq("\x1b", cmd), name)
+	}
+	for cmd, name := range DcsNames {
+		check(seq("\x1bP", cmd)+"\x1b\\", name)
+	}
+	for cmd := range OscNames {
+		input := "\x1b]" + strconv.Itoa(cmd) + ";x\a"
+		if got := Dump(input); !strings.Contains(got, " — ") {
+			t.Errorf("%q: expected a description, got %q", input, got)
+		}
+	}
+}
+
+// TestDumperChunks checks that the listing doesn't depend on how the input
+// is split across writes.
+func TestDumperChunks(t *testing.T) {
+	input := "héllo 你\x1b[1;38:2::1:2:3m\x1b]8;;https://charm.sh\x1b\\link\x1b]8;;\a" +
+		"\x1bP$qm\x1b\\\x1b_Gi=1\x1b\\\x9b2J\x1b(0\r\n"
+	expect := Dump(input)
+	for size := 1; size <= len(input); size++ {
+		var b strings.Builder
+		d := NewDumper(&b)
+		for i := 0; i < len(input); i += size {
+			end := i + size
+			if end > len(input) {
+				end = len(input)
+			}
+			if _, err := d.Write([]byte(input[i:end])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := d.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != expect {
+			t.Errorf("chunks of %d: expected:\n%s\ngot:\n%s", size, expect, got)
+		}
+	}
+}
+
+func TestDumperFlush(t *testing.T) {
+	var b strings.Builder
+	d := NewDumper(&b)
+
+	// Text is only listed once the sequence that follows it is complete, or
+	// when flushed.
+	d.Write([]byte("ab\x1b[")) //nolint:errcheck
+	if got := b.String(); got != "" {
+		t.Errorf("expected nothing, got %q", got)
+	}
+
+	// Flushing lists the incomplete sequence and resets the parser.
+	d.Flush()              //nolint:errcheck
+	d.Write([]byte("1mc")) //nolint:errcheck
+	d.Flush()              //nolint:errcheck
+	d.Flush()              //nolint:errcheck
+	expect := "\"ab\" — text, 2 cells\nCSI — incomplete sequence\n\"1mc\" — text, 3 cells\n"
+	if got := b.String(); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+// errWriter is a writer that always fails.
+type errWriter struct{}
+
+var errWrite = errors.New("write error")
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestDumperWriteError(t *testing.T) {
+	d := NewDumper(errWriter{})
+	if n, err := d.Write([]byte("\x1b[m")); n != 3 || !errors.Is(err, errWrite) {
+		t.Errorf("expected 3 bytes and a write error, got %d and %v", n, err)
+	}
+	d.Write([]byte("a")) //nolint:errcheck
+	if err := d.Flush(); !errors.Is(err, errWrite) {
+		t.Errorf("expected a write error, got %v", err)
+	}
+}
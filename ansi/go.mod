@@ -2,4 +2,7 @@ module github.com/charmbracelet/x/ansi
 
 go 1.18
 
-require github.com/rivo/uniseg v0.4.7
+require (
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/text v0.19.0
+)
@@ -0,0 +1,160 @@
+package ansi
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// GraphicSequences is how [Graphic] shows escape sequences.
+type GraphicSequences uint8
+
+// Ways to show escape sequences.
+const (
+	// ShowSequences shows escape sequences as text, with their control
+	// characters made visible, like "␛[1m".
+	ShowSequences GraphicSequences = iota
+
+	// KeepSequences keeps escape sequences as they are, so that styles still
+	// apply. Only use it with strings that are trusted to hold harmless
+	// sequences.
+	KeepSequences
+
+	// FlagSequences replaces escape sequences with their name, like "⟨SGR⟩",
+	// see [SequenceName]. Sequences without a name are flagged with their
+	// introducer, like "⟨CSI⟩".
+	FlagSequences
+)
+
+// GraphicOptions are the options of [GraphicOptions.Graphic].
+type GraphicOptions struct {
+	// Caret shows C0 control characters and DEL in caret notation, like
+	// "^[", instead of Unicode Control Pictures, like "␛".
+	Caret bool
+
+	// Sequences is how escape sequences are shown.
+	Sequences GraphicSequences
+}
+
+// Graphic returns s with its control characters made visible, to safely
+// show untrusted strings in user interfaces and logs. C0 control characters
+// and DEL are replaced with Unicode Control Pictures, like "␀", "␛", and "␡",
+// and C1 control characters with their "M-" notation, like "M-^[" for CSI.
+// Escape sequences are shown as text, and printable text is kept.
+//
+//	ansi.Graphic("\x1b[1mhi\x1b[m\r\n") // "␛[1mhi␛[m␍␊"
+//
+// Use [GraphicOptions] to choose another notation.
+func Graphic(s string) string {
+	return GraphicOptions{}.Graphic(s)
+}
+
+// Graphic returns s with its control characters made visible, like
+// [Graphic], using the options.
+//
+//	ansi.GraphicOptions{Caret: true}.Graphic("a\tb") // "a^Ib"
+func (o GraphicOptions) Graphic(s string) string {
+	var (
+		b  strings.Builder
+		sc = NewScanner(s)
+	)
+	b.Grow(len(s))
+	for sc.Scan() {
+		tok := sc.Token()
+		switch sc.Kind() {
+		case TextToken:
+			for _, r := range tok {
+				if r >= 0x80 && r < 0xa0 {
+					o.writeControl(&b, byte(r))
+				} else {
+					b.WriteRune(r)
+				}
+			}
+		case ControlToken:
+			o.writeControl(&b, tok[0])
+		case SequenceToken:
+			switch o.Sequences {
+			case KeepSequences:
+				b.WriteString(tok)
+			case FlagSequences:
+				b.WriteString("⟨" + sequenceFlag(tok) + "⟩")
+			default:
+				for i := 0; i < len(tok); {
+					r, n := utf8.DecodeRuneInString(tok[i:])
+					if r < 0x20 || r == DEL || r >= 0x80 && r < 0xa0 || r == utf8.RuneError && n == 1 {
+						o.writeControl(&b, tok[i])
+					} else {
+						b.WriteString(tok[i : i+n])
+					}
+					i += n
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeControl writes the visible form of the control character, or invalid
+// byte, c.
+func (o GraphicOptions) writeControl(b *strings.Builder, c byte) {
+	switch {
+	case c < 0x20 && o.Caret:
+		b.WriteByte('^')
+		b.WriteByte(c + 0x40)
+	case c < 0x20:
+		b.WriteRune(0x2400 + rune(c))
+	case c == DEL && o.Caret:
+		b.WriteString("^?")
+	case c == DEL:
+		b.WriteRune('␡')
+	case c >= 0x80:
+		// C1 control characters, and bytes that aren't valid UTF-8, have
+		// no pictures.
+		b.WriteString("M-")
+		c -= 0x80
+		switch {
+		case c < 0x20:
+			b.WriteByte('^')
+			b.WriteByte(c + 0x40)
+		case c == DEL:
+			b.WriteString("^?")
+		default:
+			b.WriteByte(c)
+		}
+	default:
+		b.WriteByte(c)
+	}
+}
+
+// sequenceFlag returns the name of the escape sequence seq, or the name of
+// its introducer when it has none.
+func sequenceFlag(seq string) string {
+	var name string
+	p := GetParser()
+	p.Parse(func(s Sequence) {
+		if name == "" {
+			name = SequenceName(s)
+		}
+	}, []byte(seq))
+	PutParser(p)
+	if name != "" {
+		return name
+	}
+
+	if seq[0] == ESC && len(seq) > 1 {
+		switch seq[1] {
+		case '[':
+			return "CSI"
+		case ']':
+			return "OSC"
+		case 'P':
+			return "DCS"
+		case '_':
+			return "APC"
+		case 'X':
+			return "SOS"
+		case '^':
+			return "PM"
+		}
+	}
+	return ControlName(seq[0])
+}
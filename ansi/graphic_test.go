@@ -0,0 +1,34 @@
+package ansi
+
+import "testing"
+
+func TestGraphic(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   GraphicOptions
+		input  string
+		expect string
+	}{
+		{"text", GraphicOptions{}, "hello, 世界", "hello, 世界"},
+		{"controls", GraphicOptions{}, "a\x7fb\x00\r\n", "a␡b␀␍␊"},
+		{"caret", GraphicOptions{Caret: true}, "a\x7fb\x00\t", "a^?b^@^I"},
+		{"sequences", GraphicOptions{}, "\x1b[1mhi\x1b[m", "␛[1mhi␛[m"},
+		{"sequences caret", GraphicOptions{Caret: true}, "\x1b]2;title\a", "^[]2;title^G"},
+		{"c1", GraphicOptions{}, "\x9b2Jx\u009by", "M-^[2JxM-^[y"},
+		{"invalid", GraphicOptions{}, "\xffz", "M-^?z"},
+		{"keep", GraphicOptions{Sequences: KeepSequences}, "\x1b[1mhi\x1b[m\n", "\x1b[1mhi\x1b[m␊"},
+		{"flag", GraphicOptions{Sequences: FlagSequences}, "\x1b[1mhi\x1b[m\x1b[?2004h", "⟨SGR⟩hi⟨SGR⟩⟨DECSET 2004⟩"},
+		{"flag unknown", GraphicOptions{Sequences: FlagSequences}, "\x1b[5z\x1b", "⟨CSI⟩⟨ESC⟩"},
+		{"flag string", GraphicOptions{Sequences: FlagSequences}, "\x1b]8;;https://charm.sh\x1b\\link\x1b]8;;\x1b\\", "⟨hyperlink⟩link⟨hyperlink⟩"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.opts.Graphic(c.input); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+	if got := Graphic("\x1b[m\n"); got != "␛[m␊" {
+		t.Errorf("expected %q, got %q", "␛[m␊", got)
+	}
+}
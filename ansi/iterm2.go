@@ -0,0 +1,51 @@
+package ansi
+
+import "encoding/base64"
+
+// RequestITerm2CellSize is a sequence for requesting the size of a cell in
+// points, and the number of pixels per point, from iTerm2.
+//
+//	OSC 1337 ; ReportCellSize ST
+//	OSC 1337 ; ReportCellSize BEL
+//
+// The terminal replies with:
+//
+//	OSC 1337 ; ReportCellSize = height ; width ; scale ST
+//
+// See: https://iterm2.com/documentation-escape-codes.html
+const RequestITerm2CellSize = "\x1b]1337;ReportCellSize\x07"
+
+// ITerm2SetUserVar returns a sequence for setting the iTerm2 user variable
+// name to value, which can be used in badges and titles.
+//
+//	OSC 1337 ; SetUserVar = name = value ST
+//	OSC 1337 ; SetUserVar = name = value BEL
+//
+// Where value is base64 encoded.
+//
+// See: https://iterm2.com/documentation-escape-codes.html
+func ITerm2SetUserVar(name, value string) string {
+	return "\x1b]1337;SetUserVar=" + name + "=" +
+		base64.StdEncoding.EncodeToString([]byte(value)) + "\x07"
+}
+
+// ITerm2CopyToClipboard returns a sequence that makes iTerm2 copy the text
+// written after it to the clipboard, until [ITerm2EndCopy]. The clipboard
+// name can be empty for the general clipboard, "rule", "find", or "font".
+//
+//	OSC 1337 ; CopyToClipboard = name ST
+//	OSC 1337 ; CopyToClipboard = name BEL
+//
+// See: https://iterm2.com/documentation-escape-codes.html
+func ITerm2CopyToClipboard(name string) string {
+	return "\x1b]1337;CopyToClipboard=" + name + "\x07"
+}
+
+// ITerm2EndCopy is a sequence that ends the copy started by
+// [ITerm2CopyToClipboard].
+//
+//	OSC 1337 ; EndCopy ST
+//	OSC 1337 ; EndCopy BEL
+//
+// See: https://iterm2.com/documentation-escape-codes.html
+const ITerm2EndCopy = "\x1b]1337;EndCopy\x07"
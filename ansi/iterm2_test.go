@@ -0,0 +1,21 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestITerm2SetUserVar(t *testing.T) {
+	expect := "\x1b]1337;SetUserVar=foo=YmFy\x07"
+	if got := ansi.ITerm2SetUserVar("foo", "bar"); got != expect {
+		t.Errorf("expected: %q, got: %q", expect, got)
+	}
+}
+
+func TestITerm2CopyToClipboard(t *testing.T) {
+	expect := "\x1b]1337;CopyToClipboard=\x07"
+	if got := ansi.ITerm2CopyToClipboard(""); got != expect {
+		t.Errorf("expected: %q, got: %q", expect, got)
+	}
+}
@@ -0,0 +1,18 @@
+package ansi
+
+// KeypadApplicationMode (DECKPAM) is an escape sequence that makes the
+// numeric keypad send application sequences, like SS3 M for the keypad
+// Enter key, instead of the characters printed on the keys.
+//
+//	ESC =
+//
+// See: https://vt100.net/docs/vt510-rm/DECKPAM.html
+const KeypadApplicationMode = "\x1b="
+
+// KeypadNumericMode (DECKPNM) is an escape sequence that makes the numeric
+// keypad send the characters printed on the keys. This is the default.
+//
+//	ESC >
+//
+// See: https://vt100.net/docs/vt510-rm/DECKPNM.html
+const KeypadNumericMode = "\x1b>"
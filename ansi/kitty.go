@@ -15,9 +15,29 @@ const (
 		KittyReportAlternateKeys | KittyReportAllKeysAsEscapeCodes | KittyReportAssociatedKeys
 )
 
+// Kitty keyboard protocol modes used with [KittyKeyboard] to specify how the
+// given flags are applied to the current set of flags.
+// See: https://sw.kovidgoyal.net/kitty/keyboard-protocol/#progressive-enhancement
+const (
+	// KittySetFlagsMode sets the given flags and unsets all others.
+	KittySetFlagsMode = iota + 1
+	// KittyAddFlagsMode sets the given flags and keeps the existing flags
+	// unchanged.
+	KittyAddFlagsMode
+	// KittyRemoveFlagsMode unsets the given flags and keeps the existing
+	// flags unchanged.
+	KittyRemoveFlagsMode
+)
+
 // RequestKittyKeyboard is a sequence to request the terminal Kitty keyboard
 // protocol enabled flags.
 //
+//	CSI ? u
+//
+// The terminal replies with the currently enabled flags:
+//
+//	CSI ? flags u
+//
 // See: https://sw.kovidgoyal.net/kitty/keyboard-protocol/
 const RequestKittyKeyboard = "\x1b[?u"
 
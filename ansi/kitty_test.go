@@ -0,0 +1,29 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestKittyKeyboard(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"KittyKeyboard", ansi.KittyKeyboard(ansi.KittyDisambiguateEscapeCodes|ansi.KittyReportEventTypes, ansi.KittyAddFlagsMode), "\x1b[=3;2u"},
+		{"PushKittyKeyboard(0)", ansi.PushKittyKeyboard(0), "\x1b[>u"},
+		{"PushKittyKeyboard(all)", ansi.PushKittyKeyboard(ansi.KittyAllFlags), "\x1b[>31u"},
+		{"PopKittyKeyboard(0)", ansi.PopKittyKeyboard(0), "\x1b[<u"},
+		{"PopKittyKeyboard(2)", ansi.PopKittyKeyboard(2), "\x1b[<2u"},
+		{"RequestKittyKeyboard", ansi.RequestKittyKeyboard, "\x1b[?u"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
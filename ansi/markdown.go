@@ -0,0 +1,271 @@
+package ansi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mdAttrs are the attributes of text that have a Markdown emphasis.
+type mdAttrs uint8
+
+const (
+	mdBold mdAttrs = 1 << iota
+	mdItalic
+	mdStrike
+)
+
+// mdEmphasis are the attributes and their Markdown delimiters. The alt
+// delimiter is used right after a closing "*" delimiter, which would
+// otherwise run into it.
+var mdEmphasis = [...]struct {
+	attr       mdAttrs
+	delim, alt string
+}{
+	{mdBold, "**", "__"},
+	{mdItalic, "*", "_"},
+	{mdStrike, "~~", "~~"},
+}
+
+// ToMarkdown converts styled text to Markdown, to paste terminal output in
+// issue trackers and chat. Bold, italic, and struck through text is
+// emphasized, like **bold**, *italic*, and ~~strike~~, and hyperlinks (OSC 8)
+// become links, like [text](url). Colors, other attributes, and other escape
+// sequences are dropped, and so are control characters other than newlines
+// and tabs. Characters that Markdown would take for emphasis or links are
+// escaped.
+//
+//	ansi.ToMarkdown("\x1b[1mWarning:\x1b[m see \x1b]8;;https://charm.sh\x07charm.sh\x1b]8;;\x07")
+//	// "**Warning:** see [charm.sh](https://charm.sh)"
+//
+// Spaces around emphasized text are left out of the emphasis, since Markdown
+// doesn't emphasize text that starts or ends with a space. Emphases that
+// overlap, like bold text that turns italic and then stops being bold, are
+// closed innermost first and reopened.
+func ToMarkdown(s string) string {
+	type cell struct {
+		text  string
+		attrs mdAttrs
+		link  string
+	}
+
+	var (
+		cells []cell
+		attrs mdAttrs
+		link  string
+		sc    = NewScanner(s)
+	)
+	for sc.Scan() {
+		tok := sc.Token()
+		switch sc.Kind() {
+		case SequenceToken:
+			if url, ok := hyperlinkURL(tok); ok {
+				link = url
+			} else if isSgr(tok) {
+				intro := 1
+				if tok[0] == ESC {
+					intro = 2
+				}
+				attrs = mdApplySgr(attrs, tok[intro:len(tok)-1])
+			}
+		case ControlToken:
+			switch tok {
+			case "\n":
+				// Emphasis and links don't span lines.
+				cells = append(cells, cell{text: tok})
+			case "\t":
+				cells = append(cells, cell{text: tok, attrs: attrs, link: link})
+			}
+		default:
+			cells = append(cells, cell{text: tok, attrs: attrs, link: link})
+		}
+	}
+
+	// Spaces take the emphasis and link of the text around them, when they
+	// have the same.
+	isSpace := func(c cell) bool {
+		return c.text == " " || c.text == "\t"
+	}
+	for i := 0; i < len(cells); {
+		if !isSpace(cells[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(cells) && isSpace(cells[j]) {
+			j++
+		}
+		var before, after cell
+		if i > 0 {
+			before = cells[i-1]
+		}
+		if j < len(cells) {
+			after = cells[j]
+		}
+		for k := i; k < j; k++ {
+			cells[k].attrs = before.attrs & after.attrs
+			cells[k].link = ""
+			if before.link == after.link {
+				cells[k].link = before.link
+			}
+		}
+		i = j
+	}
+
+	type emphasis struct {
+		attr  mdAttrs
+		delim string
+	}
+
+	var (
+		buf   strings.Builder
+		open  []emphasis // open are the opened emphases, innermost last.
+		cur   string     // cur is the URL of the open link.
+		delim bool       // delim reports whether buf ends with a "*" delimiter.
+	)
+	closeTo := func(n int) {
+		for len(open) > n {
+			e := open[len(open)-1]
+			buf.WriteString(e.delim)
+			delim = e.delim[0] == '*'
+			open = open[:len(open)-1]
+		}
+	}
+	isOpen := func(attr mdAttrs) bool {
+		for _, e := range open {
+			if e.attr == attr {
+				return true
+			}
+		}
+		return false
+	}
+	// runEnd returns the index of the first cell from i on that doesn't
+	// have the attribute attr, or the link of cell i.
+	runEnd := func(i int, attr mdAttrs) int {
+		j := i
+		for j < len(cells) && cells[j].attrs&attr != 0 && cells[j].link == cells[i].link {
+			j++
+		}
+		return j
+	}
+
+	cells = append(cells, cell{})
+	for i, c := range cells {
+		if c.link != cur {
+			closeTo(0)
+			if cur != "" {
+				buf.WriteString("](" + mdEscapeURL(cur) + ")")
+			}
+			if c.link != "" {
+				buf.WriteByte('[')
+			}
+			cur = c.link
+			delim = false
+		}
+
+		// Keep the emphases that are still on, up to the first one that's
+		// off.
+		n := 0
+		for n < len(open) && c.attrs&open[n].attr != 0 {
+			n++
+		}
+		closeTo(n)
+
+		// Open the missing emphases on the text after the spaces, the ones
+		// that last longer first, so that they're closed last.
+		if !isSpace(c) {
+			var missing []mdAttrs
+			for _, e := range mdEmphasis {
+				if c.attrs&e.attr != 0 && !isOpen(e.attr) {
+					missing = append(missing, e.attr)
+				}
+			}
+			sort.SliceStable(missing, func(a, b int) bool {
+				return runEnd(i, missing[a]) > runEnd(i, missing[b])
+			})
+			for _, attr := range missing {
+				for _, e := range mdEmphasis {
+					if e.attr != attr {
+						continue
+					}
+					d := e.delim
+					if delim {
+						d = e.alt
+					}
+					buf.WriteString(d)
+					open = append(open, emphasis{attr, d})
+					delim = false
+				}
+			}
+		}
+
+		if c.text != "" {
+			buf.WriteString(mdEscape(c.text))
+			delim = false
+		}
+	}
+
+	return buf.String()
+}
+
+// mdApplySgr returns the attributes after the SGR parameters params.
+func mdApplySgr(attrs mdAttrs, params string) mdAttrs {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if _, size, ok := readSgrColor(fields[i:]); ok {
+			// Skip the color, its parameters aren't attributes.
+			i += size - 1
+			continue
+		}
+		if j := strings.IndexByte(f, ':'); j >= 0 {
+			f = f[:j]
+		}
+		switch n, _ := strconv.Atoi(f); n {
+		case 0:
+			attrs = 0
+		case 1:
+			attrs |= mdBold
+		case 22:
+			attrs &^= mdBold
+		case 3:
+			attrs |= mdItalic
+		case 23:
+			attrs &^= mdItalic
+		case 9:
+			attrs |= mdStrike
+		case 29:
+			attrs &^= mdStrike
+		}
+	}
+	return attrs
+}
+
+// mdEscaper escapes the characters Markdown would take for emphasis, code,
+// or links.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// mdEscape escapes the text s for Markdown.
+func mdEscape(s string) string {
+	if !strings.ContainsAny(s, "\\*_~`[]") {
+		return s
+	}
+	return mdEscaper.Replace(s)
+}
+
+// mdURLEscaper escapes the characters that would end the URL of a Markdown
+// link.
+var mdURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+// mdEscapeURL escapes the URL of a Markdown link.
+func mdEscapeURL(url string) string {
+	return mdURLEscaper.Replace(url)
+}
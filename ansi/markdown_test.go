@@ -0,0 +1,42 @@
+package ansi
+
+import "testing"
+
+func TestToMarkdown(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"plain", "hello world", "hello world"},
+		{"bold", "\x1b[1mhello\x1b[m world", "**hello** world"},
+		{"italic", "\x1b[3mhello\x1b[23m world", "*hello* world"},
+		{"strike", "\x1b[9mold\x1b[29m new", "~~old~~ new"},
+		{"nested", "\x1b[1mbold \x1b[3mboth\x1b[23m bold\x1b[m", "**bold *both* bold**"},
+		{"overlapping", "\x1b[1ma\x1b[3mb\x1b[22mc\x1b[m", "**a*b***_c_"},
+		{"overlapping italic", "\x1b[3ma\x1b[1mb\x1b[23mc\x1b[m", "*a**b***__c__"},
+		{"overlapping space", "\x1b[1mbo\x1b[3mth\x1b[22m it\x1b[m", "**bo*th*** *it*"},
+		{"overlapping strike", "\x1b[1ma\x1b[9mb\x1b[22mc\x1b[m", "**a~~b~~**~~c~~"},
+		{"partial reset bold", "\x1b[1;3mab\x1b[22mc\x1b[m", "***ab**c*"},
+		{"partial reset italic", "\x1b[1;3mab\x1b[23mc\x1b[m", "***ab*c**"},
+		{"partial reset space", "\x1b[1;3mab \x1b[22mc\x1b[m", "***ab** c*"},
+		{"reopened after space", "\x1b[1;3ma\x1b[23mx \x1b[3mb\x1b[m", "***a*x *b***"},
+		{"longest outermost", "\x1b[9;1ma\x1b[22mb\x1b[m", "~~**a**b~~"},
+		{"spaces", "\x1b[1m hello \x1b[m world", " **hello**  world"},
+		{"colors", "\x1b[38;5;1;48;2;3;9;1mred\x1b[m", "red"},
+		{"colon colors", "\x1b[38:2::1:3:9;1mred\x1b[m", "**red**"},
+		{"link", "see \x1b]8;;https://charm.sh\x07charm\x1b]8;;\x07!", "see [charm](https://charm.sh)!"},
+		{"styled link", "\x1b]8;;https://charm.sh\x1b\\\x1b[1mcharm\x1b[m \x1b[1mbracelet\x1b[m\x1b]8;;\x1b\\", "[**charm bracelet**](https://charm.sh)"},
+		{"link escape", "\x1b]8;;https://a.b/(c d)\x07x\x1b]8;;\x07", "[x](https://a.b/%28c%20d%29)"},
+		{"escape", "2*3 [x] `y` ~z a_b \\", "2\\*3 \\[x\\] \\`y\\` \\~z a\\_b \\\\"},
+		{"lines", "\x1b[1mone\ntwo\x1b[m\r\n", "**one**\n**two**\n"},
+		{"controls", "a\tb\x07c", "a\tbc"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ToMarkdown(c.input); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
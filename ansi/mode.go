@@ -51,6 +51,19 @@ const (
 	RequestCursorKeys = "\x1b[?1$p"
 )
 
+// Numeric Keypad Mode (DECNKM) is a mode that determines whether the keypad
+// sends application sequences or the characters printed on the keys. It's
+// the mode form of [KeypadApplicationMode] and [KeypadNumericMode].
+//
+// See: https://vt100.net/docs/vt510-rm/DECNKM.html
+const (
+	NumericKeypadMode = PrivateMode(66)
+
+	EnableNumericKeypad  = "\x1b[?66h"
+	DisableNumericKeypad = "\x1b[?66l"
+	RequestNumericKeypad = "\x1b[?66$p"
+)
+
 // Text Cursor Enable Mode (DECTCEM) is a mode that shows/hides the cursor.
 //
 // See: https://vt100.net/docs/vt510-rm/DECTCEM.html
@@ -62,6 +75,18 @@ const (
 	RequestCursorVisibility = "\x1b[?25$p"
 )
 
+// Left Right Margin Mode (DECLRMM) is a mode that determines whether left and
+// right margins can be set with [SetLeftRightMargins] (DECSLRM).
+//
+// See: https://vt100.net/docs/vt510-rm/DECLRMM.html
+const (
+	LeftRightMarginMode = PrivateMode(69)
+
+	EnableLeftRightMargin  = "\x1b[?69h"
+	DisableLeftRightMargin = "\x1b[?69l"
+	RequestLeftRightMargin = "\x1b[?69$p"
+)
+
 // VT Mouse Tracking is a mode that determines whether the mouse reports on
 // button press and release.
 //
@@ -133,8 +158,90 @@ const (
 	RequestMouseSgrExt = "\x1b[?1006$p"
 )
 
+// UTF-8 Extended Mouse is a mode that determines whether the mouse reports
+// encode the X10 coordinates as UTF-8 characters. This raises the coordinate
+// limit from 223 to 2015. Prefer [MouseSgrExtMode] when it's supported.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+const (
+	MouseUtf8ExtMode = PrivateMode(1005)
+
+	EnableMouseUtf8Ext  = "\x1b[?1005h"
+	DisableMouseUtf8Ext = "\x1b[?1005l"
+	RequestMouseUtf8Ext = "\x1b[?1005$p"
+)
+
+// URXVT Extended Mouse is a mode that determines whether the mouse reports
+// events formatted with decimal parameters. This is supported by
+// rxvt-unicode and other terminals that don't support [MouseSgrExtMode].
+//
+//	CSI Cb ; Cx ; Cy M
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+const (
+	MouseUrxvtExtMode = PrivateMode(1015)
+
+	EnableMouseUrxvtExt  = "\x1b[?1015h"
+	DisableMouseUrxvtExt = "\x1b[?1015l"
+	RequestMouseUrxvtExt = "\x1b[?1015$p"
+)
+
+// Legacy Alternate Screen is the original xterm mode that switches between the
+// normal and alternate screen buffers. It neither saves the cursor nor clears
+// the alternate screen. Use it only when targeting terminals that don't
+// support [AltScreenBufferMode].
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-The-Alternate-Screen-Buffer
+const (
+	LegacyAltScreenMode = PrivateMode(47)
+
+	EnableLegacyAltScreen  = "\x1b[?47h"
+	DisableLegacyAltScreen = "\x1b[?47l"
+	RequestLegacyAltScreen = "\x1b[?47$p"
+)
+
+// Alternate Screen is a mode that switches to the alternate screen buffer. The
+// alternate screen is cleared when switching back to the normal screen.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-The-Alternate-Screen-Buffer
+const (
+	AltScreenMode = PrivateMode(1047)
+
+	EnableAltScreen  = "\x1b[?1047h"
+	DisableAltScreen = "\x1b[?1047l"
+	RequestAltScreen = "\x1b[?1047$p"
+)
+
+// Save Cursor is a mode that saves the cursor position when set and restores
+// it when reset. This is the same as [SaveCursor] (DECSC) and [RestoreCursor]
+// (DECRC).
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-The-Alternate-Screen-Buffer
+const (
+	SaveCursorMode = PrivateMode(1048)
+
+	EnableSaveCursor  = "\x1b[?1048h"
+	DisableSaveCursor = "\x1b[?1048l"
+	RequestSaveCursor = "\x1b[?1048$p"
+)
+
+// SGR Pixel Mouse Extension is a mode that determines whether the mouse reports
+// events formatted with SGR parameters using pixel coordinates instead of
+// cell coordinates.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+const (
+	MouseSgrPixelsExtMode = PrivateMode(1016)
+
+	EnableMouseSgrPixelsExt  = "\x1b[?1016h"
+	DisableMouseSgrPixelsExt = "\x1b[?1016l"
+	RequestMouseSgrPixelsExt = "\x1b[?1016$p"
+)
+
 // Alternate Screen Buffer is a mode that determines whether the alternate screen
-// buffer is active.
+// buffer is active. This combines [SaveCursorMode] and [AltScreenMode] by
+// saving the cursor position and clearing the alternate screen when entering
+// it, and restoring the cursor position when leaving it.
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-The-Alternate-Screen-Buffer
 const (
@@ -184,6 +291,21 @@ const (
 	RequestGraphemeClustering = "\x1b[?2027$p"
 )
 
+// In-Band Resize Mode is a mode that makes the terminal report window resizes
+// in-band as a sequence. The terminal sends a report right away when the mode
+// is enabled, and then every time the window size changes.
+//
+//	CSI 48 ; height ; width ; height_pixels ; width_pixels t
+//
+// See: https://gist.github.com/rockorager/e695fb2924d36b2bcf1fff4a3704bd83
+const (
+	InBandResizeMode = PrivateMode(2048)
+
+	EnableInBandResize  = "\x1b[?2048h"
+	DisableInBandResize = "\x1b[?2048l"
+	RequestInBandResize = "\x1b[?2048$p"
+)
+
 // Win32Input is a mode that determines whether input is processed by the
 // Win32 console and Conpty.
 //
@@ -0,0 +1,122 @@
+package ansi
+
+// MouseTracking represents the kind of mouse events a terminal reports.
+type MouseTracking uint8
+
+// Mouse tracking kinds.
+const (
+	// NoMouseTracking disables mouse tracking.
+	NoMouseTracking MouseTracking = iota
+
+	// NormalMouseTracking reports button press and release events. This uses
+	// [MouseMode].
+	NormalMouseTracking
+
+	// ButtonMouseTracking reports button press, release, and motion events
+	// while a button is held down. This uses [MouseCellMotionMode].
+	ButtonMouseTracking
+
+	// AnyMouseTracking reports button press, release, and all motion events
+	// even when no button is held down. This uses [MouseAllMotionMode].
+	AnyMouseTracking
+)
+
+// MouseOptions describes a combination of mouse modes to enable or disable at
+// once.
+type MouseOptions struct {
+	// Tracking is the kind of mouse events to report.
+	Tracking MouseTracking
+
+	// SGR reports mouse events using the SGR extended encoding. This lifts
+	// the 223 cells coordinates limit of the default X10 encoding, and
+	// reports which button was released. This uses [MouseSgrExtMode].
+	SGR bool
+
+	// SGRPixels reports mouse events using the SGR extended encoding with
+	// pixel coordinates instead of cell coordinates. This uses
+	// [MouseSgrPixelsExtMode] and takes precedence over SGR.
+	SGRPixels bool
+}
+
+// mode returns the private mode of the mouse tracking kind.
+func (t MouseTracking) mode() PrivateMode {
+	switch t {
+	case NormalMouseTracking:
+		return MouseMode
+	case ButtonMouseTracking:
+		return MouseCellMotionMode
+	case AnyMouseTracking:
+		return MouseAllMotionMode
+	}
+	return 0
+}
+
+// encoding returns the private mode of the mouse encoding, if any.
+func (o MouseOptions) encoding() PrivateMode {
+	switch {
+	case o.SGRPixels:
+		return MouseSgrPixelsExtMode
+	case o.SGR:
+		return MouseSgrExtMode
+	}
+	return 0
+}
+
+// EnableMouseTracking returns a sequence that enables the mouse modes
+// described by opts in a single write. The encoding modes are set before the
+// tracking mode so that the first reported event already uses the requested
+// encoding.
+//
+// Use [DisableMouseTracking] with the same options to disable them again.
+//
+//	CSI ? Pm h
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+func EnableMouseTracking(opts MouseOptions) string {
+	return modesSequence('h', opts.encoding(), opts.Tracking.mode())
+}
+
+// DisableMouseTracking returns a sequence that disables the mouse modes
+// described by opts and re-enables the ones described by prev, the mouse
+// modes the terminal had before the matching [EnableMouseTracking] call. Use
+// a zero prev when mouse tracking was off. Modes that are the same in both
+// are left untouched.
+//
+// Terminals keep a single tracking kind and encoding, so disabling one can
+// turn mouse tracking off entirely. That's why the modes of prev are set
+// again after the modes of opts are reset.
+//
+//	CSI ? Pm l CSI ? Pm h
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+func DisableMouseTracking(opts, prev MouseOptions) string {
+	var reset, set [2]PrivateMode
+	if e, pe := opts.encoding(), prev.encoding(); e != pe {
+		reset[0], set[0] = e, pe
+	}
+	if t, pt := opts.Tracking.mode(), prev.Tracking.mode(); t != pt {
+		reset[1], set[1] = t, pt
+	}
+	return modesSequence('l', reset[:]...) + modesSequence('h', set[:]...)
+}
+
+// modesSequence returns a single set (h) or reset (l) mode sequence for the
+// given modes. Zero modes are skipped.
+func modesSequence(cmd byte, modes ...PrivateMode) string {
+	var s string
+	for _, m := range modes {
+		if m == 0 {
+			continue
+		}
+		if s == "" {
+			s = "\x1b[?"
+		} else {
+			s += ";"
+		}
+		s += m.String()[1:]
+	}
+	if s == "" {
+		return ""
+	}
+	return s + string(cmd)
+}
@@ -0,0 +1,107 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestMouseTracking(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    ansi.MouseOptions
+		enable  string
+		disable string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:    "normal",
+			opts:    ansi.MouseOptions{Tracking: ansi.NormalMouseTracking},
+			enable:  "\x1b[?1000h",
+			disable: "\x1b[?1000l",
+		},
+		{
+			name:    "button sgr",
+			opts:    ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGR: true},
+			enable:  "\x1b[?1006;1002h",
+			disable: "\x1b[?1006;1002l",
+		},
+		{
+			name:    "any sgr pixels",
+			opts:    ansi.MouseOptions{Tracking: ansi.AnyMouseTracking, SGR: true, SGRPixels: true},
+			enable:  "\x1b[?1016;1003h",
+			disable: "\x1b[?1016;1003l",
+		},
+		{
+			name:    "encoding only",
+			opts:    ansi.MouseOptions{SGR: true},
+			enable:  "\x1b[?1006h",
+			disable: "\x1b[?1006l",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ansi.EnableMouseTracking(c.opts); got != c.enable {
+				t.Errorf("EnableMouseTracking: expected %q, got %q", c.enable, got)
+			}
+			if got := ansi.DisableMouseTracking(c.opts, ansi.MouseOptions{}); got != c.disable {
+				t.Errorf("DisableMouseTracking: expected %q, got %q", c.disable, got)
+			}
+		})
+	}
+}
+
+func TestDisableMouseTrackingRestore(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   ansi.MouseOptions
+		prev   ansi.MouseOptions
+		expect string
+	}{
+		{
+			name:   "same",
+			opts:   ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGR: true},
+			prev:   ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGR: true},
+			expect: "",
+		},
+		{
+			name:   "tracking",
+			opts:   ansi.MouseOptions{Tracking: ansi.AnyMouseTracking, SGR: true},
+			prev:   ansi.MouseOptions{Tracking: ansi.NormalMouseTracking, SGR: true},
+			expect: "\x1b[?1003l\x1b[?1000h",
+		},
+		{
+			name:   "encoding",
+			opts:   ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGRPixels: true},
+			prev:   ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGR: true},
+			expect: "\x1b[?1016l\x1b[?1006h",
+		},
+		{
+			name:   "both",
+			opts:   ansi.MouseOptions{Tracking: ansi.AnyMouseTracking, SGRPixels: true},
+			prev:   ansi.MouseOptions{Tracking: ansi.NormalMouseTracking, SGR: true},
+			expect: "\x1b[?1016;1003l\x1b[?1006;1000h",
+		},
+		{
+			name:   "tracking only before",
+			opts:   ansi.MouseOptions{Tracking: ansi.AnyMouseTracking, SGR: true},
+			prev:   ansi.MouseOptions{Tracking: ansi.NormalMouseTracking},
+			expect: "\x1b[?1006;1003l\x1b[?1000h",
+		},
+		{
+			name:   "encoding only before",
+			opts:   ansi.MouseOptions{Tracking: ansi.ButtonMouseTracking, SGR: true},
+			prev:   ansi.MouseOptions{SGR: true},
+			expect: "\x1b[?1002l",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ansi.DisableMouseTracking(c.opts, c.prev); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
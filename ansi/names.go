@@ -0,0 +1,226 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// NewCmd returns the command of a sequence with the given marker,
+// intermediate, and final bytes. Marker and intermediate are zero when the
+// sequence has none.
+func NewCmd(marker, intermediate, command byte) Cmd {
+	return Cmd(int(marker)<<parser.MarkerShift | int(intermediate)<<parser.IntermedShift | int(command))
+}
+
+// CsiNames are the canonical names of the well-known control sequences, by
+// command. Some sequences are replies terminals send, like DECRPM. Add to it
+// to name other sequences.
+var CsiNames = map[Cmd]string{
+	NewCmd(0, 0, '@'):     "ICH",
+	NewCmd(0, 0, 'A'):     "CUU",
+	NewCmd(0, 0, 'B'):     "CUD",
+	NewCmd(0, 0, 'C'):     "CUF",
+	NewCmd(0, 0, 'D'):     "CUB",
+	NewCmd(0, 0, 'E'):     "CNL",
+	NewCmd(0, 0, 'F'):     "CPL",
+	NewCmd(0, 0, 'G'):     "CHA",
+	NewCmd(0, 0, 'H'):     "CUP",
+	NewCmd(0, 0, 'I'):     "CHT",
+	NewCmd(0, 0, 'J'):     "ED",
+	NewCmd('?', 0, 'J'):   "DECSED",
+	NewCmd(0, 0, 'K'):     "EL",
+	NewCmd('?', 0, 'K'):   "DECSEL",
+	NewCmd(0, 0, 'L'):     "IL",
+	NewCmd(0, 0, 'M'):     "DL",
+	NewCmd(0, 0, 'P'):     "DCH",
+	NewCmd(0, 0, 'R'):     "CPR",
+	NewCmd('?', 0, 'R'):   "DECXCPR",
+	NewCmd(0, 0, 'S'):     "SU",
+	NewCmd(0, 0, 'T'):     "SD",
+	NewCmd(0, 0, 'X'):     "ECH",
+	NewCmd(0, 0, 'Z'):     "CBT",
+	NewCmd(0, 0, 'b'):     "REP",
+	NewCmd(0, 0, 'c'):     "DA1",
+	NewCmd('?', 0, 'c'):   "DA1 reply",
+	NewCmd('>', 0, 'c'):   "DA2",
+	NewCmd('=', 0, 'c'):   "DA3",
+	NewCmd(0, 0, 'd'):     "VPA",
+	NewCmd(0, 0, 'f'):     "HVP",
+	NewCmd(0, 0, 'g'):     "TBC",
+	NewCmd(0, 0, 'h'):     "SM",
+	NewCmd('?', 0, 'h'):   "DECSET",
+	NewCmd(0, 0, 'l'):     "RM",
+	NewCmd('?', 0, 'l'):   "DECRST",
+	NewCmd(0, 0, 'm'):     "SGR",
+	NewCmd('>', 0, 'm'):   "XTMODKEYS",
+	NewCmd(0, 0, 'n'):     "DSR",
+	NewCmd('?', 0, 'n'):   "DECDSR",
+	NewCmd(0, 0, 'r'):     "DECSTBM",
+	NewCmd(0, 0, 's'):     "SCOSC",
+	NewCmd(0, 0, 't'):     "XTWINOPS",
+	NewCmd(0, 0, 'u'):     "SCORC",
+	NewCmd('?', 0, 'u'):   "Kitty keyboard flags",
+	NewCmd('>', 0, 'u'):   "Kitty keyboard push",
+	NewCmd('<', 0, 'u'):   "Kitty keyboard pop",
+	NewCmd('=', 0, 'u'):   "Kitty keyboard set",
+	NewCmd('<', 0, 'M'):   "SGR mouse press",
+	NewCmd('<', 0, 'm'):   "SGR mouse release",
+	NewCmd(0, ' ', 'q'):   "DECSCUSR",
+	NewCmd('>', 0, 'q'):   "XTVERSION",
+	NewCmd(0, '!', 'p'):   "DECSTR",
+	NewCmd(0, '"', 'p'):   "DECSCL",
+	NewCmd(0, '"', 'q'):   "DECSCA",
+	NewCmd(0, '$', 'p'):   "DECRQM",
+	NewCmd('?', '$', 'p'): "DECRQM",
+	NewCmd(0, '$', 'y'):   "DECRPM",
+	NewCmd('?', '$', 'y'): "DECRPM",
+	NewCmd(0, '$', 'v'):   "DECCRA",
+	NewCmd(0, '$', 'x'):   "DECFRA",
+	NewCmd(0, '$', 'z'):   "DECERA",
+	NewCmd(0, '*', 'x'):   "DECSACE",
+	NewCmd('?', 0, 'W'):   "DECST8C",
+}
+
+// DcsNames are the canonical names of the well-known device control
+// strings, by command.
+var DcsNames = map[Cmd]string{
+	NewCmd(0, 0, 'q'):   "sixel",
+	NewCmd(0, '$', 'q'): "DECRQSS",
+	NewCmd(0, '$', 'r'): "DECRQSS reply",
+	NewCmd(0, '+', 'q'): "XTGETTCAP",
+	NewCmd(0, '+', 'r'): "XTGETTCAP reply",
+	NewCmd('>', 0, '|'): "XTVERSION reply",
+	NewCmd(0, '!', '|'): "DA3 reply",
+	NewCmd(0, 0, 't'):   "tmux passthrough",
+}
+
+// EscNames are the canonical names of the well-known escape sequences, by
+// command. Character set designations, with the intermediates '(', ')', '*',
+// and '+', are named SCS.
+var EscNames = map[Cmd]string{
+	NewCmd(0, 0, '7'):   "DECSC",
+	NewCmd(0, 0, '8'):   "DECRC",
+	NewCmd(0, 0, '='):   "DECKPAM",
+	NewCmd(0, 0, '>'):   "DECKPNM",
+	NewCmd(0, 0, 'D'):   "IND",
+	NewCmd(0, 0, 'E'):   "NEL",
+	NewCmd(0, 0, 'H'):   "HTS",
+	NewCmd(0, 0, 'M'):   "RI",
+	NewCmd(0, 0, 'N'):   "SS2",
+	NewCmd(0, 0, 'O'):   "SS3",
+	NewCmd(0, 0, '\\'):  "ST",
+	NewCmd(0, 0, 'c'):   "RIS",
+	NewCmd(0, 0, 'n'):   "LS2",
+	NewCmd(0, 0, 'o'):   "LS3",
+	NewCmd(0, '#', '3'): "DECDHL top",
+	NewCmd(0, '#', '4'): "DECDHL bottom",
+	NewCmd(0, '#', '5'): "DECSWL",
+	NewCmd(0, '#', '6'): "DECDWL",
+	NewCmd(0, '#', '8'): "DECALN",
+	NewCmd(0, ' ', 'F'): "S7C1T",
+	NewCmd(0, ' ', 'G'): "S8C1T",
+}
+
+// OscNames are the names of the well-known operating system commands, by
+// command number.
+var OscNames = map[int]string{
+	0:    "icon name and window title",
+	1:    "icon name",
+	2:    "window title",
+	4:    "palette color",
+	7:    "working directory",
+	8:    "hyperlink",
+	9:    "notification",
+	10:   "foreground color",
+	11:   "background color",
+	12:   "cursor color",
+	52:   "clipboard",
+	104:  "reset palette color",
+	110:  "reset foreground color",
+	111:  "reset background color",
+	112:  "reset cursor color",
+	133:  "semantic prompt",
+	1337: "iTerm2",
+}
+
+// controlNames are the names of the C0 and C1 control characters.
+var controlNames = [...]string{
+	"NUL", "SOH", "STX", "ETX", "EOT", "ENQ", "ACK", "BEL",
+	"BS", "HT", "LF", "VT", "FF", "CR", "SO", "SI",
+	"DLE", "DC1", "DC2", "DC3", "DC4", "NAK", "SYN", "ETB",
+	"CAN", "EM", "SUB", "ESC", "FS", "GS", "RS", "US",
+	"PAD", "HOP", "BPH", "NBH", "IND", "NEL", "SSA", "ESA",
+	"HTS", "HTJ", "VTS", "PLD", "PLU", "RI", "SS2", "SS3",
+	"DCS", "PU1", "PU2", "STS", "CCH", "MW", "SPA", "EPA",
+	"SOS", "SGCI", "SCI", "CSI", "ST", "OSC", "PM", "APC",
+}
+
+// ControlName returns the name of the C0 or C1 control character c, like
+// "CR", or its hexadecimal value when c isn't a control character.
+func ControlName(c byte) string {
+	switch {
+	case c < 0x20:
+		return controlNames[c]
+	case c == DEL:
+		return "DEL"
+	case c >= 0x80 && c < 0xa0:
+		return controlNames[c-0x80+0x20]
+	}
+	return fmt.Sprintf("0x%02x", c)
+}
+
+// SequenceName returns the canonical name of the sequence seq, or an empty
+// string when it's not well-known, see [CsiNames], [DcsNames], [EscNames],
+// and [OscNames]. The names of mode sequences include the modes, like
+// "DECSET 2004".
+//
+//	ansi.SequenceName(ansi.CsiSequence{Cmd: int(ansi.NewCmd(0, 0, 'H'))}) // "CUP"
+func SequenceName(seq Sequence) string {
+	switch seq := seq.(type) {
+	case ControlCode:
+		return ControlName(byte(seq))
+	case EscSequence:
+		switch seq.Intermediate() {
+		case '(', ')', '*', '+':
+			return "SCS"
+		}
+		return EscNames[Cmd(seq)]
+	case CsiSequence:
+		name := CsiNames[Cmd(seq.Cmd)]
+		switch name {
+		case "SM", "RM", "DECSET", "DECRST":
+			if modes := modesNotation(seq.Params); modes != "" {
+				name += " " + modes
+			}
+		case "DECRQM", "DECRPM":
+			if m := seq.Param(0); m >= 0 {
+				name += " " + strconv.Itoa(m)
+			}
+		}
+		return name
+	case DcsSequence:
+		return DcsNames[Cmd(seq.Cmd)]
+	case OscSequence:
+		return OscNames[seq.Cmd]
+	case ApcSequence:
+		if strings.HasPrefix(string(seq.Data), "G") {
+			return "Kitty graphics"
+		}
+	}
+	return ""
+}
+
+// modesNotation returns the modes of a mode sequence, separated by
+// semicolons.
+func modesNotation(params []int) string {
+	modes := make([]string, 0, len(params))
+	for _, p := range params {
+		if p &= parser.ParamMask; p != parser.MissingParam {
+			modes = append(modes, strconv.Itoa(p))
+		}
+	}
+	return strings.Join(modes, ";")
+}
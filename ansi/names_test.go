@@ -0,0 +1,45 @@
+package ansi
+
+import "testing"
+
+func TestSequenceName(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect string
+	}{
+		{"\r", "CR"},
+		{"\x1b7", "DECSC"},
+		{"\x1b(0", "SCS"},
+		{"\x1b[5;10H", "CUP"},
+		{"\x1b[1;31m", "SGR"},
+		{"\x1b[?2004h", "DECSET 2004"},
+		{"\x1b[?1049;2004l", "DECRST 1049;2004"},
+		{"\x1b[4h", "SM 4"},
+		{"\x1b[?2026;1$y", "DECRPM 2026"},
+		{"\x1b[2 q", "DECSCUSR"},
+		{"\x1bP1$r0m\x1b\\", "DECRQSS reply"},
+		{"\x1b]8;;https://charm.sh\x07", "hyperlink"},
+		{"\x1b_Ga=q\x1b\\", "Kitty graphics"},
+		{"\x1b[5z", ""},
+	}
+	for _, c := range cases {
+		var seq Sequence
+		p := NewParser(32, 0)
+		p.Parse(func(s Sequence) {
+			if seq == nil {
+				seq = s.Clone()
+			}
+		}, []byte(c.input))
+		if got := SequenceName(seq); got != c.expect {
+			t.Errorf("%q: expected %q, got %q", c.input, c.expect, got)
+		}
+	}
+}
+
+func TestControlName(t *testing.T) {
+	for c, expect := range map[byte]string{0x00: "NUL", 0x1b: "ESC", 0x7f: "DEL", 0x9b: "CSI", 'a': "0x61"} {
+		if got := ControlName(c); got != expect {
+			t.Errorf("%q: expected %q, got %q", c, expect, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package ansi
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// NormalizeNewlines returns s with its line endings normalized to "\n", like
+// text from Windows sources, with "\r\n" line endings, or read from a PTY,
+// with lone "\r" returning to the start of the line. Carriage returns inside
+// escape sequences are left as they are.
+//
+//	ansi.NormalizeNewlines("foo\r\nbar\rbaz") // "foo\nbar\nbaz"
+func NormalizeNewlines(s string) string {
+	if strings.IndexByte(s, CR) < 0 {
+		return s
+	}
+
+	var (
+		buf   strings.Builder
+		state = parser.GroundState
+	)
+	buf.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == CR && state == parser.GroundState {
+			if i+1 < len(s) && s[i+1] == LF {
+				continue
+			}
+			c = LF
+		}
+		buf.WriteByte(c)
+
+		state, _ = parser.Table.Transition(state, s[i])
+		if state == parser.Utf8State {
+			// The rest of the rune can't be a carriage return.
+			state = parser.GroundState
+		}
+	}
+
+	return buf.String()
+}
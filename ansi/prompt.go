@@ -0,0 +1,36 @@
+package ansi
+
+import "strconv"
+
+// Semantic prompt sequences, also known as shell integration or FinalTerm
+// sequences, mark the parts of the shell prompt and of the commands run,
+// so that terminals can jump between prompts, and select or fold the
+// output of a command.
+//
+//	OSC 133 ; A ST
+//	OSC 133 ; A BEL
+//
+// See: https://gitlab.freedesktop.org/Per_Bothner/specifications/blob/master/proposals/semantic-prompts.md
+const (
+	// PromptStart marks the start of the prompt.
+	PromptStart = "\x1b]133;A\x07"
+
+	// CommandStart marks the end of the prompt, and the start of the
+	// command typed by the user.
+	CommandStart = "\x1b]133;B\x07"
+
+	// CommandExecuted marks the end of the command, and the start of its
+	// output.
+	CommandExecuted = "\x1b]133;C\x07"
+)
+
+// CommandEnd returns a sequence that marks the end of the output of a
+// command, and its exit code.
+//
+//	OSC 133 ; D ; exitCode ST
+//	OSC 133 ; D ; exitCode BEL
+//
+// See: https://gitlab.freedesktop.org/Per_Bothner/specifications/blob/master/proposals/semantic-prompts.md
+func CommandEnd(exitCode int) string {
+	return "\x1b]133;D;" + strconv.Itoa(exitCode) + "\x07"
+}
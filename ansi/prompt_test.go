@@ -0,0 +1,18 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCommandEnd(t *testing.T) {
+	for code, expect := range map[int]string{
+		0:   "\x1b]133;D;0\x07",
+		127: "\x1b]133;D;127\x07",
+	} {
+		if got := ansi.CommandEnd(code); got != expect {
+			t.Errorf("expected: %q, got: %q", expect, got)
+		}
+	}
+}
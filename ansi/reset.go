@@ -0,0 +1,48 @@
+package ansi
+
+// SoftTerminalReset (DECSTR) is a sequence that resets the terminal modes,
+// like the cursor visibility, origin mode, margins, and text style, to
+// their defaults. Unlike [ResetInitialState], it leaves the screen contents
+// and the scrollback as is.
+//
+//	CSI ! p
+//
+// See: https://vt100.net/docs/vt510-rm/DECSTR.html
+const SoftTerminalReset = "\x1b[!p"
+
+// ResetInitialState (RIS) is a sequence that resets the terminal to its
+// initial state, as if it was just turned on. It clears the screen, and on
+// most terminals the scrollback too.
+//
+//	ESC c
+//
+// See: https://vt100.net/docs/vt510-rm/RIS.html
+const ResetInitialState = "\x1bc"
+
+// SaneReset is a sequence that undoes the changes programs usually make to
+// the terminal, so that the shell is usable again. Write it when exiting, or
+// when recovering from a crash, where the program might not know which
+// modes it left enabled. It resets the text style, the hyperlink, and the
+// cursor style, shows the cursor, exits the alternate screen, and disables
+// mouse tracking, focus reporting, bracketed paste, synchronized output,
+// and the Kitty keyboard protocol. The screen contents are left as is.
+//
+// It doesn't restore the terminal input mode, like raw mode, see the term
+// package for that.
+const SaneReset = ResetStyle +
+	"\x1b]8;;\x07" + // ResetHyperlink()
+	ResetCursorStyle +
+	ShowCursor +
+	DisableAltScreenBuffer +
+	DisableMouse +
+	DisableMouseHilite +
+	DisableMouseCellMotion +
+	DisableMouseAllMotion +
+	DisableMouseSgrExt +
+	DisableMouseSgrPixelsExt +
+	DisableMouseUtf8Ext +
+	DisableMouseUrxvtExt +
+	DisableReportFocus +
+	DisableBracketedPaste +
+	DisableSyncdOutput +
+	"\x1b[=0;1u" // KittyKeyboard(0, 1)
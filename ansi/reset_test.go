@@ -0,0 +1,29 @@
+package ansi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSaneReset(t *testing.T) {
+	for _, seq := range []string{
+		ansi.ResetStyle,
+		ansi.ResetHyperlink(),
+		ansi.ShowCursor,
+		ansi.DisableAltScreenBuffer,
+		ansi.DisableMouseAllMotion,
+		ansi.DisableBracketedPaste,
+		ansi.KittyKeyboard(0, 1),
+	} {
+		if !strings.Contains(ansi.SaneReset, seq) {
+			t.Errorf("expected %q in %q", seq, ansi.SaneReset)
+		}
+	}
+
+	// It's made of valid sequences only, and doesn't print anything.
+	if w := ansi.StringWidth(ansi.SaneReset); w != 0 {
+		t.Errorf("expected no visible output, got width %d", w)
+	}
+}
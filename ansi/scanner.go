@@ -0,0 +1,179 @@
+package ansi
+
+import (
+	"github.com/charmbracelet/x/ansi/parser"
+	"github.com/rivo/uniseg"
+)
+
+// TokenKind is the kind of a token read by a [Scanner].
+type TokenKind byte
+
+// Token kinds.
+const (
+	// TextToken is a grapheme cluster, like a printable ASCII character, an
+	// emoji, or a letter followed by its combining marks.
+	TextToken TokenKind = iota
+
+	// ControlToken is a C0 or C1 control character, like a newline, or a
+	// byte that isn't part of valid text.
+	ControlToken
+
+	// SequenceToken is an escape sequence, like a CSI, OSC, or DCS sequence,
+	// including its terminator. An unterminated sequence runs to the end of
+	// the input.
+	SequenceToken
+)
+
+// String implements fmt.Stringer.
+func (k TokenKind) String() string {
+	switch k {
+	case TextToken:
+		return "text"
+	case ControlToken:
+		return "control"
+	case SequenceToken:
+		return "sequence"
+	}
+	return "unknown"
+}
+
+// Scanner splits a string into text, control characters, and escape
+// sequences. Tokens are slices of the input, and reading them doesn't
+// allocate.
+//
+//	var s ansi.Scanner
+//	s.Reset("\x1b[1mhello\x1b[m")
+//	for s.Scan() {
+//		fmt.Println(s.Kind(), s.Width(), strconv.Quote(s.Token()))
+//	}
+//
+// The zero value is ready to use and scans nothing. A Scanner holds no
+// buffers, and [Scanner.Reset] drops every reference to the previous input,
+// so a scanner can be reused, or kept in a [sync.Pool], without holding on
+// to strings it scanned before.
+type Scanner struct {
+	s     string
+	pos   int
+	tok   string
+	kind  TokenKind
+	width int
+}
+
+// NewScanner returns a new scanner reading s.
+func NewScanner(s string) *Scanner {
+	return &Scanner{s: s}
+}
+
+// Reset makes the scanner read s from the start, as if it was returned by
+// [NewScanner].
+func (s *Scanner) Reset(str string) {
+	*s = Scanner{s: str}
+}
+
+// Scan advances the scanner to the next token. It returns false at the end
+// of the input.
+func (s *Scanner) Scan() bool {
+	if s.pos >= len(s.s) {
+		s.tok, s.kind, s.width = "", 0, 0
+		return false
+	}
+
+	i := s.pos
+	n, width := 1, 0
+	state, action := parser.Table.Transition(parser.GroundState, s.s[i])
+	switch {
+	case state == parser.Utf8State || action == parser.PrintAction:
+		s.kind = TextToken
+		n, width = scanText(s.s[i:])
+	case state == parser.GroundState:
+		s.kind = ControlToken
+	default:
+		s.kind = SequenceToken
+		n = scanSequence(s.s[i:], state)
+	}
+
+	s.pos += n
+	s.tok = s.s[i:s.pos]
+	s.width = width
+	return true
+}
+
+// Token returns the last token read by [Scanner.Scan].
+func (s *Scanner) Token() string {
+	return s.tok
+}
+
+// Kind returns the kind of the last token read by [Scanner.Scan].
+func (s *Scanner) Kind() TokenKind {
+	return s.kind
+}
+
+// Width returns the width in cells of the last token read by [Scanner.Scan].
+// Control characters and escape sequences have no width. Combining marks
+// count toward the width of the cluster they're part of, and a nonspacing
+// mark that starts a token, like a mark after an escape sequence, has no
+// width.
+func (s *Scanner) Width() int {
+	return s.width
+}
+
+// scanText returns the length and width of the grapheme cluster at the
+// start of s.
+func scanText(s string) (n int, width int) {
+	if s[0] < 0x80 && (len(s) == 1 || s[1] < 0x80) {
+		// Printable ASCII not followed by a combining mark, or anything else
+		// that could be part of its cluster.
+		return 1, 1
+	}
+	cluster, _, width, _ := uniseg.FirstGraphemeClusterInString(s, -1)
+	return len(cluster), width
+}
+
+// scanSequence returns the length of the escape sequence at the start of s.
+// state is the parser state after the first byte of the sequence.
+func scanSequence(s string, state parser.State) int {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == ESC {
+			if isStringState(state) && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2 // ST
+			}
+			// The sequence is cut short by another one.
+			return i
+		}
+		if isStringState(state) {
+			// Strings end with ST, or BEL, and can hold any other byte,
+			// including UTF-8. The 8-bit ST only ends strings introduced
+			// with an 8-bit control, since it's also a UTF-8 continuation
+			// byte.
+			switch {
+			case c == BEL, c == CAN, c == SUB:
+				return i + 1
+			case c == ST && s[0] != ESC:
+				return i + 1
+			}
+			continue
+		}
+
+		state, _ = parser.Table.Transition(state, c)
+		switch state {
+		case parser.GroundState:
+			return i + 1
+		case parser.Utf8State:
+			// Text can't be part of a sequence.
+			return i
+		}
+	}
+	return len(s)
+}
+
+// isStringState reports whether state is the data string of a DCS, OSC,
+// SOS, PM, or APC sequence.
+func isStringState(state parser.State) bool {
+	switch state {
+	case parser.DcsStringState, parser.OscStringState, parser.SosStringState,
+		parser.PmStringState, parser.ApcStringState:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package ansi_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type scannedToken struct {
+	Kind  ansi.TokenKind
+	Token string
+	Width int
+}
+
+func scanAll(s *ansi.Scanner) []scannedToken {
+	var toks []scannedToken
+	for s.Scan() {
+		toks = append(toks, scannedToken{s.Kind(), s.Token(), s.Width()})
+	}
+	return toks
+}
+
+func TestScanner(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []scannedToken
+	}{
+		{"empty", "", nil},
+		{"ascii", "hi", []scannedToken{
+			{ansi.TextToken, "h", 1},
+			{ansi.TextToken, "i", 1},
+		}},
+		{"wide", "你a", []scannedToken{
+			{ansi.TextToken, "你", 2},
+			{ansi.TextToken, "a", 1},
+		}},
+		{"emoji", "👋🏽!", []scannedToken{
+			{ansi.TextToken, "👋🏽", 2},
+			{ansi.TextToken, "!", 1},
+		}},
+		{"combining", "éx", []scannedToken{
+			{ansi.TextToken, "é", 1},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"vietnamese", "Vie\u0302\u0323t", []scannedToken{
+			{ansi.TextToken, "V", 1},
+			{ansi.TextToken, "i", 1},
+			{ansi.TextToken, "e\u0302\u0323", 1},
+			{ansi.TextToken, "t", 1},
+		}},
+		{"devanagari", "नमस्ते", []scannedToken{
+			{ansi.TextToken, "न", 1},
+			{ansi.TextToken, "म", 1},
+			{ansi.TextToken, "स\u094d", 1},
+			{ansi.TextToken, "त\u0947", 1},
+		}},
+		{"hangul jamo", "\u1100\u1161\u11a8x", []scannedToken{
+			{ansi.TextToken, "\u1100\u1161\u11a8", 2},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"lone mark", "\u0301a", []scannedToken{
+			{ansi.TextToken, "\u0301", 0},
+			{ansi.TextToken, "a", 1},
+		}},
+		{"mark after sequence", "e\x1b[1m\u0301", []scannedToken{
+			{ansi.TextToken, "e", 1},
+			{ansi.SequenceToken, "\x1b[1m", 0},
+			{ansi.TextToken, "\u0301", 0},
+		}},
+		{"controls", "a\r\nb\t", []scannedToken{
+			{ansi.TextToken, "a", 1},
+			{ansi.ControlToken, "\r", 0},
+			{ansi.ControlToken, "\n", 0},
+			{ansi.TextToken, "b", 1},
+			{ansi.ControlToken, "\t", 0},
+		}},
+		{"csi", "\x1b[1;31mx\x1b[m", []scannedToken{
+			{ansi.SequenceToken, "\x1b[1;31m", 0},
+			{ansi.TextToken, "x", 1},
+			{ansi.SequenceToken, "\x1b[m", 0},
+		}},
+		{"8-bit csi", "\x9b2Jx", []scannedToken{
+			{ansi.SequenceToken, "\x9b2J", 0},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"esc", "\x1b7\x1b(Bx", []scannedToken{
+			{ansi.SequenceToken, "\x1b7", 0},
+			{ansi.SequenceToken, "\x1b(B", 0},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"osc st", "\x1b]8;;https://charm.sh\x1b\\link\x1b]8;;\x1b\\", []scannedToken{
+			{ansi.SequenceToken, "\x1b]8;;https://charm.sh\x1b\\", 0},
+			{ansi.TextToken, "l", 1},
+			{ansi.TextToken, "i", 1},
+			{ansi.TextToken, "n", 1},
+			{ansi.TextToken, "k", 1},
+			{ansi.SequenceToken, "\x1b]8;;\x1b\\", 0},
+		}},
+		{"osc bel utf8", "\x1b]2;✓ done\ax", []scannedToken{
+			{ansi.SequenceToken, "\x1b]2;✓ done\a", 0},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"8-bit osc", "\x9d2;title\x9cx", []scannedToken{
+			{ansi.SequenceToken, "\x9d2;title\x9c", 0},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"dcs", "\x1bP>|xterm\x1b\\", []scannedToken{
+			{ansi.SequenceToken, "\x1bP>|xterm\x1b\\", 0},
+		}},
+		{"apc utf8", "\x1b_Gé\x1b\\", []scannedToken{
+			{ansi.SequenceToken, "\x1b_Gé\x1b\\", 0},
+		}},
+		{"interrupted", "\x1b]2;title\x1b[mx", []scannedToken{
+			{ansi.SequenceToken, "\x1b]2;title", 0},
+			{ansi.SequenceToken, "\x1b[m", 0},
+			{ansi.TextToken, "x", 1},
+		}},
+		{"unterminated", "x\x1b[31", []scannedToken{
+			{ansi.TextToken, "x", 1},
+			{ansi.SequenceToken, "\x1b[31", 0},
+		}},
+		{"lone esc", "\x1b\x1b[A", []scannedToken{
+			{ansi.SequenceToken, "\x1b", 0},
+			{ansi.SequenceToken, "\x1b[A", 0},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := scanAll(ansi.NewScanner(c.input))
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestScannerReset(t *testing.T) {
+	var s ansi.Scanner
+	if s.Scan() {
+		t.Fatal("expected the zero scanner to scan nothing")
+	}
+
+	s.Reset("ab")
+	s.Scan()
+	s.Reset("\x1b[mc")
+	want := []scannedToken{
+		{ansi.SequenceToken, "\x1b[m", 0},
+		{ansi.TextToken, "c", 1},
+	}
+	if got := scanAll(&s); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if s.Token() != "" || s.Width() != 0 {
+		t.Errorf("expected no token at the end, got %q", s.Token())
+	}
+}
+
+func TestScannerNoAlloc(t *testing.T) {
+	var s ansi.Scanner
+	in := "\x1b[1mhello, 世界 👋\x1b[m\n"
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Reset(in)
+		for s.Scan() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+var scannerPool = sync.Pool{
+	New: func() interface{} { return new(ansi.Scanner) },
+}
+
+func BenchmarkScannerPool(b *testing.B) {
+	in := "\x1b[1mhello, 世界 👋\x1b[m\n"
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s := scannerPool.Get().(*ansi.Scanner)
+			s.Reset(in)
+			for s.Scan() {
+			}
+			s.Reset("")
+			scannerPool.Put(s)
+		}
+	})
+}
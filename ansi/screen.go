@@ -15,7 +15,7 @@ import "strconv"
 //
 // See: https://vt100.net/docs/vt510-rm/ED.html
 func EraseDisplay(n int) string {
-	if n < 0 {
+	if n < 0 || n > 3 {
 		n = 0
 	}
 	return "\x1b[" + strconv.Itoa(n) + "J"
@@ -42,7 +42,7 @@ const (
 //
 // See: https://vt100.net/docs/vt510-rm/EL.html
 func EraseLine(n int) string {
-	if n < 0 {
+	if n < 0 || n > 2 {
 		n = 0
 	}
 	return "\x1b[" + strconv.Itoa(n) + "K"
@@ -117,13 +117,231 @@ func DeleteLine(n int) string {
 //
 //	CSI <top> ; <bottom> r
 //
+// A non-positive value for either margin leaves that margin at its default,
+// the first and last lines of the screen respectively. Margins where the
+// bottom is not greater than the top are ignored by terminals, in which case
+// this returns [ResetScrollingRegion].
+//
 // See: https://vt100.net/docs/vt510-rm/DECSTBM.html
 func SetScrollingRegion(t, b int) string {
-	if t < 0 {
-		t = 0
+	return setMargins(t, b, 'r', ResetScrollingRegion)
+}
+
+// ResetScrollingRegion is a sequence that resets the scrolling region to the
+// entire screen.
+//
+// This is equivalent to SetScrollingRegion(0, 0).
+const ResetScrollingRegion = "\x1b[r"
+
+// SetLeftRightMargins (DECSLRM) sets the left and right margins for the
+// scrolling region. The default is the entire width of the screen.
+//
+//	CSI <left> ; <right> s
+//
+// A non-positive value for either margin leaves that margin at its default,
+// the first and last columns of the screen respectively. Margins where the
+// right is not greater than the left are ignored by terminals, in which case
+// this returns [ResetLeftRightMargins].
+//
+// Note: this only takes effect when [LeftRightMarginMode] (DECLRMM) is
+// enabled. Otherwise, terminals interpret the sequence as
+// [SaveCursorPosition].
+//
+// See: https://vt100.net/docs/vt510-rm/DECSLRM.html
+func SetLeftRightMargins(l, r int) string {
+	return setMargins(l, r, 's', ResetLeftRightMargins)
+}
+
+// ResetLeftRightMargins is a sequence that resets the left and right margins
+// to the entire width of the screen.
+//
+// This is equivalent to SetLeftRightMargins(0, 0).
+const ResetLeftRightMargins = "\x1b[s"
+
+// setMargins returns a margins sequence with the given final byte. Invalid
+// margins collapse to the reset sequence.
+func setMargins(start, end int, cmd byte, reset string) string {
+	var s, e string
+	if start > 0 {
+		s = strconv.Itoa(start)
+	}
+	if end > 0 {
+		if end <= start {
+			return reset
+		}
+		e = strconv.Itoa(end)
+	}
+	if s == "" && e == "" {
+		return reset
+	}
+	return "\x1b[" + s + ";" + e + string(cmd)
+}
+
+// InsertCharacter (ICH) inserts n blank characters at the current cursor
+// position. Existing characters move to the right.
+//
+//	CSI <n> @
+//
+// See: https://vt100.net/docs/vt510-rm/ICH.html
+func InsertCharacter(n int) string {
+	var s string
+	if n > 1 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "@"
+}
+
+// DeleteCharacter (DCH) deletes n characters at the current cursor position.
+// Existing characters move to the left.
+//
+//	CSI <n> P
+//
+// See: https://vt100.net/docs/vt510-rm/DCH.html
+func DeleteCharacter(n int) string {
+	var s string
+	if n > 1 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "P"
+}
+
+// EraseCharacter (ECH) erases n characters starting at the current cursor
+// position without moving the rest of the line.
+//
+//	CSI <n> X
+//
+// See: https://vt100.net/docs/vt510-rm/ECH.html
+func EraseCharacter(n int) string {
+	var s string
+	if n > 1 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "X"
+}
+
+// EnterAltScreen returns a sequence that saves the cursor position and
+// switches to the alternate screen buffer using [AltScreenBufferMode]. When
+// clear is true, the alternate screen is also erased and the cursor is moved
+// to the upper left corner. While most terminals clear the alternate screen
+// on their own, some don't.
+//
+// Use [ExitAltScreen] to switch back to the normal screen buffer.
+func EnterAltScreen(clear bool) string {
+	if clear {
+		return EnableAltScreenBuffer + EraseEntireScreen + CursorOrigin
+	}
+	return EnableAltScreenBuffer
+}
+
+// ExitAltScreen returns a sequence that switches back to the normal screen
+// buffer and restores the cursor position saved by [EnterAltScreen].
+func ExitAltScreen() string {
+	return DisableAltScreenBuffer
+}
+
+// EnterLegacyAltScreen returns a sequence that saves the cursor position
+// with [SaveCursor] (DECSC) and switches to the alternate screen buffer using
+// [LegacyAltScreenMode]. When clear is true, the alternate screen is also
+// erased and the cursor is moved to the upper left corner.
+//
+// This is meant for terminals that don't support [AltScreenBufferMode]. Use
+// [ExitLegacyAltScreen] to switch back to the normal screen buffer.
+func EnterLegacyAltScreen(clear bool) string {
+	s := SaveCursor + EnableLegacyAltScreen
+	if clear {
+		s += EraseEntireScreen + CursorOrigin
+	}
+	return s
+}
+
+// ExitLegacyAltScreen returns a sequence that switches back to the normal
+// screen buffer using [LegacyAltScreenMode] and restores the cursor position
+// saved by [EnterLegacyAltScreen] with [RestoreCursor] (DECRC).
+func ExitLegacyAltScreen() string {
+	return DisableLegacyAltScreen + RestoreCursor
+}
+
+// Rectangular area operations work on the area of the screen from the top
+// left to the bottom right corner, inclusive. Coordinates start at 1, and
+// are relative to the scrolling region in origin mode (DECOM). They're
+// supported by VT400 and later terminals, like xterm.
+
+// validArea reports whether the area from the top left to the bottom right
+// corner is valid.
+func validArea(top, left, bottom, right int) bool {
+	return top > 0 && left > 0 && bottom >= top && right >= left
+}
+
+// areaParams returns the parameters of a rectangular area.
+func areaParams(top, left, bottom, right int) string {
+	return strconv.Itoa(top) + ";" + strconv.Itoa(left) + ";" +
+		strconv.Itoa(bottom) + ";" + strconv.Itoa(right)
+}
+
+// CopyRectangularArea (DECCRA) returns a sequence that copies the area of
+// the screen from the top left to the bottom right corner, text and
+// attributes, to the destination top left corner, on the same page. The
+// copy is clipped to the screen. It returns an empty string when the area
+// or the destination is invalid.
+//
+//	CSI Pts ; Pls ; Pbs ; Prs ; Pps ; Ptd ; Pld ; Ppd $ v
+//
+// See: https://vt100.net/docs/vt510-rm/DECCRA.html
+func CopyRectangularArea(top, left, bottom, right, dstTop, dstLeft int) string {
+	if !validArea(top, left, bottom, right) || dstTop <= 0 || dstLeft <= 0 {
+		return ""
 	}
-	if b < 0 {
-		b = 0
+	return "\x1b[" + areaParams(top, left, bottom, right) + ";1;" +
+		strconv.Itoa(dstTop) + ";" + strconv.Itoa(dstLeft) + ";1$v"
+}
+
+// FillRectangularArea (DECFRA) returns a sequence that fills the area of the
+// screen from the top left to the bottom right corner with the character c,
+// using the current text attributes. The character must be printable, in
+// the ranges 32–126 or 160–255. It returns an empty string when the area or
+// the character is invalid.
+//
+//	CSI Pch ; Pt ; Pl ; Pb ; Pr $ x
+//
+// See: https://vt100.net/docs/vt510-rm/DECFRA.html
+func FillRectangularArea(c rune, top, left, bottom, right int) string {
+	if !validArea(top, left, bottom, right) ||
+		!(c >= 32 && c <= 126 || c >= 160 && c <= 255) {
+		return ""
 	}
-	return "\x1b[" + strconv.Itoa(t) + ";" + strconv.Itoa(b) + "r"
+	return "\x1b[" + strconv.Itoa(int(c)) + ";" + areaParams(top, left, bottom, right) + "$x"
 }
+
+// EraseRectangularArea (DECERA) returns a sequence that erases the area of
+// the screen from the top left to the bottom right corner, filling it with
+// blanks. It returns an empty string when the area is invalid.
+//
+//	CSI Pt ; Pl ; Pb ; Pr $ z
+//
+// See: https://vt100.net/docs/vt510-rm/DECERA.html
+func EraseRectangularArea(top, left, bottom, right int) string {
+	if !validArea(top, left, bottom, right) {
+		return ""
+	}
+	return "\x1b[" + areaParams(top, left, bottom, right) + "$z"
+}
+
+// SelectRectangularExtent (DECSACE) is a sequence that makes the attribute
+// change operations, DECCARA and DECRARA, apply to the rectangular area
+// between their corners, instead of the stream of characters from one
+// corner to the other. Copy, fill, and erase operations are always
+// rectangular.
+//
+//	CSI 2 * x
+//
+// See: https://vt100.net/docs/vt510-rm/DECSACE.html
+const SelectRectangularExtent = "\x1b[2*x"
+
+// SelectStreamExtent (DECSACE) is a sequence that makes the attribute change
+// operations apply to the stream of characters from one corner to the
+// other. This is the default.
+//
+//	CSI 1 * x
+//
+// See: https://vt100.net/docs/vt510-rm/DECSACE.html
+const SelectStreamExtent = "\x1b[1*x"
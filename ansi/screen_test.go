@@ -0,0 +1,76 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestScreenSequences(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"EraseDisplay(0)", ansi.EraseDisplay(0), ansi.EraseScreenBelow},
+		{"EraseDisplay(2)", ansi.EraseDisplay(2), ansi.EraseEntireScreen},
+		{"EraseDisplay(-1)", ansi.EraseDisplay(-1), ansi.EraseScreenBelow},
+		{"EraseDisplay(4)", ansi.EraseDisplay(4), ansi.EraseScreenBelow},
+		{"EraseLine(1)", ansi.EraseLine(1), ansi.EraseLineLeft},
+		{"EraseLine(3)", ansi.EraseLine(3), ansi.EraseLineRight},
+		{"InsertLine(1)", ansi.InsertLine(1), "\x1b[L"},
+		{"DeleteLine(3)", ansi.DeleteLine(3), "\x1b[3M"},
+		{"ScrollUp(0)", ansi.ScrollUp(0), "\x1b[S"},
+		{"ScrollDown(5)", ansi.ScrollDown(5), "\x1b[5T"},
+		{"InsertCharacter(2)", ansi.InsertCharacter(2), "\x1b[2@"},
+		{"DeleteCharacter(1)", ansi.DeleteCharacter(1), "\x1b[P"},
+		{"EraseCharacter(4)", ansi.EraseCharacter(4), "\x1b[4X"},
+		{"SetScrollingRegion(2, 10)", ansi.SetScrollingRegion(2, 10), "\x1b[2;10r"},
+		{"SetScrollingRegion(0, 10)", ansi.SetScrollingRegion(0, 10), "\x1b[;10r"},
+		{"SetScrollingRegion(5, 0)", ansi.SetScrollingRegion(5, 0), "\x1b[5;r"},
+		{"SetScrollingRegion(0, 0)", ansi.SetScrollingRegion(0, 0), ansi.ResetScrollingRegion},
+		{"SetScrollingRegion(10, 2)", ansi.SetScrollingRegion(10, 2), ansi.ResetScrollingRegion},
+		{"SetLeftRightMargins(3, 40)", ansi.SetLeftRightMargins(3, 40), "\x1b[3;40s"},
+		{"SetLeftRightMargins(-1, -1)", ansi.SetLeftRightMargins(-1, -1), ansi.ResetLeftRightMargins},
+		{"SetLeftRightMargins(40, 40)", ansi.SetLeftRightMargins(40, 40), ansi.ResetLeftRightMargins},
+		{"CopyRectangularArea(1, 2, 3, 4, 5, 6)", ansi.CopyRectangularArea(1, 2, 3, 4, 5, 6), "\x1b[1;2;3;4;1;5;6;1$v"},
+		{"CopyRectangularArea(3, 1, 1, 4, 5, 6)", ansi.CopyRectangularArea(3, 1, 1, 4, 5, 6), ""},
+		{"CopyRectangularArea(1, 1, 1, 1, 0, 6)", ansi.CopyRectangularArea(1, 1, 1, 1, 0, 6), ""},
+		{"FillRectangularArea('x', 1, 1, 2, 10)", ansi.FillRectangularArea('x', 1, 1, 2, 10), "\x1b[120;1;1;2;10$x"},
+		{"FillRectangularArea('é', 1, 1, 1, 1)", ansi.FillRectangularArea('é', 1, 1, 1, 1), "\x1b[233;1;1;1;1$x"},
+		{"FillRectangularArea('\\n', 1, 1, 1, 1)", ansi.FillRectangularArea('\n', 1, 1, 1, 1), ""},
+		{"FillRectangularArea('世', 1, 1, 1, 1)", ansi.FillRectangularArea('世', 1, 1, 1, 1), ""},
+		{"EraseRectangularArea(2, 3, 4, 5)", ansi.EraseRectangularArea(2, 3, 4, 5), "\x1b[2;3;4;5$z"},
+		{"EraseRectangularArea(0, 3, 4, 5)", ansi.EraseRectangularArea(0, 3, 4, 5), ""},
+		{"EraseRectangularArea(2, 5, 4, 3)", ansi.EraseRectangularArea(2, 5, 4, 3), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
+
+func TestAltScreen(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"EnterAltScreen(false)", ansi.EnterAltScreen(false), "\x1b[?1049h"},
+		{"EnterAltScreen(true)", ansi.EnterAltScreen(true), "\x1b[?1049h\x1b[2J\x1b[1;1H"},
+		{"ExitAltScreen()", ansi.ExitAltScreen(), "\x1b[?1049l"},
+		{"EnterLegacyAltScreen(false)", ansi.EnterLegacyAltScreen(false), "\x1b7\x1b[?47h"},
+		{"EnterLegacyAltScreen(true)", ansi.EnterLegacyAltScreen(true), "\x1b7\x1b[?47h\x1b[2J\x1b[1;1H"},
+		{"ExitLegacyAltScreen()", ansi.ExitLegacyAltScreen(), "\x1b[?47l\x1b8"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package ansi
+
+// RequestSetting (DECRQSS) returns a sequence that requests the current
+// setting of a control function, identified by its intermediate and final
+// bytes, like "m" for SGR, or " q" for DECSCUSR.
+//
+//	DCS $ q Pt ST
+//
+// The terminal replies with a DECRPSS sequence, where Ps is 1 for a valid
+// request, 0 otherwise, and Pt is the control function with its current
+// parameters, like "0;1m":
+//
+//	DCS Ps $ r Pt ST
+//
+// See: https://vt100.net/docs/vt510-rm/DECRQSS.html
+func RequestSetting(fn string) string {
+	return "\x1bP$q" + fn + "\x1b\\"
+}
+
+// Common DECRQSS requests, see [RequestSetting].
+const (
+	// RequestSgr requests the current text style (SGR).
+	RequestSgr = "\x1bP$qm\x1b\\"
+
+	// RequestCursorStyle requests the current cursor style (DECSCUSR). See
+	// [ParseCursorStyle] to decode the style.
+	RequestCursorStyle = "\x1bP$q q\x1b\\"
+
+	// RequestScrollingRegion requests the current top and bottom margins
+	// (DECSTBM).
+	RequestScrollingRegion = "\x1bP$qr\x1b\\"
+
+	// RequestLeftRightMargins requests the current left and right margins
+	// (DECSLRM).
+	RequestLeftRightMargins = "\x1bP$qs\x1b\\"
+
+	// RequestConformanceLevel requests the current conformance level
+	// (DECSCL).
+	RequestConformanceLevel = "\x1bP$q\"p\x1b\\"
+)
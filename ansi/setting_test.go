@@ -0,0 +1,19 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRequestSetting(t *testing.T) {
+	for fn, expect := range map[string]string{
+		"m":  ansi.RequestSgr,
+		" q": ansi.RequestCursorStyle,
+		"r":  ansi.RequestScrollingRegion,
+	} {
+		if got := ansi.RequestSetting(fn); got != expect {
+			t.Errorf("expected: %q, got: %q", expect, got)
+		}
+	}
+}
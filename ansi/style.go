@@ -26,12 +26,10 @@ func (s Style) String() string {
 	return "\x1b[" + strings.Join(s, ";") + "m"
 }
 
-// Styled returns a styled string with the given style applied.
+// Styled returns a styled string with the given style applied. See [Styled]
+// for how nested styles are handled.
 func (s Style) Styled(str string) string {
-	if len(s) == 0 {
-		return str
-	}
-	return s.String() + str + ResetStyle
+	return Styled(str, s)
 }
 
 // Reset appends the reset style attribute to the style.
@@ -197,6 +195,34 @@ func (s Style) UnderlineColor(c Color) Style {
 	return append(s, underlineColorString(c))
 }
 
+// StyleAt returns the SGR style of the cell at the given index of the
+// visible text of s, replaying the SGR sequences before it. Use it to draw a
+// cell, like a cursor, with the style of the text under it. A cell past the
+// end of s has the style s ends with, and a nil style is the default style.
+//
+//	ansi.StyleAt("ab\x1b[1;31mcd\x1b[m", 2) // ansi.Style{"1", "31"}
+//
+// A wide character spans several cells, each with its style.
+func StyleAt(s string, cell int) Style {
+	var (
+		t     sgrTracker
+		width int
+		sc    = NewScanner(s)
+	)
+	for sc.Scan() {
+		switch sc.Kind() {
+		case SequenceToken:
+			t.trackSequence(sc.Token())
+		case TextToken:
+			width += sc.Width()
+			if width > cell {
+				return t.style
+			}
+		}
+	}
+	return t.style
+}
+
 // UnderlineStyle represents an ANSI SGR (Select Graphic Rendition) underline
 // style.
 type UnderlineStyle = int
@@ -2,6 +2,7 @@ package ansi_test
 
 import (
 	"image/color"
+	"reflect"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -56,3 +57,76 @@ func BenchmarkStyle(b *testing.B) {
 			String()
 	}
 }
+
+func TestStyleAt(t *testing.T) {
+	s := "ab\x1b[1;31mc你\x1b[4md\x1b[m\x1b[2me"
+	cases := []struct {
+		cell   int
+		expect ansi.Style
+	}{
+		{0, nil},
+		{1, nil},
+		{2, ansi.Style{"1", "31"}},
+		{3, ansi.Style{"1", "31"}},
+		{4, ansi.Style{"1", "31"}},
+		{5, ansi.Style{"1", "4", "31"}},
+		{6, ansi.Style{"2"}},
+		{7, ansi.Style{"2"}},
+	}
+	for _, c := range cases {
+		if got := ansi.StyleAt(s, c.cell); !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("cell %d: expected %q, got %q", c.cell, c.expect, got)
+		}
+	}
+
+	if got := ansi.StyleAt("\x1b[1mab\x1b[0;32mc", 2); got.String() != "\x1b[32m" {
+		t.Errorf("expected %q, got %q", "\x1b[32m", got.String())
+	}
+}
+
+func TestStyled(t *testing.T) {
+	bold := ansi.Style{}.Bold()
+	red := ansi.Style{}.ForegroundColor(ansi.Red)
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"empty style", ansi.Styled("hello", ansi.Style{}), "hello"},
+		{"plain", ansi.Styled("hello", bold), "\x1b[1mhello\x1b[m"},
+		{"method", bold.Styled("hello"), "\x1b[1mhello\x1b[m"},
+		{
+			"nested",
+			ansi.Styled("a"+red.Styled("b")+"c", bold),
+			"\x1b[1ma\x1b[31mb\x1b[m\x1b[1mc\x1b[m",
+		},
+		{
+			"nested reset with attributes",
+			ansi.Styled("a\x1b[0;32mb", bold),
+			"\x1b[1ma\x1b[m\x1b[1m\x1b[32mb\x1b[m",
+		},
+		{
+			"explicit reset",
+			ansi.Styled("a\x1b[0mb", bold),
+			"\x1b[1ma\x1b[m\x1b[1mb\x1b[m",
+		},
+		{
+			"other sequences",
+			ansi.Styled("a\x1b[2Kb\x1b[32mc", bold),
+			"\x1b[1ma\x1b[2Kb\x1b[32mc\x1b[m",
+		},
+		{"sprintf", red.Sprintf("%d files", 3), "\x1b[31m3 files\x1b[m"},
+		{
+			"sprintf nested",
+			bold.Sprintf("%s!", red.Styled("hi")),
+			"\x1b[1m\x1b[31mhi\x1b[m\x1b[1m!\x1b[m",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
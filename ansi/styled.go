@@ -0,0 +1,86 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Styled returns str with style applied, followed by a reset. Styles can be
+// nested: the resets of the styled strings in str restore style instead of
+// clearing all attributes, so the text after them keeps the outer style.
+//
+//	inner := ansi.Styled("world", ansi.Style{}.ForegroundColor(ansi.Red))
+//	ansi.Styled("hello "+inner+"!", ansi.Style{}.Bold()) // "!" is still bold
+func Styled(str string, style Style) string {
+	if len(style) == 0 {
+		return str
+	}
+
+	outer := style.String()
+	if !strings.Contains(str, "\x1b[") && !strings.Contains(str, "\x9b") {
+		return outer + str + ResetStyle
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(outer) + len(str) + len(ResetStyle))
+	buf.WriteString(outer)
+	sc := NewScanner(str)
+	for sc.Scan() {
+		tok := sc.Token()
+		if sc.Kind() == SequenceToken {
+			if params, ok := resetParams(tok); ok {
+				// Reset, restore the outer style, then apply the rest of
+				// the inner sequence on top of it.
+				buf.WriteString(ResetStyle)
+				buf.WriteString(outer)
+				if params != "" {
+					buf.WriteString("\x1b[" + params + "m")
+				}
+				continue
+			}
+		}
+		buf.WriteString(tok)
+	}
+	buf.WriteString(ResetStyle)
+
+	return buf.String()
+}
+
+// Sprintf formats according to a format specifier, like [fmt.Sprintf], and
+// returns the result styled with s. See [Styled].
+func (s Style) Sprintf(format string, a ...interface{}) string {
+	return Styled(fmt.Sprintf(format, a...), s)
+}
+
+// resetParams reports whether seq is a SGR sequence that resets all
+// attributes, and returns the parameters that come after the reset.
+func resetParams(seq string) (string, bool) {
+	var params string
+	switch {
+	case strings.HasPrefix(seq, "\x1b["):
+		params = seq[2:]
+	case strings.HasPrefix(seq, "\x9b"):
+		params = seq[1:]
+	default:
+		return "", false
+	}
+	if !strings.HasSuffix(params, "m") {
+		return "", false
+	}
+	params = params[:len(params)-1]
+	for i := 0; i < len(params); i++ {
+		if c := params[i]; (c < '0' || c > '9') && c != ';' && c != ':' {
+			return "", false
+		}
+	}
+
+	switch {
+	case params == "" || params == "0":
+		return "", true
+	case strings.HasPrefix(params, "0;"):
+		return params[2:], true
+	case strings.HasPrefix(params, ";"):
+		return params[1:], true
+	}
+	return "", false
+}
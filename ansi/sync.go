@@ -0,0 +1,50 @@
+package ansi
+
+import "io"
+
+// BeginSync is a sequence that starts a synchronized update. The terminal
+// keeps displaying the last rendered frame and buffers any subsequent output
+// until [EndSync] is received, which avoids tearing and flickering.
+//
+// This is equivalent to [EnableSyncdOutput].
+//
+// See: https://gist.github.com/christianparpart/d8a62cc1ab659194337d73e399004036
+const BeginSync = EnableSyncdOutput
+
+// EndSync is a sequence that ends a synchronized update started by
+// [BeginSync], and renders the buffered output at once.
+//
+// This is equivalent to [DisableSyncdOutput].
+//
+// See: https://gist.github.com/christianparpart/d8a62cc1ab659194337d73e399004036
+const EndSync = DisableSyncdOutput
+
+// Synchronized returns the given string wrapped in a synchronized update.
+//
+// This is equivalent to BeginSync + s + EndSync.
+func Synchronized(s string) string {
+	return BeginSync + s + EndSync
+}
+
+// SynchronizedFunc writes a [BeginSync] sequence to w, calls fn to render the
+// frame, and writes an [EndSync] sequence. The update is always ended, even
+// when fn returns an error, so the terminal never stays frozen. The error
+// returned by fn takes precedence over write errors.
+//
+// Terminals that don't support synchronized output ignore these sequences,
+// which makes this safe to use unconditionally.
+//
+//	err := ansi.SynchronizedFunc(os.Stdout, func(w io.Writer) error {
+//		_, err := io.WriteString(w, frame)
+//		return err
+//	})
+func SynchronizedFunc(w io.Writer, fn func(w io.Writer) error) error {
+	if _, err := io.WriteString(w, BeginSync); err != nil {
+		return err
+	}
+	ferr := fn(w)
+	if _, err := io.WriteString(w, EndSync); err != nil && ferr == nil {
+		return err
+	}
+	return ferr
+}
@@ -0,0 +1,45 @@
+package ansi_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSynchronized(t *testing.T) {
+	expect := "\x1b[?2026hhello\x1b[?2026l"
+	if got := ansi.Synchronized("hello"); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestSynchronizedFunc(t *testing.T) {
+	var buf bytes.Buffer
+	err := ansi.SynchronizedFunc(&buf, func(w io.Writer) error {
+		_, err := io.WriteString(w, "frame")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := "\x1b[?2026hframe\x1b[?2026l"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestSynchronizedFuncError(t *testing.T) {
+	var buf bytes.Buffer
+	errRender := errors.New("render failed")
+	err := ansi.SynchronizedFunc(&buf, func(io.Writer) error {
+		return errRender
+	})
+	if !errors.Is(err, errRender) {
+		t.Fatalf("expected %v, got %v", errRender, err)
+	}
+	if expect := ansi.BeginSync + ansi.EndSync; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
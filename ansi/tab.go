@@ -0,0 +1,74 @@
+package ansi
+
+import "strconv"
+
+// HorizontalTabSet (HTS) is a sequence that sets a tab stop at the current
+// cursor column.
+//
+//	ESC H
+//
+// See: https://vt100.net/docs/vt510-rm/HTS.html
+const HorizontalTabSet = "\x1bH"
+
+// TabClear (TBC) returns a sequence that clears tab stops. Possible values:
+//
+//	0: Clear the tab stop at the current cursor column.
+//	3: Clear all tab stops.
+//
+// Other values are treated as 0.
+//
+//	CSI <n> g
+//
+// See: https://vt100.net/docs/vt510-rm/TBC.html
+func TabClear(n int) string {
+	if n != 3 {
+		return ClearTabStop
+	}
+	return ClearAllTabStops
+}
+
+// TabClear constants.
+// These are the possible values for the TabClear function.
+const (
+	// ClearTabStop clears the tab stop at the current cursor column.
+	ClearTabStop = "\x1b[g"
+
+	// ClearAllTabStops clears all tab stops.
+	ClearAllTabStops = "\x1b[3g"
+)
+
+// CursorHorizontalForwardTab (CHT) returns a sequence that moves the cursor
+// forward n tab stops, or to the right margin when there are no more.
+//
+//	CSI <n> I
+//
+// See: https://vt100.net/docs/vt510-rm/CHT.html
+func CursorHorizontalForwardTab(n int) string {
+	var s string
+	if n > 1 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "I"
+}
+
+// CursorBackwardTab (CBT) returns a sequence that moves the cursor back n
+// tab stops, or to the left margin when there are no more.
+//
+//	CSI <n> Z
+//
+// See: https://vt100.net/docs/vt510-rm/CBT.html
+func CursorBackwardTab(n int) string {
+	var s string
+	if n > 1 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "Z"
+}
+
+// SetTabEvery8Columns (DECST8C) is a sequence that clears all tab stops, and
+// sets one every 8 columns, starting at column 9. This is the usual default.
+//
+//	CSI ? 5 W
+//
+// See: https://vt100.net/docs/vt510-rm/DECST8C.html
+const SetTabEvery8Columns = "\x1b[?5W"
@@ -0,0 +1,30 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTabSequences(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"TabClear(0)", ansi.TabClear(0), "\x1b[g"},
+		{"TabClear(3)", ansi.TabClear(3), "\x1b[3g"},
+		{"TabClear(2)", ansi.TabClear(2), ansi.ClearTabStop},
+		{"CursorHorizontalForwardTab(1)", ansi.CursorHorizontalForwardTab(1), "\x1b[I"},
+		{"CursorHorizontalForwardTab(4)", ansi.CursorHorizontalForwardTab(4), "\x1b[4I"},
+		{"CursorBackwardTab(0)", ansi.CursorBackwardTab(0), "\x1b[Z"},
+		{"CursorBackwardTab(2)", ansi.CursorBackwardTab(2), "\x1b[2Z"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
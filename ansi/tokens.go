@@ -0,0 +1,123 @@
+//go:build go1.23
+
+package ansi
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"unicode/utf8"
+)
+
+// Token is a token of a string, see [Tokens].
+type Token struct {
+	// Kind is the kind of the token.
+	Kind TokenKind
+
+	// Text is the text of the token.
+	Text string
+
+	// Width is the width of the token in cells. Control characters and
+	// escape sequences have no width.
+	Width int
+}
+
+// Tokens returns an iterator over the tokens of s, the text, control
+// characters, and escape sequences read by a [Scanner].
+//
+//	for tok := range ansi.Tokens("\x1b[1mhello\x1b[m") {
+//		fmt.Println(tok.Kind, tok.Width, strconv.Quote(tok.Text))
+//	}
+func Tokens(s string) iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		sc := NewScanner(s)
+		for sc.Scan() {
+			if !yield(Token{sc.Kind(), sc.Token(), sc.Width()}) {
+				return
+			}
+		}
+	}
+}
+
+// ReadTokens returns an iterator over the tokens read from r, like
+// [Tokens]. A token is yielded once what follows it is read, since it could
+// continue it, like a combining mark continues a character, or at the end of
+// the input. The iterator stops after yielding a read error, other than
+// [io.EOF].
+//
+//	for tok, err := range ansi.ReadTokens(os.Stdin) {
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Println(tok.Kind, tok.Width, strconv.Quote(tok.Text))
+//	}
+func ReadTokens(r io.Reader) iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		var (
+			buf []byte
+			sc  Scanner
+		)
+		chunk := make([]byte, 4096)
+		for {
+			n, err := r.Read(chunk)
+			buf = append(buf, chunk[:n]...)
+			eof := errors.Is(err, io.EOF)
+
+			// Yield every token but the last one, which can be continued
+			// by the next read, and leave out the beginning of a character
+			// cut short by the read.
+			end := len(buf)
+			if !eof && err == nil {
+				end -= partialRuneLen(buf)
+			}
+			sc.Reset(string(buf[:end]))
+			var (
+				prev   Token
+				hasTok bool
+				used   int
+			)
+			for sc.Scan() {
+				if hasTok {
+					if !yield(prev, nil) {
+						return
+					}
+					used += len(prev.Text)
+				}
+				prev, hasTok = Token{sc.Kind(), sc.Token(), sc.Width()}, true
+			}
+			if hasTok && (eof || err != nil) {
+				if !yield(prev, nil) {
+					return
+				}
+				used += len(prev.Text)
+			}
+			buf = append(buf[:0], buf[used:]...)
+
+			switch {
+			case eof:
+				return
+			case err != nil:
+				yield(Token{}, err)
+				return
+			}
+		}
+	}
+}
+
+// partialRuneLen returns the length of the incomplete UTF-8 character at
+// the end of b, if any.
+func partialRuneLen(b []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		c := b[len(b)-i]
+		if c < utf8.RuneSelf {
+			return 0
+		}
+		if utf8.RuneStart(c) {
+			if utf8.FullRune(b[len(b)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
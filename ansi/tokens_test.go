@@ -0,0 +1,77 @@
+//go:build go1.23
+
+package ansi
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTokens(t *testing.T) {
+	var got []Token
+	for tok := range Tokens("\x1b[1mhé\x1b[m\n") {
+		got = append(got, tok)
+	}
+	expect := []Token{
+		{SequenceToken, "\x1b[1m", 0},
+		{TextToken, "h", 1},
+		{TextToken, "é", 1},
+		{SequenceToken, "\x1b[m", 0},
+		{ControlToken, "\n", 0},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	for tok := range Tokens("ab") {
+		if tok.Text != "a" {
+			t.Errorf("expected %q, got %q", "a", tok.Text)
+		}
+		break
+	}
+}
+
+func TestReadTokens(t *testing.T) {
+	input := "\x1b]2;title\x07👋🏽 hí\x1b[m"
+	expect := []Token{
+		{SequenceToken, "\x1b]2;title\x07", 0},
+		{TextToken, "👋🏽", 2},
+		{TextToken, " ", 1},
+		{TextToken, "h", 1},
+		{TextToken, "í", 1},
+		{SequenceToken, "\x1b[m", 0},
+	}
+
+	// Read a byte at a time, so that every token is split across reads.
+	var got []Token
+	for tok, err := range ReadTokens(iotest.OneByteReader(strings.NewReader(input))) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, tok)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	errRead := errors.New("read error")
+	got = got[:0]
+	var gotErr error
+	for tok, err := range ReadTokens(io.MultiReader(strings.NewReader("ab"), iotest.ErrReader(errRead))) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, tok)
+	}
+	if expect := []Token{{TextToken, "a", 1}, {TextToken, "b", 1}}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+	if gotErr != errRead {
+		t.Errorf("expected %v, got %v", errRead, gotErr)
+	}
+}
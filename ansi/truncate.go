@@ -1,7 +1,9 @@
 package ansi
 
 import (
-	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/x/ansi/parser"
 	"github.com/rivo/uniseg"
@@ -11,97 +13,195 @@ import (
 // end if the string is longer than the given length.
 // This function is aware of ANSI escape codes and will not break them, and
 // accounts for wide-characters (such as East Asians and emojis).
+//
+// The string is scanned once, and only up to the point where it's known to
+// be too long. A string that fits is returned as is.
 func Truncate(s string, length int, tail string) string {
-	if sw := StringWidth(s); sw <= length {
+	if s == "" {
 		return s
 	}
 
-	tw := StringWidth(tail)
-	length -= tw
-	if length < 0 {
+	cut, over := truncateCut(s, length, StringWidth(tail))
+	if !over {
+		return s
+	}
+	if cut < 0 {
 		return ""
 	}
 
-	var cluster []byte
-	var buf bytes.Buffer
+	return truncateTail(s, cut, tail)
+}
+
+// TruncateWord truncates a string like [Truncate], but doesn't break words:
+// when the length falls in the middle of a word, the tail goes after the
+// previous word instead. Words are separated by spaces, and can also be
+// broken after the breakpoints, like the breakpoints of [Wordwrap]. A hyphen
+// (-) is always a breakpoint. A word that's longer than the line is cut like
+// [Truncate] does.
+//
+//	ansi.TruncateWord("The quick brown fox", 14, "…", "") // "The quick…"
+func TruncateWord(s string, length int, tail, breakpoints string) string {
+	if s == "" {
+		return s
+	}
+
+	cut, over := truncateCut(s, length, StringWidth(tail))
+	if !over {
+		return s
+	}
+	if cut < 0 {
+		return ""
+	}
+
+	if end := wordEnd(s, cut, breakpoints); end > 0 {
+		cut = end
+	}
+	return truncateTail(s, cut, tail)
+}
+
+// truncateCut returns where the tail of width tw goes when s is truncated to
+// the given length, and whether s is longer than that. The cut is negative
+// when the tail doesn't fit either.
+func truncateCut(s string, length, tw int) (cut int, over bool) {
+	cutWidth := length - tw
+	cut = -1 // cut is where the tail goes, once known.
 	curWidth := 0
-	ignoring := false
 	pstate := parser.GroundState // initial state
-	b := []byte(s)
-	i := 0
-
-	// Here we iterate over the bytes of the string and collect printable
-	// characters and runes. We also keep track of the width of the string
-	// in cells.
-	// Once we reach the given length, we start ignoring characters and only
-	// collect ANSI escape codes until we reach the end of string.
-	for i < len(b) {
-		state, action := parser.Table.Transition(pstate, b[i])
-		if state == parser.Utf8State {
-			// This action happens when we transition to the Utf8State.
-			var width int
-			cluster, _, width, _ = uniseg.FirstGraphemeCluster(b[i:], -1)
 
-			// increment the index by the length of the cluster
-			i += len(cluster)
-
-			// Are we ignoring? Skip to the next byte
-			if ignoring {
+	// Here we iterate over the bytes of the string and keep track of the
+	// width of the string in cells. We remember where the tail would go, and
+	// stop as soon as the string is wider than the given length.
+	for i := 0; i < len(s); i++ {
+		if pstate == parser.GroundState {
+			if n := asciiPrintLen(s[i:]); n > 0 {
+				if cut < 0 && curWidth+n > cutWidth {
+					cut = i
+					if cutWidth > curWidth {
+						cut += cutWidth - curWidth
+					}
+				}
+				if curWidth+n > length {
+					over = true
+					break
+				}
+				curWidth += n
+				i += n - 1
 				continue
 			}
+		}
 
-			// Is this gonna be too wide?
-			// If so write the tail and stop collecting.
-			if curWidth+width > length && !ignoring {
-				ignoring = true
-				buf.WriteString(tail)
-			}
+		state, action := parser.Table.Transition(pstate, s[i])
+		width := 0
+		n := 1
+		if state == parser.Utf8State {
+			// This action happens when we transition to the Utf8State.
+			var cluster string
+			cluster, _, width, _ = uniseg.FirstGraphemeClusterInString(s[i:], -1)
+			n = len(cluster)
+			state = parser.GroundState
+		} else if action == parser.PrintAction {
+			width = 1
+		}
 
+		if width > 0 {
+			if cut < 0 && curWidth+width > cutWidth {
+				cut = i
+			}
 			if curWidth+width > length {
-				continue
+				over = true
+				break
 			}
-
 			curWidth += width
-			buf.Write(cluster)
-
-			// Done collecting, now we're back in the ground state.
-			pstate = parser.GroundState
-			continue
 		}
 
-		switch action {
-		case parser.PrintAction:
-			// Is this gonna be too wide?
-			// If so write the tail and stop collecting.
-			if curWidth >= length && !ignoring {
-				ignoring = true
-				buf.WriteString(tail)
-			}
+		i += n - 1
+		pstate = state
+	}
 
-			// Skip to the next byte if we're ignoring
-			if ignoring {
-				i++
+	if cutWidth < 0 {
+		cut = -1
+	}
+	return cut, over
+}
+
+// truncateTail returns s cut at cut, followed by the tail and the escape
+// sequences and control characters after the cut.
+func truncateTail(s string, cut int, tail string) string {
+	var buf strings.Builder
+	buf.Grow(cut + len(tail))
+	buf.WriteString(s[:cut])
+	buf.WriteString(tail)
+
+	// Past the tail, we only collect ANSI escape codes and control
+	// characters until we reach the end of string.
+	pstate := parser.GroundState
+	for i := cut; i < len(s); i++ {
+		if pstate == parser.GroundState {
+			if n := asciiPrintLen(s[i:]); n > 0 {
+				i += n - 1
 				continue
 			}
-
-			// collects printable ASCII
-			curWidth++
-			fallthrough
-		default:
-			buf.WriteByte(b[i])
-			i++
 		}
 
-		// Transition to the next state.
-		pstate = state
-
-		// Once we reach the given length, we start ignoring runes and write
-		// the tail to the buffer.
-		if curWidth > length && !ignoring {
-			ignoring = true
-			buf.WriteString(tail)
+		state, action := parser.Table.Transition(pstate, s[i])
+		if state == parser.Utf8State {
+			// Runes are dropped, there is no need to find their clusters.
+			if n := utf8ByteLen(s[i]); n > 1 {
+				i += n - 1
+			}
+			pstate = parser.GroundState
+			continue
 		}
+		if action != parser.PrintAction {
+			buf.WriteByte(s[i])
+		}
+		pstate = state
 	}
 
 	return buf.String()
 }
+
+// wordEnd returns where the last whole word of s before cut ends, without
+// the spaces after it, or zero if there's none. The words are separated by
+// spaces and broken after hyphens and the breakpoints.
+func wordEnd(s string, cut int, breakpoints string) int {
+	var (
+		end   int  // end is the end of the last whole word.
+		text  int  // text is the end of the last character that isn't a space.
+		space bool // space is whether the last character is a space.
+		pos   int
+		sc    = NewScanner(s)
+	)
+	for sc.Scan() {
+		tok := sc.Token()
+		if sc.Kind() == SequenceToken {
+			pos += len(tok)
+			continue
+		}
+
+		r, _ := utf8.DecodeRuneInString(tok)
+		isSpace := unicode.IsSpace(r) && r != nbsp
+		if pos >= cut {
+			// The word before the cut is whole when a space follows it.
+			if isSpace && !space {
+				return text
+			}
+			return end
+		}
+		pos += len(tok)
+
+		switch {
+		case isSpace:
+			if !space {
+				end = text
+			}
+		case r == '-' || runeContainsAny(r, breakpoints):
+			end = pos
+			text = pos
+		default:
+			text = pos
+		}
+		space = isSpace
+	}
+	return end
+}
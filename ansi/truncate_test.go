@@ -1,6 +1,7 @@
 package ansi
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -36,6 +37,9 @@ var tcases = []struct {
 	{"same_tail_width", "foo", "...", 3, "foo"},
 	{"same_tail_width_control", "\x1b[31mfoo\x1b[0m", "...", 3, "\x1b[31mfoo\x1b[0m"},
 	{"same_width", "foo", "", 3, "foo"},
+	{"fits_wider_tail", "foo", "…………", 3, "foo"},
+	{"zero_width", "\x1b[31m\x1b[0m", "…", 0, "\x1b[31m\x1b[0m"},
+	{"controls_after_tail", "foo\nbar\x1b[0m", "…", 3, "fo…\n\x1b[0m"},
 	{"truncate_with_tail", "foobar", ".", 4, "foo."},
 	{"style", "I really \x1B[38;2;249;38;114mlove\x1B[0m Go!", "", 8, "I really\x1B[38;2;249;38;114m\x1B[0m"},
 	{"dcs", "\x1BPq#0;2;0;0;0#1;2;100;100;0#2;2;0;100;0#1~~@@vv@@~~@@~~$#2??}}GG}}??}}??-#1!14@\x1B\\foobar", "…", 4, "\x1BPq#0;2;0;0;0#1;2;100;100;0#2;2;0;100;0#1~~@@vv@@~~@@~~$#2??}}GG}}??}}??-#1!14@\x1B\\foo…"},
@@ -56,6 +60,58 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+// nolint
+var twcases = []struct {
+	name        string
+	input       string
+	breakpoints string
+	width       int
+	expect      string
+}{
+	{"fits", "The quick brown fox", "", 19, "The quick brown fox"},
+	{"mid_word", "The quick brown fox", "", 14, "The quick…"},
+	{"word_end", "The quick brown fox", "", 10, "The quick…"},
+	{"after_space", "The quick brown fox", "", 11, "The quick…"},
+	{"spaces", "The   quick brown", "", 12, "The   quick…"},
+	{"long_word", "Thequickbrownfox", "", 10, "Thequickb…"},
+	{"first_word", "The quick brown fox", "", 3, "Th…"},
+	{"hyphen", "The quick-brown fox", "", 14, "The quick-…"},
+	{"breakpoints", "path/to/some/file", "/", 12, "path/to/…"},
+	{"style", "\x1b[1mThe quick\x1b[m brown fox", "", 12, "\x1b[1mThe quick…\x1b[m"},
+	{"wide_chars", "日本語 テキスト です", "", 12, "日本語…"},
+	{"tail_too_wide", "The quick brown fox", "", 0, ""},
+}
+
+func TestTruncateWord(t *testing.T) {
+	for i, c := range twcases {
+		t.Run(c.name, func(t *testing.T) {
+			if result := TruncateWord(c.input, c.width, "…", c.breakpoints); result != c.expect {
+				t.Errorf("test case %d failed: expected %q, got %q", i+1, c.expect, result)
+			}
+		})
+	}
+}
+
+func TestTruncateFitsNoAlloc(t *testing.T) {
+	s := "\x1b[31mhello 👋 world\x1b[0m"
+	allocs := testing.AllocsPerRun(100, func() {
+		if Truncate(s, 20, "…") != s {
+			t.Fatal("expected the string unchanged")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkTruncateLongString(b *testing.B) {
+	s := strings.Repeat("\x1b[31mhello 👋 world\x1b[0m ", 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Truncate(s, 80, "…")
+	}
+}
+
 func BenchmarkTruncateString(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		b.ReportAllocs()
@@ -3,6 +3,7 @@ package ansi
 import (
 	"fmt"
 	"image/color"
+	"strings"
 )
 
 // colorToHexString returns a hex string representation of a color.
@@ -27,3 +28,38 @@ func colorToHexString(c color.Color) string {
 func rgbToHex(r, g, b uint32) uint32 {
 	return r<<16 + g<<8 + b
 }
+
+// asciiPrintLen returns the length of the run of printable ASCII characters
+// at the start of s. These characters are one cell wide each, and can be
+// handled in bulk without going through the parser or grapheme
+// segmentation.
+func asciiPrintLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] < SP || s[i] >= DEL {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// asciiWordLen is like asciiPrintLen but stops at spaces, hyphens, and the
+// given breakpoints.
+func asciiWordLen(b []byte, breakpoints string) int {
+	for i := 0; i < len(b); i++ {
+		if c := b[i]; c <= SP || c >= DEL || c == '-' || strings.IndexByte(breakpoints, c) >= 0 {
+			return i
+		}
+	}
+	return len(b)
+}
+
+// isPlainASCII reports whether s is made of ASCII characters only, without
+// escape sequences.
+func isPlainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ESC || s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -7,18 +7,41 @@ import (
 	"github.com/rivo/uniseg"
 )
 
-// Strip removes ANSI escape codes from a string.
+// Strip removes ANSI escape codes from a string. Text written in the DEC
+// special graphics character set, see [SelectDecSpecialGraphics], is
+// translated to the Unicode line drawing characters it stands for.
 func Strip(s string) string {
+	if isPlainASCII(s) {
+		// Nothing to strip.
+		return s
+	}
+
 	var (
-		buf    bytes.Buffer         // buffer for collecting printable characters
-		ri     int                  // rune index
-		rw     int                  // rune width
-		pstate = parser.GroundState // initial state
+		buf      bytes.Buffer         // buffer for collecting printable characters
+		ri       int                  // rune index
+		rw       int                  // rune width
+		pstate   = parser.GroundState // initial state
+		charsets charsetState         // the character sets designated with SCS
+		intermed byte                 // the escape sequence intermediate byte
 	)
 
 	// This implements a subset of the Parser to only collect runes and
 	// printable characters.
 	for i := 0; i < len(s); i++ {
+		if pstate == parser.GroundState {
+			if n := asciiPrintLen(s[i:]); n > 0 {
+				if charsets.graphics() {
+					for j := i; j < i+n; j++ {
+						buf.WriteRune(DecSpecialGraphicsRune(rune(s[j])))
+					}
+				} else {
+					buf.WriteString(s[i : i+n])
+				}
+				i += n - 1
+				continue
+			}
+		}
+
 		if pstate == parser.Utf8State {
 			// During this state, collect rw bytes to form a valid rune in the
 			// buffer. After getting all the rune bytes into the buffer,
@@ -45,9 +68,25 @@ func Strip(s string) string {
 			}
 		case parser.PrintAction, parser.ExecuteAction:
 			// collects printable ASCII and non-printable characters
+			if action == parser.PrintAction && charsets.graphics() {
+				buf.WriteRune(DecSpecialGraphicsRune(rune(s[i])))
+				break
+			}
+			charsets.shift(s[i])
 			buf.WriteByte(s[i])
 		}
 
+		// Keep track of the character sets designated with SCS.
+		switch {
+		case pstate == parser.EscapeState:
+			intermed = 0
+			if action == parser.CollectAction {
+				intermed = s[i]
+			}
+		case pstate == parser.EscapeIntermediateState && action == parser.DispatchAction:
+			charsets.designate(intermed, s[i])
+		}
+
 		// Transition to the next state.
 		// The Utf8State is managed separately above.
 		if pstate != parser.Utf8State {
@@ -58,6 +97,60 @@ func Strip(s string) string {
 	return buf.String()
 }
 
+// StripFunc removes ANSI escape codes from a string, like [Strip], and calls
+// fn with each escape sequence it removes, so the hyperlinks, titles, or
+// colors of the string can be collected while stripping it. The sequence is
+// only valid during the call, use [Sequence.Clone] to keep it. The string
+// terminator ending an OSC, DCS, APC, SOS, or PM sequence isn't passed to
+// fn.
+//
+//	var links []string
+//	text := ansi.StripFunc(s, func(seq ansi.Sequence) {
+//		if osc, ok := seq.(ansi.OscSequence); ok && osc.Cmd == 8 {
+//			links = append(links, string(osc.Data))
+//		}
+//	})
+func StripFunc(s string, fn func(seq Sequence)) string {
+	var (
+		buf      bytes.Buffer
+		str      bool         // str reports whether the last sequence was a string.
+		charsets charsetState // charsets are the character sets designated with SCS.
+	)
+	p := GetParser()
+	defer PutParser(p)
+	p.Parse(func(seq Sequence) {
+		wasStr := str
+		str = false
+		switch seq := seq.(type) {
+		case Rune:
+			if charsets.graphics() {
+				buf.WriteRune(DecSpecialGraphicsRune(rune(seq)))
+				break
+			}
+			buf.WriteRune(rune(seq))
+		case ControlCode:
+			charsets.shift(byte(seq))
+			if seq != ESC {
+				// An ESC at the end of the string is the beginning of a
+				// sequence.
+				buf.WriteByte(byte(seq))
+			}
+		case EscSequence:
+			if wasStr && seq == '\\' {
+				return
+			}
+			charsets.designate(byte(seq.Intermediate()), byte(seq.Command()))
+			fn(seq)
+		case OscSequence, DcsSequence, ApcSequence, SosSequence, PmSequence:
+			str = true
+			fn(seq)
+		default:
+			fn(seq)
+		}
+	}, []byte(s))
+	return buf.String()
+}
+
 // StringWidth returns the width of a string in cells. This is the number of
 // cells that the string will occupy when printed in a terminal. ANSI escape
 // codes are ignored and wide characters (such as East Asians and emojis) are
@@ -74,6 +167,14 @@ func StringWidth(s string) int {
 	)
 
 	for i := 0; i < len(s); i++ {
+		if pstate == parser.GroundState {
+			if n := asciiPrintLen(s[i:]); n > 0 {
+				width += n
+				i += n - 1
+				continue
+			}
+		}
+
 		state, action := parser.Table.Transition(pstate, s[i])
 		if state == parser.Utf8State {
 			var w int
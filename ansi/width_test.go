@@ -1,6 +1,8 @@
 package ansi
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -32,6 +34,8 @@ var cases = []struct {
 	{"just_unicode", "Claire’s Boutique", "Claire’s Boutique", 17},
 	{"unclosed_ansi", "Hey, \x1b[7m\n猴", "Hey, \n猴", 7},
 	{"double_asian_runes", " 你\x1b[8m好.", " 你好.", 6},
+	{"decgraphics", "\x1b(0lqk\x1b(B ok", "┌─┐ ok", 6},
+	{"decgraphicsshift", "\x1b)0\x0eqx\x0fq", "\x0e─│\x0fq", 3},
 }
 
 func TestStrip(t *testing.T) {
@@ -44,6 +48,46 @@ func TestStrip(t *testing.T) {
 	}
 }
 
+func TestStripFunc(t *testing.T) {
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if res := StripFunc(c.input, func(Sequence) {}); res != c.stripped {
+				t.Errorf("test case %d (%s) failed:\nexpected %q, got %q", i, c.name, c.stripped, res)
+			}
+		})
+	}
+
+	var (
+		links  []string
+		titles []string
+		names  []string
+	)
+	res := StripFunc("\x1b]2;title\x07\x1b[1m\x1b]8;;https://charm.sh\x1b\\charm\x1b]8;;\x1b\\\x1b[m\x1b", func(seq Sequence) {
+		switch seq := seq.(type) {
+		case OscSequence:
+			switch seq.Cmd {
+			case 2:
+				titles = append(titles, string(seq.Data))
+			case 8:
+				links = append(links, string(seq.Data))
+			}
+		}
+		names = append(names, SequenceName(seq))
+	})
+	if res != "charm" {
+		t.Errorf("expected %q, got %q", "charm", res)
+	}
+	if !reflect.DeepEqual(titles, []string{"2;title"}) {
+		t.Errorf("unexpected titles %q", titles)
+	}
+	if !reflect.DeepEqual(links, []string{"8;;https://charm.sh", "8;;"}) {
+		t.Errorf("unexpected links %q", links)
+	}
+	if expect := []string{"window title", "SGR", "hyperlink", "hyperlink", "SGR"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("expected sequences %q, got %q", expect, names)
+	}
+}
+
 func TestStringWidth(t *testing.T) {
 	for i, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -63,3 +107,31 @@ func BenchmarkStringWidth(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkStringWidthLong(b *testing.B) {
+	s := strings.Repeat("The quick brown fox jumps over the lazy dog, \x1b[1magain\x1b[m. ", 20)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StringWidth(s)
+	}
+}
+
+func BenchmarkStrip(b *testing.B) {
+	s := strings.Repeat("The quick brown fox jumps over the lazy dog, \x1b[1magain\x1b[m. ", 20)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Strip(s)
+	}
+}
+
+func TestStripPlainNoAlloc(t *testing.T) {
+	s := "hello\tworld\n"
+	allocs := testing.AllocsPerRun(100, func() {
+		if Strip(s) != s {
+			t.Fatal("expected the string unchanged")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
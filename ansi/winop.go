@@ -0,0 +1,54 @@
+package ansi
+
+import "strconv"
+
+// WindowOp (XTWINOPS) returns a sequence for the window operation op, with
+// the given parameters. Terminals often restrict the operations that change
+// the window, like xterm with its allowWindowOps resource, and ignore them.
+//
+//	CSI Ps ; Ps ; Ps t
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h4-Functions-using-CSI-_-ordered-by-the-final-character-lparen-s-rparen:CSI-Ps;Ps;Ps-t.1EB0
+func WindowOp(op int, params ...int) string {
+	s := "\x1b[" + strconv.Itoa(op)
+	for _, p := range params {
+		s += ";" + strconv.Itoa(p)
+	}
+	return s + "t"
+}
+
+// Window operations, see [WindowOp].
+const (
+	// RestoreWindow restores the window when it's minimized (de-iconify).
+	RestoreWindow = "\x1b[1t"
+
+	// MinimizeWindow minimizes the window (iconify).
+	MinimizeWindow = "\x1b[2t"
+
+	// RequestWindowState requests whether the window is minimized. The
+	// terminal replies with CSI 1 t when it isn't, and CSI 2 t when it is.
+	RequestWindowState = "\x1b[11t"
+
+	// RequestTextAreaPixelSize requests the size of the text area in
+	// pixels. The terminal replies with:
+	//
+	//	CSI 4 ; height ; width t
+	RequestTextAreaPixelSize = "\x1b[14t"
+
+	// RequestWindowPixelSize requests the size of the window in pixels,
+	// including its decorations. The terminal replies like it does to
+	// [RequestTextAreaPixelSize].
+	RequestWindowPixelSize = "\x1b[14;2t"
+
+	// RequestCellSize requests the size of a cell in pixels. The terminal
+	// replies with:
+	//
+	//	CSI 6 ; height ; width t
+	RequestCellSize = "\x1b[16t"
+
+	// RequestTextAreaSize requests the size of the text area in cells. The
+	// terminal replies with:
+	//
+	//	CSI 8 ; height ; width t
+	RequestTextAreaSize = "\x1b[18t"
+)
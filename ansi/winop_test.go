@@ -0,0 +1,26 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWindowOp(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"WindowOp(2)", ansi.WindowOp(2), ansi.MinimizeWindow},
+		{"WindowOp(14, 2)", ansi.WindowOp(14, 2), ansi.RequestWindowPixelSize},
+		{"WindowOp(8, 24, 80)", ansi.WindowOp(8, 24, 80), "\x1b[8;24;80t"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
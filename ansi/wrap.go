@@ -18,6 +18,18 @@ const nbsp = 0xA0
 // When preserveSpace is true, spaces at the beginning of a line will be
 // preserved.
 func Hardwrap(s string, limit int, preserveSpace bool) string {
+	return WrapOptions{}.Hardwrap(s, limit, preserveSpace)
+}
+
+// Hardwrap wraps s like [Hardwrap], with the options.
+func (o WrapOptions) Hardwrap(s string, limit int, preserveSpace bool) string {
+	if o.Prefix != "" {
+		return o.prefixLines(o.withoutPrefix().Hardwrap(s, o.prefixLimit(limit), preserveSpace))
+	}
+	if o.Reorder {
+		o.Reorder = false
+		return ReorderLines(o.Hardwrap(s, limit, preserveSpace))
+	}
 	if limit < 1 {
 		return s
 	}
@@ -38,6 +50,30 @@ func Hardwrap(s string, limit int, preserveSpace bool) string {
 
 	i := 0
 	for i < len(b) {
+		if pstate == parser.GroundState && curWidth > 0 && curWidth < limit {
+			// Printable ASCII goes as is up to the end of the line.
+			if n := asciiPrintLen(s[i:]); n > 0 {
+				if n > limit-curWidth {
+					n = limit - curWidth
+				}
+				buf.Write(b[i : i+n])
+				curWidth += n
+				i += n
+				continue
+			}
+		}
+		if pstate == parser.GroundState && b[i] == ESC {
+			if n, seq, width := o.cursorMovement(b[i:]); n > 0 {
+				if curWidth > 0 && curWidth+width > limit {
+					addNewline()
+				}
+				buf.Write(seq)
+				curWidth += width
+				i += n
+				continue
+			}
+		}
+
 		state, action := parser.Table.Transition(pstate, b[i])
 		if state == parser.Utf8State {
 			var width int
@@ -60,6 +96,10 @@ func Hardwrap(s string, limit int, preserveSpace bool) string {
 			pstate = parser.GroundState
 			continue
 		}
+		if pstate == parser.GroundState && o.normalizeNewline(b, i) {
+			i++
+			continue
+		}
 
 		switch action {
 		case parser.PrintAction, parser.ExecuteAction:
@@ -103,10 +143,68 @@ func Hardwrap(s string, limit int, preserveSpace bool) string {
 // account for wide-characters in the string.
 // The breakpoints string is a list of characters that are considered
 // breakpoints for word wrapping. A hyphen (-) is always considered a
-// breakpoint.
-//
-// Note: breakpoints must be a string of 1-cell wide rune characters.
+// breakpoint. Breakpoints can be any characters, including wide ones like
+// the ideographic comma (、), and take their width in the line. Use
+// string(runes) to pass them as a []rune.
 func Wordwrap(s string, limit int, breakpoints string) string {
+	return WrapOptions{}.WordwrapRules(s, limit, breakpoints, nil)
+}
+
+// Wordwrap wraps s like [Wordwrap], with the options.
+func (o WrapOptions) Wordwrap(s string, limit int, breakpoints string) string {
+	return o.WordwrapRules(s, limit, breakpoints, nil)
+}
+
+// LineBreakRules are the characters lines can't start or end with, used to
+// wrap text that isn't separated by spaces, like Japanese and Chinese.
+type LineBreakRules struct {
+	// NoStart are the characters that can't start a line, like closing
+	// punctuation.
+	NoStart string
+
+	// NoEnd are the characters that can't end a line, like opening
+	// brackets.
+	NoEnd string
+}
+
+// Kinsoku returns the line breaking rules of Japanese, kinsoku shori, which
+// also suit Chinese text. Lines don't start with closing brackets,
+// punctuation, iteration marks, or small kana, and don't end with opening
+// brackets. Each call returns new rules, which can be changed.
+func Kinsoku() *LineBreakRules {
+	return &LineBreakRules{
+		NoStart: ")]}’”〕〉》」』】〙〗〟｠»" +
+			"、。，．,.・：；:;？！?!‼⁇⁈⁉" +
+			"ヽヾーゝゞ々〻ァィゥェォッャュョヮヵヶぁぃぅぇぉっゃゅょゎゕゖㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿ" +
+			"‐゠–〜～）］｝",
+		NoEnd: "([{‘“〔〈《「『【〘〖〝｟«（［｛",
+	}
+}
+
+// WordwrapRules wraps a string or a block of text like [Wordwrap]. With
+// rules, lines also break between wide characters, like CJK ideographs and
+// kana, unless the rules forbid it. A character that can't start a line is
+// kept on the line before it, with the character before it when needed, and
+// a character that can't end a line is moved to the next line. When the
+// characters the rules keep together don't fit on a line, like at very small
+// limits, the line breaks between them anyway.
+//
+//	ansi.WordwrapRules("「こんにちは」と言った。", 8, "", ansi.Kinsoku())
+//
+// Without rules, it's the same as [Wordwrap].
+func WordwrapRules(s string, limit int, breakpoints string, rules *LineBreakRules) string {
+	return WrapOptions{}.WordwrapRules(s, limit, breakpoints, rules)
+}
+
+// WordwrapRules wraps s like [WordwrapRules], with the options.
+func (o WrapOptions) WordwrapRules(s string, limit int, breakpoints string, rules *LineBreakRules) string {
+	if o.Prefix != "" {
+		return o.prefixLines(o.withoutPrefix().WordwrapRules(s, o.prefixLimit(limit), breakpoints, rules))
+	}
+	if o.Reorder {
+		o.Reorder = false
+		return ReorderLines(o.WordwrapRules(s, limit, breakpoints, rules))
+	}
 	if limit < 1 {
 		return s
 	}
@@ -120,6 +218,11 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 		wordLen  int
 		pstate   = parser.GroundState // initial state
 		b        = []byte(s)
+
+		// canBreak reports whether the rules allow a line break after the
+		// last character of the word, and noEnd whether they forbid it.
+		canBreak bool
+		noEnd    bool
 	)
 
 	addSpace := func() {
@@ -148,6 +251,40 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 
 	i := 0
 	for i < len(b) {
+		if pstate == parser.GroundState {
+			// Printable ASCII words are added in bulk. The line breaks at
+			// most once, on the first character that doesn't fit.
+			if n := asciiWordLen(b[i:], breakpoints); n > 0 {
+				if canBreak && !runeContainsAny(rune(b[i]), rules.NoStart) {
+					addWord()
+				}
+				canBreak, noEnd = false, false
+
+				j := limit - curWidth - space.Len() - wordLen + 1
+				if j < 1 {
+					j = 1
+				}
+				word.Write(b[i : i+n])
+				if j <= n && wordLen+j < limit {
+					addNewline()
+				}
+				wordLen += n
+				i += n
+				continue
+			}
+			if b[i] == ESC {
+				if n, seq, width := o.cursorMovement(b[i:]); n > 0 {
+					word.Write(seq)
+					wordLen += width
+					if curWidth+space.Len()+wordLen > limit && wordLen < limit {
+						addNewline()
+					}
+					i += n
+					continue
+				}
+			}
+		}
+
 		state, action := parser.Table.Transition(pstate, b[i])
 		if state == parser.Utf8State {
 			var width int
@@ -158,16 +295,31 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 			if r != utf8.RuneError && unicode.IsSpace(r) && r != nbsp {
 				addWord()
 				space.WriteRune(r)
-			} else if bytes.ContainsAny(cluster, breakpoints) {
+				canBreak, noEnd = false, false
+			} else if runeContainsAny(r, breakpoints) {
 				addSpace()
 				addWord()
 				buf.Write(cluster)
-				curWidth++
+				curWidth += width
+				canBreak, noEnd = false, false
 			} else {
+				if rules != nil {
+					// Lines can break before wide characters, and after
+					// wide characters and the ones that can't start a line.
+					// When the rules keep more characters together than
+					// fit on a line, the line breaks anyway.
+					start := runeContainsAny(r, rules.NoStart)
+					if (canBreak || width > 1 && !noEnd) && !start ||
+						(width > 1 || start) && wordLen+width > limit {
+						addWord()
+					}
+					noEnd = runeContainsAny(r, rules.NoEnd)
+					canBreak = (width > 1 || start) && !noEnd
+				}
 				word.Write(cluster)
 				wordLen += width
 				if curWidth+space.Len()+wordLen > limit &&
-					wordLen < limit {
+					(wordLen < limit || rules != nil && curWidth+space.Len() > 0) {
 					addNewline()
 				}
 			}
@@ -175,6 +327,10 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 			pstate = parser.GroundState
 			continue
 		}
+		if pstate == parser.GroundState && o.normalizeNewline(b, i) {
+			i++
+			continue
+		}
 
 		switch action {
 		case parser.PrintAction, parser.ExecuteAction:
@@ -203,6 +359,9 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 				buf.WriteByte(b[i])
 				curWidth++
 			default:
+				if canBreak && !runeContainsAny(r, rules.NoStart) {
+					addWord()
+				}
 				word.WriteByte(b[i])
 				wordLen++
 				if curWidth+space.Len()+wordLen > limit &&
@@ -210,6 +369,7 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 					addNewline()
 				}
 			}
+			canBreak, noEnd = false, false
 
 		default:
 			word.WriteByte(b[i])
@@ -231,161 +391,346 @@ func Wordwrap(s string, limit int, breakpoints string) string {
 // boundaries if necessary. This will preserve ANSI escape codes and will
 // account for wide-characters in the string. The breakpoints string is a list
 // of characters that are considered breakpoints for word wrapping. A hyphen
-// (-) is always considered a breakpoint.
-//
-// Note: breakpoints must be a string of 1-cell wide rune characters.
+// (-) is always considered a breakpoint. Breakpoints can be any characters,
+// like the breakpoints of [Wordwrap].
 func Wrap(s string, limit int, breakpoints string) string {
+	return WrapOptions{}.Wrap(s, limit, breakpoints)
+}
+
+// Wrap wraps s like [Wrap], with the options.
+func (o WrapOptions) Wrap(s string, limit int, breakpoints string) string {
+	if o.Prefix != "" {
+		return o.prefixLines(o.withoutPrefix().Wrap(s, o.prefixLimit(limit), breakpoints))
+	}
+	if o.Reorder {
+		o.Reorder = false
+		return ReorderLines(o.Wrap(s, limit, breakpoints))
+	}
 	if limit < 1 {
 		return s
 	}
 
-	var (
-		cluster  []byte
-		buf      bytes.Buffer
-		word     bytes.Buffer
-		space    bytes.Buffer
-		curWidth int                  // written width of the line
-		wordLen  int                  // word buffer len without ANSI escape codes
-		pstate   = parser.GroundState // initial state
-		b        = []byte(s)
-	)
+	w := wrapState{limit: limit, breakpoints: breakpoints, opts: o}
+	w.write([]byte(s), true)
+	w.flush()
 
-	addSpace := func() {
-		curWidth += space.Len()
-		buf.Write(space.Bytes())
-		space.Reset()
+	return w.buf.String()
+}
+
+// WrapFunc wraps a string or a block of text like [Wrap], with a limit for
+// each line, so that the text can flow around something, like a sidebar or
+// an image. The limit function is called with the index of each line of the
+// wrapped text, from 0, when the line starts. Limits below 1 are taken as 1.
+//
+//	// Leave room for a 10 cells wide image next to the first 5 lines.
+//	ansi.WrapFunc(s, func(line int) int {
+//		if line < 5 {
+//			return 70
+//		}
+//		return 80
+//	}, "")
+func WrapFunc(s string, limit func(line int) int, breakpoints string) string {
+	return WrapOptions{}.WrapFunc(s, limit, breakpoints)
+}
+
+// WrapFunc wraps s like [WrapFunc], with the options.
+func (o WrapOptions) WrapFunc(s string, limit func(line int) int, breakpoints string) string {
+	if o.Prefix != "" {
+		pw := StringWidth(o.Prefix)
+		return o.prefixLines(o.withoutPrefix().WrapFunc(s, func(line int) int {
+			return limit(line) - pw
+		}, breakpoints))
+	}
+	if o.Reorder {
+		o.Reorder = false
+		return ReorderLines(o.WrapFunc(s, limit, breakpoints))
 	}
 
-	addWord := func() {
-		if word.Len() == 0 {
-			return
-		}
+	w := wrapState{
+		limit:       lineLimit(limit, 0),
+		limitFunc:   limit,
+		breakpoints: breakpoints,
+		opts:        o,
+	}
+	w.write([]byte(s), true)
+	w.flush()
 
-		addSpace()
-		curWidth += wordLen
-		buf.Write(word.Bytes())
-		word.Reset()
-		wordLen = 0
+	return w.buf.String()
+}
+
+// wrapState is the state of [Wrap], kept between chunks of text by
+// [Wrapper].
+type wrapState struct {
+	limit       int
+	limitFunc   func(line int) int // limit of each line, if not nil
+	line        int                // line being written, from 0
+	breakpoints string
+	opts        WrapOptions
+
+	buf      bytes.Buffer
+	word     bytes.Buffer
+	space    bytes.Buffer
+	curWidth int          // written width of the line
+	wordLen  int          // word buffer len without ANSI escape codes
+	pstate   parser.State // parser state, ground by default
+	style    *sgrTracker  // style tracking, if not nil
+}
+
+func (w *wrapState) addSpace() {
+	w.curWidth += w.space.Len()
+	w.buf.Write(w.space.Bytes())
+	w.space.Reset()
+}
+
+func (w *wrapState) addWord() {
+	if w.word.Len() == 0 {
+		return
 	}
 
-	addNewline := func() {
-		buf.WriteByte('\n')
-		curWidth = 0
-		space.Reset()
+	w.addSpace()
+	w.curWidth += w.wordLen
+	w.buf.Write(w.word.Bytes())
+	w.word.Reset()
+	w.wordLen = 0
+}
+
+func (w *wrapState) addNewline() {
+	w.buf.WriteByte('\n')
+	w.curWidth = 0
+	w.space.Reset()
+	w.nextLine()
+}
+
+// nextLine starts a new line, with its own limit when the limit is given per
+// line.
+func (w *wrapState) nextLine() {
+	w.line++
+	if w.limitFunc != nil {
+		w.limit = lineLimit(w.limitFunc, w.line)
+	}
+}
+
+// wordFull reports whether the word fills the line, and has to be
+// hardwrapped. With a limit per line, the word can be longer than the limit
+// of the line it's on.
+func (w *wrapState) wordFull() bool {
+	if w.limitFunc != nil {
+		return w.wordLen >= w.limit
+	}
+	return w.wordLen == w.limit
+}
+
+// lineLimit returns the limit of the given line, at least 1.
+func lineLimit(limit func(line int) int, line int) int {
+	if n := limit(line); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// write wraps b and returns the number of bytes consumed. Unless atEOF, a
+// grapheme cluster at the end of b isn't consumed, since the next chunk of
+// text might continue it.
+func (w *wrapState) write(b []byte, atEOF bool) int {
+	breakpoints := w.breakpoints
+	if !atEOF {
+		// Leave out an incomplete rune at the end.
+		for j := len(b) - 1; j >= 0 && j >= len(b)-utf8.UTFMax; j-- {
+			if utf8.RuneStart(b[j]) {
+				if !utf8.FullRune(b[j:]) {
+					b = b[:j]
+				}
+				break
+			}
+		}
 	}
 
 	i := 0
 	for i < len(b) {
-		state, action := parser.Table.Transition(pstate, b[i])
+		if w.pstate == parser.GroundState {
+			// Printable ASCII words are added in chunks, each ending where
+			// the word needs to be hardwrapped or the line broken.
+			if n := asciiWordLen(b[i:], breakpoints); n > 0 {
+				for run := b[i : i+n]; len(run) > 0; {
+					if w.curWidth == w.limit {
+						w.addNewline()
+					}
+					k := len(run)
+					if m := w.limit - w.wordLen; m >= 1 && m < k {
+						k = m
+					}
+					if m := w.limit - w.curWidth - w.wordLen - w.space.Len() + 1; m < k {
+						k = m
+					}
+					if k < 1 {
+						k = 1
+					}
+
+					w.word.Write(run[:k])
+					w.wordLen += k
+					run = run[k:]
+
+					if w.wordFull() {
+						// Hardwrap the word if it's too long
+						w.addWord()
+					}
+					if w.curWidth+w.wordLen+w.space.Len() > w.limit {
+						w.addNewline()
+					}
+				}
+				i += n
+				continue
+			}
+			if b[i] == ESC {
+				if n, seq, width := w.opts.cursorMovement(b[i:]); n > 0 {
+					if w.wordLen+width > w.limit {
+						// Hardwrap the word if it's too long
+						w.addWord()
+					}
+					w.word.Write(seq)
+					w.wordLen += width
+					if w.curWidth+w.wordLen+w.space.Len() > w.limit {
+						w.addNewline()
+					}
+					i += n
+					continue
+				}
+			}
+		}
+
+		state, action := parser.Table.Transition(w.pstate, b[i])
 		if state == parser.Utf8State {
-			var width int
-			cluster, _, width, _ = uniseg.FirstGraphemeCluster(b[i:], -1)
+			cluster, _, width, _ := uniseg.FirstGraphemeCluster(b[i:], -1)
+			if !atEOF && i+len(cluster) == len(b) {
+				return i
+			}
 			i += len(cluster)
 
 			r, _ := utf8.DecodeRune(cluster)
 			switch {
 			case r != utf8.RuneError && unicode.IsSpace(r) && r != nbsp: // nbsp is a non-breaking space
-				addWord()
-				space.WriteRune(r)
-			case bytes.ContainsAny(cluster, breakpoints):
-				addSpace()
-				if curWidth+wordLen+width > limit {
-					word.Write(cluster)
-					wordLen += width
+				w.addWord()
+				w.space.WriteRune(r)
+			case runeContainsAny(r, breakpoints):
+				w.addSpace()
+				if w.curWidth+w.wordLen+width > w.limit {
+					w.word.Write(cluster)
+					w.wordLen += width
 				} else {
-					addWord()
-					buf.Write(cluster)
-					curWidth += width
+					w.addWord()
+					w.buf.Write(cluster)
+					w.curWidth += width
 				}
 			default:
-				if wordLen+width > limit {
+				if w.wordLen+width > w.limit {
 					// Hardwrap the word if it's too long
-					addWord()
+					w.addWord()
 				}
 
-				word.Write(cluster)
-				wordLen += width
+				w.word.Write(cluster)
+				w.wordLen += width
 
-				if curWidth+wordLen+space.Len() > limit {
-					addNewline()
+				if w.curWidth+w.wordLen+w.space.Len() > w.limit {
+					w.addNewline()
 				}
 			}
 
-			pstate = parser.GroundState
+			w.pstate = parser.GroundState
 			continue
 		}
+		if w.pstate == parser.GroundState && b[i] == CR {
+			if !atEOF && i+1 == len(b) {
+				// The next chunk of text might start with "\n".
+				return i
+			}
+			if w.opts.normalizeNewline(b, i) {
+				i++
+				continue
+			}
+		}
 
 		switch action {
 		case parser.PrintAction, parser.ExecuteAction:
 			switch r := rune(b[i]); {
 			case r == '\n':
-				if wordLen == 0 {
-					if curWidth+space.Len() > limit {
-						curWidth = 0
+				if w.wordLen == 0 {
+					if w.curWidth+w.space.Len() > w.limit {
+						w.curWidth = 0
 					} else {
 						// preserve whitespaces
-						buf.Write(space.Bytes())
+						w.buf.Write(w.space.Bytes())
 					}
-					space.Reset()
+					w.space.Reset()
 				}
 
-				addWord()
-				addNewline()
+				w.addWord()
+				w.addNewline()
 			case unicode.IsSpace(r):
-				addWord()
-				space.WriteRune(r)
+				w.addWord()
+				w.space.WriteRune(r)
 			case r == '-':
 				fallthrough
 			case runeContainsAny(r, breakpoints):
-				addSpace()
-				if curWidth+wordLen >= limit {
+				w.addSpace()
+				if w.curWidth+w.wordLen >= w.limit {
 					// We can't fit the breakpoint in the current line, treat
 					// it as part of the word.
-					word.WriteRune(r)
-					wordLen++
+					w.word.WriteRune(r)
+					w.wordLen++
 				} else {
-					addWord()
-					buf.WriteRune(r)
-					curWidth++
+					w.addWord()
+					w.buf.WriteRune(r)
+					w.curWidth++
 				}
 			default:
-				if curWidth == limit {
-					addNewline()
+				if w.curWidth == w.limit {
+					w.addNewline()
 				}
-				word.WriteRune(r)
-				wordLen++
+				w.word.WriteRune(r)
+				w.wordLen++
 
-				if wordLen == limit {
+				if w.wordFull() {
 					// Hardwrap the word if it's too long
-					addWord()
+					w.addWord()
 				}
 
-				if curWidth+wordLen+space.Len() > limit {
-					addNewline()
+				if w.curWidth+w.wordLen+w.space.Len() > w.limit {
+					w.addNewline()
 				}
 			}
 
 		default:
-			word.WriteByte(b[i])
+			w.word.WriteByte(b[i])
+			if w.style != nil {
+				w.style.track(w.pstate, state, action, b[i])
+			}
 		}
 
 		// We manage the UTF8 state separately manually above.
-		if pstate != parser.Utf8State {
-			pstate = state
+		if w.pstate != parser.Utf8State {
+			w.pstate = state
 		}
 		i++
 	}
 
-	if word.Len() != 0 {
+	return i
+}
+
+// flush writes out the pending word, as if the text ended here.
+func (w *wrapState) flush() {
+	if w.word.Len() != 0 {
 		// Preserve ANSI wrapped spaces at the end of string
-		if curWidth+space.Len() > limit {
-			buf.WriteByte('\n')
+		if w.curWidth+w.space.Len() > w.limit {
+			w.buf.WriteByte('\n')
+			w.curWidth = 0
+			w.nextLine()
 		}
-		addSpace()
+		w.addSpace()
 	}
-	buf.Write(word.Bytes())
-
-	return buf.String()
+	w.curWidth += w.wordLen
+	w.buf.Write(w.word.Bytes())
+	w.word.Reset()
+	w.wordLen = 0
 }
 
 func runeContainsAny(r rune, s string) bool {
@@ -1,6 +1,8 @@
 package ansi_test
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -72,6 +74,8 @@ var wwCases = []struct {
 	{"style_code_dont_affect_length", "\x1B[38;2;249;38;114mfoo\x1B[0m\x1B[38;2;248;248;242m \x1B[0m\x1B[38;2;230;219;116mbar\x1B[0m", 7, "", "\x1B[38;2;249;38;114mfoo\x1B[0m\x1B[38;2;248;248;242m \x1B[0m\x1B[38;2;230;219;116mbar\x1B[0m"},
 	{"style_code_dont_get_wrapped", "\x1B[38;2;249;38;114m(\x1B[0m\x1B[38;2;248;248;242mjust another test\x1B[38;2;249;38;114m)\x1B[0m", 3, "", "\x1B[38;2;249;38;114m(\x1B[0m\x1B[38;2;248;248;242mjust\nanother\ntest\x1B[38;2;249;38;114m)\x1B[0m"},
 	{"osc8_wrap", "สวัสดีสวัสดี\x1b]8;;https://example.com\x1b\\ สวัสดีสวัสดี\x1b]8;;\x1b\\", 8, "", "สวัสดีสวัสดี\x1b]8;;https://example.com\x1b\\\nสวัสดีสวัสดี\x1b]8;;\x1b\\"},
+	{"wide_breakpoint", "ab、cdef", 7, "、", "ab、\ncdef"},
+	{"mixed_breakpoints", "foo/bar、bazqux", 8, "/、", "foo/bar、\nbazqux"},
 }
 
 func TestWordwrap(t *testing.T) {
@@ -84,6 +88,52 @@ func TestWordwrap(t *testing.T) {
 	}
 }
 
+var kinsokuCases = []struct {
+	name     string
+	input    string
+	limit    int
+	expected string
+}{
+	{"no start", "吾輩は猫である。名前はまだ無い。", 10, "吾輩は猫で\nある。名前\nはまだ無\nい。"},
+	{"closing bracket", "「こんにちは」と言った。", 8, "「こんに\nちは」と\n言った。"},
+	{"opening bracket", "これは「テスト」です。", 6, "これは\n「テス\nト」で\nす。"},
+	{"mixed", "日本語とEnglishの混在テキスト。", 10, "日本語と\nEnglishの\n混在テキス\nト。"},
+	{"styled", "\x1b[1m吾輩は猫である。\x1b[m", 6, "\x1b[1m吾輩は\n猫であ\nる。\x1b[m"},
+	{"spaces", "hello world foo bar", 7, "hello\nworld\nfoo bar"},
+	{"push out", "「こんにちは」と言った。", 4, "「こ\nんに\nち\nは」\nと\n言っ\nた。"},
+	{"too narrow for rules", "吾輩は猫である。", 3, "吾\n輩\nは\n猫\nで\nあ\nる\n。"},
+	{"narrower than a character", "猫です。", 1, "猫\nで\nす\n。"},
+	{"brackets longer than a line", "あ「「「「い", 4, "あ\n「「\n「「\nい"},
+}
+
+func TestWordwrapRules(t *testing.T) {
+	for i, tt := range kinsokuCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.WordwrapRules(tt.input, tt.limit, "", ansi.Kinsoku()); got != tt.expected {
+				t.Errorf("case %d, expected %q, got %q", i+1, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestKinsoku(t *testing.T) {
+	rules := ansi.Kinsoku()
+	rules.NoStart = ""
+	if ansi.Kinsoku().NoStart == "" {
+		t.Error("changing the returned rules changed the next ones")
+	}
+}
+
+func TestWordwrapRulesNil(t *testing.T) {
+	for i, tt := range wwCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.WordwrapRules(tt.input, tt.limit, tt.breakPoints, nil); got != tt.expected {
+				t.Errorf("case %d, expected %q, got %q", i+1, tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestWrapWordwrap(t *testing.T) {
 	input := "the quick brown foxxxxxxxxxxxxxxxx jumped over the lazy dog."
 	limit := 16
@@ -210,6 +260,8 @@ var wrapCases = []struct {
 	{"style_code_dont_affect_length", "\x1B[38;2;249;38;114mfoo\x1B[0m\x1B[38;2;248;248;242m \x1B[0m\x1B[38;2;230;219;116mbar\x1B[0m", "\x1B[38;2;249;38;114mfoo\x1B[0m\x1B[38;2;248;248;242m \x1B[0m\x1B[38;2;230;219;116mbar\x1B[0m", 7},
 	{"style_code_dont_get_wrapped", "\x1B[38;2;249;38;114m(\x1B[0m\x1B[38;2;248;248;242mjust another test\x1B[38;2;249;38;114m)\x1B[0m", "\x1b[38;2;249;38;114m(\x1b[0m\x1b[38;2;248;248;242mjust\nanother\ntest\x1b[38;2;249;38;114m)\x1b[0m", 7},
 	{"osc8_wrap", "สวัสดีสวัสดี\x1b]8;;https://example.com\x1b\\ สวัสดีสวัสดี\x1b]8;;\x1b\\", "สวัสดีสวัสดี\x1b]8;;https://example.com\x1b\\\nสวัสดีสวัสดี\x1b]8;;\x1b\\", 8},
+	{"crlf", "foo bar\r\nbaz\r\n", "foo\nbar\nbaz\n", 5},
+	{"cr", "foo\rbar baz\r", "foo\nbar\nbaz\n", 5},
 }
 
 func TestWrap(t *testing.T) {
@@ -222,3 +274,253 @@ func TestWrap(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapFunc(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		limit    func(line int) int
+		expected string
+	}{
+		{
+			"sidebar",
+			"the quick brown fox jumps over the lazy dog",
+			func(line int) int {
+				if line < 2 {
+					return 6
+				}
+				return 12
+			},
+			"the\nquick\nbrown fox\njumps over\nthe lazy dog",
+		},
+		{
+			"narrowing",
+			"abcdefghijklmnopqrstuvwxyz",
+			func(line int) int { return 8 - 2*line },
+			"abcdefgh\nijklmn\nopqr\nst\nu\nv\nw\nx\ny\nz",
+		},
+		{
+			"constant",
+			"abc def\nghi jkl",
+			func(int) int { return 3 },
+			ansi.Wrap("abc def\nghi jkl", 3, ""),
+		},
+		{
+			"wide",
+			"ab 日本語",
+			func(line int) int { return 4 - line },
+			"ab\n日\n本\n語",
+		},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ansi.WrapFunc(tc.input, tc.limit, ""); got != tc.expected {
+				t.Errorf("case %d, expected %q, got %q", i+1, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWrapPrefix(t *testing.T) {
+	quote := ansi.WrapOptions{Prefix: "> "}
+	cases := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"wrap", quote.Wrap("the quick brown fox jumps", 10, ""), "> the\n> quick\n> brown\n> fox\n> jumps"},
+		{"hardwrap", quote.Hardwrap("the quick brown fox", 10, false), "> the quic\n> k brown \n> fox"},
+		{"wordwrap", quote.Wordwrap("the quick brown fox", 10, ""), "> the\n> quick\n> brown\n> fox"},
+		{"empty", quote.Wrap("", 10, ""), "> "},
+		{"no limit", quote.Wrap("a b\nc", 0, ""), "> a b\n> c"},
+		{
+			"wrap func",
+			quote.WrapFunc("the quick brown fox", func(line int) int { return 8 + line*4 }, ""),
+			"> the\n> quick\n> brown fox",
+		},
+		{
+			"text style",
+			quote.Wrap("\x1b[31mthe quick brown\x1b[m fox", 10, ""),
+			"> \x1b[31mthe\n\x1b[m> \x1b[31mquick\n\x1b[m> \x1b[31mbrown\x1b[m\n> fox",
+		},
+		{
+			"prefix style",
+			ansi.WrapOptions{Prefix: "\x1b[2m│\x1b[m "}.Wrap("\x1b[1mhello world\x1b[m", 9, ""),
+			"\x1b[2m│\x1b[m \x1b[1mhello\n\x1b[m\x1b[2m│\x1b[m \x1b[1mworld\x1b[m",
+		},
+		{
+			"prefix style not reset",
+			ansi.WrapOptions{Prefix: "\x1b[2m│ "}.Wrap("hello world", 9, ""),
+			"\x1b[2m│ \x1b[mhello\n\x1b[2m│ \x1b[mworld",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestWrapReorder(t *testing.T) {
+	bidi := ansi.WrapOptions{Reorder: true}
+	cases := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"wrap", bidi.Wrap("שלום עולם hello", 10, ""), "םלוע םולש\nhello"},
+		{"hardwrap", bidi.Hardwrap("שלום עולם", 5, false), " םולש\nםלוע"},
+		{"wordwrap", bidi.Wordwrap("hello שלום עולם", 10, ""), "hello םולש\nםלוע"},
+		{"wrap func", bidi.WrapFunc("שלום עולם hello", func(int) int { return 10 }, ""), "םלוע םולש\nhello"},
+		{"ltr", bidi.Wrap("hello world", 5, ""), "hello\nworld"},
+		{"prefix", ansi.WrapOptions{Prefix: "> ", Reorder: true}.Wrap("שלום עולם", 7, ""), "> םולש\n> םלוע"},
+		{
+			"style",
+			bidi.Wrap("\x1b[1mשלום\x1b[m עולם", 10, ""),
+			"םלוע \x1b[1mםולש\x1b[m",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestWrapBreakpoints(t *testing.T) {
+	cases := []struct {
+		name        string
+		input       string
+		limit       int
+		breakpoints string
+		expected    string
+	}{
+		{"wide_breakpoint", "ab、cdef", 7, "、", "ab、\ncdef"},
+		{"mixed_breakpoints", "foo/bar、bazqux", 8, "/、", "foo/\nbar、baz\nqux"},
+		{"runes", "foo/bar、bazqux", 8, string([]rune{'/', '、'}), "foo/\nbar、baz\nqux"},
+		{"space_breakpoint", " ab-cd…", 4, " ", " ab-\ncd…"},
+		{"hardwrap_hyphen", "abcdefgh-ij", 4, " ", "abcd\nefgh\n-ij"},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ansi.Wrap(tc.input, tc.limit, tc.breakpoints); got != tc.expected {
+				t.Errorf("case %d, expected %q, got %q", i+1, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWrapNewlines(t *testing.T) {
+	const input = "foo bar\r\nbaz\rqux"
+	const expect = "foo\nbar\nbaz\nqux"
+	if got := ansi.Hardwrap(input, 5, false); got != "foo b\nar\nbaz\nqux" {
+		t.Errorf("Hardwrap: expected %q, got %q", "foo b\nar\nbaz\nqux", got)
+	}
+	if got := ansi.Wordwrap(input, 5, ""); got != expect {
+		t.Errorf("Wordwrap: expected %q, got %q", expect, got)
+	}
+	if got := ansi.Wrap(input, 5, ""); got != expect {
+		t.Errorf("Wrap: expected %q, got %q", expect, got)
+	}
+
+	opts := ansi.WrapOptions{KeepCarriageReturns: true}
+	if got := opts.Wrap(input, 5, ""); got != "foo\nbar\r\nbaz\nqux" {
+		t.Errorf("KeepCarriageReturns: expected %q, got %q", "foo\nbar\r\nbaz\nqux", got)
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"empty", "", ""},
+		{"lf", "foo\nbar", "foo\nbar"},
+		{"crlf", "foo\r\nbar\r\n", "foo\nbar\n"},
+		{"cr", "foo\rbar\r", "foo\nbar\n"},
+		{"cr before crlf", "foo\r\r\nbar", "foo\n\nbar"},
+		{"style", "\x1b[1mfoo\r\n\x1b[mbar", "\x1b[1mfoo\n\x1b[mbar"},
+		{"osc", "\x1b]2;foo\rbar\x07\r\n", "\x1b]2;foo\rbar\x07\n"},
+		{"unicode", "café\r\n日本", "café\n日本"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ansi.NormalizeNewlines(tc.input); got != tc.expect {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+var cursorMovementCases = []struct {
+	name     string
+	mode     ansi.CursorMovement
+	hardwrap string
+	wordwrap string
+	wrap     string
+}{
+	{"keep", ansi.KeepCursorMovement, "ab\x1b[3Ccd\nef", "ab\x1b[3Ccd\nef", "ab\x1b[3Ccd\nef"},
+	{"strip", ansi.StripCursorMovement, "abcd\nef", "abcd\nef", "abcd\nef"},
+	{"count", ansi.CountCursorMovement, "ab\n\x1b[3Cc\nd ef", "ab\x1b[3Ccd\nef", "ab\x1b[3Cc\nd ef"},
+}
+
+func TestWrapCursorMovement(t *testing.T) {
+	const input = "ab\x1b[3Ccd ef"
+	for _, tc := range cursorMovementCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := ansi.WrapOptions{CursorMovement: tc.mode}
+			if got := opts.Hardwrap(input, 4, false); got != tc.hardwrap {
+				t.Errorf("Hardwrap: expected %q, got %q", tc.hardwrap, got)
+			}
+			if got := opts.Wordwrap(input, 6, ""); got != tc.wordwrap {
+				t.Errorf("Wordwrap: expected %q, got %q", tc.wordwrap, got)
+			}
+			if got := opts.Wrap(input, 6, ""); got != tc.wrap {
+				t.Errorf("Wrap: expected %q, got %q", tc.wrap, got)
+			}
+		})
+	}
+}
+
+func TestWrapOnCursorMovement(t *testing.T) {
+	var seqs []string
+	opts := ansi.WrapOptions{OnCursorMovement: func(seq string) {
+		seqs = append(seqs, seq)
+	}}
+	input := "a\x1b[2;3Hb\x1b[1mc\x1b[D"
+	if got := opts.Wrap(input, 10, ""); got != input {
+		t.Errorf("expected %q, got %q", input, got)
+	}
+	expect := []string{"\x1b[2;3H", "\x1b[D"}
+	if !reflect.DeepEqual(seqs, expect) {
+		t.Errorf("expected %q, got %q", expect, seqs)
+	}
+}
+
+var benchText = strings.Repeat("The quick brown fox jumps over the lazy dog, \x1b[1magain\x1b[m and again. ", 20)
+
+func BenchmarkHardwrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ansi.Hardwrap(benchText, 40, false)
+	}
+}
+
+func BenchmarkWordwrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ansi.Wordwrap(benchText, 40, "")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ansi.Wrap(benchText, 40, "")
+	}
+}
@@ -0,0 +1,185 @@
+package ansi
+
+import "strings"
+
+// CursorMovement is how the wrap functions handle sequences that move the
+// cursor, like CUF, CUB, and CUP. Their effect on the line isn't known when
+// the text is wrapped, so a line that moves the cursor can end up wider or
+// narrower than the limit.
+type CursorMovement int
+
+const (
+	// KeepCursorMovement keeps the cursor movement sequences, without
+	// counting their effect. It's the default.
+	KeepCursorMovement CursorMovement = iota
+
+	// StripCursorMovement removes the cursor movement sequences.
+	StripCursorMovement
+
+	// CountCursorMovement counts the sequences that move the cursor forward
+	// on the line, CUF (Cursor Forward) and HPR (Horizontal Position
+	// Relative), as the cells they move over. The other ones are kept
+	// without counting their effect.
+	CountCursorMovement
+)
+
+// WrapOptions are options of the wrap functions. The zero value is the
+// default behavior of [Hardwrap], [Wordwrap], [WordwrapRules], and [Wrap].
+//
+//	opts := ansi.WrapOptions{CursorMovement: ansi.StripCursorMovement}
+//	opts.Wrap(s, 80, "")
+type WrapOptions struct {
+	// CursorMovement is how cursor movement sequences are handled.
+	CursorMovement CursorMovement
+
+	// OnCursorMovement, if not nil, is called with each cursor movement
+	// sequence found in the text, so that callers can tell the wrapped text
+	// might not fit.
+	OnCursorMovement func(seq string)
+
+	// KeepCarriageReturns keeps carriage returns in the text, counted as
+	// spaces. By default, line endings are normalized like
+	// [NormalizeNewlines] does: "\r\n" and a lone "\r" break the line like
+	// "\n", and the wrapped text only has "\n" line endings.
+	KeepCarriageReturns bool
+
+	// Prefix is written at the start of every line of the wrapped text, like
+	// "> " for a blockquote, or "│ " for a message in a chat. Its width is
+	// counted against the limit. It can have its own style, which doesn't
+	// apply to the text, and the style of the text doesn't apply to it.
+	//
+	//	opts := ansi.WrapOptions{Prefix: "\x1b[2m│\x1b[m "}
+	//	opts.Wrap(message, 40, "")
+	Prefix string
+
+	// Reorder reorders each line of the wrapped text for display with
+	// [ReorderLine], so that right to left text, like Hebrew and Arabic,
+	// shows right to left. The text is broken into lines in logical order
+	// first, and the prefix isn't reordered.
+	Reorder bool
+}
+
+// withoutPrefix returns the options without the prefix, to wrap the text
+// before the prefix is added.
+func (o WrapOptions) withoutPrefix() WrapOptions {
+	o.Prefix = ""
+	return o
+}
+
+// prefixLimit returns the limit of the text next to the prefix, at least 1.
+// A limit below 1 doesn't wrap the text, and is left as is.
+func (o WrapOptions) prefixLimit(limit int) int {
+	if limit < 1 {
+		return limit
+	}
+	if n := limit - StringWidth(o.Prefix); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// prefixLines writes the prefix at the start of each line of s. The style
+// of the text is reset before the prefix, and set again after it, and so is
+// the style the prefix leaves.
+func (o WrapOptions) prefixLines(s string) string {
+	var prefixStyle, style sgrTracker
+	prefix := o.Prefix
+	prefixStyle.trackSequence(prefix)
+	if len(prefixStyle.style) > 0 {
+		prefix += ResetStyle
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(s) + (strings.Count(s, "\n")+1)*len(prefix))
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		if len(style.style) > 0 {
+			buf.WriteString(ResetStyle)
+			buf.WriteString(prefix)
+			buf.WriteString(style.style.String())
+		} else {
+			buf.WriteString(prefix)
+		}
+		buf.WriteString(line)
+		style.trackSequence(line)
+	}
+
+	return buf.String()
+}
+
+// normalizeNewline normalizes the line ending at b[i], unless carriage
+// returns are kept. It reports whether b[i] is the carriage return of a
+// "\r\n" line ending, to be skipped, and turns a lone one into "\n".
+func (o WrapOptions) normalizeNewline(b []byte, i int) bool {
+	if o.KeepCarriageReturns || b[i] != CR {
+		return false
+	}
+	if i+1 < len(b) && b[i+1] == LF {
+		return true
+	}
+	b[i] = LF
+	return false
+}
+
+// cursorMovement returns the length of the cursor movement sequence at the
+// start of b, when the options handle them, and the sequence and the width to
+// wrap in its place. The sequence is nil when it's stripped.
+func (o WrapOptions) cursorMovement(b []byte) (n int, seq []byte, width int) {
+	if o.CursorMovement == KeepCursorMovement && o.OnCursorMovement == nil {
+		return 0, nil, 0
+	}
+
+	n, cells := cursorMovementLen(b)
+	if n == 0 {
+		return 0, nil, 0
+	}
+	if o.OnCursorMovement != nil {
+		o.OnCursorMovement(string(b[:n]))
+	}
+
+	switch o.CursorMovement {
+	case StripCursorMovement:
+		return n, nil, 0
+	case CountCursorMovement:
+		return n, b[:n], cells
+	}
+	return n, b[:n], 0
+}
+
+// cursorMovementLen returns the length of the CSI cursor movement sequence at
+// the start of b, or zero if there's none, and the number of cells it moves
+// the cursor forward on the line.
+func cursorMovementLen(b []byte) (n, cells int) {
+	if len(b) < 3 || b[0] != ESC || b[1] != '[' {
+		return 0, 0
+	}
+
+	for i := 2; i < len(b); i++ {
+		c := b[i]
+		if c >= '0' && c <= '9' || c == ';' {
+			continue
+		}
+		// CUU, CUD, CUF, CUB, CNL, CPL, CHA, CUP, CHT, CBT, HPA, HPR,
+		// VPA, VPR, and HVP.
+		if strings.IndexByte("ABCDEFGHIZ`adef", c) < 0 {
+			return 0, 0
+		}
+		if c == 'C' || c == 'a' {
+			cells = 0
+			for _, d := range b[2:i] {
+				if d == ';' {
+					break
+				}
+				cells = cells*10 + int(d-'0')
+			}
+			if cells == 0 {
+				cells = 1
+			}
+		}
+		return i + 1, cells
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,256 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// Wrapper wraps text that arrives in chunks, like the output of a command
+// shown in a log viewer, the way [Wrap] wraps a whole string. Each chunk is
+// wrapped on its own, using the state left by the previous ones, so the cost
+// of appending text doesn't grow with the text already wrapped.
+//
+//	w := ansi.NewWrapper(80, "")
+//	for line := range lines {
+//		view.Append(w.Append(line))
+//	}
+//	view.Append(w.Flush())
+//
+// Text that can't be placed yet, like the last word of a chunk, which the
+// next chunk might continue, stays pending until more text arrives or
+// [Wrapper.Flush] is called. The concatenated output is the same as the
+// output of [Wrap] on the concatenated text.
+type Wrapper struct {
+	w       wrapState
+	style   sgrTracker
+	pending []byte // pending is text not consumed yet.
+}
+
+// NewWrapper returns a new wrapper that wraps text to the given line length.
+// The breakpoints are used like the breakpoints of [Wrap].
+func NewWrapper(limit int, breakpoints string) *Wrapper {
+	w := &Wrapper{}
+	w.w.limit = limit
+	w.w.breakpoints = breakpoints
+	w.w.style = &w.style
+	return w
+}
+
+// Append wraps s, appended to the text written so far, and returns the newly
+// wrapped output.
+func (w *Wrapper) Append(s string) string {
+	if w.w.limit < 1 {
+		return s
+	}
+
+	w.pending = append(w.pending, s...)
+	n := w.w.write(w.pending, false)
+	w.pending = append(w.pending[:0], w.pending[n:]...)
+	return w.output()
+}
+
+// Flush returns the pending text, as if the text ended here. Text appended
+// afterwards continues the current line.
+func (w *Wrapper) Flush() string {
+	if w.w.limit < 1 {
+		return ""
+	}
+
+	w.w.write(w.pending, true)
+	w.pending = w.pending[:0]
+	w.w.flush()
+	return w.output()
+}
+
+// Width returns the width of the last line of the output so far, not
+// counting pending text.
+func (w *Wrapper) Width() int {
+	return w.w.curWidth
+}
+
+// Style returns the SGR style in effect after the text appended so far. Use
+// it to render the wrapped output from the middle, like from the first
+// visible line of a scrolled view.
+func (w *Wrapper) Style() Style {
+	return append(Style(nil), w.style.style...)
+}
+
+// output returns the output wrapped so far and clears it.
+func (w *Wrapper) output() string {
+	s := w.w.buf.String()
+	w.w.buf.Reset()
+	return s
+}
+
+// sgrTracker keeps track of the SGR style set by the sequences it sees. The
+// style is kept as the attributes and colors it sets, so it doesn't grow with
+// the number of sequences.
+type sgrTracker struct {
+	style  Style  // style is the tracked style, nil when it's the default.
+	params []byte // params of the CSI sequence being read
+	sgr    bool   // whether the CSI sequence can be a SGR sequence
+
+	attrs      sgrAttrs
+	underline  string // underline is the underline parameter, like "4:3".
+	fg, bg, ul string // fg, bg, and ul are the color parameters.
+}
+
+// sgrAttrs are the attributes of a style tracked by sgrTracker.
+type sgrAttrs uint16
+
+const (
+	sgrBold sgrAttrs = 1 << iota
+	sgrFaint
+	sgrItalic
+	sgrBlink
+	sgrRapidBlink
+	sgrReverse
+	sgrConceal
+	sgrStrike
+	sgrOverline
+)
+
+// sgrAttrParams are the attributes, in the order they're written, and the
+// SGR parameters that set them.
+var sgrAttrParams = [...]struct {
+	attr  sgrAttrs
+	param int
+}{
+	{sgrBold, 1},
+	{sgrFaint, 2},
+	{sgrItalic, 3},
+	{sgrBlink, 5},
+	{sgrRapidBlink, 6},
+	{sgrReverse, 7},
+	{sgrConceal, 8},
+	{sgrStrike, 9},
+	{sgrOverline, 53},
+}
+
+// track updates the style with the byte b of an escape sequence. from and
+// to are the parser states before and after b, and action is the parser
+// action for b.
+func (t *sgrTracker) track(from, to parser.State, action parser.Action, b byte) {
+	switch {
+	case to == parser.CsiEntryState && from != parser.CsiEntryState:
+		t.params = t.params[:0]
+		t.sgr = true
+	case from == parser.CsiEntryState, from == parser.CsiParamState, from == parser.CsiIntermediateState:
+		switch action {
+		case parser.ParamAction:
+			t.params = append(t.params, b)
+		case parser.DispatchAction:
+			if t.sgr && b == 'm' {
+				t.apply(string(t.params))
+			}
+		default:
+			// Private markers and intermediates make it something else.
+			t.sgr = false
+		}
+	}
+}
+
+// trackSequence updates the style with the escape sequence seq.
+func (t *sgrTracker) trackSequence(seq string) {
+	state := parser.GroundState
+	for i := 0; i < len(seq); i++ {
+		next, action := parser.Table.Transition(state, seq[i])
+		t.track(state, next, action, seq[i])
+		state = next
+	}
+}
+
+// apply applies the SGR parameters to the style. Parameters it doesn't know
+// are dropped.
+func (t *sgrTracker) apply(params string) {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		code := f
+		if j := strings.IndexByte(f, ':'); j >= 0 {
+			code = f[:j]
+		}
+
+		switch code {
+		case "38", "48", "58":
+			_, size, ok := readSgrColor(fields[i:])
+			if !ok {
+				// The rest of the parameters are the ones of a color
+				// that's missing some.
+				i = len(fields)
+				continue
+			}
+			color := strings.Join(fields[i:i+size], ";")
+			i += size - 1
+			switch code {
+			case "38":
+				t.fg = color
+			case "48":
+				t.bg = color
+			default:
+				t.ul = color
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(code)
+		if err != nil && code != "" {
+			continue
+		}
+		switch {
+		case n == 0:
+			*t = sgrTracker{params: t.params, sgr: t.sgr}
+		case n == 4 && f == "4:0", n == 24:
+			t.underline = ""
+		case n == 4, n == 21:
+			t.underline = f
+		case n == 22:
+			t.attrs &^= sgrBold | sgrFaint
+		case n == 23:
+			t.attrs &^= sgrItalic
+		case n == 25:
+			t.attrs &^= sgrBlink | sgrRapidBlink
+		case n == 27:
+			t.attrs &^= sgrReverse
+		case n == 28:
+			t.attrs &^= sgrConceal
+		case n == 29:
+			t.attrs &^= sgrStrike
+		case n == 55:
+			t.attrs &^= sgrOverline
+		case n >= 30 && n <= 37, n >= 90 && n <= 97:
+			t.fg = f
+		case n == 39:
+			t.fg = ""
+		case n >= 40 && n <= 47, n >= 100 && n <= 107:
+			t.bg = f
+		case n == 49:
+			t.bg = ""
+		case n == 59:
+			t.ul = ""
+		default:
+			for _, a := range sgrAttrParams {
+				if a.param == n {
+					t.attrs |= a.attr
+				}
+			}
+		}
+	}
+
+	t.style = t.style[:0]
+	for _, a := range sgrAttrParams {
+		if t.attrs&a.attr != 0 {
+			t.style = append(t.style, strconv.Itoa(a.param))
+		}
+	}
+	for _, p := range [...]string{t.underline, t.fg, t.bg, t.ul} {
+		if p != "" {
+			t.style = append(t.style, p)
+		}
+	}
+	if len(t.style) == 0 {
+		t.style = nil
+	}
+}
@@ -0,0 +1,122 @@
+package ansi_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWrapperChunks(t *testing.T) {
+	for i, tc := range wrapCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Split the input in two at every byte, including in the
+			// middle of runes and escape sequences.
+			for k := 0; k <= len(tc.input); k++ {
+				w := ansi.NewWrapper(tc.width, "")
+				output := w.Append(tc.input[:k]) + w.Append(tc.input[k:]) + w.Flush()
+				if output != tc.expected {
+					t.Fatalf("case %d, input %q split at %d, expected %q, got %q", i+1, tc.input, k, tc.expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapperByteByByte(t *testing.T) {
+	input := "foo 👨‍👩‍👦 \x1b[1mbar\x1b[m ex́ample\nlonger line here"
+	expected := ansi.Wrap(input, 6, "")
+
+	w := ansi.NewWrapper(6, "")
+	var output string
+	for i := 0; i < len(input); i++ {
+		output += w.Append(input[i : i+1])
+	}
+	output += w.Flush()
+	if output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestWrapperPending(t *testing.T) {
+	w := ansi.NewWrapper(10, "")
+	if out := w.Append("hello wor"); out != "hello" {
+		t.Errorf("expected %q, got %q", "hello", out)
+	}
+	if out := w.Append("ld and more"); out != "\nworld and\n" {
+		t.Errorf("expected %q, got %q", "\nworld and\n", out)
+	}
+	if out := w.Flush(); out != "more" {
+		t.Errorf("expected %q, got %q", "more", out)
+	}
+	if w.Width() != 4 {
+		t.Errorf("expected width 4, got %d", w.Width())
+	}
+}
+
+func TestWrapperStyle(t *testing.T) {
+	cases := []struct {
+		name   string
+		chunks []string
+		style  ansi.Style
+	}{
+		{"none", []string{"foo"}, nil},
+		{"bold", []string{"\x1b[1mfoo"}, ansi.Style{"1"}},
+		{"accumulated", []string{"\x1b[1mfoo \x1b[38;5;1mbar"}, ansi.Style{"1", "38;5;1"}},
+		{"reset", []string{"\x1b[1mfoo\x1b[m bar"}, nil},
+		{"reset zero", []string{"\x1b[1mfoo\x1b[0m bar"}, nil},
+		{"reset and set", []string{"\x1b[1mfoo\x1b[0;3m bar"}, ansi.Style{"3"}},
+		{"split", []string{"\x1b[1mfoo \x1b[3", "1mbar"}, ansi.Style{"1", "31"}},
+		{"not sgr", []string{"\x1b[1mfoo\x1b[?1m\x1b[2 m"}, ansi.Style{"1"}},
+		{"replaced color", []string{"\x1b[31mfoo \x1b[32mbar \x1b[38;2;1;2;3mbaz"}, ansi.Style{"38;2;1;2;3"}},
+		{"colors", []string{"\x1b[31;44;58:5:1;4:3mfoo"}, ansi.Style{"4:3", "31", "44", "58:5:1"}},
+		{"default colors", []string{"\x1b[31;44;58;5;1mfoo\x1b[39;49;59m"}, nil},
+		{"partial reset", []string{"\x1b[1;2;3;9mfoo\x1b[22;29m"}, ansi.Style{"3"}},
+		{"underline reset", []string{"\x1b[1;4mfoo\x1b[24m"}, ansi.Style{"1"}},
+		{"short color", []string{"\x1b[1;38;5mfoo"}, ansi.Style{"1"}},
+		{"repeated", []string{"\x1b[1mfoo\x1b[1m\x1b[1mbar"}, ansi.Style{"1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := ansi.NewWrapper(4, "")
+			for _, s := range c.chunks {
+				w.Append(s)
+			}
+			if got := w.Style(); !reflect.DeepEqual(got, c.style) {
+				t.Errorf("expected %q, got %q", c.style, got)
+			}
+		})
+	}
+}
+
+func TestWrapperStyleBounded(t *testing.T) {
+	// The tracked style doesn't grow with the number of sequences seen.
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&input, "\x1b[1;3%dm\x1b[4%dmx\x1b[22m ", i%8, i%8)
+	}
+	expect := ansi.Style{"37", "47"}
+
+	w := ansi.NewWrapper(10, "")
+	w.Append(input.String())
+	if got := w.Style(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	// Neither does the style prefixed lines are reopened with.
+	out := ansi.WrapOptions{Prefix: "> "}.Wrap(input.String()+"yyyyyyyy yyyyyyyy", 10, "")
+	lines := strings.Split(out, "\n")
+	if last, want := lines[len(lines)-1], ansi.ResetStyle+"> "+expect.String()+"yyyyyyyy"; last != want {
+		t.Errorf("expected the last line to be %q, got %q", want, last)
+	}
+}
+
+func BenchmarkWrapperAppend(b *testing.B) {
+	b.ReportAllocs()
+	w := ansi.NewWrapper(40, "")
+	for i := 0; i < b.N; i++ {
+		w.Append("The quick brown fox jumps over the lazy dog, \x1b[1magain\x1b[m.\n")
+	}
+}
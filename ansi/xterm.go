@@ -17,6 +17,14 @@ func ModifyOtherKeys(mode int) string {
 	return "\x1b[>4;" + strconv.Itoa(mode) + "m"
 }
 
+// ResetModifyOtherKeys resets the modifyOtherKeys mode to the terminal's
+// initial value, as set by the modifyOtherKeys resource.
+//
+//	CSI > 4 m
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Functions-using-CSI-_-ordered-by-the-final-character_s_
+const ResetModifyOtherKeys = "\x1b[>4m"
+
 // DisableModifyOtherKeys disables the modifyOtherKeys mode.
 //
 //	CSI > 4 ; 0 m
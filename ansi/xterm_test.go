@@ -0,0 +1,27 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestModifyOtherKeys(t *testing.T) {
+	cases := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{"ModifyOtherKeys(0)", ansi.ModifyOtherKeys(0), ansi.DisableModifyOtherKeys},
+		{"ModifyOtherKeys(1)", ansi.ModifyOtherKeys(1), ansi.EnableModifyOtherKeys1},
+		{"ModifyOtherKeys(2)", ansi.ModifyOtherKeys(2), "\x1b[>4;2m"},
+		{"ResetModifyOtherKeys", ansi.ResetModifyOtherKeys, "\x1b[>4m"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, c.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package asciicast records and plays terminal sessions in the asciicast v2
+// format of asciinema.
+//
+// A recording is a header, a JSON object, followed by events, one JSON array
+// per line:
+//
+//	{"version": 2, "width": 80, "height": 24}
+//	[0.248848, "o", "\u001b[1;31mHello \u001b[32mWorld!\u001b[0m\n"]
+//	[1.001376, "o", "That was ok\rThis is better."]
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/.
+//
+// Recordings convert to and from the ttyrec format, see [FromTtyrec] and
+// [ToTtyrec].
+package asciicast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Version is the version of the asciicast format of this package.
+const Version = 2
+
+var (
+	// ErrUnsupportedVersion is returned when reading a recording of another
+	// version of the asciicast format.
+	ErrUnsupportedVersion = errors.New("unsupported asciicast version")
+
+	// ErrInvalidEvent is returned when reading a malformed event.
+	ErrInvalidEvent = errors.New("invalid asciicast event")
+)
+
+// Header is the header of a recording.
+type Header struct {
+	// Version is the version of the format, always 2.
+	Version int `json:"version"`
+
+	// Width and Height are the size of the terminal, in cells.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// Timestamp is the time the recording started, in seconds since the
+	// Unix epoch.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Duration is the duration of the recording, in seconds.
+	Duration float64 `json:"duration,omitempty"`
+
+	// IdleTimeLimit is the maximum time between events when playing the
+	// recording, in seconds. Zero means no limit.
+	IdleTimeLimit float64 `json:"idle_time_limit,omitempty"`
+
+	// Command is the command that was recorded.
+	Command string `json:"command,omitempty"`
+
+	// Title is the title of the recording.
+	Title string `json:"title,omitempty"`
+
+	// Env holds the environment variables of the recorded session, usually
+	// SHELL and TERM.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// EventType is the type of an event.
+type EventType string
+
+// Event types.
+const (
+	// OutputEvent is data written to the terminal.
+	OutputEvent EventType = "o"
+
+	// InputEvent is data read from the terminal, usually key presses.
+	InputEvent EventType = "i"
+
+	// MarkerEvent is a marker, a point of interest, with a label as data.
+	MarkerEvent EventType = "m"
+
+	// ResizeEvent is a terminal resize, with the new size as data, like
+	// "80x24".
+	ResizeEvent EventType = "r"
+)
+
+// Event is an event of a recording.
+type Event struct {
+	// Time is the time of the event since the start of the recording.
+	Time time.Duration
+
+	// Type is the type of the event.
+	Type EventType
+
+	// Data is the data of the event.
+	Data string
+}
+
+// Size returns the size of a resize event.
+func (e Event) Size() (width, height int, ok bool) {
+	if e.Type != ResizeEvent {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(e.Data, "%dx%d", &width, &height); err != nil {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// MarshalJSON implements json.Marshaler. The event is an array of its time,
+// in seconds, type, and data.
+func (e Event) MarshalJSON() ([]byte, error) {
+	b := []byte{'['}
+	b = strconv.AppendFloat(b, e.Time.Seconds(), 'f', 6, 64)
+	b = append(b, ", "...)
+	typ, err := json.Marshal(string(e.Type))
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, typ...)
+	b = append(b, ", "...)
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, data...)
+	return append(b, ']'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var v []json.RawMessage
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if len(v) != 3 {
+		return ErrInvalidEvent
+	}
+
+	var secs float64
+	if err := json.Unmarshal(v[0], &secs); err != nil {
+		return ErrInvalidEvent
+	}
+	var typ string
+	if err := json.Unmarshal(v[1], &typ); err != nil {
+		return ErrInvalidEvent
+	}
+	var data string
+	if err := json.Unmarshal(v[2], &data); err != nil {
+		return ErrInvalidEvent
+	}
+
+	*e = Event{
+		Time: time.Duration(secs * float64(time.Second)),
+		Type: EventType(typ),
+		Data: data,
+	}
+	return nil
+}
+
+// Reader reads a recording.
+type Reader struct {
+	// Header is the header of the recording.
+	Header Header
+
+	dec *json.Decoder
+}
+
+// NewReader returns a new reader of the recording read from r. It reads
+// the header.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{dec: json.NewDecoder(r)}
+	if err := rd.dec.Decode(&rd.Header); err != nil {
+		return nil, fmt.Errorf("asciicast: reading header: %w", err)
+	}
+	if rd.Header.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, rd.Header.Version)
+	}
+	return rd, nil
+}
+
+// Next returns the next event of the recording. It returns io.EOF at the end
+// of the recording.
+func (r *Reader) Next() (Event, error) {
+	var e Event
+	if err := r.dec.Decode(&e); err != nil {
+		return Event{}, err
+	}
+	return e, nil
+}
+
+// ReadAll reads the rest of the events of the recording.
+func (r *Reader) ReadAll() ([]Event, error) {
+	var events []Event
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+}
+
+// Writer writes a recording.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a new writer of a recording to w, and writes the header
+// h. The version of the header is set.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	h.Version = Version
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w}, nil
+}
+
+// WriteEvent writes the event e.
+func (w *Writer) WriteEvent(e Event) error {
+	b, err := e.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,91 @@
+package asciicast
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recording = `{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"TERM": "xterm-256color"}}
+[0.248848, "o", "\u001b[1;31mHello \u001b[32mWorld!\u001b[0m\n"]
+[1.001376, "i", "q"]
+[2.5, "r", "100x40"]
+[3, "m", "end"]
+`
+
+func TestReader(t *testing.T) {
+	r, err := NewReader(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectHeader := Header{
+		Version: 2, Width: 80, Height: 24, Timestamp: 1504467315,
+		Env: map[string]string{"TERM": "xterm-256color"},
+	}
+	if !reflect.DeepEqual(r.Header, expectHeader) {
+		t.Errorf("expected header %+v, got %+v", expectHeader, r.Header)
+	}
+
+	events, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{
+		{Time: 248848 * time.Microsecond, Type: OutputEvent, Data: "\x1b[1;31mHello \x1b[32mWorld!\x1b[0m\n"},
+		{Time: 1001376 * time.Microsecond, Type: InputEvent, Data: "q"},
+		{Time: 2500 * time.Millisecond, Type: ResizeEvent, Data: "100x40"},
+		{Time: 3 * time.Second, Type: MarkerEvent, Data: "end"},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected events %+v, got %+v", expect, events)
+	}
+
+	if w, h, ok := events[2].Size(); !ok || w != 100 || h != 40 {
+		t.Errorf("expected size 100x40, got %dx%d (%v)", w, h, ok)
+	}
+	if _, _, ok := events[0].Size(); ok {
+		t.Errorf("expected no size for an output event")
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	if _, err := NewReader(strings.NewReader(`{"version": 1, "width": 80, "height": 24}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected an unsupported version error, got %v", err)
+	}
+	if _, err := NewReader(strings.NewReader("")); err == nil {
+		t.Errorf("expected an error for a missing header")
+	}
+
+	r, err := NewReader(strings.NewReader(`{"version": 2, "width": 80, "height": 24}` + "\n" + `[1, "o"]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Next(); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("expected an invalid event error, got %v", err)
+	}
+}
+
+func TestEventMarshalJSON(t *testing.T) {
+	e := Event{Time: 1500 * time.Millisecond, Type: OutputEvent, Data: "\x1b[1mhi\n"}
+	b, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `[1.500000, "o", "\u001b[1mhi\n"]`; string(b) != expect {
+		t.Errorf("expected %s, got %s", expect, b)
+	}
+
+	var got Event
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != e {
+		t.Errorf("expected %+v, got %+v", e, got)
+	}
+}
@@ -0,0 +1,86 @@
+package asciicast
+
+import (
+	"io"
+	"time"
+
+	"github.com/charmbracelet/x/asciicast/ttyrec"
+)
+
+// FromTtyrec converts the ttyrec recording read from r to an asciicast
+// recording, written to w with the header h. The frames are output events
+// timed from the first frame, which is the timestamp of the recording when
+// the header has none.
+func FromTtyrec(w io.Writer, r *ttyrec.Reader, h Header) error {
+	var (
+		enc   *Writer
+		start time.Time
+		last  time.Duration
+		rest  []byte
+	)
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if enc == nil {
+			start = f.Time
+			if h.Timestamp == 0 {
+				h.Timestamp = start.Unix()
+			}
+			if enc, err = NewWriter(w, h); err != nil {
+				return err
+			}
+		}
+
+		// Frames can split UTF-8 characters, which events can't.
+		last = f.Time.Sub(start)
+		data := append(rest, f.Data...)
+		n := incompleteLen(data)
+		rest = append(rest[:0:0], data[len(data)-n:]...)
+		if len(data) == n {
+			continue
+		}
+		e := Event{Time: last, Type: OutputEvent, Data: string(data[:len(data)-n])}
+		if err := enc.WriteEvent(e); err != nil {
+			return err
+		}
+	}
+
+	if enc == nil {
+		// An empty recording.
+		_, err := NewWriter(w, h)
+		return err
+	}
+	if len(rest) > 0 {
+		return enc.WriteEvent(Event{Time: last, Type: OutputEvent, Data: string(rest)})
+	}
+	return nil
+}
+
+// ToTtyrec converts the asciicast recording read from r to a ttyrec
+// recording, written to w. The output events are frames timed from the
+// timestamp of the recording. Other events have no ttyrec equivalent and are
+// dropped.
+func ToTtyrec(w *ttyrec.Writer, r *Reader) error {
+	start := time.Unix(r.Header.Timestamp, 0)
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if e.Type != OutputEvent {
+			continue
+		}
+		if err := w.WriteFrame(ttyrec.Frame{Time: start.Add(e.Time), Data: []byte(e.Data)}); err != nil {
+			return err
+		}
+	}
+}
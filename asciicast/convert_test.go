@@ -0,0 +1,87 @@
+package asciicast
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/asciicast/ttyrec"
+)
+
+func TestFromTtyrec(t *testing.T) {
+	var rec bytes.Buffer
+	tw := ttyrec.NewWriter(&rec)
+	frames := []ttyrec.Frame{
+		{Time: time.Unix(100, 0), Data: []byte("hello \xc3")},
+		{Time: time.Unix(101, 500000000), Data: []byte("\xa9\n")},
+		{Time: time.Unix(103, 0), Data: []byte("\xe2")},
+	}
+	for _, f := range frames {
+		if err := tw.WriteFrame(f); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var b strings.Builder
+	if err := FromTtyrec(&b, ttyrec.NewReader(&rec), Header{Width: 80, Height: 24}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r, err := NewReader(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Header.Timestamp != 100 || r.Header.Width != 80 {
+		t.Errorf("unexpected header %+v", r.Header)
+	}
+	events, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{
+		{Time: 0, Type: OutputEvent, Data: "hello "},
+		{Time: 1500 * time.Millisecond, Type: OutputEvent, Data: "é\n"},
+		{Time: 3 * time.Second, Type: OutputEvent, Data: "�"},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected events %+v, got %+v", expect, events)
+	}
+}
+
+func TestToTtyrec(t *testing.T) {
+	r, err := NewReader(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rec bytes.Buffer
+	if err := ToTtyrec(ttyrec.NewWriter(&rec), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frames, err := ttyrec.NewReader(&rec).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected only the output to be converted, got %d frames", len(frames))
+	}
+	if expect := time.Unix(1504467315, 248848000); !frames[0].Time.Equal(expect) {
+		t.Errorf("expected time %v, got %v", expect, frames[0].Time)
+	}
+	if expect := "\x1b[1;31mHello \x1b[32mWorld!\x1b[0m\n"; string(frames[0].Data) != expect {
+		t.Errorf("expected data %q, got %q", expect, frames[0].Data)
+	}
+}
+
+func TestFromTtyrecEmpty(t *testing.T) {
+	var b strings.Builder
+	if err := FromTtyrec(&b, ttyrec.NewReader(strings.NewReader("")), Header{Width: 80, Height: 24}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"version":2,"width":80,"height":24}` + "\n"; b.String() != expect {
+		t.Errorf("expected %q, got %q", expect, b.String())
+	}
+}
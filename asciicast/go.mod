@@ -0,0 +1,3 @@
+module github.com/charmbracelet/x/asciicast
+
+go 1.18
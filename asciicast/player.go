@@ -0,0 +1,93 @@
+package asciicast
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Player plays recordings.
+type Player struct {
+	// Speed is the speed of the playback, like 2 to play twice as fast. Zero
+	// means 1.
+	Speed float64
+
+	// IdleTimeLimit is the maximum time between events, before the speed is
+	// applied. Zero means the limit of the recording, if any.
+	IdleTimeLimit time.Duration
+}
+
+// Play plays the recording read from r to w at its original speed, see
+// [Player.Play].
+func Play(ctx context.Context, w io.Writer, r *Reader) error {
+	return Player{}.Play(ctx, w, r)
+}
+
+// Play writes the output of the recording read from r to w, as it was
+// recorded. When w has a Resize(width, height int) method, like a virtual
+// terminal, it's resized to the size of the recording first, and on resize
+// events. Play returns when the recording ends, or the context is done.
+func (p Player) Play(ctx context.Context, w io.Writer, r *Reader) error {
+	speed := p.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	limit := p.IdleTimeLimit
+	if limit == 0 {
+		limit = time.Duration(r.Header.IdleTimeLimit * float64(time.Second))
+	}
+
+	resizer, _ := w.(interface{ Resize(width, height int) })
+	if resizer != nil && r.Header.Width > 0 && r.Header.Height > 0 {
+		resizer.Resize(r.Header.Width, r.Header.Height)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	// at is the time of the playback the last event was due.
+	var prev, at time.Duration
+	start := time.Now()
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		wait := e.Time - prev
+		if limit > 0 && wait > limit {
+			wait = limit
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		prev = e.Time
+		at += time.Duration(float64(wait) / speed)
+
+		if d := time.Until(start.Add(at)); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch e.Type {
+		case OutputEvent:
+			if _, err := io.WriteString(w, e.Data); err != nil {
+				return err
+			}
+		case ResizeEvent:
+			if width, height, ok := e.Size(); ok && resizer != nil {
+				resizer.Resize(width, height)
+			}
+		}
+	}
+}
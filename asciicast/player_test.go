@@ -0,0 +1,84 @@
+package asciicast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// screen is a writer that records its resizes.
+type screen struct {
+	strings.Builder
+	sizes []string
+}
+
+func (s *screen) Resize(width, height int) {
+	s.sizes = append(s.sizes, fmt.Sprintf("%dx%d", width, height))
+}
+
+func TestPlayer(t *testing.T) {
+	r, err := NewReader(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var s screen
+	start := time.Now()
+	if err := (Player{Speed: 10}).Play(context.Background(), &s, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := time.Since(start); d < 300*time.Millisecond || d > 2*time.Second {
+		t.Errorf("expected the playback to take about 300ms, took %v", d)
+	}
+	if expect := "\x1b[1;31mHello \x1b[32mWorld!\x1b[0m\n"; s.String() != expect {
+		t.Errorf("expected output %q, got %q", expect, s.String())
+	}
+	if expect := "80x24,100x40"; strings.Join(s.sizes, ",") != expect {
+		t.Errorf("expected sizes %q, got %q", expect, s.sizes)
+	}
+}
+
+func TestPlayerIdleTimeLimit(t *testing.T) {
+	rec := `{"version": 2, "width": 80, "height": 24, "idle_time_limit": 0.05}
+[0, "o", "a"]
+[60, "o", "b"]
+[120, "o", "c"]
+`
+	r, err := NewReader(strings.NewReader(rec))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var b strings.Builder
+	start := time.Now()
+	if err := Play(context.Background(), &b, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("expected the idle time to be limited, took %v", d)
+	}
+	if b.String() != "abc" {
+		t.Errorf("expected output %q, got %q", "abc", b.String())
+	}
+}
+
+func TestPlayerCancel(t *testing.T) {
+	r, err := NewReader(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var b strings.Builder
+	if err := Play(ctx, &b, r); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the playback to be canceled, got %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected no output, got %q", b.String())
+	}
+}
@@ -0,0 +1,127 @@
+package asciicast
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Recorder records a terminal session. The output written to it is recorded
+// as output events, timed from the creation of the recorder.
+//
+//	rec, err := asciicast.NewRecorder(f, asciicast.Header{Width: 80, Height: 24})
+//	if err != nil {
+//		return err
+//	}
+//	defer rec.Flush()
+//	io.Copy(io.MultiWriter(os.Stdout, rec), pty)
+//
+// It's safe to use a recorder from multiple goroutines, like one recording
+// the output and another the input of a session.
+type Recorder struct {
+	w     *Writer
+	start time.Time
+	now   func() time.Time
+	mu    sync.Mutex
+
+	// rest is the beginning of a UTF-8 character at the end of the last
+	// output.
+	rest []byte
+}
+
+// NewRecorder returns a new recorder that writes the recording to w, and
+// writes the header h. The version of the header is set, and so is its
+// timestamp when it's zero.
+func NewRecorder(w io.Writer, h Header) (*Recorder, error) {
+	return newRecorder(w, h, time.Now)
+}
+
+func newRecorder(w io.Writer, h Header, now func() time.Time) (*Recorder, error) {
+	r := &Recorder{start: now(), now: now}
+	if h.Timestamp == 0 {
+		h.Timestamp = r.start.Unix()
+	}
+
+	var err error
+	r.w, err = NewWriter(w, h)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write implements io.Writer. It records p as output. A UTF-8 character
+// split across writes is recorded as a whole with the next write, or when
+// the recorder is flushed.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.rest, p...)
+	n := incompleteLen(data)
+	r.rest = append([]byte(nil), data[len(data)-n:]...)
+	if len(data) == n {
+		return len(p), nil
+	}
+	if err := r.write(OutputEvent, string(data[:len(data)-n])); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Input records p as input.
+func (r *Recorder) Input(p []byte) error {
+	return r.WriteEvent(InputEvent, string(p))
+}
+
+// Resize records a resize of the terminal to the given size, in cells.
+func (r *Recorder) Resize(width, height int) error {
+	return r.WriteEvent(ResizeEvent, fmt.Sprintf("%dx%d", width, height))
+}
+
+// Marker records a marker with the given label.
+func (r *Recorder) Marker(label string) error {
+	return r.WriteEvent(MarkerEvent, label)
+}
+
+// WriteEvent records an event of the given type and data, timed now.
+func (r *Recorder) WriteEvent(typ EventType, data string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.write(typ, data)
+}
+
+// Flush records the rest of the output, the beginning of a UTF-8 character,
+// if any.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.rest) == 0 {
+		return nil
+	}
+	data := string(r.rest)
+	r.rest = r.rest[:0]
+	return r.write(OutputEvent, data)
+}
+
+func (r *Recorder) write(typ EventType, data string) error {
+	return r.w.WriteEvent(Event{Time: r.now().Sub(r.start), Type: typ, Data: data})
+}
+
+// incompleteLen returns the length of the incomplete UTF-8 character at the
+// end of b, if any.
+func incompleteLen(b []byte) int {
+	// Look for the first byte of the last character.
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		c := b[len(b)-i]
+		if utf8.RuneStart(c) {
+			if c >= utf8.RuneSelf && !utf8.FullRune(b[len(b)-i:]) {
+				return i
+			}
+			return 0
+		}
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package asciicast
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock is a fake clock that advances by a second each time it's read.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	t := c.t
+	c.t = c.t.Add(time.Second)
+	return t
+}
+
+func TestRecorder(t *testing.T) {
+	var b strings.Builder
+	c := &clock{t: time.Unix(1504467315, 0)}
+	rec, err := newRecorder(&b, Header{Width: 80, Height: 24}, c.now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// "é" is split across writes.
+	rec.Write([]byte("hello \xc3")) // nolint: errcheck
+	rec.Write([]byte("\xa9\x1b[m")) // nolint: errcheck
+	rec.Input([]byte("q"))          // nolint: errcheck
+	rec.Resize(100, 40)             // nolint: errcheck
+	rec.Marker("done")              // nolint: errcheck
+	rec.Write([]byte("\xe2\x94"))   // nolint: errcheck
+	if err := rec.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expect := `{"version":2,"width":80,"height":24,"timestamp":1504467315}
+[1.000000, "o", "hello "]
+[2.000000, "o", "é\u001b[m"]
+[3.000000, "i", "q"]
+[4.000000, "r", "100x40"]
+[5.000000, "m", "done"]
+[6.000000, "o", "��"]
+`
+	if got := b.String(); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	// The recording can be read back.
+	r, err := NewReader(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 6 || events[1].Data != "é\x1b[m" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestIncompleteLen(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect int
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"é", 0},
+		{"a\xc3", 1},
+		{"a\xe2\x94", 2},
+		{"a\xf0\x9f\x98", 3},
+		{"a\xf0\x9f\x98\x80", 0},
+		{"\x80", 0},
+	}
+	for _, c := range cases {
+		if got := incompleteLen([]byte(c.input)); got != c.expect {
+			t.Errorf("%q: expected %d, got %d", c.input, c.expect, got)
+		}
+	}
+}
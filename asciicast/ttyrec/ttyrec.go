@@ -0,0 +1,113 @@
+// Package ttyrec reads and writes terminal sessions in the ttyrec format.
+//
+// A recording is a sequence of frames, the output of the session with the
+// time it was written. Each frame is a header of three little-endian 32-bit
+// integers, the seconds and microseconds of the time since the Unix epoch,
+// and the length of the data, followed by the data.
+//
+// Use package asciicast to convert recordings to and from the asciicast
+// format.
+package ttyrec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// headerSize is the size of the header of a frame.
+const headerSize = 12
+
+// Frame is a frame of a recording.
+type Frame struct {
+	// Time is the time the data was written.
+	Time time.Time
+
+	// Data is the output of the session.
+	Data []byte
+}
+
+// Reader reads a recording.
+type Reader struct {
+	r   io.Reader
+	hdr [headerSize]byte
+}
+
+// NewReader returns a new reader of the recording read from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next returns the next frame of the recording. It returns io.EOF at the end
+// of the recording, and io.ErrUnexpectedEOF when the last frame is cut
+// short.
+func (r *Reader) Next() (Frame, error) {
+	if _, err := io.ReadFull(r.r, r.hdr[:]); err != nil {
+		return Frame{}, err
+	}
+	sec := binary.LittleEndian.Uint32(r.hdr[0:])
+	usec := binary.LittleEndian.Uint32(r.hdr[4:])
+	size := binary.LittleEndian.Uint32(r.hdr[8:])
+
+	// The data is copied as it's read, rather than allocated up front, in
+	// case the size is bogus.
+	var data bytes.Buffer
+	if _, err := io.CopyN(&data, r.r, int64(size)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Frame{}, err
+	}
+	return Frame{
+		Time: time.Unix(int64(sec), int64(usec)*int64(time.Microsecond)),
+		Data: data.Bytes(),
+	}, nil
+}
+
+// ReadAll reads the rest of the frames of the recording.
+func (r *Reader) ReadAll() ([]Frame, error) {
+	var frames []Frame
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			return frames, nil
+		}
+		if err != nil {
+			return frames, err
+		}
+		frames = append(frames, f)
+	}
+}
+
+// Writer writes a recording.
+type Writer struct {
+	w   io.Writer
+	now func() time.Time
+	hdr [headerSize]byte
+}
+
+// NewWriter returns a new writer of a recording to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, now: time.Now}
+}
+
+// Write implements io.Writer. It writes p as a frame, timed now.
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.WriteFrame(Frame{Time: w.now(), Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteFrame writes the frame f.
+func (w *Writer) WriteFrame(f Frame) error {
+	binary.LittleEndian.PutUint32(w.hdr[0:], uint32(f.Time.Unix()))
+	binary.LittleEndian.PutUint32(w.hdr[4:], uint32(f.Time.Nanosecond()/int(time.Microsecond)))
+	binary.LittleEndian.PutUint32(w.hdr[8:], uint32(len(f.Data)))
+	if _, err := w.w.Write(w.hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(f.Data)
+	return err
+}
@@ -0,0 +1,74 @@
+package ttyrec
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadWrite(t *testing.T) {
+	frames := []Frame{
+		{Time: time.Unix(1504467315, 248848000), Data: []byte("\x1b[1;31mHello\x1b[m\n")},
+		{Time: time.Unix(1504467316, 1000), Data: []byte{}},
+		{Time: time.Unix(1504467318, 0), Data: []byte("bye")},
+	}
+
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	for _, f := range frames {
+		if err := w.WriteFrame(f); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if expect := 3*headerSize + 16 + 3; b.Len() != expect {
+		t.Errorf("expected %d bytes, got %d", expect, b.Len())
+	}
+	if expect := []byte{0x73, 0x59, 0xac, 0x59, 0x10, 0xcc, 0x03, 0x00, 16, 0, 0, 0}; !bytes.Equal(b.Bytes()[:headerSize], expect) {
+		t.Errorf("expected header % x, got % x", expect, b.Bytes()[:headerSize])
+	}
+
+	got, err := NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(frames) {
+		t.Fatalf("expected %d frames, got %d", len(frames), len(got))
+	}
+	for i := range frames {
+		if !got[i].Time.Equal(frames[i].Time) || !bytes.Equal(got[i].Data, frames[i].Data) {
+			t.Errorf("frame %d: expected %+v, got %+v", i, frames[i], got[i])
+		}
+	}
+}
+
+func TestWriterWrite(t *testing.T) {
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	w.now = func() time.Time { return time.Unix(10, 5000) }
+	if n, err := w.Write([]byte("hi")); n != 2 || err != nil {
+		t.Fatalf("unexpected write result %d, %v", n, err)
+	}
+
+	f, err := NewReader(&b).Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := (Frame{Time: time.Unix(10, 5000), Data: []byte("hi")}); !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %+v, got %+v", expect, f)
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	rec := []byte{1, 0, 0, 0, 0, 0, 0, 0, 10, 0, 0, 0, 'a', 'b'}
+	if _, err := NewReader(bytes.NewReader(rec)).Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected an unexpected EOF, got %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(rec[:5])).Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected an unexpected EOF for a partial header, got %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(nil)).Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package cellbuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultArtWidth is the width of ANSI art without SAUCE metadata.
+const DefaultArtWidth = 80
+
+// Art is a piece of ANSI art, like the content of an .ans or .nfo file.
+type Art struct {
+	// Buffer holds the cells of the art.
+	Buffer *Buffer
+
+	// Sauce is the SAUCE metadata of the art, or nil.
+	Sauce *Sauce
+}
+
+// ReadArt reads ANSI art from r, see [ParseArt].
+func ReadArt(r io.Reader) (*Art, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseArt(data), nil
+}
+
+// ParseArt parses classic ANSI art, text encoded in CP437 with escape
+// sequences, as the DOS ANSI.SYS driver would display it. The CP437 glyphs
+// are translated to Unicode, including the ones of control characters other
+// than CR, LF, HT, and ESC. The text ends at the first SUB character, the
+// DOS end-of-file marker.
+//
+// The art is as wide as its SAUCE metadata says, [DefaultArtWidth]
+// otherwise, and as tall as it needs to be. Lines wrap at the edge. Bold
+// text uses the bright foreground colors, and blinking text the bright
+// background colors when the SAUCE metadata asks for iCE colors.
+func ParseArt(data []byte) *Art {
+	sauce, data := ParseSauce(data)
+	a := &Art{Buffer: &Buffer{}, Sauce: sauce}
+
+	width := DefaultArtWidth
+	if sauce != nil && sauce.DataType == 1 && sauce.TInfo1 > 0 {
+		width = int(sauce.TInfo1)
+	}
+	d := artDrawer{buf: a.Buffer, width: width, ice: sauce != nil && sauce.ICEColors()}
+	d.buf.Resize(width, 1)
+
+	p := ansi.GetParser()
+	defer ansi.PutParser(p)
+	p.Parse(d.draw, []byte(artText(data)))
+
+	return a
+}
+
+// String returns the art as a styled string, see [Render].
+func (a *Art) String() string {
+	return Render(a.Buffer)
+}
+
+// artText translates the CP437 text of ANSI art to UTF-8, leaving the
+// control sequences as is. It stops at the first SUB character.
+func artText(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == ansi.SUB:
+			return b.String()
+		case c == ansi.ESC && i+1 < len(data) && data[i+1] == '[':
+			// Copy the control sequence, up to its final byte.
+			j := i + 2
+			for j < len(data) && data[j] >= 0x20 && data[j] <= 0x3f {
+				j++
+			}
+			if j < len(data) && data[j] >= 0x40 && data[j] <= 0x7e {
+				j++
+			}
+			b.Write(data[i:j])
+			i = j - 1
+		case c == ansi.ESC, c == ansi.CR, c == ansi.LF, c == ansi.HT, c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			b.WriteRune(cp437[c])
+		}
+	}
+	return b.String()
+}
+
+// artDrawer draws ANSI art on a buffer.
+type artDrawer struct {
+	buf   *Buffer
+	width int
+	ice   bool
+
+	x, y   int
+	sx, sy int // sx and sy are the saved cursor position.
+	wrap   bool
+	pen    Style
+	style  Style
+}
+
+// draw draws the sequence seq.
+func (d *artDrawer) draw(seq ansi.Sequence) {
+	switch seq := seq.(type) {
+	case ansi.Rune:
+		if d.wrap {
+			d.wrap = false
+			d.moveTo(0, d.y+1)
+		}
+		SetCell(d.buf, d.x, d.y, Cell{Content: string(rune(seq)), Width: 1, Style: d.style})
+		if d.x == d.width-1 {
+			d.wrap = true
+		} else {
+			d.x++
+		}
+	case ansi.ControlCode:
+		switch seq {
+		case ansi.CR:
+			d.moveTo(0, d.y)
+		case ansi.LF:
+			d.moveTo(0, d.y+1)
+		case ansi.HT:
+			d.moveTo((d.x/8+1)*8, d.y)
+		}
+	case ansi.CsiSequence:
+		d.control(seq)
+	}
+}
+
+// control handles the control sequence seq.
+func (d *artDrawer) control(seq ansi.CsiSequence) {
+	if seq.Marker() != 0 || seq.Intermediate() != 0 {
+		return
+	}
+
+	// n returns the ith parameter, 1 by default.
+	n := func(i int) int {
+		if p := seq.Param(i); p > 0 {
+			return p
+		}
+		return 1
+	}
+
+	switch seq.Command() {
+	case 'A': // CUU
+		d.moveTo(d.x, d.y-n(0))
+	case 'B': // CUD
+		d.moveTo(d.x, d.y+n(0))
+	case 'C': // CUF
+		d.moveTo(d.x+n(0), d.y)
+	case 'D': // CUB
+		d.moveTo(d.x-n(0), d.y)
+	case 'H', 'f': // CUP, HVP
+		d.moveTo(n(1)-1, n(0)-1)
+	case 'J': // ED
+		if seq.Param(0) == 2 {
+			// ANSI.SYS clears the screen and moves the cursor home.
+			d.buf.Resize(d.width, 0)
+			d.buf.Resize(d.width, 1)
+			d.moveTo(0, 0)
+		}
+	case 'K': // EL
+		for x := d.x; x < d.width; x++ {
+			d.buf.Set(x, d.y, spaceCell) //nolint:errcheck
+		}
+	case 's': // SCOSC
+		d.sx, d.sy = d.x, d.y
+	case 'u': // SCORC
+		d.moveTo(d.sx, d.sy)
+	case 'm': // SGR
+		ReadStyle(seq.Params, &d.pen)
+		d.style = artStyle(d.pen, d.ice)
+	}
+}
+
+// moveTo moves the cursor to the given position, in the bounds of the art.
+// The art grows to the bottom as needed.
+func (d *artDrawer) moveTo(x, y int) {
+	d.wrap = false
+	if x < 0 {
+		x = 0
+	} else if x >= d.width {
+		x = d.width - 1
+	}
+	if y < 0 {
+		y = 0
+	}
+	if y >= d.buf.Height() {
+		d.buf.Resize(d.width, y+1)
+	}
+	d.x, d.y = x, y
+}
+
+// artStyle returns the style of ANSI art drawn with the pen. Bold text is
+// drawn with the bright foreground colors, and blinking text with the bright
+// background colors when ice is true.
+func artStyle(pen Style, ice bool) Style {
+	s := pen
+	if s.Attrs&BoldAttr != 0 {
+		s.Attrs &^= BoldAttr
+		s.Fg = brightColor(s.Fg, ansi.White)
+	}
+	if ice && s.Attrs&(SlowBlinkAttr|RapidBlinkAttr) != 0 {
+		s.Attrs &^= SlowBlinkAttr | RapidBlinkAttr
+		s.Bg = brightColor(s.Bg, ansi.Black)
+	}
+	return s
+}
+
+// brightColor returns the bright variant of the basic color c, or of def
+// when c is nil.
+func brightColor(c ansi.Color, def ansi.BasicColor) ansi.Color {
+	if c == nil {
+		c = def
+	}
+	if b, ok := c.(ansi.BasicColor); ok && b < ansi.BrightBlack {
+		return b + 8
+	}
+	return c
+}
+
+// Sauce is the SAUCE metadata of a file, a record at its end describing its
+// content. See https://www.acid.org/info/sauce/sauce.htm.
+type Sauce struct {
+	Title  string
+	Author string
+	Group  string
+
+	// Date is the creation date, in the form CCYYMMDD.
+	Date string
+
+	// FileSize is the size of the file without the metadata.
+	FileSize uint32
+
+	// DataType and FileType are the type of the content, like 1 and 1 for
+	// ANSI art.
+	DataType byte
+	FileType byte
+
+	// TInfo1 to TInfo4 depend on the type of the content. For ANSI art,
+	// TInfo1 is the width and TInfo2 the height, in characters.
+	TInfo1 uint16
+	TInfo2 uint16
+	TInfo3 uint16
+	TInfo4 uint16
+
+	// Comments are the lines of the comment block, if any.
+	Comments []string
+
+	// Flags depend on the type of the content, see [Sauce.ICEColors].
+	Flags byte
+
+	// Font is the name of the font of the content, like "IBM VGA".
+	Font string
+}
+
+// ICEColors reports whether blinking text is drawn with the bright
+// background colors instead, for ANSI art.
+func (s *Sauce) ICEColors() bool {
+	return s.DataType == 1 && s.Flags&1 != 0
+}
+
+// sauceSize and commentSize are the sizes of a SAUCE record and of a line of
+// its comment block.
+const (
+	sauceSize   = 128
+	commentSize = 64
+)
+
+// ParseSauce returns the SAUCE metadata at the end of data, if any, and the
+// data before it.
+func ParseSauce(data []byte) (*Sauce, []byte) {
+	if len(data) < sauceSize {
+		return nil, data
+	}
+	rec := data[len(data)-sauceSize:]
+	if !bytes.HasPrefix(rec, []byte("SAUCE00")) {
+		return nil, data
+	}
+
+	s := &Sauce{
+		Title:    sauceString(rec[7:42]),
+		Author:   sauceString(rec[42:62]),
+		Group:    sauceString(rec[62:82]),
+		Date:     sauceString(rec[82:90]),
+		FileSize: binary.LittleEndian.Uint32(rec[90:94]),
+		DataType: rec[94],
+		FileType: rec[95],
+		TInfo1:   binary.LittleEndian.Uint16(rec[96:98]),
+		TInfo2:   binary.LittleEndian.Uint16(rec[98:100]),
+		TInfo3:   binary.LittleEndian.Uint16(rec[100:102]),
+		TInfo4:   binary.LittleEndian.Uint16(rec[102:104]),
+		Flags:    rec[105],
+		Font:     sauceString(rec[106:128]),
+	}
+	data = data[:len(data)-sauceSize]
+
+	// The comment block is right before the record.
+	if n := int(rec[104]); n > 0 {
+		start := len(data) - 5 - n*commentSize
+		if start >= 0 && bytes.Equal(data[start:start+5], []byte("COMNT")) {
+			for i := 0; i < n; i++ {
+				at := start + 5 + i*commentSize
+				s.Comments = append(s.Comments, sauceString(data[at:at+commentSize]))
+			}
+			data = data[:start]
+		}
+	}
+
+	// The end-of-file marker is part of the metadata.
+	if len(data) > 0 && data[len(data)-1] == ansi.SUB {
+		data = data[:len(data)-1]
+	}
+	return s, data
+}
+
+// sauceString returns the CP437 string of a SAUCE field, without its padding.
+func sauceString(b []byte) string {
+	b = bytes.TrimRight(b, " \x00")
+	var s strings.Builder
+	for _, c := range b {
+		if c < 0x80 && c >= 0x20 {
+			s.WriteByte(c)
+		} else {
+			s.WriteRune(cp437[c])
+		}
+	}
+	return s.String()
+}
+
+// cp437 maps the bytes of the IBM PC character set, code page 437, to the
+// runes of their glyphs.
+var cp437 = [256]rune{
+	' ', '☺', '☻', '♥', '♦', '♣', '♠', '•', '◘', '○', '◙', '♂', '♀', '♪', '♫', '☼',
+	'►', '◄', '↕', '‼', '¶', '§', '▬', '↨', '↑', '↓', '→', '←', '∟', '↔', '▲', '▼',
+	' ', '!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'@', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', '[', '\\', ']', '^', '_',
+	'`', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', '{', '|', '}', '~', '⌂',
+	'Ç', 'ü', 'é', 'â', 'ä', 'à', 'å', 'ç', 'ê', 'ë', 'è', 'ï', 'î', 'ì', 'Ä', 'Å',
+	'É', 'æ', 'Æ', 'ô', 'ö', 'ò', 'û', 'ù', 'ÿ', 'Ö', 'Ü', '¢', '£', '¥', '₧', 'ƒ',
+	'á', 'í', 'ó', 'ú', 'ñ', 'Ñ', 'ª', 'º', '¿', '⌐', '¬', '½', '¼', '¡', '«', '»',
+	'░', '▒', '▓', '│', '┤', '╡', '╢', '╖', '╕', '╣', '║', '╗', '╝', '╜', '╛', '┐',
+	'└', '┴', '┬', '├', '─', '┼', '╞', '╟', '╚', '╔', '╩', '╦', '╠', '═', '╬', '╧',
+	'╨', '╤', '╥', '╙', '╘', '╒', '╓', '╫', '╪', '┘', '┌', '█', '▄', '▌', '▐', '▀',
+	'α', 'ß', 'Γ', 'π', 'Σ', 'σ', 'µ', 'τ', 'Φ', 'Θ', 'Ω', 'δ', '∞', 'φ', 'ε', '∩',
+	'≡', '±', '≥', '≤', '⌠', '⌡', '÷', '≈', '°', '∙', '·', '√', 'ⁿ', '²', '■', ' ',
+}
@@ -0,0 +1,256 @@
+package cellbuf_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// sauce returns data followed by the end-of-file marker, a comment block
+// with the given lines if any, and a SAUCE record for ANSI art of the given
+// width and flags.
+func sauce(data, title string, width uint16, flags byte, comments ...string) []byte {
+	b := []byte(data + "\x1a")
+	if len(comments) > 0 {
+		b = append(b, "COMNT"...)
+		for _, c := range comments {
+			b = append(b, pad(c, 64)...)
+		}
+	}
+
+	rec := make([]byte, 128)
+	copy(rec, "SAUCE00")
+	copy(rec[7:42], pad(title, 35))
+	copy(rec[42:62], pad("artist", 20))
+	copy(rec[62:82], pad("group", 20))
+	copy(rec[82:90], "19960101")
+	binary.LittleEndian.PutUint32(rec[90:94], uint32(len(data)))
+	rec[94], rec[95] = 1, 1
+	binary.LittleEndian.PutUint16(rec[96:98], width)
+	rec[104] = byte(len(comments))
+	rec[105] = flags
+	copy(rec[106:], "IBM VGA")
+	return append(b, rec...)
+}
+
+// pad pads s with spaces to n bytes.
+func pad(s string, n int) []byte {
+	return []byte(s + strings.Repeat(" ", n-len(s)))
+}
+
+// artLines returns the content of the lines of the art, without trailing
+// spaces.
+func artLines(a *cellbuf.Art) []string {
+	lines := make([]string, a.Buffer.Height())
+	for y := range lines {
+		var b strings.Builder
+		for x := 0; x < a.Buffer.Width(); x++ {
+			c, _ := a.Buffer.At(x, y)
+			b.WriteString(c.Content)
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+func TestParseArtText(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		expect []string
+	}{
+		{"ascii", "Hello, World!", []string{"Hello, World!"}},
+		{"control glyphs", "\x01\x02\x03\x04\x07\x08\x0c\x1f", []string{"☺☻♥♦•◘♀▼"}},
+		{"delete", "\x7f", []string{"⌂"}},
+		{"shades", "\xb0\xb1\xb2\xdb", []string{"░▒▓█"}},
+		{"box drawing", "\xc9\xcd\xbb\r\n\xc8\xcd\xbc", []string{"╔═╗", "╚═╝"}},
+		{"latin", "\x80\x81\x82\xa4\xa5", []string{"ÇüéñÑ"}},
+		{"greek", "\xe0\xe1\xe3\xea", []string{"αßπΩ"}},
+		{"nbsp", "a\xffb", []string{"a b"}},
+		{"eof", "ab\x1acd", []string{"ab"}},
+		{"tab", "a\tb", []string{"a       b"}},
+		{"sequence bytes", "\x1b[1;31m\xdb", []string{"█"}},
+		{"newlines", "a\nb\r\nc", []string{"a", "b", "c"}},
+		{"wrap", strings.Repeat("x", 80) + "y", []string{strings.Repeat("x", 80), "y"}},
+		{"no wrap before newline", strings.Repeat("x", 80) + "\r\ny", []string{strings.Repeat("x", 80), "y"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := artLines(cellbuf.ParseArt([]byte(c.data)))
+			if !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestParseArtCursor(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		expect []string
+	}{
+		{"cup", "\x1b[2;3Hx", []string{"", "  x"}},
+		{"cup default", "ab\x1b[Hx", []string{"xb"}},
+		{"hvp", "\x1b[3;2fx", []string{"", "", " x"}},
+		{"cuf", "a\x1b[3Cb", []string{"a   b"}},
+		{"cuf default", "a\x1b[Cb", []string{"a b"}},
+		{"cub", "abc\x1b[2Dx", []string{"axc"}},
+		{"cub clamped", "ab\x1b[9Dx", []string{"xb"}},
+		{"cuf clamped", "\x1b[99Cx", []string{strings.Repeat(" ", 79) + "x"}},
+		{"cud", "a\x1b[2Bb", []string{"a", "", " b"}},
+		{"cuu", "\r\n\r\nab\x1b[2Ax", []string{"  x", "", "ab"}},
+		{"cuu clamped", "a\x1b[5Ab", []string{"ab"}},
+		{"save restore", "ab\x1b[sc\x1b[ud", []string{"abd"}},
+		{"el", "abcd\x1b[3D\x1b[K", []string{"a"}},
+		{"ed", "abc\r\ndef\x1b[2Jx", []string{"x"}},
+		{"ed below ignored", "abc\x1b[Jx", []string{"abcx"}},
+		{"private ignored", "a\x1b[?7hb", []string{"ab"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := artLines(cellbuf.ParseArt([]byte(c.data)))
+			if !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestParseArtStyle(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   []byte
+		expect cellbuf.Style
+	}{
+		{"none", []byte("x"), cellbuf.Style{}},
+		{"color", []byte("\x1b[31;44mx"), cellbuf.Style{Fg: ansi.Red, Bg: ansi.Blue}},
+		{"bold", []byte("\x1b[1mx"), cellbuf.Style{Fg: ansi.BrightWhite}},
+		{"bold color", []byte("\x1b[1;32mx"), cellbuf.Style{Fg: ansi.BrightGreen}},
+		{"bold color after", []byte("\x1b[32m\x1b[1mx"), cellbuf.Style{Fg: ansi.BrightGreen}},
+		{"bold off", []byte("\x1b[1;32m\x1b[22mx"), cellbuf.Style{Fg: ansi.Green}},
+		{"bold bright", []byte("\x1b[1;92mx"), cellbuf.Style{Fg: ansi.BrightGreen}},
+		{"reset", []byte("\x1b[1;31;44m\x1b[0mx"), cellbuf.Style{}},
+		{"reset empty", []byte("\x1b[1;31;44m\x1b[mx"), cellbuf.Style{}},
+		{"blink", []byte("\x1b[5;44mx"), cellbuf.Style{Bg: ansi.Blue, Attrs: cellbuf.SlowBlinkAttr}},
+		{"ice blink", sauce("\x1b[5;44mx", "", 80, 1), cellbuf.Style{Bg: ansi.BrightBlue}},
+		{"ice blink default", sauce("\x1b[5mx", "", 80, 1), cellbuf.Style{Bg: ansi.BrightBlack}},
+		{"ice off", sauce("\x1b[5;44mx", "", 80, 0), cellbuf.Style{Bg: ansi.Blue, Attrs: cellbuf.SlowBlinkAttr}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := cellbuf.ParseArt(c.data)
+			cell, err := a.Buffer.At(0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cell.Content != "x" {
+				t.Fatalf("expected %q, got %q", "x", cell.Content)
+			}
+			if !cell.Style.Equal(c.expect) {
+				t.Errorf("expected %+v, got %+v", c.expect, cell.Style)
+			}
+		})
+	}
+}
+
+func TestParseArtWidth(t *testing.T) {
+	a := cellbuf.ParseArt(sauce("abcdef", "", 4, 0))
+	if w := a.Buffer.Width(); w != 4 {
+		t.Errorf("expected width 4, got %d", w)
+	}
+	expect := []string{"abcd", "ef"}
+	if got := artLines(a); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestParseSauce(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		sauce    bool
+		expect   string
+		title    string
+		comments []string
+	}{
+		{"none", []byte("art\x1a"), false, "art\x1a", "", nil},
+		{"short", []byte("SAUCE00 art"), false, "SAUCE00 art", "", nil},
+		{"record", sauce("art", "Title", 80, 0), true, "art", "Title", nil},
+		{"cp437 title", sauce("art", "\x01 \x80", 80, 0), true, "art", "☺ Ç", nil},
+		{"sub in art", sauce("a\x1ab", "", 80, 0), true, "a\x1ab", "", nil},
+		{"record only", sauce("art", "", 80, 0)[4:], true, "", "", nil},
+		{"without eof", append([]byte("art"), sauce("", "", 80, 0)[1:]...), true, "art", "", nil},
+		{"comments", sauce("art", "", 80, 0, "first", "second"), true, "art", "", []string{"first", "second"}},
+		{"empty comment", sauce("art", "", 80, 0, ""), true, "art", "", []string{""}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, data := cellbuf.ParseSauce(c.data)
+			if string(data) != c.expect {
+				t.Errorf("expected data %q, got %q", c.expect, data)
+			}
+			if !c.sauce {
+				if s != nil {
+					t.Errorf("expected no SAUCE metadata, got %+v", s)
+				}
+				return
+			}
+			if s == nil {
+				t.Fatal("expected SAUCE metadata")
+			}
+			if s.Title != c.title {
+				t.Errorf("expected title %q, got %q", c.title, s.Title)
+			}
+			if !reflect.DeepEqual(s.Comments, c.comments) {
+				t.Errorf("expected comments %q, got %q", c.comments, s.Comments)
+			}
+		})
+	}
+}
+
+func TestParseSauceFields(t *testing.T) {
+	s, _ := cellbuf.ParseSauce(sauce("art", "Title", 132, 1))
+	if s == nil {
+		t.Fatal("expected SAUCE metadata")
+	}
+	expect := cellbuf.Sauce{
+		Title:    "Title",
+		Author:   "artist",
+		Group:    "group",
+		Date:     "19960101",
+		FileSize: 3,
+		DataType: 1,
+		FileType: 1,
+		TInfo1:   132,
+		Flags:    1,
+		Font:     "IBM VGA",
+	}
+	if !reflect.DeepEqual(*s, expect) {
+		t.Errorf("expected %+v, got %+v", expect, *s)
+	}
+	if !s.ICEColors() {
+		t.Error("expected iCE colors")
+	}
+}
+
+func TestParseSauceMissingComments(t *testing.T) {
+	// The record says there is a comment block, but there is none.
+	data := sauce("art", "", 80, 0)
+	data[len(data)-128+104] = 1
+	s, rest := cellbuf.ParseSauce(data)
+	if s == nil {
+		t.Fatal("expected SAUCE metadata")
+	}
+	if s.Comments != nil {
+		t.Errorf("expected no comments, got %q", s.Comments)
+	}
+	if !bytes.Equal(rest, []byte("art")) {
+		t.Errorf("expected data %q, got %q", "art", rest)
+	}
+}
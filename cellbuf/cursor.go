@@ -0,0 +1,76 @@
+package cellbuf
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// moveCursor returns the shortest sequence that moves the cursor from x0, y0
+// to x1, y1. Positions are 0-based, and a negative x0 or y0 means the cursor
+// position is unknown.
+func moveCursor(x0, y0, x1, y1 int) string {
+	if x0 == x1 && y0 == y1 {
+		return ""
+	}
+
+	best := cursorPosition(x1, y1)
+	if x0 < 0 || y0 < 0 {
+		return best
+	}
+	try := func(s string) {
+		if len(s) < len(best) {
+			best = s
+		}
+	}
+
+	var v string
+	switch {
+	case y1 < y0:
+		v = ansi.CursorUp(y0 - y1)
+	case y1 > y0:
+		v = ansi.CursorDown(y1 - y0)
+	}
+
+	// Relative moves.
+	switch {
+	case x1 == x0:
+		try(v)
+	case x1 == x0-1:
+		try(v + "\b")
+	case x1 < x0:
+		try(v + ansi.CursorLeft(x0-x1))
+	default:
+		try(v + ansi.CursorRight(x1-x0))
+	}
+
+	// Back to the start of the line first.
+	if x1 < x0 {
+		cr := "\r"
+		if x1 > 0 {
+			cr += ansi.CursorRight(x1)
+		}
+		try(v + cr)
+	}
+	if x1 == 0 && y1 == y0+1 {
+		// The next line, whether the terminal translates LF to CR LF or
+		// not.
+		try("\r\n")
+	}
+
+	return best
+}
+
+// cursorPosition returns the shortest CUP sequence that moves the cursor to
+// the given 0-based position.
+func cursorPosition(x, y int) string {
+	switch {
+	case x == 0 && y == 0:
+		return "\x1b[H"
+	case x == 0:
+		return "\x1b[" + strconv.Itoa(y+1) + "H"
+	case y == 0:
+		return "\x1b[;" + strconv.Itoa(x+1) + "H"
+	}
+	return ansi.SetCursorPosition(x+1, y+1)
+}
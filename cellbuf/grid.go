@@ -68,13 +68,8 @@ func RenderLine(g Grid, n int) (w int, line string) {
 	var pendingWidth int // this ignores space cells until we hit a non-space cell
 	for x := 0; x < g.Width(); x++ {
 		if cell, err := g.At(x, n); err == nil && cell.Width > 0 {
-			if cell.Style.Empty() && !pen.Empty() {
-				buf.WriteString(ansi.ResetStyle) //nolint:errcheck
-				pen.Reset()
-			}
 			if !cell.Style.Equal(pen) {
-				seq := cell.Style.DiffSequence(pen)
-				buf.WriteString(seq) // nolint:errcheck
+				buf.WriteString(cell.Style.Diff(pen)) // nolint:errcheck
 				pen = cell.Style
 			}
 
@@ -110,11 +105,115 @@ func RenderLine(g Grid, n int) (w int, line string) {
 	return w, strings.TrimRight(buf.String(), " ") // Trim trailing spaces
 }
 
+// SetCell writes a cell to the grid at the given position, keeping wide cells
+// intact. A wide cell is followed by zero-width placeholder cells, and a wide
+// cell partially overwritten is replaced with spaces of the same style. It
+// returns false if the position is out of bounds, or the cell doesn't fit.
+func SetCell(g Grid, x, y int, c Cell) bool {
+	if x < 0 || y < 0 || x >= g.Width() || y >= g.Height() || x+c.Width > g.Width() {
+		return false
+	}
+
+	// Break up the wide cells the new cell overlaps.
+	for i := x; i < x+c.Width || i == x; i++ {
+		clearWide(g, i, y)
+	}
+
+	g.Set(x, y, c) //nolint:errcheck
+	for j := 1; j < c.Width; j++ {
+		g.Set(x+j, y, emptyCell) //nolint:errcheck
+	}
+	return true
+}
+
+// clearWide replaces the wide cell covering the given position, if any, with
+// space cells of the same style.
+func clearWide(g Grid, x, y int) {
+	// Wide cells can go up to 4 cells wide, look for one starting up to 3
+	// cells to the left.
+	for i := x; i >= 0 && i > x-4; i-- {
+		c, err := g.At(i, y)
+		if err != nil {
+			return
+		}
+		if c.Width == 0 {
+			// A placeholder, keep looking for its wide cell.
+			continue
+		}
+		if c.Width > 1 && i+c.Width > x {
+			s := c
+			s.Content = " "
+			s.Width = 1
+			for j := 0; j < c.Width; j++ {
+				g.Set(i+j, y, s) //nolint:errcheck
+			}
+		}
+		return
+	}
+}
+
 // Fill fills the grid with the given cell.
 func Fill(g Grid, c Cell) {
-	for y := 0; y < g.Height(); y++ {
-		for x := 0; x < g.Width(); x++ {
-			g.Set(x, y, c) //nolint:errcheck
+	FillRect(g, c, 0, 0, g.Width(), g.Height())
+}
+
+// FillRect fills the rectangle of the given size at the given position with
+// the given cell. Wide cells are repeated every width cells, and are not
+// written where they don't fit.
+func FillRect(g Grid, c Cell, x, y, w, h int) {
+	step := c.Width
+	if step < 1 {
+		step = 1
+	}
+	for j := y; j < y+h; j++ {
+		for i := x; i+step <= x+w; i += step {
+			SetCell(g, i, j, c)
+		}
+	}
+}
+
+// CopyRect copies the rectangle of the given size at the given position in
+// src to the position dx, dy in dst. The grids can be the same, and the
+// rectangles can overlap. Wide cells cut by the edges of the rectangle are
+// copied as spaces of the same style.
+func CopyRect(dst Grid, dx, dy int, src Grid, x, y, w, h int) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	// Read the cells first, in case the rectangles overlap.
+	cells := make([]Cell, w*h)
+	for j := 0; j < h; j++ {
+		var cut *Cell // cut is the space replacing a wide cell cut short.
+		for i := 0; i < w; i++ {
+			c, err := src.At(x+i, y+j)
+			switch {
+			case err != nil:
+				c = spaceCell
+			case c.Width == 0 && i == 0:
+				// A placeholder of a wide cell starting left of the
+				// rectangle.
+				c = spaceCell
+			case c.Width == 0 && cut != nil:
+				c = *cut
+			case c.Width > 1 && i+c.Width > w:
+				// A wide cell going past the right edge.
+				c.Content = " "
+				c.Width = 1
+				cut = &c
+			}
+			cells[j*w+i] = c
+		}
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			c := cells[j*w+i]
+			if c.Width == 0 {
+				// Written with its wide cell.
+				continue
+			}
+			SetCell(dst, dx+i, dy+j, c)
 		}
 	}
 }
@@ -0,0 +1,280 @@
+package cellbuf_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// grid returns a grid of the given size with the given lines.
+func grid(width, height int, lines ...string) *cellbuf.Buffer {
+	b := &cellbuf.Buffer{}
+	b.Resize(width, height)
+	cellbuf.WcWidth.SetContent(b, strings.Join(lines, "\n"))
+	return b
+}
+
+// rows returns the content of the cells of g, line by line. The placeholders
+// following wide cells are "".
+func rows(g cellbuf.Grid) [][]string {
+	lines := make([][]string, g.Height())
+	for y := range lines {
+		for x := 0; x < g.Width(); x++ {
+			c, _ := g.At(x, y)
+			lines[y] = append(lines[y], c.Content)
+		}
+	}
+	return lines
+}
+
+// cell returns a cell with the given content.
+func cell(content string) cellbuf.Cell {
+	return cellbuf.Cell{Content: content, Width: ansi.StringWidth(content)}
+}
+
+func TestSetCell(t *testing.T) {
+	cases := []struct {
+		name   string
+		grid   *cellbuf.Buffer
+		x, y   int
+		cell   cellbuf.Cell
+		ok     bool
+		expect [][]string
+	}{
+		{"narrow", grid(4, 1, "abcd"), 1, 0, cell("x"), true, [][]string{{"a", "x", "c", "d"}}},
+		{"wide", grid(4, 1, "abcd"), 1, 0, cell("世"), true, [][]string{{"a", "世", "", "d"}}},
+		{"wide start overwritten", grid(4, 1, "世ab"), 0, 0, cell("x"), true, [][]string{{"x", " ", "a", "b"}}},
+		{"wide end overwritten", grid(4, 1, "世ab"), 1, 0, cell("x"), true, [][]string{{" ", "x", "a", "b"}}},
+		{"wide over wide", grid(4, 1, "世界"), 1, 0, cell("国"), true, [][]string{{" ", "国", "", " "}}},
+		{"wide over wide start", grid(4, 1, "世界"), 0, 0, cell("国"), true, [][]string{{"国", "", "界", ""}}},
+		{"last column", grid(4, 1, "abcd"), 3, 0, cell("x"), true, [][]string{{"a", "b", "c", "x"}}},
+		{"wide past edge", grid(4, 1, "abcd"), 3, 0, cell("世"), false, [][]string{{"a", "b", "c", "d"}}},
+		{"negative x", grid(4, 1, "abcd"), -1, 0, cell("x"), false, [][]string{{"a", "b", "c", "d"}}},
+		{"negative y", grid(4, 1, "abcd"), 0, -1, cell("x"), false, [][]string{{"a", "b", "c", "d"}}},
+		{"past right", grid(4, 1, "abcd"), 4, 0, cell("x"), false, [][]string{{"a", "b", "c", "d"}}},
+		{"past bottom", grid(4, 1, "abcd"), 0, 1, cell("x"), false, [][]string{{"a", "b", "c", "d"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if ok := cellbuf.SetCell(c.grid, c.x, c.y, c.cell); ok != c.ok {
+				t.Errorf("expected %v, got %v", c.ok, ok)
+			}
+			if got := rows(c.grid); !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestSetCellKeepsStyle(t *testing.T) {
+	// The halves of a split wide cell keep its style.
+	g := grid(4, 1, "\x1b[44m世\x1b[m")
+	cellbuf.SetCell(g, 0, 0, cell("x"))
+	c, _ := g.At(1, 0)
+	expect := cellbuf.Cell{Content: " ", Width: 1, Style: cellbuf.Style{Bg: ansi.Blue}}
+	if !c.Equal(expect) {
+		t.Errorf("expected %+v, got %+v", expect, c)
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	cases := []struct {
+		name       string
+		grid       *cellbuf.Buffer
+		cell       cellbuf.Cell
+		x, y, w, h int
+		expect     [][]string
+	}{
+		{
+			"inside",
+			grid(4, 3), cell("x"), 1, 1, 2, 1,
+			[][]string{{" ", " ", " ", " "}, {" ", "x", "x", " "}, {" ", " ", " ", " "}},
+		},
+		{
+			"clipped",
+			grid(4, 3), cell("x"), 2, 1, 10, 10,
+			[][]string{{" ", " ", " ", " "}, {" ", " ", "x", "x"}, {" ", " ", "x", "x"}},
+		},
+		{
+			"clipped left",
+			grid(4, 2), cell("x"), -2, -1, 3, 2,
+			[][]string{{"x", " ", " ", " "}, {" ", " ", " ", " "}},
+		},
+		{
+			"wide",
+			grid(4, 1), cell("世"), 0, 0, 4, 1,
+			[][]string{{"世", "", "世", ""}},
+		},
+		{
+			"wide odd width",
+			grid(4, 1, "abcd"), cell("世"), 0, 0, 3, 1,
+			[][]string{{"世", "", "c", "d"}},
+		},
+		{
+			"wide at edge",
+			grid(5, 1, "abcde"), cell("世"), 1, 0, 4, 1,
+			[][]string{{"a", "世", "", "世", ""}},
+		},
+		{
+			"wide past edge",
+			grid(5, 1, "abcde"), cell("世"), 2, 0, 4, 1,
+			[][]string{{"a", "b", "世", "", "e"}},
+		},
+		{
+			"split wide left",
+			grid(4, 1, "世ab"), cell("x"), 1, 0, 1, 1,
+			[][]string{{" ", "x", "a", "b"}},
+		},
+		{
+			"split wide right",
+			grid(4, 1, "ab世"), cell("x"), 0, 0, 3, 1,
+			[][]string{{"x", "x", "x", " "}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cellbuf.FillRect(c.grid, c.cell, c.x, c.y, c.w, c.h)
+			if got := rows(c.grid); !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestCopyRect(t *testing.T) {
+	cases := []struct {
+		name       string
+		src        *cellbuf.Buffer
+		x, y, w, h int
+		dx, dy     int
+		expect     [][]string
+	}{
+		{
+			"copy",
+			grid(4, 2, "abcd", "efgh"), 0, 0, 2, 1, 2, 1,
+			[][]string{{"a", "b", "c", "d"}, {"e", "f", "a", "b"}},
+		},
+		{
+			"overlap right",
+			grid(4, 1, "abcd"), 0, 0, 3, 1, 1, 0,
+			[][]string{{"a", "a", "b", "c"}},
+		},
+		{
+			"overlap left",
+			grid(4, 1, "abcd"), 1, 0, 3, 1, 0, 0,
+			[][]string{{"b", "c", "d", "d"}},
+		},
+		{
+			"overlap down",
+			grid(2, 3, "ab", "cd", "ef"), 0, 0, 2, 2, 0, 1,
+			[][]string{{"a", "b"}, {"a", "b"}, {"c", "d"}},
+		},
+		{
+			"overlap up",
+			grid(2, 3, "ab", "cd", "ef"), 0, 1, 2, 2, 0, 0,
+			[][]string{{"c", "d"}, {"e", "f"}, {"e", "f"}},
+		},
+		{
+			"wide",
+			grid(6, 1, "世ab"), 0, 0, 3, 1, 3, 0,
+			[][]string{{"世", "", "a", "世", "", "a"}},
+		},
+		{
+			"wide cut left",
+			grid(6, 1, "世ab"), 1, 0, 3, 1, 3, 0,
+			[][]string{{"世", "", "a", " ", "a", "b"}},
+		},
+		{
+			"wide cut right",
+			grid(6, 1, "a世b"), 0, 0, 2, 1, 4, 0,
+			[][]string{{"a", "世", "", "b", "a", " "}},
+		},
+		{
+			"wide overlap",
+			grid(4, 1, "世界"), 0, 0, 2, 1, 1, 0,
+			[][]string{{" ", "世", "", " "}},
+		},
+		{
+			"source clipped",
+			grid(4, 1, "abcd"), 2, 0, 4, 1, 0, 0,
+			[][]string{{"c", "d", " ", " "}},
+		},
+		{
+			"destination clipped",
+			grid(4, 2, "abcd", "efgh"), 0, 0, 4, 2, 2, 1,
+			[][]string{{"a", "b", "c", "d"}, {"e", "f", "a", "b"}},
+		},
+		{
+			// The wide cell doesn't fit, and the one it overlapped is
+			// split.
+			"wide clipped",
+			grid(4, 1, "a世"), 0, 0, 3, 1, 2, 0,
+			[][]string{{"a", " ", "a", " "}},
+		},
+		{
+			"empty",
+			grid(4, 1, "abcd"), 0, 0, 0, 1, 1, 0,
+			[][]string{{"a", "b", "c", "d"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cellbuf.CopyRect(c.src, c.dx, c.dy, c.src, c.x, c.y, c.w, c.h)
+			if got := rows(c.src); !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestCopyRectOtherGrid(t *testing.T) {
+	src := grid(4, 1, "世ab")
+	dst := grid(4, 2, "wxyz", "wxyz")
+	cellbuf.CopyRect(dst, 1, 1, src, 1, 0, 3, 1)
+	expect := [][]string{{"w", "x", "y", "z"}, {"w", " ", "a", "b"}}
+	if got := rows(dst); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+	if got := rows(src); !reflect.DeepEqual(got, [][]string{{"世", "", "a", "b"}}) {
+		t.Errorf("source changed: %q", got)
+	}
+}
+
+func TestSetContentStyle(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect cellbuf.Style
+	}{
+		{"underline style", "\x1b[4:3mx", cellbuf.Style{UlStyle: cellbuf.CurlyUnderline}},
+		{"underline style then bold", "\x1b[4:3;1mx", cellbuf.Style{UlStyle: cellbuf.CurlyUnderline, Attrs: cellbuf.BoldAttr}},
+		{"rgb", "\x1b[38;2;1;2;3mx", cellbuf.Style{Fg: ansi.TrueColor(0x010203)}},
+		{"rgb subparams", "\x1b[38:2:1:2:3mx", cellbuf.Style{Fg: ansi.TrueColor(0x010203)}},
+		{"256", "\x1b[48;5;200mx", cellbuf.Style{Bg: ansi.ExtendedColor(200)}},
+		{"short", "\x1b[38mx", cellbuf.Style{}},
+		{"underline at end", "\x1b[1;4mx", cellbuf.Style{UlStyle: cellbuf.SingleUnderline, Attrs: cellbuf.BoldAttr}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := grid(2, 1, c.input)
+			got, _ := g.At(0, 0)
+			if !got.Style.Equal(c.expect) {
+				t.Errorf("expected %q, got %q", c.expect.Sequence(), got.Style.Sequence())
+			}
+		})
+	}
+}
+
+func TestSetContentShortColor(t *testing.T) {
+	// Extended colors missing parameters are ignored.
+	for _, input := range []string{"\x1b[38mx", "\x1b[38;5mx", "\x1b[48;2;1;2mx", "\x1b[58:2mx"} {
+		g := grid(2, 1, input)
+		c, _ := g.At(0, 0)
+		if c.Style.Fg != nil || c.Style.Bg != nil || c.Style.Ul != nil {
+			t.Errorf("%q: expected no colors, got %q", input, c.Style.Sequence())
+		}
+	}
+}
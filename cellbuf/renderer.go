@@ -0,0 +1,200 @@
+package cellbuf
+
+import (
+	"io"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Renderer draws grids on a terminal screen. The first frame is drawn on a
+// cleared screen, and the following frames only write the cells that
+// changed since the previous one.
+//
+// The renderer assumes nothing else writes to the screen. Call
+// [Renderer.Redraw] when something does, like after a resize, to draw the
+// next frame from scratch.
+//
+//	r := cellbuf.NewRenderer(os.Stdout)
+//	r.SyncOutput = true
+//	for frame := range frames {
+//		if err := r.Render(frame); err != nil {
+//			return err
+//		}
+//	}
+type Renderer struct {
+	// SyncOutput wraps each frame in synchronized update sequences, so that
+	// the terminal displays it at once. See [ansi.BeginSync].
+	SyncOutput bool
+
+	w    io.Writer
+	prev *Buffer // prev is the last frame drawn, or nil to redraw it all.
+	scr  screenWriter
+}
+
+// NewRenderer returns a new renderer that writes to w.
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{
+		w:   w,
+		scr: screenWriter{x: -1, y: -1},
+	}
+}
+
+// Redraw makes the next call to [Renderer.Render] clear the screen and draw
+// the whole frame.
+func (r *Renderer) Redraw() {
+	r.prev = nil
+	r.scr.x, r.scr.y, r.scr.wrap = -1, -1, false
+}
+
+// Render draws the grid on the screen, writing only what changed since the
+// last frame. The cursor is left after the last cell written, and the style
+// and hyperlink are reset.
+func (r *Renderer) Render(g Grid) error {
+	w := &r.scr
+	w.buf.Reset()
+	w.width = g.Width()
+
+	if r.prev == nil || r.prev.Width() != g.Width() || r.prev.Height() != g.Height() {
+		w.buf.WriteString(ansi.ResetStyle) //nolint:errcheck
+		w.clear()
+		w.draw(g)
+		r.prev = &Buffer{}
+	} else {
+		w.diff(r.prev, g)
+	}
+	w.reset()
+
+	r.prev.Resize(g.Width(), g.Height())
+	for y := 0; y < g.Height(); y++ {
+		for x := 0; x < g.Width(); x++ {
+			c, _ := g.At(x, y)
+			r.prev.Set(x, y, c)
+		}
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	if r.SyncOutput {
+		_, err := io.WriteString(r.w, ansi.Synchronized(w.buf.String()))
+		return err
+	}
+	_, err := r.w.Write(w.buf.Bytes())
+	return err
+}
+
+// diff writes the cells of next that differ from prev. Both grids must have
+// the same size.
+func (w *screenWriter) diff(prev, next Grid) {
+	height := next.Height()
+
+	// Erase the blank lines at the bottom at once when more than one of
+	// them changed.
+	blankFrom := height
+	for blankFrom > 0 && lineEnd(next, blankFrom-1) == 0 {
+		blankFrom--
+	}
+	var dirty int
+	for y := blankFrom; y < height; y++ {
+		if first, _ := dirtySpan(prev, next, y, 0); first >= 0 {
+			dirty++
+		}
+	}
+	if dirty > 1 {
+		height = blankFrom
+	}
+
+	for y := 0; y < height; y++ {
+		w.diffLine(prev, next, y)
+	}
+
+	if height < next.Height() {
+		w.reset()
+		w.moveTo(0, height)
+		w.buf.WriteString(ansi.EraseScreenBelow) //nolint:errcheck
+	}
+}
+
+// diffLine writes the cells of the yth line of next that differ from prev.
+// When the blank cells at the end of the line changed, it erases them at
+// once if that is shorter.
+func (w *screenWriter) diffLine(prev, next Grid, y int) {
+	first, last := dirtySpan(prev, next, y, 0)
+	if first < 0 {
+		return
+	}
+
+	end := lineEnd(next, y)
+	if tail, _ := dirtySpan(prev, next, y, end); tail >= 0 && last-tail+1 > len(ansi.EraseLineRight) {
+		last = end - 1
+	} else {
+		end = -1
+	}
+
+	for x := first; x <= last; {
+		c, err := next.At(x, y)
+		if err != nil {
+			break
+		}
+		if c.Width == 0 {
+			x++
+			continue
+		}
+		if cellDirty(prev, next, x, y, c.Width) {
+			w.skipTo(next, x, y)
+			w.put(c)
+		}
+		x += c.Width
+	}
+
+	if end >= 0 {
+		w.reset()
+		w.skipTo(next, end, y)
+		w.buf.WriteString(ansi.EraseLineRight) //nolint:errcheck
+	}
+}
+
+// dirtySpan returns the first and last columns of the yth line, starting at
+// x, where next differs from prev, or -1 when none does.
+func dirtySpan(prev, next Grid, y, x int) (first, last int) {
+	first, last = -1, -1
+	for ; x < next.Width(); x++ {
+		if cellDirty(prev, next, x, y, 1) {
+			if first < 0 {
+				first = x
+			}
+			last = x
+		}
+	}
+	return first, last
+}
+
+// cellDirty reports whether any of the n cells at x, y differ between prev
+// and next.
+func cellDirty(prev, next Grid, x, y, n int) bool {
+	for i := x; i < x+n; i++ {
+		a, aerr := prev.At(i, y)
+		b, berr := next.At(i, y)
+		if aerr != berr || !a.Equal(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineEnd returns the column after the last cell of the yth line that isn't
+// blank.
+func lineEnd(g Grid, y int) int {
+	for x := g.Width() - 1; x >= 0; x-- {
+		c, err := g.At(x, y)
+		if err != nil || isBlank(c) {
+			continue
+		}
+		if c.Width == 0 {
+			// The last column of a wide cell.
+			return x + 1
+		}
+		return x + c.Width
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package cellbuf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// frame returns an 8x3 grid with the given styled lines.
+func frame(lines ...string) *cellbuf.Buffer {
+	return grid(8, 3, lines...)
+}
+
+func TestRenderer(t *testing.T) {
+	// Each frame is drawn over the previous one.
+	cases := []struct {
+		name   string
+		frame  *cellbuf.Buffer
+		expect string
+	}{
+		{"first frame", frame("ab", "", "  c"), "\x1b[m\x1b[H\x1b[2Jab\x1b[2Bc"},
+		{"unchanged", frame("ab", "", "  c"), ""},
+		{"cup", frame("ax", "", "  c"), "\x1b[;2Hx"},
+		{"carriage return", frame("\x1b[1max\x1b[m", "", "  c"), "\r\x1b[1max\x1b[m"},
+		{"style removed", frame("abcdefgh", "", "  c"), "\rabcdefgh"},
+		{"erase line", frame("a", "", "  c"), "\x1b[6D\x1b[0K"},
+		{"next line", frame("a", "x", "  c"), "\r\nx"},
+		{"erase below", frame("a", "", ""), "\b\x1b[0J"},
+		{"cup over cuu", frame("abcdefgh", "", ""), "\x1b[;2Hbcdefgh"},
+		{"pending wrap", frame("abcdefgX", "y", ""), "\x1b[;8HX\r\ny"},
+		{
+			"sgr delta",
+			frame("a\x1b[31mb\x1b[44mc", "", ""),
+			"\x1b[A\x1b[31mb\x1b[44mc\x1b[m\x1b[0K\r\n ",
+		},
+		{"sgr color only", frame("a\x1b[32mb\x1b[44mc", "", ""), "\x1b[A\x1b[32mb\x1b[44mc\x1b[m"},
+		{"wide", frame("a世c", "", ""), "\x1b[2D世c"},
+		{"wide removed", frame("ab c", "", ""), "\x1b[3Db "},
+		{"skip by writing", frame("a    b", "", ""), "\x1b[2D    b"},
+		{"backspace", frame("a    c", "q", ""), "\bc\r\nq"},
+	}
+
+	var out bytes.Buffer
+	r := cellbuf.NewRenderer(&out)
+	for _, c := range cases {
+		out.Reset()
+		if err := r.Render(c.frame); err != nil {
+			t.Fatal(err)
+		}
+		if got := out.String(); got != c.expect {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestRendererSyncOutput(t *testing.T) {
+	var out bytes.Buffer
+	r := cellbuf.NewRenderer(&out)
+	r.SyncOutput = true
+
+	if err := r.Render(frame("ab")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "\x1b[?2026h\x1b[m\x1b[H\x1b[2Jab\x1b[?2026l"; out.String() != expect {
+		t.Errorf("expected %q, got %q", expect, out.String())
+	}
+
+	// Nothing is written, not even the synchronized update sequences, when
+	// nothing changed.
+	out.Reset()
+	if err := r.Render(frame("ab")); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := r.Render(frame("ac")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "\x1b[?2026h\bc\x1b[?2026l"; out.String() != expect {
+		t.Errorf("expected %q, got %q", expect, out.String())
+	}
+}
+
+func TestRendererRedraw(t *testing.T) {
+	var out bytes.Buffer
+	r := cellbuf.NewRenderer(&out)
+	if err := r.Render(frame("ab")); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	r.Redraw()
+	if err := r.Render(frame("ab")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "\x1b[m\x1b[H\x1b[2Jab"; out.String() != expect {
+		t.Errorf("redraw: expected %q, got %q", expect, out.String())
+	}
+
+	// A grid of another size is drawn on a cleared screen too, from the
+	// cursor position left by the last frame.
+	out.Reset()
+	if err := r.Render(grid(4, 2, "ab")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "\x1b[m\r\x1b[2Jab"; out.String() != expect {
+		t.Errorf("resize: expected %q, got %q", expect, out.String())
+	}
+}
@@ -0,0 +1,135 @@
+package cellbuf
+
+import (
+	"bytes"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// screenWriter writes cells to a terminal screen, keeping track of the
+// cursor position, style, and hyperlink to keep the output to a minimum.
+type screenWriter struct {
+	buf   bytes.Buffer
+	width int // width of the screen
+
+	x, y int // cursor position, or -1 when unknown
+	pen  Style
+	link Link
+
+	// wrap reports whether the cursor is past the last column, where the
+	// next character wraps to the next line.
+	wrap bool
+}
+
+// moveTo moves the cursor to the given position.
+func (w *screenWriter) moveTo(x, y int) {
+	if w.wrap && x == w.x && y == w.y {
+		// Cancel the pending wrap.
+		w.buf.WriteString(cursorPosition(x, y)) //nolint:errcheck
+	} else {
+		w.buf.WriteString(moveCursor(w.x, w.y, x, y)) //nolint:errcheck
+	}
+	w.x, w.y = x, y
+	w.wrap = false
+}
+
+// skipTo moves the cursor forward on the current line to x. When shorter
+// than moving the cursor, it writes the cells of g in between again instead.
+func (w *screenWriter) skipTo(g Grid, x, y int) {
+	if y == w.y && x > w.x && w.x >= 0 {
+		mv := moveCursor(w.x, w.y, x, y)
+		var n int
+		for i := w.x; i < x && n < len(mv); i++ {
+			c, err := g.At(i, y)
+			if err != nil || c.Width > 0 && (!c.Style.Equal(w.pen) || c.Link != w.link) {
+				n = len(mv)
+				break
+			}
+			n += len(c.Content)
+		}
+		if n < len(mv) {
+			for i := w.x; i < x; i++ {
+				c, _ := g.At(i, y)
+				w.buf.WriteString(c.Content) //nolint:errcheck
+			}
+			w.x = x
+			return
+		}
+	}
+	w.moveTo(x, y)
+}
+
+// setPen updates the style and hyperlink of the cells to write.
+func (w *screenWriter) setPen(s Style, l Link) {
+	if !s.Equal(w.pen) {
+		w.buf.WriteString(s.Diff(w.pen)) //nolint:errcheck
+		w.pen = s
+	}
+	if l != w.link {
+		if l.Empty() {
+			w.buf.WriteString(ansi.ResetHyperlink()) //nolint:errcheck
+		} else {
+			w.buf.WriteString(ansi.SetHyperlink(l.URL, l.URLID)) //nolint:errcheck
+		}
+		w.link = l
+	}
+}
+
+// put writes the cell at the cursor position and advances the cursor.
+func (w *screenWriter) put(c Cell) {
+	w.setPen(c.Style, c.Link)
+	w.buf.WriteString(c.Content) //nolint:errcheck
+	w.x += c.Width
+	if w.x >= w.width {
+		// The cursor stays on the last column, waiting to wrap.
+		w.x = w.width - 1
+		w.wrap = true
+	}
+}
+
+// reset resets the style and hyperlink.
+func (w *screenWriter) reset() {
+	w.setPen(Style{}, Link{})
+}
+
+// clear clears the screen and moves the cursor to the top-left corner.
+func (w *screenWriter) clear() {
+	w.reset()
+	w.moveTo(0, 0)
+	w.buf.WriteString(ansi.EraseEntireScreen) //nolint:errcheck
+}
+
+// draw writes the cells of g that aren't blank, assuming a cleared screen.
+func (w *screenWriter) draw(g Grid) {
+	for y := 0; y < g.Height(); y++ {
+		for x := 0; x < g.Width(); x++ {
+			c, err := g.At(x, y)
+			if err != nil || c.Width == 0 || isBlank(c) {
+				continue
+			}
+			w.skipTo(g, x, y)
+			w.put(c)
+		}
+	}
+}
+
+// isBlank reports whether the cell looks like a cleared cell.
+func isBlank(c Cell) bool {
+	return c.Equal(spaceCell)
+}
+
+// RenderScreen returns the sequences that draw the grid on the terminal
+// screen, with the top-left cell of the grid at the top-left corner of the
+// screen. Unlike [Render], it clears the screen first and moves the cursor
+// over blank cells instead of writing spaces, which makes for a shorter
+// output for full-screen apps. The cursor is left after the last cell
+// written, and the style and hyperlink are reset.
+func RenderScreen(g Grid) string {
+	w := screenWriter{width: g.Width(), x: -1, y: -1}
+	w.buf.WriteString(ansi.ResetStyle) //nolint:errcheck
+	w.clear()
+	w.draw(g)
+	w.reset()
+
+	return w.buf.String()
+}
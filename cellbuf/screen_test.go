@@ -0,0 +1,40 @@
+package cellbuf_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+func TestRenderScreen(t *testing.T) {
+	cases := []struct {
+		name   string
+		lines  []string
+		expect string
+	}{
+		{"empty", nil, "\x1b[m\x1b[H\x1b[2J"},
+		{"cud", []string{"ab", "", "  c"}, "\x1b[m\x1b[H\x1b[2Jab\x1b[2Bc"},
+		{"next line", []string{"abcdefgh", "x"}, "\x1b[m\x1b[H\x1b[2Jabcdefgh\r\nx"},
+		{"wide", []string{"a世b", "  世"}, "\x1b[m\x1b[H\x1b[2Ja世b\x1b[2;3H世"},
+		{"styled blanks", []string{"\x1b[44m  \x1b[m  x"}, "\x1b[m\x1b[H\x1b[2J\x1b[44m  \x1b[2C\x1b[mx"},
+		{"skip styled", []string{"\x1b[1ma\x1b[m b"}, "\x1b[m\x1b[H\x1b[2J\x1b[1ma\x1b[C\x1b[mb"},
+		{"skip by writing", []string{"a b"}, "\x1b[m\x1b[H\x1b[2Ja b"},
+		{
+			"hyperlink",
+			[]string{"\x1b]8;;https://charm.sh\x07ab\x1b]8;;\x07c"},
+			"\x1b[m\x1b[H\x1b[2J\x1b]8;;https://charm.sh\x07ab\x1b]8;;\x07c",
+		},
+		{
+			"hyperlink reset",
+			[]string{"\x1b]8;;https://charm.sh\x07ab"},
+			"\x1b[m\x1b[H\x1b[2J\x1b]8;;https://charm.sh\x07ab\x1b]8;;\x07",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := cellbuf.RenderScreen(grid(8, 3, c.lines...)); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
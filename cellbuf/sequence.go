@@ -0,0 +1,152 @@
+package cellbuf
+
+import (
+	"bytes"
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// ReadStyle applies the parameters of an SGR (Select Graphic Rendition)
+// sequence to the style. The parameters are the raw parameters of
+// [ansi.CsiSequence], with sub-parameters flagged. No parameters reset the
+// style.
+func ReadStyle(params []int, pen *Style) {
+	if len(params) == 0 {
+		pen.Reset()
+	}
+	for i := 0; i < len(params); i++ {
+		param := parser.Param(params, i)
+		hasMore := parser.HasMore(params, i) // Are there more subparameters i.e. separated by ":"?
+		switch param {
+		case -1, 0: // Reset
+			pen.Reset()
+		case 1: // Bold
+			pen.Bold(true)
+		case 2: // Dim/Faint
+			pen.Faint(true)
+		case 3: // Italic
+			pen.Italic(true)
+		case 4: // Underline
+			if hasMore && i+1 < len(params) { // Only accept subparameters i.e. separated by ":"
+				i++
+				switch parser.Param(params, i) {
+				case 0: // No Underline
+					pen.UnderlineStyle(NoUnderline)
+				case 1: // Single Underline
+					pen.UnderlineStyle(SingleUnderline)
+				case 2: // Double Underline
+					pen.UnderlineStyle(DoubleUnderline)
+				case 3: // Curly Underline
+					pen.UnderlineStyle(CurlyUnderline)
+				case 4: // Dotted Underline
+					pen.UnderlineStyle(DottedUnderline)
+				case 5: // Dashed Underline
+					pen.UnderlineStyle(DashedUnderline)
+				}
+			} else {
+				// Single Underline
+				pen.Underline(true)
+			}
+		case 5: // Slow Blink
+			pen.SlowBlink(true)
+		case 6: // Rapid Blink
+			pen.RapidBlink(true)
+		case 7: // Reverse
+			pen.Reverse(true)
+		case 8: // Conceal
+			pen.Conceal(true)
+		case 9: // Crossed-out/Strikethrough
+			pen.Strikethrough(true)
+		case 22: // Normal Intensity (not bold or faint)
+			pen.Bold(false).Faint(false)
+		case 23: // Not italic, not Fraktur
+			pen.Italic(false)
+		case 24: // Not underlined
+			pen.Underline(false)
+		case 25: // Blink off
+			pen.SlowBlink(false).RapidBlink(false)
+		case 27: // Positive (not reverse)
+			pen.Reverse(false)
+		case 28: // Reveal
+			pen.Conceal(false)
+		case 29: // Not crossed out
+			pen.Strikethrough(false)
+		case 30, 31, 32, 33, 34, 35, 36, 37: // Set foreground
+			pen.Foreground(ansi.Black + ansi.BasicColor(param-30)) //nolint:gosec
+		case 38: // Set foreground 256 or truecolor
+			if c := readColor(&i, params); c != nil {
+				pen.Foreground(c)
+			}
+		case 39: // Default foreground
+			pen.Foreground(nil)
+		case 40, 41, 42, 43, 44, 45, 46, 47: // Set background
+			pen.Background(ansi.Black + ansi.BasicColor(param-40)) //nolint:gosec
+		case 48: // Set background 256 or truecolor
+			if c := readColor(&i, params); c != nil {
+				pen.Background(c)
+			}
+		case 49: // Default Background
+			pen.Background(nil)
+		case 58: // Set underline color
+			if c := readColor(&i, params); c != nil {
+				pen.UnderlineColor(c)
+			}
+		case 59: // Default underline color
+			pen.UnderlineColor(nil)
+		case 90, 91, 92, 93, 94, 95, 96, 97: // Set bright foreground
+			pen.Foreground(ansi.BrightBlack + ansi.BasicColor(param-90)) //nolint:gosec
+		case 100, 101, 102, 103, 104, 105, 106, 107: // Set bright background
+			pen.Background(ansi.BrightBlack + ansi.BasicColor(param-100)) //nolint:gosec
+		}
+	}
+}
+
+// ReadLink applies the data of an OSC 8 hyperlink sequence, like
+// "8;id=1;https://example.com", to the link. An empty URL resets it.
+func ReadLink(data []byte, link *Link) {
+	params := bytes.SplitN(data, []byte{';'}, 3)
+	if len(params) != 3 {
+		return
+	}
+	var id string
+	for _, param := range bytes.Split(params[1], []byte{':'}) {
+		if bytes.HasPrefix(param, []byte("id=")) {
+			id = string(param)
+		}
+	}
+	link.URLID = id
+	link.URL = string(params[2])
+}
+
+// readColor reads an extended color at params[*idxp], the parameter after
+// 38, 48, or 58, and advances *idxp past it.
+func readColor(idxp *int, params []int) (c ansi.Color) {
+	i := *idxp
+	paramsLen := len(params)
+	if i > paramsLen-2 {
+		return
+	}
+	// Note: we accept both main and subparams here
+	switch parser.Param(params, i+1) {
+	case 2: // RGB
+		if i > paramsLen-5 {
+			return
+		}
+		c = color.RGBA{
+			R: uint8(parser.Param(params, i+2)), //nolint:gosec
+			G: uint8(parser.Param(params, i+3)), //nolint:gosec
+			B: uint8(parser.Param(params, i+4)), //nolint:gosec
+			A: 0xff,
+		}
+		*idxp += 4
+	case 5: // 256 colors
+		if i > paramsLen-3 {
+			return
+		}
+		c = ansi.ExtendedColor(parser.Param(params, i+2)) //nolint:gosec
+		*idxp += 2
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package cellbuf_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// csiParams returns the raw parameters of the CSI sequence s.
+func csiParams(s string) (params []int) {
+	p := ansi.GetParser()
+	defer ansi.PutParser(p)
+	p.Parse(func(seq ansi.Sequence) {
+		if csi, ok := seq.(ansi.CsiSequence); ok {
+			params = append([]int(nil), csi.Params...)
+		}
+	}, []byte(s))
+	return
+}
+
+func TestReadStyle(t *testing.T) {
+	bold := cellbuf.Style{Attrs: cellbuf.BoldAttr}
+	cases := []struct {
+		name   string
+		from   cellbuf.Style
+		seq    string
+		expect cellbuf.Style
+	}{
+		{"no params", bold, "\x1b[m", cellbuf.Style{}},
+		{"reset", bold, "\x1b[0m", cellbuf.Style{}},
+		{"missing param", bold, "\x1b[;3m", cellbuf.Style{Attrs: cellbuf.ItalicAttr}},
+		{"keeps style", bold, "\x1b[31m", cellbuf.Style{Attrs: cellbuf.BoldAttr, Fg: ansi.Red}},
+		{"normal intensity", bold, "\x1b[22m", cellbuf.Style{}},
+		{"bright", cellbuf.Style{}, "\x1b[91;102m", cellbuf.Style{Fg: ansi.BrightRed, Bg: ansi.BrightGreen}},
+		{"underline color", cellbuf.Style{}, "\x1b[58;5;1m", cellbuf.Style{Ul: ansi.ExtendedColor(1)}},
+		{"underline style", cellbuf.Style{}, "\x1b[4:5m", cellbuf.Style{UlStyle: cellbuf.DashedUnderline}},
+		{"no underline", cellbuf.Style{UlStyle: cellbuf.CurlyUnderline}, "\x1b[4:0m", cellbuf.Style{}},
+		{"default colors", cellbuf.Style{Fg: ansi.Red, Bg: ansi.Blue}, "\x1b[39;49m", cellbuf.Style{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pen := c.from
+			cellbuf.ReadStyle(csiParams(c.seq), &pen)
+			if !pen.Equal(c.expect) {
+				t.Errorf("expected %q, got %q", c.expect.Sequence(), pen.Sequence())
+			}
+		})
+	}
+}
+
+func TestReadLink(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		expect cellbuf.Link
+	}{
+		{"url", "8;;https://charm.sh", cellbuf.Link{URL: "https://charm.sh"}},
+		{"id", "8;id=1;https://charm.sh", cellbuf.Link{URL: "https://charm.sh", URLID: "id=1"}},
+		{"id among params", "8;foo=bar:id=1;https://charm.sh", cellbuf.Link{URL: "https://charm.sh", URLID: "id=1"}},
+		{"semicolon in url", "8;;https://charm.sh/?a;b", cellbuf.Link{URL: "https://charm.sh/?a;b"}},
+		{"reset", "8;;", cellbuf.Link{}},
+		{"invalid", "8;https://charm.sh", cellbuf.Link{URL: "https://example.com"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			link := cellbuf.Link{URL: "https://example.com"}
+			cellbuf.ReadLink([]byte(c.data), &link)
+			if link != c.expect {
+				t.Errorf("expected %+v, got %+v", c.expect, link)
+			}
+		})
+	}
+}
@@ -106,18 +106,7 @@ func (s Style) Sequence() string {
 		}
 	}
 	if s.UlStyle != NoUnderline {
-		switch s.UlStyle {
-		case SingleUnderline:
-			b = b.Underline()
-		case DoubleUnderline:
-			b = b.DoubleUnderline()
-		case CurlyUnderline:
-			b = b.CurlyUnderline()
-		case DottedUnderline:
-			b = b.DottedUnderline()
-		case DashedUnderline:
-			b = b.DashedUnderline()
-		}
+		b = appendUnderlineStyle(b, s.UlStyle)
 	}
 	if s.Fg != nil {
 		b = b.ForegroundColor(s.Fg)
@@ -138,88 +127,167 @@ func (s Style) DiffSequence(o Style) string {
 	if o.Empty() {
 		return s.Sequence()
 	}
+	return s.Diff(o)
+}
 
-	var b ansi.Style
-
-	if !colorEqual(s.Fg, o.Fg) {
-		b = b.ForegroundColor(s.Fg)
+// Diff returns the shortest ANSI sequence that changes the style from to s.
+// It's either the attributes that changed, or a reset followed by s when
+// that's shorter. It returns an empty string when the styles are equal.
+func (s Style) Diff(from Style) string {
+	if s.Equal(from) {
+		return ""
 	}
-
-	if !colorEqual(s.Bg, o.Bg) {
-		b = b.BackgroundColor(s.Bg)
+	if s.Empty() {
+		return ansi.ResetStyle
 	}
-
-	if !colorEqual(s.Ul, o.Ul) {
-		b = b.UnderlineColor(s.Ul)
+	full := s.Sequence()
+	if from.Empty() {
+		return full
 	}
 
-	var (
-		noBlink  bool
-		isNormal bool
-	)
+	var b ansi.Style
 
-	if s.Attrs != o.Attrs {
-		if s.Attrs&BoldAttr != o.Attrs&BoldAttr {
-			if s.Attrs&BoldAttr != 0 {
-				b = b.Bold()
-			} else if !isNormal {
-				isNormal = true
-				b = b.NormalIntensity()
-			}
-		}
-		if s.Attrs&FaintAttr != o.Attrs&FaintAttr {
-			if s.Attrs&FaintAttr != 0 {
-				b = b.Faint()
-			} else if !isNormal {
-				isNormal = true
-				b = b.NormalIntensity()
-			}
-		}
-		if s.Attrs&ItalicAttr != o.Attrs&ItalicAttr {
-			if s.Attrs&ItalicAttr != 0 {
-				b = b.Italic()
-			} else {
-				b = b.NoItalic()
-			}
+	// Bold and faint are both turned off by normal intensity, and so are
+	// slow and rapid blink by no blink. Turn the ones s has back on.
+	b = diffAttrs(b, s.Attrs, from.Attrs, BoldAttr|FaintAttr, ansi.Style.NormalIntensity)
+	b = diffAttrs(b, s.Attrs, from.Attrs, SlowBlinkAttr|RapidBlinkAttr, ansi.Style.NoBlink)
+	b = diffAttrs(b, s.Attrs, from.Attrs, ItalicAttr, ansi.Style.NoItalic)
+	b = diffAttrs(b, s.Attrs, from.Attrs, ReverseAttr, ansi.Style.NoReverse)
+	b = diffAttrs(b, s.Attrs, from.Attrs, ConcealAttr, ansi.Style.NoConceal)
+	b = diffAttrs(b, s.Attrs, from.Attrs, StrikethroughAttr, ansi.Style.NoStrikethrough)
+
+	if s.UlStyle != from.UlStyle {
+		if s.UlStyle == NoUnderline {
+			b = b.NoUnderline()
+		} else {
+			b = appendUnderlineStyle(b, s.UlStyle)
 		}
-		if s.Attrs&SlowBlinkAttr != o.Attrs&SlowBlinkAttr {
-			if s.Attrs&SlowBlinkAttr != 0 {
-				b = b.SlowBlink()
-			} else if !noBlink {
-				b = b.NoBlink()
-			}
-		}
-		if s.Attrs&RapidBlinkAttr != o.Attrs&RapidBlinkAttr {
-			if s.Attrs&RapidBlinkAttr != 0 {
-				b = b.RapidBlink()
-			} else if !noBlink {
-				b = b.NoBlink()
-			}
+	}
+	if !colorEqual(s.Fg, from.Fg) {
+		if s.Fg == nil {
+			b = b.DefaultForegroundColor()
+		} else {
+			b = b.ForegroundColor(s.Fg)
 		}
-		if s.Attrs&ReverseAttr != o.Attrs&ReverseAttr {
-			if s.Attrs&ReverseAttr != 0 {
-				b = b.Reverse()
-			} else {
-				b = b.NoReverse()
-			}
+	}
+	if !colorEqual(s.Bg, from.Bg) {
+		if s.Bg == nil {
+			b = b.DefaultBackgroundColor()
+		} else {
+			b = b.BackgroundColor(s.Bg)
 		}
-		if s.Attrs&ConcealAttr != o.Attrs&ConcealAttr {
-			if s.Attrs&ConcealAttr != 0 {
-				b = b.Conceal()
-			} else {
-				b = b.NoConceal()
-			}
+	}
+	if !colorEqual(s.Ul, from.Ul) {
+		if s.Ul == nil {
+			b = b.DefaultUnderlineColor()
+		} else {
+			b = b.UnderlineColor(s.Ul)
 		}
-		if s.Attrs&StrikethroughAttr != o.Attrs&StrikethroughAttr {
-			if s.Attrs&StrikethroughAttr != 0 {
-				b = b.Strikethrough()
-			} else {
-				b = b.NoStrikethrough()
-			}
+	}
+
+	// Resetting takes a "0;" on top of the full sequence.
+	diff := b.String()
+	if len(diff) > len(full)+2 {
+		return "\x1b[0;" + full[2:]
+	}
+	return diff
+}
+
+// diffAttrs appends the attributes in mask that changed from o to s to b.
+// When one of them is turned off, off is appended to turn all of them off,
+// and the ones s has are turned back on.
+func diffAttrs(b ansi.Style, s, o, mask AttrMask, off func(ansi.Style) ansi.Style) ansi.Style {
+	s, o = s&mask, o&mask
+	if s == o {
+		return b
+	}
+	on := s &^ o
+	if o&^s != 0 {
+		b = off(b)
+		on = s
+	}
+	for _, a := range [...]AttrMask{
+		BoldAttr, FaintAttr, ItalicAttr, SlowBlinkAttr,
+		RapidBlinkAttr, ReverseAttr, ConcealAttr, StrikethroughAttr,
+	} {
+		if on&a != 0 {
+			b = appendAttr(b, a)
 		}
 	}
+	return b
+}
 
-	return b.String()
+// appendAttr appends the attribute a to b.
+func appendAttr(b ansi.Style, a AttrMask) ansi.Style {
+	switch a {
+	case BoldAttr:
+		return b.Bold()
+	case FaintAttr:
+		return b.Faint()
+	case ItalicAttr:
+		return b.Italic()
+	case SlowBlinkAttr:
+		return b.SlowBlink()
+	case RapidBlinkAttr:
+		return b.RapidBlink()
+	case ReverseAttr:
+		return b.Reverse()
+	case ConcealAttr:
+		return b.Conceal()
+	case StrikethroughAttr:
+		return b.Strikethrough()
+	}
+	return b
+}
+
+// appendUnderlineStyle appends the underline style u to b.
+func appendUnderlineStyle(b ansi.Style, u UnderlineStyle) ansi.Style {
+	switch u {
+	case SingleUnderline:
+		return b.Underline()
+	case DoubleUnderline:
+		return b.DoubleUnderline()
+	case CurlyUnderline:
+		return b.CurlyUnderline()
+	case DottedUnderline:
+		return b.DottedUnderline()
+	case DashedUnderline:
+		return b.DashedUnderline()
+	}
+	return b
+}
+
+// Inherit returns the style with its unset fields taken from parent: the
+// colors that are nil, and the underline style when there's none. The
+// attributes of both styles are combined, since an attribute can't be unset
+// on its own.
+func (s Style) Inherit(parent Style) Style {
+	if s.Fg == nil {
+		s.Fg = parent.Fg
+	}
+	if s.Bg == nil {
+		s.Bg = parent.Bg
+	}
+	if s.Ul == nil {
+		s.Ul = parent.Ul
+	}
+	if s.UlStyle == NoUnderline {
+		s.UlStyle = parent.UlStyle
+	}
+	s.Attrs |= parent.Attrs
+	return s
+}
+
+// Merge returns the style with the set fields of o on top of it. It's the
+// same as o inheriting from s, see [Style.Inherit].
+func (s Style) Merge(o Style) Style {
+	return o.Inherit(s)
+}
+
+// Patch sets the set fields of o on the style, see [Style.Merge].
+func (s *Style) Patch(o Style) *Style {
+	*s = s.Merge(o)
+	return s
 }
 
 // Equal returns true if the style is equal to the other style.
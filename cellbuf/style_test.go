@@ -0,0 +1,163 @@
+package cellbuf_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// style returns a style set up by f.
+func style(f func(s *cellbuf.Style)) cellbuf.Style {
+	var s cellbuf.Style
+	f(&s)
+	return s
+}
+
+func TestStyleDiff(t *testing.T) {
+	cases := []struct {
+		name   string
+		from   cellbuf.Style
+		to     cellbuf.Style
+		expect string
+	}{
+		{
+			"equal",
+			style(func(s *cellbuf.Style) { s.Bold(true).Foreground(ansi.Red) }),
+			style(func(s *cellbuf.Style) { s.Bold(true).Foreground(ansi.Red) }),
+			"",
+		},
+		{
+			"to empty",
+			style(func(s *cellbuf.Style) { s.Bold(true) }),
+			cellbuf.Style{},
+			"\x1b[m",
+		},
+		{
+			"from empty",
+			cellbuf.Style{},
+			style(func(s *cellbuf.Style) { s.Bold(true).Foreground(ansi.Red) }),
+			"\x1b[1;31m",
+		},
+		{
+			"foreground change",
+			style(func(s *cellbuf.Style) { s.Foreground(ansi.Red) }),
+			style(func(s *cellbuf.Style) { s.Foreground(ansi.Blue) }),
+			"\x1b[34m",
+		},
+		{
+			"foreground removed",
+			style(func(s *cellbuf.Style) { s.Bold(true).Foreground(ansi.Red) }),
+			style(func(s *cellbuf.Style) { s.Bold(true) }),
+			"\x1b[39m",
+		},
+		{
+			"background added",
+			style(func(s *cellbuf.Style) { s.Bold(true) }),
+			style(func(s *cellbuf.Style) { s.Bold(true).Background(ansi.Green) }),
+			"\x1b[42m",
+		},
+		{
+			"bold removed",
+			style(func(s *cellbuf.Style) { s.Bold(true).Italic(true).Foreground(ansi.Red) }),
+			style(func(s *cellbuf.Style) { s.Italic(true).Foreground(ansi.Red) }),
+			"\x1b[22m",
+		},
+		{
+			"bold removed, faint kept",
+			style(func(s *cellbuf.Style) { s.Bold(true).Faint(true).Foreground(ansi.ExtendedColor(200)) }),
+			style(func(s *cellbuf.Style) { s.Faint(true).Foreground(ansi.ExtendedColor(200)) }),
+			"\x1b[22;2m",
+		},
+		{
+			"reset is shorter",
+			style(func(s *cellbuf.Style) { s.Bold(true).Faint(true) }),
+			style(func(s *cellbuf.Style) { s.Faint(true) }),
+			"\x1b[0;2m",
+		},
+		{
+			"blink changed",
+			style(func(s *cellbuf.Style) { s.SlowBlink(true).Foreground(ansi.ExtendedColor(200)) }),
+			style(func(s *cellbuf.Style) { s.RapidBlink(true).Foreground(ansi.ExtendedColor(200)) }),
+			"\x1b[25;6m",
+		},
+		{
+			"attributes off",
+			style(func(s *cellbuf.Style) {
+				s.Italic(true).Reverse(true).Conceal(true).Strikethrough(true).Foreground(ansi.ExtendedColor(200))
+			}),
+			style(func(s *cellbuf.Style) { s.Foreground(ansi.ExtendedColor(200)) }),
+			"\x1b[0;38;5;200m",
+		},
+		{
+			"underline style",
+			style(func(s *cellbuf.Style) { s.Underline(true) }),
+			style(func(s *cellbuf.Style) { s.UnderlineStyle(cellbuf.CurlyUnderline) }),
+			"\x1b[4:3m",
+		},
+		{
+			"underline removed",
+			style(func(s *cellbuf.Style) { s.Bold(true).Underline(true) }),
+			style(func(s *cellbuf.Style) { s.Bold(true) }),
+			"\x1b[24m",
+		},
+		{
+			"underline color",
+			style(func(s *cellbuf.Style) { s.Underline(true) }),
+			style(func(s *cellbuf.Style) { s.Underline(true).UnderlineColor(ansi.Red) }),
+			"\x1b[58;5;1m",
+		},
+		{
+			"underline color removed",
+			style(func(s *cellbuf.Style) { s.Underline(true).UnderlineColor(ansi.Red) }),
+			style(func(s *cellbuf.Style) { s.Underline(true) }),
+			"\x1b[59m",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.to.Diff(c.from); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestStyleDiffSequence(t *testing.T) {
+	bold := style(func(s *cellbuf.Style) { s.Bold(true) })
+	if got := bold.DiffSequence(cellbuf.Style{}); got != "\x1b[1m" {
+		t.Errorf("expected %q, got %q", "\x1b[1m", got)
+	}
+	if got := bold.DiffSequence(bold); got != "" {
+		t.Errorf("expected no sequence, got %q", got)
+	}
+}
+
+func TestStyleInherit(t *testing.T) {
+	parent := style(func(s *cellbuf.Style) {
+		s.Bold(true).Foreground(ansi.Red).Background(ansi.Blue).Underline(true)
+	})
+	child := style(func(s *cellbuf.Style) { s.Italic(true).Foreground(ansi.Green) })
+
+	expect := style(func(s *cellbuf.Style) {
+		s.Bold(true).Italic(true).Foreground(ansi.Green).Background(ansi.Blue).Underline(true)
+	})
+	if got := child.Inherit(parent); !got.Equal(expect) {
+		t.Errorf("Inherit: expected %+v, got %+v", expect, got)
+	}
+	if got := parent.Merge(child); !got.Equal(expect) {
+		t.Errorf("Merge: expected %+v, got %+v", expect, got)
+	}
+
+	patched := parent
+	patched.Patch(child)
+	if !patched.Equal(expect) {
+		t.Errorf("Patch: expected %+v, got %+v", expect, patched)
+	}
+
+	// The underline style of the child takes precedence.
+	curly := style(func(s *cellbuf.Style) { s.UnderlineStyle(cellbuf.CurlyUnderline) })
+	if got := curly.Inherit(parent); got.UlStyle != cellbuf.CurlyUnderline {
+		t.Errorf("expected curly underline, got %v", got.UlStyle)
+	}
+}
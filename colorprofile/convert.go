@@ -0,0 +1,84 @@
+package colorprofile
+
+import (
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// cubeLevels are the levels of the red, green, and blue components of the
+// 6x6x6 color cube of the 256 extended colors.
+var cubeLevels = [6]uint32{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+
+// toExtended returns the extended color closest to c. It's either a color of
+// the color cube, or a shade of gray. The 16 basic colors are left out since
+// terminals change them with their themes.
+func toExtended(c color.Color) ansi.ExtendedColor {
+	r, g, b := rgb(c)
+
+	// The closest color of the cube.
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// The closest shade of gray, from 0x08 to 0xee by steps of 10.
+	avg := (r + g + b) / 3
+	gi = 0
+	if avg > 0x08 {
+		gi = int((avg - 0x08 + 5) / 10)
+	}
+	if gi > 23 {
+		gi = 23
+	}
+	level := uint32(0x08 + 10*gi)
+	grayDist := distance(r, g, b, level, level, level)
+
+	if grayDist < cubeDist {
+		return ansi.ExtendedColor(232 + gi)
+	}
+	return ansi.ExtendedColor(cube)
+}
+
+// cubeIndex returns the index of the cube level closest to v.
+func cubeIndex(v uint32) int {
+	switch {
+	case v < 0x30:
+		return 0
+	case v < 0x73:
+		return 1
+	}
+	return int((v - 0x23) / 0x28)
+}
+
+// toBasic returns the basic color closest to c.
+func toBasic(c color.Color) ansi.BasicColor {
+	r, g, b := rgb(c)
+	best, bestDist := ansi.BasicColor(0), ^uint32(0)
+	for i := ansi.Black; i <= ansi.BrightWhite; i++ {
+		br, bg, bb := rgb(i)
+		if d := distance(r, g, b, br, bg, bb); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// rgb returns the 8-bit red, green, and blue components of c.
+func rgb(c color.Color) (r, g, b uint32) {
+	r, g, b, _ = c.RGBA()
+	return r >> 8, g >> 8, b >> 8
+}
+
+// distance returns the squared distance between two colors, weighted for
+// the way human eyes perceive the components.
+func distance(r1, g1, b1, r2, g2, b2 uint32) uint32 {
+	dr, dg, db := diff(r1, r2), diff(g1, g2), diff(b1, b2)
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
+
+func diff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
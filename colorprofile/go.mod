@@ -0,0 +1,13 @@
+module github.com/charmbracelet/x/colorprofile
+
+go 1.18
+
+require (
+	github.com/charmbracelet/x/ansi v0.3.2
+	github.com/charmbracelet/x/term v0.2.0
+)
+
+require (
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
@@ -0,0 +1,130 @@
+package colorprofile
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HyperlinkFallback is how a [Writer] shows hyperlinks (OSC 8) when the
+// terminal doesn't support them.
+type HyperlinkFallback byte
+
+// Hyperlink fallbacks.
+const (
+	// HyperlinkStrip removes the hyperlinks, keeping their text.
+	HyperlinkStrip HyperlinkFallback = iota
+
+	// HyperlinkInline writes the URL after the text of the link, like
+	// "text (https://charm.sh)". The URL isn't repeated when it's the text
+	// of the link.
+	HyperlinkInline
+
+	// HyperlinkFootnotes numbers the links, like "text[1]", and writes the
+	// numbered URLs when the writer is flushed, see [Writer.Flush]. Links to
+	// the same URL share the same number.
+	HyperlinkFootnotes
+)
+
+// hyperlinks tracks the hyperlinks written to a [Writer], to show them
+// with a fallback.
+type hyperlinks struct {
+	url  []byte // url is the URL of the open link, or nil.
+	text []byte // text is the beginning of the text of the open link.
+	open bool
+
+	notes []string       // notes are the URLs of the footnotes.
+	ids   map[string]int // ids are the footnote numbers by URL.
+}
+
+// hyperlinkURL returns the URL of the OSC 8 hyperlink data, after the
+// command and up to the terminator.
+//
+//	8 ; params ; url ST
+func hyperlinkURL(data []byte) []byte {
+	parts := bytes.SplitN(data, []byte{';'}, 3)
+	if len(parts) < 3 {
+		return nil
+	}
+	url := parts[2]
+	switch {
+	case bytes.HasSuffix(url, []byte{ansi.ESC, '\\'}):
+		return url[:len(url)-2]
+	case len(url) > 0:
+		switch url[len(url)-1] {
+		case ansi.BEL, ansi.ST, ansi.CAN, ansi.SUB:
+			return url[:len(url)-1]
+		}
+	}
+	return url
+}
+
+// writeHyperlink handles the OSC 8 hyperlink with the given data, on a
+// terminal that doesn't support hyperlinks.
+func (w *Writer) writeHyperlink(data []byte) {
+	w.endHyperlink()
+	if url := hyperlinkURL(data); len(url) > 0 {
+		w.links.url = append(w.links.url[:0], url...)
+		w.links.text = w.links.text[:0]
+		w.links.open = true
+	}
+}
+
+// linkText records the text byte c of the open link, if any. Only what's
+// needed to compare the text to the URL is kept.
+func (w *Writer) linkText(c byte) {
+	if w.links.open && len(w.links.text) <= len(w.links.url) {
+		w.links.text = append(w.links.text, c)
+	}
+}
+
+// endHyperlink closes the open link, if any, and writes its fallback.
+func (w *Writer) endHyperlink() {
+	l := &w.links
+	if !l.open {
+		return
+	}
+	l.open = false
+
+	switch w.HyperlinkFallback {
+	case HyperlinkInline:
+		if !bytes.Equal(l.text, l.url) {
+			w.out = append(w.out, " ("...)
+			w.out = append(w.out, l.url...)
+			w.out = append(w.out, ')')
+		}
+	case HyperlinkFootnotes:
+		url := string(l.url)
+		id, ok := l.ids[url]
+		if !ok {
+			if l.ids == nil {
+				l.ids = make(map[string]int)
+			}
+			l.notes = append(l.notes, url)
+			id = len(l.notes)
+			l.ids[url] = id
+		}
+		w.out = append(w.out, '[')
+		w.out = strconv.AppendInt(w.out, int64(id), 10)
+		w.out = append(w.out, ']')
+	}
+}
+
+// Flush writes the footnotes of the links written so far, one per line,
+// like "[1]: https://charm.sh", when hyperlinks are shown as footnotes. An
+// open link is closed first. The numbering starts over afterwards.
+func (w *Writer) Flush() error {
+	w.out = w.out[:0]
+	w.endHyperlink()
+	for i, url := range w.links.notes {
+		w.out = append(w.out, '[')
+		w.out = strconv.AppendInt(w.out, int64(i+1), 10)
+		w.out = append(w.out, "]: "...)
+		w.out = append(w.out, url...)
+		w.out = append(w.out, '\n')
+	}
+	w.links.notes = w.links.notes[:0]
+	w.links.ids = nil
+	return w.flush()
+}
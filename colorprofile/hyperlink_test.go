@@ -0,0 +1,57 @@
+package colorprofile
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterHyperlinkFallback(t *testing.T) {
+	const (
+		link     = "\x1b]8;;https://charm.sh\x1b\\\x1b[1mCharm\x1b[m\x1b]8;;\x1b\\"
+		autolink = "\x1b]8;id=1;https://charm.sh\x07https://charm.sh\x1b]8;;\x07"
+	)
+	cases := []struct {
+		name     string
+		profile  Profile
+		fallback HyperlinkFallback
+		in       string
+		want     string
+	}{
+		{"strip", ANSI, HyperlinkStrip, link, "\x1b[1mCharm\x1b[m"},
+		{"inline", ANSI, HyperlinkInline, link + "!", "\x1b[1mCharm\x1b[m (https://charm.sh)!"},
+		{"inline autolink", ANSI, HyperlinkInline, autolink, "https://charm.sh"},
+		{"inline no tty", NoTTY, HyperlinkInline, link, "Charm (https://charm.sh)"},
+		{"inline unclosed", ANSI, HyperlinkInline, "\x1b]8;;a\x07x\x1b]8;;b\x07y", "x (a)y (b)"},
+		{
+			"footnotes", ANSI, HyperlinkFootnotes,
+			link + " and \x1b]8;;https://go.dev\x07Go\x1b]8;;\x07, " + link + "\n",
+			"\x1b[1mCharm\x1b[m[1] and Go[2], \x1b[1mCharm\x1b[m[1]\n[1]: https://charm.sh\n[2]: https://go.dev\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for i := 0; i <= len(c.in); i++ {
+				var buf bytes.Buffer
+				w := &Writer{Forward: &buf, Profile: c.profile, HyperlinkFallback: c.fallback}
+				w.WriteString(c.in[:i]) // nolint: errcheck
+				w.WriteString(c.in[i:]) // nolint: errcheck
+				w.Flush()               // nolint: errcheck
+				if got := buf.String(); got != c.want {
+					t.Fatalf("split at %d: got %q, want %q", i, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterFlushRestartsFootnotes(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{Forward: &buf, Profile: ANSI, HyperlinkFallback: HyperlinkFootnotes}
+	w.WriteString("\x1b]8;;a\x07x\x1b]8;;\x07\n") // nolint: errcheck
+	w.Flush()                                     // nolint: errcheck
+	w.WriteString("\x1b]8;;b\x07y\x1b]8;;\x07\n") // nolint: errcheck
+	w.Flush()                                     // nolint: errcheck
+	if got, want := buf.String(), "x[1]\n[1]: a\ny[1]\n[1]: b\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
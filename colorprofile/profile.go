@@ -0,0 +1,155 @@
+// Package colorprofile detects the colors a terminal supports, and degrades
+// styled output to what it supports.
+package colorprofile
+
+import (
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// Profile is a color profile, the set of colors a terminal supports.
+// Profiles are ordered, each one supports the colors of the profiles before
+// it.
+type Profile byte
+
+// Color profiles.
+const (
+	// NoTTY is the profile of an output that isn't a terminal, like a file
+	// or a pipe. It supports no escape sequences at all.
+	NoTTY Profile = iota
+
+	// Ascii supports text attributes, like bold and underline, but no
+	// colors.
+	Ascii
+
+	// ANSI supports the 16 basic colors.
+	ANSI
+
+	// ANSI256 supports the 256 extended colors.
+	ANSI256
+
+	// TrueColor supports 24-bit colors.
+	TrueColor
+)
+
+// String implements fmt.Stringer.
+func (p Profile) String() string {
+	switch p {
+	case NoTTY:
+		return "NoTTY"
+	case Ascii:
+		return "Ascii"
+	case ANSI:
+		return "ANSI"
+	case ANSI256:
+		return "ANSI256"
+	case TrueColor:
+		return "TrueColor"
+	}
+	return "Unknown"
+}
+
+// Convert converts c to the closest color the profile supports. It returns
+// nil when the profile supports no colors.
+func (p Profile) Convert(c color.Color) color.Color {
+	if c == nil || p < ANSI {
+		return nil
+	}
+
+	switch c := c.(type) {
+	case ansi.BasicColor:
+		return c
+	case ansi.ExtendedColor:
+		switch {
+		case c < 16:
+			return ansi.BasicColor(c)
+		case p == ANSI:
+			return toBasic(c)
+		}
+		return c
+	}
+
+	switch p {
+	case ANSI:
+		return toBasic(c)
+	case ANSI256:
+		return toExtended(c)
+	}
+	return c
+}
+
+// Detect returns the color profile of the terminal output is written to.
+// environ is the environment of the program, in the form of [os.Environ].
+//
+// The output is a terminal when it has a file descriptor that refers to one,
+// like [os.Stdout] usually does. Otherwise, the profile is [NoTTY], unless
+// CLICOLOR_FORCE is set. The profile then depends on the TERM and COLORTERM
+// variables, and is [Ascii] at most when NO_COLOR is set to any non-empty
+// value, as https://no-color.org asks.
+func Detect(output io.Writer, environ []string) Profile {
+	env := environMap(environ)
+	if !isTTY(output) && !isSet(env["CLICOLOR_FORCE"]) {
+		return NoTTY
+	}
+
+	p := envProfile(env)
+	if env["NO_COLOR"] != "" && p > Ascii {
+		p = Ascii
+	}
+	return p
+}
+
+// envProfile returns the color profile of the terminal described by the
+// environment.
+func envProfile(env map[string]string) Profile {
+	termName := env["TERM"]
+	if termName == "dumb" {
+		return NoTTY
+	}
+
+	switch strings.ToLower(env["COLORTERM"]) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+
+	switch {
+	case strings.HasSuffix(termName, "-direct"), strings.Contains(termName, "truecolor"):
+		return TrueColor
+	case strings.HasPrefix(termName, "xterm-kitty"), strings.HasPrefix(termName, "xterm-ghostty"),
+		strings.HasPrefix(termName, "alacritty"), strings.HasPrefix(termName, "wezterm"):
+		// These terminals always support 24-bit colors, but don't set
+		// COLORTERM over SSH.
+		return TrueColor
+	case strings.Contains(termName, "256color"):
+		return ANSI256
+	}
+	return ANSI
+}
+
+// isTTY reports whether w is a terminal.
+func isTTY(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// isSet reports whether the boolean-like variable v, like CLICOLOR_FORCE, is
+// set.
+func isSet(v string) bool {
+	return v != "" && v != "0" && !strings.EqualFold(v, "false")
+}
+
+// environMap returns the variables of environ, in the form "key=value",
+// by name. Later variables take precedence.
+func environMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	return env
+}
@@ -0,0 +1,63 @@
+package colorprofile
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name    string
+		environ []string
+		want    Profile
+	}{
+		{"not a tty", []string{"TERM=xterm-256color"}, NoTTY},
+		{"forced", []string{"CLICOLOR_FORCE=1", "TERM=xterm"}, ANSI},
+		{"forced off", []string{"CLICOLOR_FORCE=0", "TERM=xterm"}, NoTTY},
+		{"256 colors", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color"}, ANSI256},
+		{"colorterm", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color", "COLORTERM=truecolor"}, TrueColor},
+		{"direct", []string{"CLICOLOR_FORCE=1", "TERM=xterm-direct"}, TrueColor},
+		{"kitty", []string{"CLICOLOR_FORCE=1", "TERM=xterm-kitty"}, TrueColor},
+		{"dumb", []string{"CLICOLOR_FORCE=1", "TERM=dumb"}, NoTTY},
+		{"no color", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color", "NO_COLOR=1"}, Ascii},
+		{"empty no color", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color", "NO_COLOR="}, ANSI256},
+		{"no color zero", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color", "NO_COLOR=0"}, Ascii},
+		{"no color false", []string{"CLICOLOR_FORCE=1", "TERM=xterm-256color", "NO_COLOR=false"}, Ascii},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Detect(&bytes.Buffer{}, c.environ); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestProfileConvert(t *testing.T) {
+	cases := []struct {
+		profile Profile
+		in      color.Color
+		want    color.Color
+	}{
+		{TrueColor, ansi.TrueColor(0x123456), ansi.TrueColor(0x123456)},
+		{ANSI256, ansi.TrueColor(0xff8700), ansi.ExtendedColor(208)},
+		{ANSI256, ansi.TrueColor(0x808080), ansi.ExtendedColor(244)},
+		{ANSI256, ansi.TrueColor(0x000000), ansi.ExtendedColor(16)},
+		{ANSI256, ansi.ExtendedColor(9), ansi.BasicColor(9)},
+		{ANSI256, color.RGBA{R: 0xff, A: 0xff}, ansi.ExtendedColor(196)},
+		{ANSI, ansi.TrueColor(0xff0000), ansi.BrightRed},
+		{ANSI, ansi.TrueColor(0x000080), ansi.Blue},
+		{ANSI, ansi.ExtendedColor(196), ansi.BrightRed},
+		{ANSI, ansi.Green, ansi.Green},
+		{Ascii, ansi.Green, nil},
+		{NoTTY, ansi.TrueColor(0xffffff), nil},
+	}
+	for _, c := range cases {
+		if got := c.profile.Convert(c.in); got != c.want {
+			t.Errorf("%v: Convert(%v) = %v, want %v", c.profile, c.in, got, c.want)
+		}
+	}
+}
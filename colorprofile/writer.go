@@ -0,0 +1,371 @@
+package colorprofile
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// Writer degrades the output written to it to what the terminal supports,
+// and writes the result to the Forward writer:
+//
+//   - Colors are converted to the closest colors of the profile, or removed
+//     when the profile supports no colors.
+//   - Hyperlinks (OSC 8) are shown with the fallback of the writer when the
+//     terminal doesn't support them, see [HyperlinkFallback].
+//   - Every escape sequence is removed when the profile is [NoTTY], leaving
+//     the text and the control characters.
+//
+// Other escape sequences are written as is. Escape sequences can be split
+// across writes, the beginning of a sequence is held until the rest of it
+// is written.
+//
+//	w := colorprofile.NewWriter(os.Stdout, os.Environ())
+//	fmt.Fprintln(w, "\x1b[38;2;255;135;0mOrange\x1b[m")
+type Writer struct {
+	Forward io.Writer
+
+	// Profile is the color profile of the terminal.
+	Profile Profile
+
+	// Hyperlinks reports whether the terminal supports hyperlinks.
+	Hyperlinks bool
+
+	// HyperlinkFallback is how hyperlinks are shown when the terminal
+	// doesn't support them.
+	HyperlinkFallback HyperlinkFallback
+
+	state  parser.State
+	seq    []byte // seq is the escape sequence being written.
+	esc    bool   // esc reports whether seq is a string ending with ESC.
+	utf8   int    // utf8 is the number of bytes left of a UTF-8 character.
+	out    []byte
+	params []byte
+	links  hyperlinks
+}
+
+// NewWriter returns a new writer that degrades the output written to w. The
+// profile is detected with [Detect], and hyperlinks are supported by
+// terminals other than the Linux console.
+func NewWriter(w io.Writer, environ []string) *Writer {
+	p := Detect(w, environ)
+	return &Writer{
+		Forward:    w,
+		Profile:    p,
+		Hyperlinks: p > NoTTY && environMap(environ)["TERM"] != "linux",
+	}
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.out = w.out[:0]
+	for _, c := range p {
+		w.advance(c)
+	}
+	return len(p), w.flush()
+}
+
+// WriteString implements io.StringWriter.
+func (w *Writer) WriteString(s string) (int, error) {
+	w.out = w.out[:0]
+	for i := 0; i < len(s); i++ {
+		w.advance(s[i])
+	}
+	return len(s), w.flush()
+}
+
+// flush writes the output to the forward writer.
+func (w *Writer) flush() error {
+	if len(w.out) == 0 {
+		return nil
+	}
+	_, err := w.Forward.Write(w.out)
+	return err
+}
+
+// advance processes the byte c.
+func (w *Writer) advance(c byte) {
+	if w.utf8 > 0 {
+		w.utf8--
+		w.out = append(w.out, c)
+		w.linkText(c)
+		return
+	}
+
+	if len(w.seq) == 0 {
+		state, _ := parser.Table.Transition(parser.GroundState, c)
+		switch state {
+		case parser.GroundState:
+			// Text and control characters.
+			w.out = append(w.out, c)
+			w.linkText(c)
+		case parser.Utf8State:
+			w.utf8 = utf8Len(c) - 1
+			w.out = append(w.out, c)
+			w.linkText(c)
+		default:
+			w.state = state
+			w.seq = append(w.seq, c)
+		}
+		return
+	}
+
+	if isStringState(w.state) {
+		switch {
+		case w.esc && c == '\\':
+			w.seq = append(w.seq, c)
+			w.end(true)
+		case w.esc:
+			// The string is cut short by another sequence.
+			w.seq = w.seq[:len(w.seq)-1]
+			w.end(false)
+			w.advance(ansi.ESC)
+			w.advance(c)
+		case c == ansi.BEL, c == ansi.CAN, c == ansi.SUB, c == ansi.ST && w.seq[0] != ansi.ESC:
+			// The 8-bit ST only ends strings introduced with an 8-bit
+			// control, since it's also a UTF-8 continuation byte.
+			w.seq = append(w.seq, c)
+			w.end(true)
+		default:
+			w.seq = append(w.seq, c)
+			w.esc = c == ansi.ESC
+		}
+		return
+	}
+
+	if c == ansi.ESC {
+		// The sequence is cut short by another one.
+		w.end(false)
+		w.advance(c)
+		return
+	}
+
+	state, _ := parser.Table.Transition(w.state, c)
+	switch state {
+	case parser.GroundState:
+		w.seq = append(w.seq, c)
+		w.end(true)
+	case parser.Utf8State:
+		// Text can't be part of a sequence.
+		w.end(false)
+		w.advance(c)
+	default:
+		w.state = state
+		w.seq = append(w.seq, c)
+	}
+}
+
+// end writes the sequence in seq, degraded, to the output, and resets it.
+// complete reports whether the sequence is complete, incomplete sequences
+// are written as is.
+func (w *Writer) end(complete bool) {
+	seq := w.seq
+	w.seq, w.state, w.esc = w.seq[:0], parser.GroundState, false
+	if !complete {
+		if w.Profile > NoTTY {
+			w.out = append(w.out, seq...)
+		}
+		return
+	}
+
+	// Skip the introducer.
+	var kind byte
+	data := seq[1:]
+	switch seq[0] {
+	case ansi.ESC:
+		if len(data) > 0 {
+			kind, data = data[0], data[1:]
+		}
+	case ansi.CSI:
+		kind = '['
+	case ansi.OSC:
+		kind = ']'
+	}
+
+	if kind == ']' && bytes.HasPrefix(data, []byte("8;")) && (!w.Hyperlinks || w.Profile == NoTTY) {
+		w.writeHyperlink(data)
+		return
+	}
+	if w.Profile == NoTTY {
+		return
+	}
+
+	switch kind {
+	case '[':
+		if len(data) > 0 && data[len(data)-1] == 'm' && isParams(data[:len(data)-1]) {
+			w.writeSgr(seq[:len(seq)-len(data)], data[:len(data)-1])
+			return
+		}
+	}
+	w.out = append(w.out, seq...)
+}
+
+// writeSgr writes the SGR sequence with the given introducer and parameters
+// to the output, with the colors the profile doesn't support converted or
+// removed.
+func (w *Writer) writeSgr(intro, params []byte) {
+	if w.Profile >= TrueColor {
+		w.out = append(append(append(w.out, intro...), params...), 'm')
+		return
+	}
+
+	w.params = w.params[:0]
+	add := func(p ...byte) {
+		if len(w.params) > 0 {
+			w.params = append(w.params, ';')
+		}
+		w.params = append(w.params, p...)
+	}
+
+	fields := bytes.Split(params, []byte{';'})
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		n := atoi(f)
+		switch {
+		case n >= 30 && n <= 37, n >= 40 && n <= 47, n >= 90 && n <= 97,
+			n >= 100 && n <= 107, n == 39, n == 49, n == 59:
+			if w.Profile >= ANSI {
+				add(f...)
+			}
+		case n == 38, n == 48, n == 58:
+			c, size, ok := readColor(fields[i:])
+			if !ok {
+				// Keep what we don't understand.
+				add(f...)
+				continue
+			}
+			i += size - 1
+			if c = w.Profile.Convert(c); c != nil {
+				w.params = appendColor(w.params, n, c)
+			}
+		default:
+			add(f...)
+		}
+	}
+
+	if len(w.params) == 0 && len(params) > 0 {
+		// Nothing left but a reset, which the original sequence wasn't.
+		return
+	}
+	w.out = append(append(append(w.out, intro...), w.params...), 'm')
+}
+
+// readColor reads the color of a 38, 48, or 58 SGR parameter at the start
+// of fields. It returns the color, and the number of fields it spans.
+func readColor(fields [][]byte) (color.Color, int, bool) {
+	if sub := bytes.Split(fields[0], []byte{':'}); len(sub) > 1 {
+		// The colon form, 38:5:n or 38:2:[colorspace:]r:g:b, fits in one
+		// field.
+		switch {
+		case atoi(sub[1]) == 5 && len(sub) >= 3:
+			return ansi.ExtendedColor(atoi(sub[2])), 1, true
+		case atoi(sub[1]) == 2 && len(sub) >= 5:
+			return trueColor(sub[len(sub)-3:]), 1, true
+		}
+		return nil, 1, false
+	}
+
+	// The semicolon form, 38;5;n or 38;2;r;g;b.
+	args := fields[1:]
+	switch {
+	case len(args) >= 2 && atoi(args[0]) == 5:
+		return ansi.ExtendedColor(atoi(args[1])), 3, true
+	case len(args) >= 4 && atoi(args[0]) == 2:
+		return trueColor(args[1:4]), 5, true
+	}
+	return nil, 1, false
+}
+
+// trueColor returns the color of the red, green, and blue components in
+// rgb.
+func trueColor(rgb [][]byte) ansi.TrueColor {
+	r, g, b := atoi(rgb[0])&0xff, atoi(rgb[1])&0xff, atoi(rgb[2])&0xff
+	return ansi.TrueColor(r<<16 | g<<8 | b)
+}
+
+// appendColor appends the parameters of the color c, for the SGR parameter
+// n, 38, 48, or 58, to b.
+func appendColor(b []byte, n int, c color.Color) []byte {
+	if len(b) > 0 {
+		b = append(b, ';')
+	}
+	switch c := c.(type) {
+	case ansi.BasicColor:
+		if n != 58 {
+			base := n - 8 // 30 or 40
+			if c > 7 {
+				base += 60
+				c -= 8
+			}
+			return strconv.AppendInt(b, int64(base+int(c)), 10)
+		}
+		// There are no basic underline colors.
+		b = strconv.AppendInt(b, int64(n), 10)
+		b = append(b, ";5;"...)
+		return strconv.AppendInt(b, int64(c), 10)
+	case ansi.ExtendedColor:
+		b = strconv.AppendInt(b, int64(n), 10)
+		b = append(b, ";5;"...)
+		return strconv.AppendInt(b, int64(c), 10)
+	}
+	r, g, bl := rgb(c)
+	b = strconv.AppendInt(b, int64(n), 10)
+	b = append(b, ";2;"...)
+	b = strconv.AppendInt(b, int64(r), 10)
+	b = append(b, ';')
+	b = strconv.AppendInt(b, int64(g), 10)
+	b = append(b, ';')
+	return strconv.AppendInt(b, int64(bl), 10)
+}
+
+// isParams reports whether b is made of CSI parameter bytes.
+func isParams(b []byte) bool {
+	for _, c := range b {
+		if (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// atoi returns the number in b, or 0 when b doesn't start with one. Anything
+// after the first non-digit is ignored.
+func atoi(b []byte) int {
+	var n int
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// utf8Len returns the length of the UTF-8 character starting with the byte
+// b.
+func utf8Len(b byte) int {
+	switch {
+	case b >= 0xf0:
+		return 4
+	case b >= 0xe0:
+		return 3
+	case b >= 0xc0:
+		return 2
+	}
+	return 1
+}
+
+// isStringState reports whether state is the data string of a DCS, OSC,
+// SOS, PM, or APC sequence.
+func isStringState(state parser.State) bool {
+	switch state {
+	case parser.DcsStringState, parser.OscStringState, parser.SosStringState,
+		parser.PmStringState, parser.ApcStringState:
+		return true
+	}
+	return false
+}
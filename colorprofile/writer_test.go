@@ -0,0 +1,60 @@
+package colorprofile
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	cases := []struct {
+		name       string
+		profile    Profile
+		hyperlinks bool
+		in         string
+		want       string
+	}{
+		{"truecolor", TrueColor, true, "\x1b[1;38;2;255;135;0mx\x1b[m", "\x1b[1;38;2;255;135;0mx\x1b[m"},
+		{"256", ANSI256, true, "\x1b[1;38;2;255;135;0;48:2::0:0:0mx\x1b[m", "\x1b[1;38;5;208;48;5;16mx\x1b[m"},
+		{"256 keeps basic", ANSI256, true, "\x1b[31;4:3mx", "\x1b[31;4:3mx"},
+		{"16", ANSI, true, "\x1b[38;5;196;48;2;0;0;128mx", "\x1b[91;44mx"},
+		{"16 underline color", ANSI, true, "\x1b[58;2;255;0;0mx", "\x1b[58;5;9mx"},
+		{"ascii", Ascii, true, "\x1b[1;31;48;5;200mx\x1b[0m", "\x1b[1mx\x1b[0m"},
+		{"ascii drops color only", Ascii, true, "\x1b[31mx\x1b[39m", "x"},
+		{"ascii keeps other", Ascii, true, "\x1b[2J\x1b[Hx", "\x1b[2J\x1b[Hx"},
+		{"no tty", NoTTY, true, "\x1b[1;31mx\x1b]8;;https://charm.sh\x07y\x1b]8;;\x07\r\n世\x1b[2J", "xy\r\n世"},
+		{"hyperlinks", ANSI, true, "\x1b]8;;https://charm.sh\x1b\\y\x1b]8;;\x1b\\", "\x1b]8;;https://charm.sh\x1b\\y\x1b]8;;\x1b\\"},
+		{"no hyperlinks", ANSI, false, "\x1b]8;;https://charm.sh\x1b\\y\x1b]8;;\x1b\\\x1b]2;title\x07", "y\x1b]2;title\x07"},
+		{"8-bit", ANSI, true, "\x9b38;5;196mx\x9d8;;u\x9c", "\x9b91mx\x9d8;;u\x9c"},
+		{"cut short", ANSI256, true, "\x1b[38;2\x1b[38;2;0;0;0mx", "\x1b[38;2\x1b[38;5;16mx"},
+		{"unknown color", ANSI, true, "\x1b[38;7mx", "\x1b[38;7mx"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &Writer{Forward: &buf, Profile: c.profile, Hyperlinks: c.hyperlinks}
+			w.WriteString(c.in) // nolint: errcheck
+			if got := buf.String(); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+
+			// The output is the same when the input is split anywhere.
+			for i := 0; i <= len(c.in); i++ {
+				buf.Reset()
+				w := &Writer{Forward: &buf, Profile: c.profile, Hyperlinks: c.hyperlinks}
+				w.Write([]byte(c.in[:i])) // nolint: errcheck
+				w.Write([]byte(c.in[i:])) // nolint: errcheck
+				if got := buf.String(); got != c.want {
+					t.Fatalf("split at %d: got %q, want %q", i, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []string{"CLICOLOR_FORCE=1", "TERM=linux"})
+	if w.Profile != ANSI || w.Hyperlinks {
+		t.Errorf("got profile %v and hyperlinks %v", w.Profile, w.Hyperlinks)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ansidump lists the escape sequences of its arguments, or of its input when
+// there are none.
+//
+//	printf '\x1b[1;31mhello\x1b[m\n' | go run ./ansidump
+func main() {
+	d := ansi.NewDumper(os.Stdout)
+
+	var err error
+	if len(os.Args) > 1 {
+		_, err = io.WriteString(d, strings.Join(os.Args[1:], " "))
+	} else {
+		_, err = io.Copy(d, os.Stdin)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := d.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,90 @@
+package input
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/muesli/cancelreader"
+)
+
+// fallbackReader is a cancelable reader for readers that can't be polled,
+// like network connections, SSH channels, serial ports, regular files, and
+// in-memory buffers. Reads happen in the background, so that a blocked read
+// can be canceled. The read in flight when the reader is canceled might
+// still consume input, unless the underlying reader supports read
+// deadlines, like a [net.Conn], in which case the deadline is used to
+// interrupt it.
+type fallbackReader struct {
+	r       io.Reader
+	buf     []byte
+	results chan fallbackResult
+	done    chan struct{}
+	once    sync.Once
+}
+
+// fallbackResult is the result of a background read.
+type fallbackResult struct {
+	n   int
+	err error
+}
+
+// readDeadliner is a reader whose reads can be interrupted by a deadline.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+var _ cancelreader.CancelReader = &fallbackReader{}
+
+func newFallbackReader(r io.Reader) *fallbackReader {
+	return &fallbackReader{
+		r:       r,
+		results: make(chan fallbackResult, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Read implements io.Reader.
+func (r *fallbackReader) Read(p []byte) (int, error) {
+	select {
+	case <-r.done:
+		return 0, cancelreader.ErrCanceled
+	default:
+	}
+
+	// A read only returns once the background read is done, or the reader
+	// is canceled for good, so there's at most one background read, and its
+	// buffer isn't used by anyone else.
+	if cap(r.buf) < len(p) {
+		r.buf = make([]byte, len(p))
+	}
+	buf := r.buf[:len(p)]
+	go func() {
+		n, err := r.r.Read(buf)
+		r.results <- fallbackResult{n, err}
+	}()
+
+	select {
+	case res := <-r.results:
+		return copy(p, buf[:res.n]), res.err
+	case <-r.done:
+		return 0, cancelreader.ErrCanceled
+	}
+}
+
+// Cancel implements cancelreader.CancelReader.
+func (r *fallbackReader) Cancel() bool {
+	r.once.Do(func() {
+		close(r.done)
+		if d, ok := r.r.(readDeadliner); ok {
+			d.SetReadDeadline(time.Now()) // nolint: errcheck
+		}
+	})
+	return true
+}
+
+// Close implements cancelreader.CancelReader. The underlying reader is left
+// open.
+func (r *fallbackReader) Close() error {
+	return nil
+}
@@ -10,5 +10,12 @@ import (
 )
 
 func newCancelreader(r io.Reader) (cancelreader.CancelReader, error) {
-	return cancelreader.NewReader(r)
+	if _, ok := r.(cancelreader.File); ok {
+		if cr, err := cancelreader.NewReader(r); err == nil {
+			return cr, nil
+		}
+		// Files that can't be polled, like regular files and /dev/null,
+		// are read in the background instead.
+	}
+	return newFallbackReader(r), nil
 }
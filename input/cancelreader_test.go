@@ -0,0 +1,115 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muesli/cancelreader"
+)
+
+func TestFallbackReaderCancel(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	drv, err := New(pr, WithTerm("dumb"))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := drv.ReadEvents()
+		errc <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if !drv.Cancel() {
+		t.Fatal("expected the read to be canceled")
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, cancelreader.ErrCanceled) {
+			t.Errorf("expected %v, got %v", cancelreader.ErrCanceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("read wasn't canceled")
+	}
+}
+
+func TestFallbackReaderConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	drv, err := New(server, WithTerm("dumb"))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	go io.WriteString(client, "\x1b[A") // nolint: errcheck
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{KeyPressEvent{Sym: KeyUp}}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+
+	// The read in flight is interrupted with a deadline, and doesn't
+	// consume the input that comes after it.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := drv.ReadEvents()
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	drv.Cancel()
+	if err := <-errc; !errors.Is(err, cancelreader.ErrCanceled) {
+		t.Errorf("expected %v, got %v", cancelreader.ErrCanceled, err)
+	}
+	server.SetReadDeadline(time.Time{}) // nolint: errcheck
+	go io.WriteString(client, "a")      // nolint: errcheck
+	buf := make([]byte, 1)
+	if _, err := server.Read(buf); err != nil || buf[0] != 'a' {
+		t.Errorf("expected the input to be left unread, got %q, %v", buf, err)
+	}
+}
+
+func TestFallbackReaderRegularFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(name, []byte("ab"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	drv, err := New(f, WithTerm("dumb"))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{KeyPressEvent{Rune: 'a'}, KeyPressEvent{Rune: 'b'}}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+	if _, err := drv.ReadEvents(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
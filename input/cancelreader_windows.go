@@ -4,6 +4,7 @@
 package input
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -30,8 +31,8 @@ type conInputReader struct {
 var _ cancelreader.CancelReader = &conInputReader{}
 
 func newCancelreader(r io.Reader) (cancelreader.CancelReader, error) {
-	fallback := func(io.Reader) (cancelreader.CancelReader, error) {
-		return cancelreader.NewReader(r)
+	fallback := func(r io.Reader) (cancelreader.CancelReader, error) {
+		return newFallbackReader(r), nil
 	}
 
 	var dummy uint32
@@ -170,6 +171,52 @@ func waitForInput(conin, cancel windows.Handle) error {
 	}
 }
 
+// waitForInputContext is like waitForInput but also returns ctx.Err() when
+// the context is done before input is available.
+func waitForInputContext(ctx context.Context, conin, cancel windows.Handle) error {
+	done := ctx.Done()
+	if done == nil {
+		return waitForInput(conin, cancel)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ctxEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("create event: %w", err)
+	}
+	defer windows.CloseHandle(ctxEvent) // nolint: errcheck
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-done:
+			windows.SetEvent(ctxEvent) // nolint: errcheck
+		case <-stop:
+		}
+	}()
+	defer wg.Wait()
+	defer close(stop)
+
+	event, err := windows.WaitForMultipleObjects([]windows.Handle{conin, cancel, ctxEvent}, false, windows.INFINITE)
+	switch event {
+	case windows.WAIT_OBJECT_0:
+		return nil
+	case windows.WAIT_OBJECT_0 + 1:
+		return cancelreader.ErrCanceled
+	case windows.WAIT_OBJECT_0 + 2:
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("wait for input: %w", err)
+	}
+	return fmt.Errorf("unexpected wait result: %d", event)
+}
+
 // cancelMixin represents a goroutine-safe cancelation status.
 type cancelMixin struct {
 	unsafeCanceled bool
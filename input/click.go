@@ -0,0 +1,73 @@
+package input
+
+import "time"
+
+// DefaultClickInterval is the default maximum time between the clicks of a
+// multi-click.
+const DefaultClickInterval = 500 * time.Millisecond
+
+// ClickTracker counts consecutive mouse clicks and sets the ClickCount of
+// mouse click and release events. A click counts as part of a multi-click
+// when it uses the same button, happens within Interval of the previous
+// click, and moves at most Threshold cells away from it.
+//
+// Use it with [WithClickTracker] to have the driver count clicks, or call
+// [ClickTracker.Track] on each event.
+type ClickTracker struct {
+	// Interval is the maximum time between clicks. Zero means
+	// [DefaultClickInterval].
+	Interval time.Duration
+
+	// Threshold is the maximum distance, in cells, the mouse can move
+	// between clicks.
+	Threshold int
+
+	last   Mouse
+	lastAt time.Time
+	count  int
+}
+
+// Track returns the given event with its click count set. Events other than
+// mouse clicks and releases are returned as is.
+func (t *ClickTracker) Track(e Event) Event {
+	switch e := e.(type) {
+	case MouseClickEvent:
+		m := Mouse(e)
+		m.ClickCount = t.click(m, time.Now())
+		return MouseClickEvent(m)
+	case MouseReleaseEvent:
+		m := Mouse(e)
+		if m.Button == t.last.Button || m.Button == MouseNone {
+			// Some encodings don't report which button was released.
+			m.ClickCount = t.count
+		}
+		return MouseReleaseEvent(m)
+	}
+	return e
+}
+
+func (t *ClickTracker) click(m Mouse, now time.Time) int {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultClickInterval
+	}
+
+	if t.count > 0 && m.Button == t.last.Button &&
+		now.Sub(t.lastAt) <= interval &&
+		abs(m.X-t.last.X) <= t.Threshold && abs(m.Y-t.last.Y) <= t.Threshold {
+		t.count++
+	} else {
+		t.count = 1
+	}
+
+	t.last = m
+	t.lastAt = now
+	return t.count
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
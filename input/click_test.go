@@ -0,0 +1,83 @@
+package input
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClickTracker(t *testing.T) {
+	var ct ClickTracker
+	ct.Threshold = 1
+
+	count := func(e Event) int {
+		switch e := ct.Track(e).(type) {
+		case MouseClickEvent:
+			return e.ClickCount
+		case MouseReleaseEvent:
+			return e.ClickCount
+		}
+		return -1
+	}
+
+	left := MouseClickEvent{X: 10, Y: 5, Button: MouseLeft}
+	for i := 1; i <= 3; i++ {
+		if n := count(left); n != i {
+			t.Errorf("expected click count %d, got %d", i, n)
+		}
+		if n := count(MouseReleaseEvent{X: 10, Y: 5}); n != i {
+			t.Errorf("expected release click count %d, got %d", i, n)
+		}
+	}
+
+	// Another button starts over.
+	if n := count(MouseClickEvent{X: 10, Y: 5, Button: MouseRight}); n != 1 {
+		t.Errorf("expected click count 1, got %d", n)
+	}
+
+	// Moving within the threshold keeps counting, beyond it starts over.
+	count(left)
+	if n := count(MouseClickEvent{X: 11, Y: 6, Button: MouseLeft}); n != 2 {
+		t.Errorf("expected click count 2, got %d", n)
+	}
+	if n := count(MouseClickEvent{X: 20, Y: 6, Button: MouseLeft}); n != 1 {
+		t.Errorf("expected click count 1, got %d", n)
+	}
+
+	// Other events are left alone.
+	if e := ct.Track(MouseWheelEvent{Button: MouseWheelUp}); e != (MouseWheelEvent{Button: MouseWheelUp}) {
+		t.Errorf("expected wheel event to be unchanged, got %v", e)
+	}
+}
+
+func TestClickTrackerInterval(t *testing.T) {
+	ct := ClickTracker{Interval: 10 * time.Millisecond}
+	left := MouseClickEvent{Button: MouseLeft}
+	ct.Track(left)
+	time.Sleep(20 * time.Millisecond)
+	if e := ct.Track(left).(MouseClickEvent); e.ClickCount != 1 {
+		t.Errorf("expected click count 1, got %d", e.ClickCount)
+	}
+}
+
+func TestDriverClickTracker(t *testing.T) {
+	drv, err := New(strings.NewReader("\x1b[<0;1;1M\x1b[<0;1;1m\x1b[<0;1;1M"),
+		WithTerm("dumb"),
+		WithClickTracker(&ClickTracker{}),
+	)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %v", events)
+	}
+	if e, ok := events[2].(MouseClickEvent); !ok || e.ClickCount != 2 {
+		t.Errorf("expected a double click, got %#v", events[2])
+	}
+}
@@ -12,7 +12,7 @@ type ForegroundColorEvent struct{ color.Color }
 
 // String implements fmt.Stringer.
 func (e ForegroundColorEvent) String() string {
-	return colorToHex(e)
+	return colorToHex(e.Color)
 }
 
 // BackgroundColorEvent represents a background color change event.
@@ -20,7 +20,7 @@ type BackgroundColorEvent struct{ color.Color }
 
 // String implements fmt.Stringer.
 func (e BackgroundColorEvent) String() string {
-	return colorToHex(e)
+	return colorToHex(e.Color)
 }
 
 // CursorColorEvent represents a cursor color change event.
@@ -28,7 +28,57 @@ type CursorColorEvent struct{ color.Color }
 
 // String implements fmt.Stringer.
 func (e CursorColorEvent) String() string {
-	return colorToHex(e)
+	return colorToHex(e.Color)
+}
+
+// HighlightBackgroundColorEvent represents a highlight (selection)
+// background color change event. This is reported in response to
+// [ansi.RequestHighlightBackgroundColor].
+type HighlightBackgroundColorEvent struct{ color.Color }
+
+// String implements fmt.Stringer.
+func (e HighlightBackgroundColorEvent) String() string {
+	return colorToHex(e.Color)
+}
+
+// HighlightForegroundColorEvent represents a highlight (selection)
+// foreground color change event. This is reported in response to
+// [ansi.RequestHighlightForegroundColor].
+type HighlightForegroundColorEvent struct{ color.Color }
+
+// String implements fmt.Stringer.
+func (e HighlightForegroundColorEvent) String() string {
+	return colorToHex(e.Color)
+}
+
+// ColorResetEvent represents a color reset notification. Its value is the OSC
+// number of the color that was reset, i.e. 10 for the foreground color, 11
+// for the background color, 12 for the cursor color, 17 for the highlight
+// background color, and 19 for the highlight foreground color.
+//
+//	OSC 110 ST
+//	OSC 111 ST
+//	OSC 112 ST
+//	OSC 117 ST
+//	OSC 119 ST
+type ColorResetEvent int
+
+// String implements fmt.Stringer.
+func (e ColorResetEvent) String() string {
+	return fmt.Sprintf("color reset: %d", int(e))
+}
+
+// MissingColorEvent is reported when the terminal replies to a color query
+// without a valid color, like an empty reply or a "?". Its value is the OSC
+// number of the color that was queried, like the value of
+// [ColorResetEvent].
+//
+//	OSC 11 ; ? ST
+type MissingColorEvent int
+
+// String implements fmt.Stringer.
+func (e MissingColorEvent) String() string {
+	return fmt.Sprintf("missing color: %d", int(e))
 }
 
 type shiftable interface {
@@ -43,16 +93,27 @@ func shift[T shiftable](x T) T {
 }
 
 func colorToHex(c color.Color) string {
+	if c == nil {
+		return ""
+	}
 	r, g, b, _ := c.RGBA()
 	return fmt.Sprintf("#%02x%02x%02x", shift(r), shift(g), shift(b))
 }
 
+// xParseColor parses a color reported by the terminal. It returns nil if the
+// color can't be parsed.
 func xParseColor(s string) color.Color {
 	switch {
+	case strings.HasPrefix(s, "#") && len(s) == 7:
+		c, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return nil
+		}
+		return color.RGBA{uint8(c >> 16), uint8(c >> 8), uint8(c), 255}
 	case strings.HasPrefix(s, "rgb:"):
 		parts := strings.Split(s[4:], "/")
 		if len(parts) != 3 {
-			return color.Black
+			return nil
 		}
 
 		r, _ := strconv.ParseUint(parts[0], 16, 32)
@@ -63,7 +124,7 @@ func xParseColor(s string) color.Color {
 	case strings.HasPrefix(s, "rgba:"):
 		parts := strings.Split(s[5:], "/")
 		if len(parts) != 4 {
-			return color.Black
+			return nil
 		}
 
 		r, _ := strconv.ParseUint(parts[0], 16, 32)
@@ -73,5 +134,5 @@ func xParseColor(s string) color.Color {
 
 		return color.RGBA{uint8(shift(r)), uint8(shift(g)), uint8(shift(b)), uint8(shift(a))}
 	}
-	return color.Black
+	return nil
 }
@@ -1,10 +1,32 @@
 package input
 
-// CursorPositionEvent represents a cursor position event.
+import "fmt"
+
+// CursorPositionEvent represents a cursor position event. This is reported
+// in response to [ansi.RequestCursorPosition] (CPR) or
+// [ansi.RequestExtendedCursorPosition] (DECXCPR).
+//
+//	CSI Pl ; Pc R
+//	CSI ? Pl ; Pc ; Pp R
+//
+// Row and Column are 1-based, like in the report.
+//
+// Note that a CPR report with the cursor on the first row is
+// indistinguishable from a modified F3 key, and is reported as such. Use
+// DECXCPR to get an unambiguous report.
 type CursorPositionEvent struct {
 	// Row is the row number.
 	Row int
 
 	// Column is the column number.
 	Column int
+
+	// Page is the page number. This is only reported by DECXCPR and is zero
+	// otherwise.
+	Page int
+}
+
+// String implements fmt.Stringer.
+func (e CursorPositionEvent) String() string {
+	return fmt.Sprintf("cursor position: %d;%d", e.Row, e.Column)
 }
@@ -0,0 +1,57 @@
+package input
+
+import (
+	"errors"
+
+	"golang.org/x/text/transform"
+)
+
+// inputDecoder converts the input to UTF-8 before parsing. It keeps the
+// bytes of an incomplete character between reads.
+type inputDecoder struct {
+	t   transform.Transformer
+	src []byte // src is an incomplete character left over by the last read.
+
+	// dst and out are reused across reads.
+	dst []byte
+	out []byte
+}
+
+// decode converts b to UTF-8. atEOF reports whether there is no more input,
+// in which case leftover bytes are flushed. The output is only valid until
+// the next call.
+func (d *inputDecoder) decode(b []byte, atEOF bool) ([]byte, error) {
+	src := b
+	if len(d.src) > 0 {
+		src = append(d.src, b...)
+		d.src = nil
+	}
+
+	if n := 2*len(src) + 4; cap(d.dst) < n {
+		d.dst = make([]byte, n)
+	}
+	dst := d.dst[:cap(d.dst)]
+	out := d.out[:0]
+	defer func() { d.out = out[:0] }()
+	for {
+		nDst, nSrc, err := d.t.Transform(dst, src, atEOF)
+		out = append(out, dst[:nDst]...)
+		src = src[nSrc:]
+		switch {
+		case err == nil:
+			return out, nil
+		case errors.Is(err, transform.ErrShortDst) && (nDst > 0 || nSrc > 0):
+			// Made progress, keep going.
+		case errors.Is(err, transform.ErrShortDst):
+			dst = make([]byte, 2*len(dst))
+			d.dst = dst
+		case errors.Is(err, transform.ErrShortSrc) && !atEOF:
+			d.src = append([]byte(nil), src...)
+			return out, nil
+		default:
+			d.t.Reset()
+			out = append(out, src...)
+			return out, err
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestDriverDecoder(t *testing.T) {
+	cases := []struct {
+		name string
+		enc  encoding.Encoding
+		s    string
+	}{
+		{"cp437 box drawing", charmap.CodePage437, "┌─┬─┐│ ║ │╞═╪═╡└─┴─┘"},
+		{"cp437 blocks", charmap.CodePage437, "░▒▓█▄▀"},
+		{"latin1", charmap.ISO8859_1, "café ñandú"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			raw, err := c.enc.NewEncoder().String(c.s)
+			if err != nil {
+				t.Fatalf("could not encode %q: %v", c.s, err)
+			}
+			if raw == c.s {
+				t.Fatalf("expected %q to change when encoded", c.s)
+			}
+
+			// Round trip both keys and bracketed pastes.
+			input := raw + "\x1b[200~" + raw + "\x1b[201~"
+			drv, err := New(strings.NewReader(input), WithTerm("dumb"),
+				WithDecoder(c.enc.NewDecoder()))
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			defer drv.Close()
+
+			var keys strings.Builder
+			var paste PasteEvent
+			for paste == "" {
+				events, err := drv.ReadEvents()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, e := range events {
+					switch e := e.(type) {
+					case KeyPressEvent:
+						keys.WriteRune(e.Rune)
+					case PasteEvent:
+						paste = e
+					}
+				}
+			}
+			if keys.String() != c.s {
+				t.Errorf("expected keys %q, got %q", c.s, keys.String())
+			}
+			if string(paste) != c.s {
+				t.Errorf("expected paste %q, got %q", c.s, paste)
+			}
+		})
+	}
+}
+
+func TestInputDecoderSplit(t *testing.T) {
+	// Shift JIS uses two bytes per character, split them across reads.
+	raw, err := japanese.ShiftJIS.NewEncoder().String("日本語")
+	if err != nil {
+		t.Fatalf("could not encode: %v", err)
+	}
+
+	d := &inputDecoder{t: japanese.ShiftJIS.NewDecoder()}
+	var got []byte
+	for i := 0; i < len(raw); i++ {
+		b, err := d.decode([]byte{raw[i]}, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, b...)
+	}
+	if string(got) != "日本語" {
+		t.Errorf("expected %q, got %q", "日本語", got)
+	}
+
+	// An incomplete character is flushed at EOF.
+	b, err := d.decode([]byte{raw[0]}, false)
+	if err != nil || len(b) != 0 {
+		t.Fatalf("expected the incomplete character to be kept, got %q, %v", b, err)
+	}
+	b, err = d.decode(nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != "�" {
+		t.Errorf("expected %q, got %q", "�", b)
+	}
+}
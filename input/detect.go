@@ -0,0 +1,120 @@
+package input
+
+import (
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultDetectTimeout is a reasonable timeout for [Driver.Detect]. Local
+// terminals reply within a few milliseconds, but replies over SSH take a
+// round trip.
+const DefaultDetectTimeout = 500 * time.Millisecond
+
+// Capabilities are the features supported by the terminal, as reported by
+// [Driver.Detect].
+type Capabilities struct {
+	// Attributes are the primary device attributes (DA1). The first one is
+	// the conformance level, like 62 for a VT220, and the rest are the
+	// supported extensions.
+	Attributes []uint
+
+	// Version is the name and version of the terminal reported by XTVERSION,
+	// like "kitty(0.31.0)", or empty.
+	Version string
+
+	// SynchronizedOutput reports whether the terminal supports synchronized
+	// output (mode 2026).
+	SynchronizedOutput bool
+
+	// InBandResize reports whether the terminal supports in-band window
+	// size reports (mode 2048).
+	InBandResize bool
+
+	// BracketedPaste reports whether the terminal supports bracketed paste
+	// (mode 2004).
+	BracketedPaste bool
+
+	// SgrMouse reports whether the terminal supports SGR extended mouse
+	// reports (mode 1006). Terminals that don't, like the Linux console, only
+	// report mouse events in the X10 encoding, which the driver reads as
+	// well, but with coordinates limited to 223 cells.
+	SgrMouse bool
+
+	// KittyKeyboard reports whether the terminal supports the Kitty keyboard
+	// protocol, and KittyFlags are the enhancements currently enabled.
+	KittyKeyboard bool
+	KittyFlags    KittyKeyboardEvent
+
+	// Sixel reports whether the terminal supports sixel graphics.
+	Sixel bool
+}
+
+// detectQueries are the queries sent by [Driver.Detect]. Terminals reply in
+// order, and they all reply to DA1, so it goes last to mark the end of the
+// replies.
+const detectQueries = ansi.RequestXTVersion +
+	ansi.RequestSyncdOutput +
+	ansi.RequestInBandResize +
+	ansi.RequestBracketedPaste +
+	ansi.RequestMouseSgrExt +
+	ansi.RequestKittyKeyboard +
+	ansi.RequestPrimaryDeviceAttributes
+
+// Detect queries the terminal for its capabilities. It writes the queries to
+// the driver output, see [WithOutput], and reads the replies until the
+// terminal has answered them all, or the timeout expires. Other events read
+// in the meantime are kept and delivered by the next reads.
+//
+// When the timeout expires, Detect returns the capabilities found so far
+// and [os.ErrDeadlineExceeded]. The terminal might not support any of the
+// queries, or it might not be a terminal at all.
+//
+// Like [Driver.ReadEvents], Detect must not be called once the event pump
+// is started.
+func (d *Driver) Detect(timeout time.Duration) (Capabilities, error) {
+	var caps Capabilities
+	err := d.query(detectQueries, timeout, func(e Event) (bool, bool) {
+		_, done := e.(PrimaryDeviceAttributesEvent)
+		return caps.update(e), done
+	})
+	return caps, err
+}
+
+// update updates the capabilities with the reply e. It reports whether e is
+// a reply to one of the detection queries.
+func (c *Capabilities) update(e Event) bool {
+	switch e := e.(type) {
+	case PrimaryDeviceAttributesEvent:
+		c.Attributes = append([]uint(nil), e...)
+		for i := 1; i < len(e); i++ {
+			if e[i] == 4 {
+				c.Sixel = true
+			}
+		}
+	case TerminalVersionEvent:
+		c.Version = string(e)
+	case KittyKeyboardEvent:
+		c.KittyKeyboard = true
+		c.KittyFlags = e
+	case ReportModeEvent:
+		// The mode is set, reset, or permanently set. Zero means it's not
+		// recognized, and 4 that it's permanently reset.
+		supported := e.Value >= 1 && e.Value <= 3
+		switch ansi.PrivateMode(e.Mode) {
+		case ansi.SyncdOutputMode:
+			c.SynchronizedOutput = supported
+		case ansi.InBandResizeMode:
+			c.InBandResize = supported
+		case ansi.BracketedPasteMode:
+			c.BracketedPaste = supported
+		case ansi.MouseSgrExtMode:
+			c.SgrMouse = supported
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// replier is a terminal output that replies to the queries written to it.
+type replier struct {
+	w     io.Writer
+	reply string
+}
+
+func (r *replier) Write(p []byte) (int, error) {
+	go io.WriteString(r.w, r.reply) // nolint: errcheck
+	return len(p), nil
+}
+
+func TestDriverDetect(t *testing.T) {
+	cases := []struct {
+		name  string
+		reply string
+		caps  Capabilities
+		err   error
+	}{
+		{
+			name:  "all",
+			reply: "\x1bP>|kitty(0.31.0)\x1b\\\x1b[?2026;2$y\x1b[?2048;1$y\x1b[?2004;2$y\x1b[?1006;2$y\x1b[?1u\x1b[?62;4;22c",
+			caps: Capabilities{
+				Attributes:         []uint{62, 4, 22},
+				Version:            "kitty(0.31.0)",
+				SynchronizedOutput: true,
+				InBandResize:       true,
+				BracketedPaste:     true,
+				SgrMouse:           true,
+				KittyKeyboard:      true,
+				KittyFlags:         1,
+				Sixel:              true,
+			},
+		},
+		{
+			name:  "da1 only",
+			reply: "\x1b[?1;2c",
+			caps:  Capabilities{Attributes: []uint{1, 2}},
+		},
+		{
+			// The Linux console only replies to DA1.
+			name:  "linux console",
+			reply: "\x1b[?6c",
+			caps:  Capabilities{Attributes: []uint{6}},
+		},
+		{
+			name:  "unrecognized modes",
+			reply: "\x1b[?2026;0$y\x1b[?2048;4$y\x1b[?2004;3$y\x1b[?62c",
+			caps:  Capabilities{Attributes: []uint{62}, BracketedPaste: true},
+		},
+		{
+			name:  "no da1",
+			reply: "\x1bP>|XTerm(388)\x1b\\",
+			caps:  Capabilities{Version: "XTerm(388)"},
+			err:   os.ErrDeadlineExceeded,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pr, pw := io.Pipe()
+			drv, err := New(pr, WithTerm("dumb"), WithOutput(&replier{pw, c.reply}))
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			defer drv.Close()
+
+			caps, err := drv.Detect(50 * time.Millisecond)
+			if !errors.Is(err, c.err) {
+				t.Errorf("expected error %v, got %v", c.err, err)
+			}
+			if !reflect.DeepEqual(caps, c.caps) {
+				t.Errorf("expected %+v, got %+v", c.caps, caps)
+			}
+		})
+	}
+}
+
+func TestDriverDetectQueuesEvents(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithOutput(&replier{pw, "a\x1b[?62cb"}))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if _, err := drv.Detect(time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The keys read while detecting are delivered in order.
+	var events []Event
+	for len(events) < 2 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, evs...)
+	}
+	want := []Event{KeyPressEvent{Rune: 'a'}, KeyPressEvent{Rune: 'b'}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("expected %v, got %v", want, events)
+	}
+}
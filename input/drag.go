@@ -0,0 +1,79 @@
+package input
+
+// MouseDragEvent represents a mouse motion event while a button is held
+// down. Button is the button that started the drag.
+//
+// This is only reported when drag tracking is enabled, see [DragTracker].
+type MouseDragEvent Mouse
+
+// String implements fmt.Stringer.
+func (e MouseDragEvent) String() string {
+	return Mouse(e).String() + "+drag"
+}
+
+// DragTracker tracks the mouse button held down across motion events. It
+// reports motion events with a button held down as [MouseDragEvent]s, and
+// fills in the button of motion and release events that don't report one,
+// like X10 encoded releases.
+//
+// Use it with [WithDragTracking] to have the driver track drags, or call
+// [DragTracker.Track] on each event.
+type DragTracker struct {
+	button MouseButton
+}
+
+// Track returns the given event classified as a press, release, drag, or
+// motion event. Other events are returned as is.
+func (t *DragTracker) Track(e Event) Event {
+	switch e := e.(type) {
+	case MouseClickEvent:
+		t.button = e.Button
+	case MouseReleaseEvent:
+		if e.Button == MouseNone {
+			e.Button = t.button
+		}
+		t.button = MouseNone
+		return e
+	case MouseMotionEvent:
+		if t.button != MouseNone {
+			e.Button = t.button
+		}
+		if e.Button != MouseNone {
+			return MouseDragEvent(e)
+		}
+	}
+	return e
+}
+
+// CoalesceMotion replaces runs of consecutive motion events of the same kind
+// with their last event. High frequency mouse tracking can report many
+// motion events per read while consumers usually only care about the latest
+// position.
+func CoalesceMotion(events []Event) []Event {
+	if len(events) < 2 {
+		return events
+	}
+
+	out := events[:0]
+	for i, e := range events {
+		if i+1 < len(events) && sameMotion(e, events[i+1]) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// sameMotion reports whether two events are motion events of the same kind,
+// i.e. with the same button and modifiers.
+func sameMotion(a, b Event) bool {
+	switch a := a.(type) {
+	case MouseMotionEvent:
+		b, ok := b.(MouseMotionEvent)
+		return ok && a.Button == b.Button && a.Mod == b.Mod
+	case MouseDragEvent:
+		b, ok := b.(MouseDragEvent)
+		return ok && a.Button == b.Button && a.Mod == b.Mod
+	}
+	return false
+}
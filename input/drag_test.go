@@ -0,0 +1,78 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDragTracker(t *testing.T) {
+	var dt DragTracker
+	cases := []struct {
+		event  Event
+		expect Event
+	}{
+		{MouseMotionEvent{X: 1, Y: 1}, MouseMotionEvent{X: 1, Y: 1}},
+		{MouseClickEvent{X: 1, Y: 1, Button: MouseLeft}, MouseClickEvent{X: 1, Y: 1, Button: MouseLeft}},
+		{MouseMotionEvent{X: 2, Y: 1}, MouseDragEvent{X: 2, Y: 1, Button: MouseLeft}},
+		{MouseMotionEvent{X: 3, Y: 1, Button: MouseLeft}, MouseDragEvent{X: 3, Y: 1, Button: MouseLeft}},
+		{MouseReleaseEvent{X: 3, Y: 1}, MouseReleaseEvent{X: 3, Y: 1, Button: MouseLeft}},
+		{MouseMotionEvent{X: 4, Y: 1}, MouseMotionEvent{X: 4, Y: 1}},
+		{KeyPressEvent{Rune: 'a'}, KeyPressEvent{Rune: 'a'}},
+	}
+	for i, c := range cases {
+		if got := dt.Track(c.event); got != c.expect {
+			t.Errorf("%d: expected %#v, got %#v", i, c.expect, got)
+		}
+	}
+}
+
+func TestCoalesceMotion(t *testing.T) {
+	events := []Event{
+		MouseMotionEvent{X: 1},
+		MouseMotionEvent{X: 2},
+		MouseMotionEvent{X: 3},
+		MouseClickEvent{X: 3, Button: MouseLeft},
+		MouseDragEvent{X: 4, Button: MouseLeft},
+		MouseDragEvent{X: 5, Button: MouseLeft},
+		MouseDragEvent{X: 6, Button: MouseLeft, Mod: ModCtrl},
+		MouseReleaseEvent{X: 6, Button: MouseLeft},
+	}
+	expect := []Event{
+		MouseMotionEvent{X: 3},
+		MouseClickEvent{X: 3, Button: MouseLeft},
+		MouseDragEvent{X: 5, Button: MouseLeft},
+		MouseDragEvent{X: 6, Button: MouseLeft, Mod: ModCtrl},
+		MouseReleaseEvent{X: 6, Button: MouseLeft},
+	}
+	if got := CoalesceMotion(events); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
+
+func TestDriverDragTracking(t *testing.T) {
+	// X10 encoded press, two motions with the button held, and a release.
+	input := "\x1b[M !!\x1b[M@\"!\x1b[M@#!\x1b[M##!"
+	drv, err := New(strings.NewReader(input),
+		WithTerm("dumb"),
+		WithDragTracking(),
+		WithMotionCoalescing(),
+	)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{
+		MouseClickEvent{X: 0, Y: 0, Button: MouseLeft},
+		MouseDragEvent{X: 2, Y: 0, Button: MouseLeft},
+		MouseReleaseEvent{X: 2, Y: 0, Button: MouseLeft},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+}
@@ -2,27 +2,117 @@ package input
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"os"
+	"sync"
+	"time"
 	"unicode/utf8"
 
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
 	"github.com/erikgeiser/coninput"
 	"github.com/muesli/cancelreader"
 )
 
+// Input buffer limits.
+const (
+	// DefaultMaxReadBufferSize is the default maximum size of the read
+	// buffer. The buffer starts small and grows whenever a read fills it, so
+	// that large pastes and long replies need fewer reads.
+	DefaultMaxReadBufferSize = 64 * 1024
+
+	// DefaultMaxSequenceSize is the default maximum size of an incomplete
+	// sequence kept between reads. Longer sequences are reported as unknown
+	// events.
+	DefaultMaxSequenceSize = 64 * 1024
+
+	// minReadBufferSize is the initial size of the read buffer.
+	minReadBufferSize = 256
+)
+
 // Driver represents an ANSI terminal input Driver.
 // It reads input events and parses ANSI sequences from the terminal input
 // buffer.
 type Driver struct {
-	rd    cancelreader.CancelReader
-	table map[string]Key // table is a lookup table for key sequences.
+	rd     cancelreader.CancelReader
+	keys   *keyTrie      // keys is a lookup trie for key sequences.
+	parser *EventParser  // parser decodes input sequences.
+	logger Logger        // logger reports unusual input, can be nil.
+	clicks *ClickTracker // clicks counts mouse clicks, can be nil.
+	drags  *DragTracker  // drags tracks mouse drags, can be nil.
+	tap    io.Writer     // tap receives the raw input, can be nil.
+	out    io.Writer     // out is the terminal output, can be nil.
+
+	// sources records where the table sequences come from, see
+	// [Driver.Sequences]. Missing sequences are built-in defaults.
+	sources map[string]KeySource
+
+	// decoder converts the input to UTF-8, can be nil.
+	decoder *inputDecoder
+
+	// releases synthesizes key releases, can be nil.
+	releases *keyReleaser
+
+	// filters transform the events before they're delivered.
+	filters []EventFilter
+
+	// coalesce reports whether consecutive motion events are coalesced.
+	coalesce bool
 
 	term string // term is the terminal name $TERM.
 
+	// queued are the events read while waiting for a reply to a query, to
+	// be delivered by the next read.
+	queued []Event
+
+	// pending is an incomplete sequence left over by the previous read.
+	pending []byte
+
+	// buf is the parse buffer, reused across reads.
+	buf []byte
+
 	// paste is the bracketed paste mode buffer.
 	// When nil, bracketed paste mode is disabled.
-	paste []byte
+	paste    []byte
+	pasteLen int // pasteLen is the size of the current paste.
 
-	buf [256]byte // do we need a larger buffer?
+	pasteStream bool // pasteStream delivers pastes in chunks.
+	maxPaste    int  // maxPaste is the maximum paste size, if positive.
+	rawPaste    bool // rawPaste delivers the paste bytes as is.
+
+	// pasteSanitizer sanitizes the pasted data, if it has any flags set.
+	pasteSanitizer pasteSanitizer
+
+	maxReadBuf int // maxReadBuf is the maximum read buffer size.
+	maxSeq     int // maxSeq is the maximum incomplete sequence size.
+
+	// escTimeout is how long to wait for the rest of an escape sequence that
+	// got split across reads.
+	escTimeout time.Duration
+
+	// The reader goroutine performs one read per request on readReq and
+	// sends the result to reads. This allows reads to time out without
+	// losing any data, and without reading input no one asked for.
+	readOnce sync.Once
+	readReq  chan struct{}
+	reads    chan readResult
+	reading  bool  // whether a read request is in flight
+	readErr  error // sticky error from the underlying reader
+	done     chan struct{}
+	doneOnce sync.Once
+
+	// The event pump started by Start. The channels are created with the
+	// driver so that Start and Close can race, started is guarded by mu.
+	started  bool
+	events   chan Event
+	errs     chan error
+	pumpDone chan struct{}
+
+	// deadline is the read deadline set by SetReadDeadline.
+	deadline time.Time
+	mu       sync.Mutex
 
 	// prevMouseState keeps track of the previous mouse state to determine mouse
 	// up button events.
@@ -32,61 +122,598 @@ type Driver struct {
 	// multiple size events from firing.
 	lastWinsizeEvent coninput.WindowBufferSizeEventRecord // nolint: unused
 
+	// winsz reports window size changes when FlagWindowSize is set and the
+	// platform supports it.
+	winsz *winsizeNotifier
+
+	// rawState is the state of the terminal before [WithRawMode] put it into
+	// raw mode, can be nil.
+	rawFd    uintptr
+	rawState *term.State
+
+	// input is the reader the driver reads from, raw reports whether the
+	// driver puts it into raw mode, interrupt is how ctrl+c is handled, and
+	// flowControl is how ctrl+s and ctrl+q are handled. They're used to take
+	// the terminal back after [Driver.Pause].
+	input       io.Reader
+	raw         bool
+	interrupt   InterruptMode
+	flowControl FlowControlMode
+
+	// resumed is closed when the driver resumes, it's nil unless the
+	// driver is paused. rdBusy reports whether the reader is in use, and
+	// must be closed by the reader goroutine when it's replaced.
+	resumed chan struct{}
+	rdBusy  bool
+
+	// enableModes and disableModes are the sequences that enable and
+	// disable the terminal modes, see [Driver.SetModes].
+	enableModes, disableModes string
+
+	// suspend reports ctrl+z as a SuspendEvent.
+	suspend bool
+
+	// metrics receives measurements of the input, can be nil.
+	metrics Metrics
+
 	flags int // control the behavior of the driver.
 }
 
+// DefaultEscTimeout is the default time the driver waits for the rest of an
+// escape sequence before reporting what it has, e.g. a lone Escape key.
+const DefaultEscTimeout = 50 * time.Millisecond
+
 // NewDriver returns a new ANSI input driver.
 // This driver uses ANSI control codes compatible with VT100/VT200 terminals,
 // and XTerm. It supports reading Terminfo databases to overwrite the default
 // key sequences.
+//
+// Use [New] to create a driver using functional options.
 func NewDriver(r io.Reader, term string, flags int) (*Driver, error) {
-	d := new(Driver)
+	return newDriver(r, driverOptions{
+		term:       term,
+		flags:      flags,
+		escTimeout: DefaultEscTimeout,
+		maxReadBuf: DefaultMaxReadBufferSize,
+		maxSeq:     DefaultMaxSequenceSize,
+	})
+}
+
+func newDriver(r io.Reader, o driverOptions) (*Driver, error) {
+	d := &Driver{raw: o.raw, interrupt: o.interrupt, flowControl: o.flowControl}
+	if err := d.setupTerminal(r); err != nil {
+		return nil, err
+	}
+
 	cr, err := newCancelreader(r)
 	if err != nil {
+		d.restoreMode() // nolint: errcheck
 		return nil, err
 	}
 
+	if o.flags&FlagWindowSize != 0 {
+		d.winsz, err = newWinsizeNotifier(r)
+		if err != nil {
+			cr.Close()      // nolint: errcheck
+			d.restoreMode() // nolint: errcheck
+			return nil, err
+		}
+	}
+
+	d.parser = o.parser
+	if d.parser == nil {
+		d.parser = &EventParser{Flags: o.flags}
+	}
+
 	d.rd = cr
-	d.table = buildKeysTable(flags, term)
-	d.term = term
-	d.flags = flags
+	d.input = r
+	d.suspend = o.suspend
+	d.metrics = o.metrics
+	d.escTimeout = o.escTimeout
+	d.maxReadBuf = o.maxReadBuf
+	if d.maxReadBuf < minReadBufferSize {
+		d.maxReadBuf = minReadBufferSize
+	}
+	d.maxSeq = o.maxSeq
+	d.logger = o.logger
+	d.clicks = o.clicks
+	d.tap = o.tap
+	d.out = o.out
+	if o.decoder != nil {
+		d.decoder = &inputDecoder{t: o.decoder}
+	}
+	if o.drags {
+		d.drags = &DragTracker{}
+	}
+	if o.releaseDelay > 0 {
+		d.releases = &keyReleaser{delay: o.releaseDelay}
+	}
+	d.coalesce = o.coalesce
+	d.filters = o.filters
+	d.pasteStream = o.pasteStream
+	d.maxPaste = o.maxPaste
+	d.rawPaste = o.rawPaste
+	d.pasteSanitizer.flags = o.pasteSanitize
+	d.done = make(chan struct{})
+	d.events = make(chan Event)
+	d.errs = make(chan error, 1)
+	d.pumpDone = make(chan struct{})
+	table, sources := buildKeysTableSources(o.flags, o.term, o.terminfo)
+	d.keys, d.sources = newKeyTrie(table), sources
+	d.term = o.term
+	d.flags = o.flags
 	return d, nil
 }
 
+// processEvents applies the optional event processing, like click
+// tracking, to the events read from the terminal.
+func (d *Driver) processEvents(events []Event) []Event {
+	if d.releases != nil {
+		events = d.releases.track(events, time.Now())
+	}
+	for i, e := range events {
+		if d.clicks != nil {
+			e = d.clicks.Track(e)
+		}
+		if d.drags != nil {
+			e = d.drags.Track(e)
+		}
+		if d.suspend && isSuspendKey(e) {
+			e = SuspendEvent{}
+		}
+		events[i] = e
+	}
+	if d.interrupt == InterruptSignal {
+		events = d.raiseInterrupts(events)
+	}
+	if d.coalesce {
+		events = CoalesceMotion(events)
+	}
+	return filterEvents(events, d.filters)
+}
+
+// logf logs a message using the driver logger, if any.
+func (d *Driver) logf(format string, v ...interface{}) {
+	if d.logger != nil {
+		d.logger.Printf(format, v...)
+	}
+}
+
+// logUnknown logs the sequence b the parser didn't recognize, by name when
+// it's a well-known sequence, and reports it to the metrics.
+func (d *Driver) logUnknown(b []byte) {
+	if d.metrics != nil {
+		d.metrics.UnknownSequence(b)
+	}
+	if d.logger == nil {
+		return
+	}
+	seq := ParseUnknownSequence(string(b))
+	if seq.Name != "" {
+		d.logf("input: %s", seq.Summary())
+		return
+	}
+	d.logf("input: unknown sequence %s", seq)
+}
+
 // Cancel cancels the underlying reader.
 func (d *Driver) Cancel() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.rd == nil {
+		// The driver is paused.
+		return false
+	}
 	return d.rd.Cancel()
 }
 
-// Close closes the underlying reader.
+// Close closes the underlying reader. If the event pump was started, Close
+// stops it and waits for it to exit. The terminal mode changed by
+// [WithRawMode] is restored.
 func (d *Driver) Close() error {
-	return d.rd.Close()
+	d.doneOnce.Do(func() { close(d.done) })
+	if d.winsz != nil {
+		d.winsz.Close() // nolint: errcheck
+	}
+	d.mu.Lock()
+	started := d.started
+	d.started = true // Start has no effect once the driver is closed.
+	d.mu.Unlock()
+	if started {
+		d.Cancel()
+		<-d.pumpDone
+	} else {
+		close(d.events)
+		close(d.errs)
+		close(d.pumpDone)
+	}
+	var err error
+	d.mu.Lock()
+	if d.rd != nil {
+		err = d.rd.Close()
+	}
+	d.mu.Unlock()
+	if rerr := d.restoreMode(); err == nil {
+		err = rerr
+	}
+	return err
 }
 
-func (d *Driver) readEvents() (e []Event, err error) {
-	nb, err := d.rd.Read(d.buf[:])
-	if err != nil {
-		return nil, err
+// Start starts a goroutine that reads input events and delivers them on the
+// [Driver.Events] channel. Read errors are delivered on the [Driver.Errors]
+// channel, and the pump stops after the first one. Calling Start more than
+// once, or after [Driver.Close], has no effect.
+//
+// Once started, the driver must not be read from directly using
+// [Driver.ReadEvents]. Use [Driver.Close] to stop the pump.
+func (d *Driver) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started {
+		return
+	}
+	d.started = true
+	go d.pump()
+}
+
+// Events returns the channel input events are delivered on. It starts the
+// event pump if it isn't running yet, see [Driver.Start]. The channel is
+// closed when the pump stops.
+func (d *Driver) Events() <-chan Event {
+	d.Start()
+	return d.events
+}
+
+// Errors returns the channel read errors are delivered on. It starts the
+// event pump if it isn't running yet, see [Driver.Start]. At most one error
+// is delivered, and the channel is closed when the pump stops.
+func (d *Driver) Errors() <-chan error {
+	d.Start()
+	return d.errs
+}
+
+// pump reads events and sends them to the events channel until an error
+// occurs or the driver is closed.
+func (d *Driver) pump() {
+	defer close(d.pumpDone)
+	defer close(d.errs)
+	defer close(d.events)
+
+	var events []Event
+	for {
+		var err error
+		events, err = d.ReadEventsInto(events)
+		for _, e := range events {
+			select {
+			case d.events <- e:
+			case <-d.done:
+				return
+			}
+		}
+		if err != nil {
+			select {
+			case <-d.done:
+			default:
+				d.errs <- err
+			}
+			return
+		}
+	}
+}
+
+// ReadEvents reads input events from the terminal.
+//
+// It reads the events available in the input buffer and returns them.
+func (d *Driver) ReadEvents() ([]Event, error) {
+	return d.ReadEventsContext(context.Background())
+}
+
+// ReadEventsContext reads input events from the terminal. It returns
+// ctx.Err() if the context is done before any input is available.
+//
+// It reads the events available in the input buffer and returns them.
+func (d *Driver) ReadEventsContext(ctx context.Context) ([]Event, error) {
+	return d.nextEvents(ctx, nil)
+}
+
+// ReadEventsInto is like [Driver.ReadEvents] but reuses the storage of
+// events, which it truncates, for the events read. It returns the updated
+// slice. Use it to avoid allocating a new slice on every read when reading
+// in a loop, like when streaming mouse motion:
+//
+//	var events []input.Event
+//	for {
+//		events, err = drv.ReadEventsInto(events)
+//		// ...
+//	}
+//
+// The events of the previous call are overwritten.
+func (d *Driver) ReadEventsInto(events []Event) ([]Event, error) {
+	return d.nextEvents(context.Background(), events[:0])
+}
+
+// nextEvents appends the queued events to events, or reads new ones when
+// there are none.
+func (d *Driver) nextEvents(ctx context.Context, events []Event) ([]Event, error) {
+	if len(d.queued) > 0 {
+		events = append(events, d.queued...)
+		d.queued = d.queued[:0]
+		return events, nil
+	}
+	return d.readEventsContext(ctx, events)
+}
+
+// SetReadDeadline sets the deadline for reads started after this call. A
+// read that times out returns [os.ErrDeadlineExceeded], and no input is
+// lost. A zero value for t means reads will not time out.
+func (d *Driver) SetReadDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.deadline = t
+	d.mu.Unlock()
+	return nil
+}
+
+// withDeadline returns a context that is done when the read deadline set by
+// SetReadDeadline expires.
+func (d *Driver) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	d.mu.Lock()
+	deadline := d.deadline
+	d.mu.Unlock()
+	if deadline.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, deadline)
+}
+
+// deadlineErr translates context errors caused by the read deadline into
+// os.ErrDeadlineExceeded.
+func (d *Driver) deadlineErr(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		// The parent context is still alive, so the read deadline expired.
+		return os.ErrDeadlineExceeded
 	}
+	return err
+}
+
+// SetEscTimeout sets how long the driver waits for the rest of an escape
+// sequence that arrives split across reads. When the timeout expires, the
+// driver reports what it has received so far, for example, a lone ESC byte
+// is reported as an Escape key press. A zero timeout disables waiting. The
+// default is [DefaultEscTimeout].
+func (d *Driver) SetEscTimeout(timeout time.Duration) {
+	d.escTimeout = timeout
+}
 
-	buf := d.buf[:nb]
+// readResult is the result of a read from the underlying reader.
+type readResult struct {
+	b   []byte
+	err error
+}
 
-	// Lookup table first
-	if bytes.HasPrefix(buf, []byte{'\x1b'}) {
-		if k, ok := d.table[string(buf)]; ok {
-			e = append(e, KeyPressEvent(k))
+// readLoop performs reads on the underlying reader on request.
+func (d *Driver) readLoop() {
+	buf := make([]byte, minReadBufferSize)
+	for {
+		select {
+		case <-d.readReq:
+		case <-d.done:
 			return
 		}
+
+		// The buffer is only read into again on the next request, by then
+		// the reader is done with the last result.
+		n, err := d.readInput(buf)
+		r := readResult{err: err}
+		if n > 0 {
+			r.b = buf[:n]
+		}
+
+		// Grow the buffer when the read fills it, there's probably more.
+		if n == len(buf) && len(buf) < d.maxReadBuf {
+			size := len(buf) * 2
+			if size > d.maxReadBuf {
+				size = d.maxReadBuf
+			}
+			buf = make([]byte, size)
+		}
+
+		select {
+		case d.reads <- r:
+		case <-d.done:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// read waits for input from the underlying reader, or a window size change.
+// If timeout is a non-nil channel, read gives up and returns nil when it
+// fires, or when the context is done. The pending read is then picked up by
+// the next call. The input returned is only valid until the next call.
+func (d *Driver) read(ctx context.Context, timeout <-chan time.Time) ([]byte, Event, error) {
+	d.readOnce.Do(func() {
+		d.readReq = make(chan struct{}, 1)
+		d.reads = make(chan readResult, 1)
+		go d.readLoop()
+	})
+
+	if !d.reading {
+		d.reading = true
+		d.readReq <- struct{}{}
+	}
+
+	// Only report window size changes when waiting for new input.
+	var winsz <-chan Event
+	if timeout == nil {
+		winsz = d.winsz.events()
+	}
+
+	select {
+	case r := <-d.reads:
+		d.reading = false
+		d.readErr = r.err
+		if d.metrics != nil && len(r.b) > 0 {
+			d.metrics.BytesRead(len(r.b))
+		}
+		if d.tap != nil && len(r.b) > 0 {
+			if _, err := d.tap.Write(r.b); err != nil {
+				d.logf("input: tap: %v", err)
+			}
+		}
+		if d.decoder != nil {
+			b, err := d.decoder.decode(r.b, r.err != nil)
+			if err != nil {
+				d.logf("input: decode: %v", err)
+			}
+			r.b = b
+		}
+		return r.b, nil, nil
+	case ev := <-winsz:
+		return nil, ev, nil
+	case <-timeout:
+		return nil, nil, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-d.done:
+		return nil, nil, cancelreader.ErrCanceled
+	}
+}
+
+// isIncompleteSeq reports whether b, which was parsed as the event e of
+// length n, might be the beginning of a longer escape sequence.
+func isIncompleteSeq(b []byte, n int, e Event) bool {
+	if n != len(b) || len(b) == 0 || b[0] != ansi.ESC {
+		return false
+	}
+	if len(b) == 1 {
+		// A lone ESC.
+		return true
+	}
+	if len(b) == 2 {
+		// Sequence introducers, and ESC ESC which might be an alt
+		// modified sequence.
+		switch b[1] {
+		case '[', 'O', 'P', ']', '_', ansi.ESC:
+			return true
+		}
+	}
+	switch e.(type) {
+	case UnknownEvent:
+		// The parser reports unterminated sequences as unknown events.
+		return true
+	case UnknownDcsEvent, UnknownApcEvent:
+		// String sequences are terminated by a ST.
+		return !bytes.HasSuffix(b, []byte{ansi.ESC, '\\'}) && b[len(b)-1] != ansi.ST
+	}
+	return false
+}
+
+// canResume reports whether the incomplete sequence b can be kept pending
+// until the next read. A lone ESC, or an ESC followed by a sequence
+// introducer, might be an escape or alt modified key and is reported right
+// away.
+func (d *Driver) canResume(b []byte) bool {
+	return len(b) > 2 && len(b) <= d.maxSeq
+}
+
+func (d *Driver) readEvents(ctx context.Context, e []Event) ([]Event, error) {
+	for {
+		if d.readErr != nil && len(d.pending) == 0 {
+			return e, d.readErr
+		}
+
+		var b []byte
+		if d.readErr == nil {
+			// Wake up when a synthesized key release is due.
+			var timer *time.Timer
+			var timeout <-chan time.Time
+			if d.releases != nil {
+				if at, ok := d.releases.next(); ok {
+					timer = time.NewTimer(time.Until(at))
+					timeout = timer.C
+				}
+			}
+
+			var ev Event
+			var err error
+			b, ev, err = d.read(ctx, timeout)
+			if timer != nil {
+				timer.Stop()
+			}
+			if err != nil {
+				return e, err
+			}
+			if timeout != nil && b == nil && ev == nil {
+				return e, nil
+			}
+			if ev != nil {
+				return append(e, ev), nil
+			}
+		}
+
+		// Resume the sequence left incomplete by the previous read. The
+		// input is copied to the parse buffer, since it's only valid until
+		// the next read.
+		buf := append(append(d.buf[:0], d.pending...), b...)
+		d.pending = d.pending[:0]
+		if len(buf) == 0 {
+			return e, d.readErr
+		}
+
+		n := len(e)
+		e = d.parseEvents(ctx, buf, e)
+		if len(e) > n || len(d.pending) == 0 {
+			return e, nil
+		}
 	}
+}
 
-	var i int
+// parseEvents parses the events in buf and appends them to e. An incomplete
+// sequence at the end of buf is kept in the pending buffer to be resumed by
+// the next read.
+func (d *Driver) parseEvents(ctx context.Context, buf []byte, e []Event) []Event {
+	var (
+		i         int
+		start     = len(e)
+		parseTime time.Duration
+	)
 	for i < len(buf) {
-		nb, ev := ParseSequence(buf[i:])
+		var t time.Time
+		if d.metrics != nil {
+			t = time.Now()
+		}
+		nb, ev, custom := d.parser.parseEvent(buf[i:])
+		if d.metrics != nil {
+			parseTime += time.Since(t)
+		}
+
+		if d.readErr == nil && isIncompleteSeq(buf[i:], nb, ev) {
+			// Wait for the rest of an escape sequence that might have been
+			// split across reads.
+			if d.escTimeout > 0 {
+				timer := time.NewTimer(d.escTimeout)
+				more, _, _ := d.read(ctx, timer.C)
+				timer.Stop()
+				if len(more) > 0 {
+					buf = append(buf, more...)
+					continue
+				}
+			}
+
+			// Keep unambiguous sequences around until the rest arrives.
+			if d.canResume(buf[i:]) {
+				d.pending = append(d.pending[:0], buf[i:]...)
+				break
+			}
+		}
 
 		// Handle bracketed-paste
 		if d.paste != nil {
 			if _, ok := ev.(PasteEndEvent); !ok {
-				d.paste = append(d.paste, buf[i])
+				d.pasteByte(buf[i])
 				i++
 				continue
 			}
@@ -94,27 +721,41 @@ func (d *Driver) readEvents() (e []Event, err error) {
 
 		switch ev.(type) {
 		case UnknownCsiEvent, UnknownSs3Event, UnknownEvent:
-			// If the sequence is not recognized by the parser, try looking it up.
-			if k, ok := d.table[string(buf[i:i+nb])]; ok {
-				ev = KeyPressEvent(k)
+			// If the sequence is not recognized by the parser, look up the
+			// longest key sequence instead. Some sequences, like the Linux
+			// console CSI [ A, extend past where the parser stops.
+			if n, k, ok := d.keys.match(buf[i:]); ok && n >= nb {
+				nb, ev = n, KeyPressEvent(k)
+			} else {
+				d.logUnknown(buf[i : i+nb])
 			}
+		case UnknownOscEvent, UnknownDcsEvent, UnknownApcEvent:
+			d.logUnknown(buf[i : i+nb])
 		case PasteStartEvent:
 			d.paste = []byte{}
+			d.pasteLen = 0
+			d.pasteSanitizer.cr = false
 		case PasteEndEvent:
-			// Decode the captured data into runes.
-			var paste []rune
-			for len(d.paste) > 0 {
-				r, w := utf8.DecodeRune(d.paste)
-				if r != utf8.RuneError {
-					paste = append(paste, r)
+			if d.pasteStream {
+				if chunk := d.pasteChunk(); len(chunk) > 0 {
+					e = append(e, PasteChunkEvent(chunk))
 				}
-				d.paste = d.paste[w:]
+			} else {
+				e = append(e, d.pasteEvent())
 			}
 			d.paste = nil // reset the buffer
-			e = append(e, PasteEvent(paste))
 		case nil:
 			i++
 			continue
+		default:
+			// Key sequences, like the ones from Terminfo, take precedence
+			// over the parser, but not over the sequences registered with
+			// it.
+			if buf[i] == ansi.ESC && !custom {
+				if n, k, ok := d.keys.match(buf[i : i+nb]); ok && n == nb {
+					ev = KeyPressEvent(k)
+				}
+			}
 		}
 
 		if mevs, ok := ev.(MultiEvent); ok {
@@ -125,5 +766,65 @@ func (d *Driver) readEvents() (e []Event, err error) {
 		i += nb
 	}
 
-	return
+	// Deliver the data pasted so far when streaming.
+	if d.pasteStream && len(d.paste) > 0 {
+		if chunk := d.pasteChunk(); len(chunk) > 0 {
+			e = append(e, PasteChunkEvent(chunk))
+		}
+		d.paste = d.paste[:0]
+	}
+
+	// Keep the buffer, which might have grown, for the next read.
+	d.buf = buf[:0]
+
+	if d.metrics != nil {
+		d.metrics.ParseTime(parseTime)
+		d.reportEvents(e[start:])
+	}
+
+	return e
+}
+
+// reportEvents reports the parsed events to the metrics.
+func (d *Driver) reportEvents(events []Event) {
+	for _, e := range events {
+		d.metrics.EventParsed(e)
+	}
+}
+
+// pasteByte adds a byte to the paste buffer, unless the paste has reached
+// the maximum size.
+func (d *Driver) pasteByte(b byte) {
+	if d.maxPaste > 0 && d.pasteLen >= d.maxPaste {
+		return
+	}
+	d.paste = append(d.paste, b)
+	d.pasteLen++
+}
+
+// pasteChunk returns the sanitized paste buffer.
+func (d *Driver) pasteChunk() []byte {
+	if d.pasteSanitizer.flags != 0 {
+		return d.pasteSanitizer.sanitize(d.paste)
+	}
+	return d.paste
+}
+
+// pasteEvent returns the buffered paste data as a [PasteEvent].
+func (d *Driver) pasteEvent() PasteEvent {
+	d.paste = d.pasteChunk()
+	if d.rawPaste {
+		return PasteEvent(d.paste)
+	}
+
+	// Decode the captured data into runes.
+	var paste []rune
+	for b := d.paste; len(b) > 0; {
+		r, w := utf8.DecodeRune(b)
+		if r != utf8.RuneError {
+			paste = append(paste, r)
+		}
+		b = b[w:]
+	}
+	return PasteEvent(paste)
 }
@@ -3,9 +3,14 @@
 
 package input
 
-// ReadEvents reads input events from the terminal.
-//
-// It reads the events available in the input buffer and returns them.
-func (d *Driver) ReadEvents() ([]Event, error) {
-	return d.readEvents()
+import "context"
+
+// readEventsContext reads input events from the terminal and appends them to
+// events.
+func (d *Driver) readEventsContext(ctx context.Context, events []Event) ([]Event, error) {
+	rctx, cancel := d.withDeadline(ctx)
+	defer cancel()
+
+	events, err := d.readEvents(rctx, events)
+	return d.processEvents(events), d.deadlineErr(ctx, err)
 }
@@ -1,9 +1,16 @@
 package input
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func BenchmarkDriver(b *testing.B) {
@@ -23,3 +30,584 @@ func BenchmarkDriver(b *testing.B) {
 		}
 	}
 }
+
+func TestDriverEscTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := NewDriver(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+	drv.SetEscTimeout(time.Second)
+
+	go func() {
+		pw.Write([]byte("\x1b"))          // nolint: errcheck
+		time.Sleep(10 * time.Millisecond) // split the sequence across reads
+		pw.Write([]byte("[A"))            // nolint: errcheck
+	}()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != (KeyPressEvent{Sym: KeyUp}) {
+		t.Errorf("expected up key, got %v", events)
+	}
+
+	drv.SetEscTimeout(10 * time.Millisecond)
+	go pw.Write([]byte("\x1b")) // nolint: errcheck
+
+	events, err = drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != (KeyPressEvent{Sym: KeyEscape}) {
+		t.Errorf("expected escape key, got %v", events)
+	}
+
+	// The pending read must not lose any input.
+	go pw.Write([]byte("a")) // nolint: errcheck
+
+	events, err = drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != (KeyPressEvent{Rune: 'a'}) {
+		t.Errorf("expected a key, got %v", events)
+	}
+}
+
+func TestDriverReadDeadline(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := NewDriver(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if err := drv.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := drv.ReadEvents(); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := drv.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := drv.ReadEventsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error, got %v", err)
+	}
+
+	// Input that arrives after a timed out read is not lost.
+	go pw.Write([]byte("a")) // nolint: errcheck
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != (KeyPressEvent{Rune: 'a'}) {
+		t.Errorf("expected a key, got %v", events)
+	}
+}
+
+func TestDriverEvents(t *testing.T) {
+	drv, err := NewDriver(strings.NewReader("ab\x1b[A"), "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	drv.Start()
+	var events []Event
+	for e := range drv.Events() {
+		events = append(events, e)
+	}
+	want := []Event{
+		KeyPressEvent{Rune: 'a'},
+		KeyPressEvent{Rune: 'b'},
+		KeyPressEvent{Sym: KeyUp},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("expected %v, got %v", want, events)
+	}
+	if err := <-drv.Errors(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestDriverEventsClose(t *testing.T) {
+	pr, _ := io.Pipe()
+	drv, err := NewDriver(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+
+	events := drv.Events()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range events { // nolint: revive
+		}
+	}()
+
+	if err := drv.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("events channel was not closed")
+	}
+}
+
+func TestDriverStartCloseRace(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		pr, _ := io.Pipe()
+		drv, err := NewDriver(pr, "dumb", 0)
+		if err != nil {
+			t.Fatalf("could not create driver: %v", err)
+		}
+
+		ready := make(chan struct{})
+		go func() {
+			close(ready)
+			drv.Start()
+		}()
+		<-ready
+		if err := drv.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Whether or not Start ran first, the pump is stopped and its
+		// channels are closed.
+		select {
+		case _, ok := <-drv.Events():
+			if ok {
+				t.Fatal("unexpected event")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("events channel was not closed")
+		}
+	}
+}
+
+func TestDriverCloseBeforeStart(t *testing.T) {
+	pr, _ := io.Pipe()
+	drv, err := NewDriver(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	if err := drv.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	drv.Start()
+	if _, ok := <-drv.Events(); ok {
+		t.Error("expected the events channel to be closed")
+	}
+	if _, ok := <-drv.Errors(); ok {
+		t.Error("expected the errors channel to be closed")
+	}
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestNewDriverOptions(t *testing.T) {
+	p := &EventParser{}
+	p.RegisterSequence("\x1b[99x", KeyPressEvent{Sym: KeyF1})
+
+	var logger testLogger
+	drv, err := New(strings.NewReader("\x1b[99x\x1b[?1;2;3;4;5;6;7;8Y"),
+		WithTerm("dumb"),
+		WithFlags(FlagCtrlAt, FlagCtrlI),
+		WithoutTerminfo(),
+		WithEscTimeout(0),
+		WithLogger(&logger),
+		WithParser(p),
+	)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if drv.term != "dumb" {
+		t.Errorf("expected term %q, got %q", "dumb", drv.term)
+	}
+	if expect := FlagCtrlAt | FlagCtrlI; drv.flags != expect {
+		t.Errorf("expected flags %d, got %d", expect, drv.flags)
+	}
+	if drv.escTimeout != 0 {
+		t.Errorf("expected no esc timeout, got %v", drv.escTimeout)
+	}
+	if drv.parser != p {
+		t.Errorf("expected the driver to use the given parser")
+	}
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) == 0 || events[0] != (KeyPressEvent{Sym: KeyF1}) {
+		t.Errorf("expected registered sequence event, got %v", events)
+	}
+	if len(logger.lines) != 1 {
+		t.Errorf("expected one unknown sequence to be logged, got %q", logger.lines)
+	}
+}
+
+func TestDriverLogsUnknownSequences(t *testing.T) {
+	var logger testLogger
+	drv, err := New(strings.NewReader("\x1bP$qm\x1b\\\x1b[1;2Y"),
+		WithTerm("dumb"),
+		WithLogger(&logger),
+	)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if _, err := drv.ReadEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []string{
+		"input: unrecognized DECRQSS",
+		`input: unknown sequence CSI 1;2 Y "\x1b[1;2Y"`,
+	}
+	if !reflect.DeepEqual(logger.lines, expect) {
+		t.Errorf("expected %q to be logged, got %q", expect, logger.lines)
+	}
+}
+
+func TestNewDriverDefaults(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	drv, err := New(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if drv.term != "xterm-256color" {
+		t.Errorf("expected term from $TERM, got %q", drv.term)
+	}
+	if drv.flags != FlagTerminfo {
+		t.Errorf("expected terminfo flag, got %d", drv.flags)
+	}
+	if drv.escTimeout != DefaultEscTimeout {
+		t.Errorf("expected default esc timeout, got %v", drv.escTimeout)
+	}
+}
+
+func TestDriverPasteStreaming(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithPasteStreaming())
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	go func() {
+		pw.Write([]byte("\x1b[200~ab\xff")) // nolint: errcheck
+		time.Sleep(10 * time.Millisecond)
+		pw.Write([]byte("\rc\x1b[201~")) // nolint: errcheck
+	}()
+
+	var events []Event
+	for len(events) < 4 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, evs...)
+	}
+
+	expect := []Event{
+		PasteStartEvent{},
+		PasteChunkEvent("ab\xff"),
+		PasteChunkEvent("\rc"),
+		PasteEndEvent{},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %q, got %q", expect, events)
+	}
+}
+
+func TestDriverPasteOptions(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   []DriverOption
+		expect PasteEvent
+	}{
+		{"default", nil, "héllo\r"},
+		{"raw", []DriverOption{WithRawPaste()}, "h\xc3\xa9l\xfflo\r"},
+		{"max size", []DriverOption{WithMaxPasteSize(3)}, "hé"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input := "\x1b[200~h\xc3\xa9l\xfflo\r\x1b[201~"
+			drv, err := New(strings.NewReader(input), append(c.opts, WithTerm("dumb"))...)
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			defer drv.Close()
+
+			events, err := drv.ReadEvents()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != 3 || events[1] != c.expect {
+				t.Errorf("expected paste %q, got %q", c.expect, events)
+			}
+		})
+	}
+}
+
+func TestDriverInputTap(t *testing.T) {
+	var tap bytes.Buffer
+	input := "a\x1b[A\x1b[?1;2;3;4;5;6;7;8Y"
+	drv, err := New(strings.NewReader(input), WithTerm("dumb"), WithInputTap(&tap))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if _, err := drv.ReadEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tap.String() != input {
+		t.Errorf("expected tap to receive %q, got %q", input, tap.String())
+	}
+}
+
+func TestDriverResumeSequence(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithEscTimeout(0))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	// Without waiting for split sequences, the driver keeps incomplete
+	// sequences until the next read.
+	chunks := []string{
+		"a\x1b]11;rgb:ffff/",
+		"0000/0000\x1b",
+		"\\\x1b[1;",
+		"5A",
+	}
+	go func() {
+		for _, c := range chunks {
+			pw.Write([]byte(c)) // nolint: errcheck
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	var events []Event
+	for len(events) < 3 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, evs...)
+	}
+
+	if events[0] != (KeyPressEvent{Rune: 'a'}) {
+		t.Errorf("expected a key, got %#v", events[0])
+	}
+	if _, ok := events[1].(BackgroundColorEvent); !ok {
+		t.Errorf("expected a background color event, got %#v", events[1])
+	}
+	if events[2] != (KeyPressEvent{Sym: KeyUp, Mod: ModCtrl}) {
+		t.Errorf("expected ctrl+up, got %#v", events[2])
+	}
+}
+
+func TestDriverResumeSequenceEOF(t *testing.T) {
+	drv, err := New(strings.NewReader("\x1b[1;"), WithTerm("dumb"), WithEscTimeout(0))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != UnknownEvent("\x1b[1;") {
+		t.Errorf("expected the incomplete sequence, got %#v", events)
+	}
+	if _, err := drv.ReadEvents(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+type chunkRecorder struct {
+	sizes []int
+}
+
+func (r *chunkRecorder) Write(p []byte) (int, error) {
+	r.sizes = append(r.sizes, len(p))
+	return len(p), nil
+}
+
+func TestDriverReadBufferGrowth(t *testing.T) {
+	input := "\x1b[200~" + strings.Repeat("x", 10000) + "\x1b[201~"
+	cases := []struct {
+		name   string
+		opts   []DriverOption
+		expect []int
+	}{
+		{"default", nil, []int{256, 512, 1024, 2048, 4096, 2076}},
+		{"limited", []DriverOption{WithMaxReadBufferSize(1000)}, []int{256, 512, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 244}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var rec chunkRecorder
+			opts := append([]DriverOption{WithTerm("dumb"), WithInputTap(&rec)}, c.opts...)
+			drv, err := New(strings.NewReader(input), opts...)
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			defer drv.Close()
+
+			var paste PasteEvent
+			for paste == "" {
+				events, err := drv.ReadEvents()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, e := range events {
+					if p, ok := e.(PasteEvent); ok {
+						paste = p
+					}
+				}
+			}
+			if len(paste) != 10000 {
+				t.Errorf("expected a 10000 bytes paste, got %d", len(paste))
+			}
+			if !reflect.DeepEqual(rec.sizes, c.expect) {
+				t.Errorf("expected reads of %v, got %v", c.expect, rec.sizes)
+			}
+		})
+	}
+}
+
+func TestDriverMaxSequenceSize(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithEscTimeout(0), WithMaxSequenceSize(8))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	go pw.Write([]byte("\x1b]11;rgb:ffff/0000/0000")) // nolint: errcheck
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0] != UnknownEvent("\x1b]11;rgb:ffff/0000/0000") {
+		t.Errorf("expected an unknown event, got %#v", events)
+	}
+}
+
+// repeatReader returns the same input on every read.
+type repeatReader []byte
+
+func (r repeatReader) Read(p []byte) (int, error) {
+	return copy(p, r), nil
+}
+
+func TestDriverReadEventsInto(t *testing.T) {
+	motion := "\x1b[<35;10;20M"
+	drv, err := New(repeatReader(strings.Repeat(motion, 4)), WithTerm("dumb"))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	want := MouseMotionEvent{X: 9, Y: 19, Button: MouseNone}
+	events := make([]Event, 1, 16)
+	for i := 0; i < 3; i++ {
+		got, err := drv.ReadEventsInto(events)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 4 || got[0] != want || got[3] != want {
+			t.Fatalf("expected 4 motion events, got %v", got)
+		}
+		if &got[0] != &events[0] {
+			t.Fatal("expected the events storage to be reused")
+		}
+		events = got
+	}
+}
+
+func BenchmarkDriverReadEventsInto(b *testing.B) {
+	drv, err := New(repeatReader(strings.Repeat("\x1b[<35;10;20M", 8)), WithTerm("dumb"))
+	if err != nil {
+		b.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	b.Run("ReadEvents", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := drv.ReadEvents(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ReadEventsInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var events []Event
+		for i := 0; i < b.N; i++ {
+			if events, err = drv.ReadEventsInto(events); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDriverRawModeNotTerminal(t *testing.T) {
+	drv, err := New(strings.NewReader("a"), WithTerm("dumb"), WithRawMode())
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	if drv.rawState != nil {
+		t.Error("expected no raw mode for a non-terminal reader")
+	}
+	if err := drv.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDriverSuspendEvents(t *testing.T) {
+	for _, suspend := range []bool{false, true} {
+		opts := []DriverOption{WithTerm("dumb")}
+		expect := []Event{KeyPressEvent{Rune: 'z', Mod: ModCtrl}, KeyPressEvent{Rune: 'z'}}
+		if suspend {
+			opts = append(opts, WithSuspendEvents())
+			expect[0] = SuspendEvent{}
+		}
+		drv, err := New(strings.NewReader("\x1az"), opts...)
+		if err != nil {
+			t.Fatalf("could not create driver: %v", err)
+		}
+		events, err := drv.ReadEvents()
+		drv.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(events, expect) {
+			t.Errorf("expected %v, got %v", expect, events)
+		}
+	}
+}
@@ -4,52 +4,86 @@
 package input
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"unicode/utf16"
 
 	"github.com/charmbracelet/x/ansi"
 	"github.com/erikgeiser/coninput"
+	"github.com/muesli/cancelreader"
 	"golang.org/x/sys/windows"
 )
 
-// ReadEvents reads input events from the terminal.
-//
-// It reads the events available in the input buffer and returns them.
-func (d *Driver) ReadEvents() ([]Event, error) {
-	events, err := d.handleConInput(coninput.ReadConsoleInput)
+// readEventsContext reads input events from the terminal and appends them to
+// events.
+func (d *Driver) readEventsContext(ctx context.Context, events []Event) ([]Event, error) {
+	rctx, cancel := d.withDeadline(ctx)
+	defer cancel()
+
+	evs, err := d.handleConInput(rctx, coninput.ReadConsoleInput)
 	if errors.Is(err, errNotConInputReader) {
-		return d.readEvents()
+		events, err = d.readEvents(rctx, events)
+	} else {
+		events = append(events, evs...)
 	}
-	return events, err
+	return d.processEvents(events), d.deadlineErr(ctx, err)
 }
 
 var errNotConInputReader = fmt.Errorf("handleConInput: not a conInputReader")
 
 func (d *Driver) handleConInput(
+	ctx context.Context,
 	finput func(windows.Handle, []coninput.InputRecord) (uint32, error),
 ) ([]Event, error) {
-	cc, ok := d.rd.(*conInputReader)
+	rd, err := d.acquireReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc, ok := rd.(*conInputReader)
 	if !ok {
+		d.releaseReader(rd)
 		return nil, errNotConInputReader
 	}
 
+	// Wait for input to become available, or for the reader to get
+	// canceled, before blocking on reading console input records.
+	if !cc.isCanceled() {
+		err = waitForInputContext(ctx, cc.conin, cc.cancelEvent)
+	}
+	if err == nil && cc.isCanceled() {
+		err = cancelreader.ErrCanceled
+	}
+	if err != nil {
+		if d.releaseReader(rd) && errors.Is(err, cancelreader.ErrCanceled) {
+			// The driver was paused, read from the console again once
+			// it's resumed.
+			return d.handleConInput(ctx, finput)
+		}
+		return nil, err
+	}
+	defer d.releaseReader(rd)
+
 	// read up to 256 events, this is to allow for sequences events reported as
 	// key events.
 	var events [256]coninput.InputRecord
-	_, err := finput(cc.conin, events[:])
+	n, err := finput(cc.conin, events[:])
 	if err != nil {
 		return nil, fmt.Errorf("read coninput events: %w", err)
 	}
 
 	var evs []Event
-	for _, event := range events {
+	for _, event := range events[:n] {
 		if e := parseConInputEvent(event, &d.prevMouseState, &d.lastWinsizeEvent); e != nil {
 			evs = append(evs, e)
 		}
 	}
 
-	return d.detectConInputQuerySequences(evs), nil
+	evs = d.detectConInputQuerySequences(evs)
+	if d.metrics != nil {
+		d.reportEvents(evs)
+	}
+	return evs, nil
 }
 
 // Using ConInput API, Windows Terminal responds to sequence query events with
@@ -89,7 +123,7 @@ loop:
 		}
 	}
 
-	n, seqevent := ParseSequence(seq)
+	n, seqevent, _ := d.parser.parseEvent(seq)
 	switch seqevent.(type) {
 	case UnknownEvent:
 		// We're not interested in unknown events
@@ -181,7 +215,12 @@ func parseConInputEvent(event coninput.InputRecord, ps *coninput.ButtonState, ws
 		mevent := mouseEvent(*ps, e)
 		*ps = e.ButtonState
 		return mevent
-	case coninput.FocusEventRecord, coninput.MenuEventRecord:
+	case coninput.FocusEventRecord:
+		if e.SetFocus {
+			return FocusEvent{}
+		}
+		return BlurEvent{}
+	case coninput.MenuEventRecord:
 		// ignore
 	}
 	return nil
@@ -246,12 +285,16 @@ func mouseEvent(p coninput.ButtonState, e coninput.MouseEventRecord) (ev Event)
 	case coninput.CLICK, coninput.DOUBLE_CLICK:
 		m.Button, isRelease = mouseEventButton(p, e.ButtonState)
 	case coninput.MOUSE_WHEELED:
+		// The high word of the button state is the signed wheel delta, in
+		// multiples of WHEEL_DELTA for notched wheels.
+		m.Delta = int(int16(uint32(e.ButtonState) >> 16))
 		if e.WheelDirection > 0 {
 			m.Button = MouseWheelUp
 		} else {
 			m.Button = MouseWheelDown
 		}
 	case coninput.MOUSE_HWHEELED:
+		m.Delta = int(int16(uint32(e.ButtonState) >> 16))
 		if e.WheelDirection > 0 {
 			m.Button = MouseWheelRight
 		} else {
@@ -0,0 +1,49 @@
+//go:build windows
+// +build windows
+
+package input
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/erikgeiser/coninput"
+)
+
+func TestParseConInputEvent(t *testing.T) {
+	focus := func(set bool) coninput.InputRecord {
+		r := coninput.InputRecord{EventType: coninput.FocusEventType}
+		if set {
+			r.Event[0] = 1
+		}
+		return r
+	}
+	winsize := func(w, h uint16) coninput.InputRecord {
+		r := coninput.InputRecord{EventType: coninput.WindowBufferSizeEventType}
+		binary.LittleEndian.PutUint16(r.Event[0:2], w)
+		binary.LittleEndian.PutUint16(r.Event[2:4], h)
+		return r
+	}
+
+	cases := []struct {
+		name   string
+		record coninput.InputRecord
+		expect Event
+	}{
+		{"focus", focus(true), FocusEvent{}},
+		{"blur", focus(false), BlurEvent{}},
+		{"window size", winsize(80, 24), WindowSizeEvent{Width: 80, Height: 24}},
+		{"menu", coninput.InputRecord{EventType: coninput.MenuEventType}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ps coninput.ButtonState
+			var ws coninput.WindowBufferSizeEventRecord
+			got := parseConInputEvent(c.record, &ps, &ws)
+			if !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %#v, got %#v", c.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package input
+
+// EventFilter drops, transforms, or expands an event before the driver
+// delivers it. It returns the events to deliver in its place, which can be
+// none to drop the event. See [WithFilter].
+//
+// For example, this filter turns alt+h and alt+l into arrow keys, and drops
+// mouse motion events:
+//
+//	func(e input.Event) []input.Event {
+//		switch e := e.(type) {
+//		case input.KeyPressEvent:
+//			switch e.String() {
+//			case "alt+h":
+//				return []input.Event{input.KeyPressEvent{Sym: input.KeyLeft}}
+//			case "alt+l":
+//				return []input.Event{input.KeyPressEvent{Sym: input.KeyRight}}
+//			}
+//		case input.MouseMotionEvent:
+//			return nil
+//		}
+//		return []input.Event{e}
+//	}
+type EventFilter func(e Event) []Event
+
+// filterEvents runs the events through the filters, in order. The events
+// returned by a filter are passed to the next one.
+func filterEvents(events []Event, filters []EventFilter) []Event {
+	for _, f := range filters {
+		if len(events) == 0 {
+			break
+		}
+		var out []Event
+		for _, e := range events {
+			out = append(out, f(e)...)
+		}
+		events = out
+	}
+	return events
+}
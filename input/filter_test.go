@@ -0,0 +1,60 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDriverFilters(t *testing.T) {
+	vimArrows := func(e Event) []Event {
+		if k, ok := e.(KeyPressEvent); ok {
+			switch k.String() {
+			case "alt+h":
+				return []Event{KeyPressEvent{Sym: KeyLeft}}
+			case "alt+l":
+				return []Event{KeyPressEvent{Sym: KeyRight}}
+			}
+		}
+		return []Event{e}
+	}
+	noMotion := func(e Event) []Event {
+		if _, ok := e.(MouseMotionEvent); ok {
+			return nil
+		}
+		return []Event{e}
+	}
+	double := func(e Event) []Event {
+		if k, ok := e.(KeyPressEvent); ok && k.Sym == KeyRight {
+			return []Event{e, e}
+		}
+		return []Event{e}
+	}
+
+	input := "\x1bh\x1b[<35;1;1M\x1bl" + "x"
+	drv, err := New(strings.NewReader(input), WithTerm("dumb"),
+		WithFilter(vimArrows), WithFilter(noMotion), WithFilter(double))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	var events []Event
+	for {
+		e, err := drv.ReadEvents()
+		if err != nil {
+			break
+		}
+		events = append(events, e...)
+	}
+
+	expect := []Event{
+		KeyPressEvent{Sym: KeyLeft},
+		KeyPressEvent{Sym: KeyRight},
+		KeyPressEvent{Sym: KeyRight},
+		KeyPressEvent{Rune: 'x'},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %#v, got %#v", expect, events)
+	}
+}
@@ -0,0 +1,21 @@
+package input
+
+// FlowControlMode is how the driver handles the ctrl+s and ctrl+q software
+// flow control keys, also known as XOFF and XON, see [WithFlowControl].
+type FlowControlMode int
+
+const (
+	// FlowControlDefault leaves flow control to the terminal settings the
+	// program inherited. In raw mode, see [WithRawMode], ctrl+s and ctrl+q
+	// arrive as keys.
+	FlowControlDefault FlowControlMode = iota
+
+	// FlowControlKey turns off the terminal flow control, so that ctrl+s and
+	// ctrl+q arrive as key presses, even without raw mode.
+	FlowControlKey
+
+	// FlowControlTerminal turns on the terminal flow control, even in raw
+	// mode. Ctrl+s stops the program output, and ctrl+q resumes it. The keys
+	// don't arrive as input.
+	FlowControlTerminal
+)
@@ -1,12 +1,24 @@
 package input
 
-// FocusEvent represents a focus event.
+// FocusEvent represents a focus event. This is reported when the terminal
+// window gains focus and focus reporting is enabled using
+// [ansi.EnableReportFocus].
+//
+//	CSI I
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-FocusIn_FocusOut
 type FocusEvent struct{}
 
 // String implements fmt.Stringer.
 func (FocusEvent) String() string { return "focus" }
 
-// BlurEvent represents a blur event.
+// BlurEvent represents a blur event. This is reported when the terminal
+// window loses focus and focus reporting is enabled using
+// [ansi.EnableReportFocus].
+//
+//	CSI O
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-FocusIn_FocusOut
 type BlurEvent struct{}
 
 // String implements fmt.Stringer.
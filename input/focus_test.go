@@ -23,3 +23,14 @@ func TestBlur(t *testing.T) {
 		t.Error("invalid sequence")
 	}
 }
+
+func TestFocusWithParams(t *testing.T) {
+	seq := "\x1b[2I"
+	n, e := ParseSequence([]byte(seq))
+	if n != len(seq) {
+		t.Errorf("expected %d bytes, got %d", len(seq), n)
+	}
+	if _, ok := e.(UnknownCsiEvent); !ok {
+		t.Errorf("expected UnknownCsiEvent, got %T", e)
+	}
+}
@@ -4,10 +4,12 @@ go 1.18
 
 require (
 	github.com/charmbracelet/x/ansi v0.3.2
+	github.com/charmbracelet/x/term v0.2.0
 	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f
 	github.com/muesli/cancelreader v0.2.2
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e
 	golang.org/x/sys v0.26.0
+	golang.org/x/text v0.19.0
 )
 
 require (
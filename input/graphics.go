@@ -0,0 +1,77 @@
+package input
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// GraphicsReplyEvent is the reply of the terminal to a Kitty graphics
+// command, reporting whether it succeeded. The terminal replies to commands
+// with an image ID or number, unless told to be quiet.
+//
+//	ESC _ G i=31 ; OK ESC \
+//	ESC _ G i=31 ; ENOENT:file not found ESC \
+//
+// See: https://sw.kovidgoyal.net/kitty/graphics-protocol/
+type GraphicsReplyEvent struct {
+	// ImageID is the image ID, the i= key, zero if not set.
+	ImageID int
+
+	// ImageNumber is the image number, the I= key, zero if not set.
+	ImageNumber int
+
+	// PlacementID is the placement ID, the p= key, zero if not set.
+	PlacementID int
+
+	// Message is "OK" when the command succeeded, or an error code followed
+	// by a colon and a description, like "ENOENT:file not found".
+	Message string
+}
+
+// OK reports whether the command succeeded.
+func (e GraphicsReplyEvent) OK() bool {
+	return e.Message == "OK"
+}
+
+// ErrorCode returns the error code of the reply, like "ENOENT", or an empty
+// string when the command succeeded.
+func (e GraphicsReplyEvent) ErrorCode() string {
+	if e.OK() {
+		return ""
+	}
+	if i := strings.IndexByte(e.Message, ':'); i >= 0 {
+		return e.Message[:i]
+	}
+	return e.Message
+}
+
+// parseGraphicsReply parses the data of a Kitty graphics reply, after the
+// G. It returns nil when data isn't a reply.
+func parseGraphicsReply(data []byte) Event {
+	i := bytes.IndexByte(data, ';')
+	if i < 0 {
+		return nil
+	}
+
+	var e GraphicsReplyEvent
+	e.Message = string(data[i+1:])
+	for _, kv := range bytes.Split(data[:i], []byte{','}) {
+		if len(kv) < 3 || kv[1] != '=' {
+			continue
+		}
+		v, err := strconv.Atoi(string(kv[2:]))
+		if err != nil {
+			continue
+		}
+		switch kv[0] {
+		case 'i':
+			e.ImageID = v
+		case 'I':
+			e.ImageNumber = v
+		case 'p':
+			e.PlacementID = v
+		}
+	}
+	return e
+}
@@ -0,0 +1,24 @@
+package input
+
+import "testing"
+
+func TestGraphicsReplyEventError(t *testing.T) {
+	cases := []struct {
+		msg  string
+		ok   bool
+		code string
+	}{
+		{"OK", true, ""},
+		{"ENOENT:file not found", false, "ENOENT"},
+		{"EINVAL", false, "EINVAL"},
+	}
+	for _, tc := range cases {
+		e := GraphicsReplyEvent{Message: tc.msg}
+		if e.OK() != tc.ok {
+			t.Errorf("%q: expected OK() to be %v", tc.msg, tc.ok)
+		}
+		if code := e.ErrorCode(); code != tc.code {
+			t.Errorf("%q: expected error code %q, got %q", tc.msg, tc.code, code)
+		}
+	}
+}
@@ -24,16 +24,62 @@ func (e UnknownEvent) String() string {
 	return fmt.Sprintf("%q", string(e))
 }
 
-// WindowSizeEvent represents a window resize event.
+// WindowSizeEvent represents a window resize event. Width and Height are in
+// cells. PixelWidth and PixelHeight are only reported by some sources, like
+// in-band resize reports, and are zero otherwise.
 type WindowSizeEvent struct {
-	Width, Height int
+	Width, Height           int
+	PixelWidth, PixelHeight int
 }
 
 // String implements fmt.Stringer.
 func (e WindowSizeEvent) String() string {
+	if e.PixelWidth > 0 || e.PixelHeight > 0 {
+		return fmt.Sprintf("resize: %dx%d (%dx%d px)", e.Width, e.Height, e.PixelWidth, e.PixelHeight)
+	}
 	return fmt.Sprintf("resize: %dx%d", e.Width, e.Height)
 }
 
+// CellSizeEvent represents the size of a terminal cell in pixels. It's the
+// response to [ansi.RequestCellSize].
+type CellSizeEvent struct {
+	Width, Height int
+}
+
+// String implements fmt.Stringer.
+func (e CellSizeEvent) String() string {
+	return fmt.Sprintf("cell size: %dx%d px", e.Width, e.Height)
+}
+
+// PixelSizeEvent represents the size of the terminal text area, or window,
+// in pixels. It's the response to [ansi.RequestTextAreaPixelSize] and
+// [ansi.RequestWindowPixelSize].
+type PixelSizeEvent struct {
+	Width, Height int
+}
+
+// String implements fmt.Stringer.
+func (e PixelSizeEvent) String() string {
+	return fmt.Sprintf("pixel size: %dx%d px", e.Width, e.Height)
+}
+
+// TextAreaSizeEvent represents the size of the terminal text area in cells.
+// It's the response to [ansi.RequestTextAreaSize].
+type TextAreaSizeEvent struct {
+	Width, Height int
+}
+
+// String implements fmt.Stringer.
+func (e TextAreaSizeEvent) String() string {
+	return fmt.Sprintf("text area size: %dx%d", e.Width, e.Height)
+}
+
+// WindowStateEvent reports whether the terminal window is minimized. It's
+// the response to [ansi.RequestWindowState].
+type WindowStateEvent struct {
+	Minimized bool
+}
+
 // MultiEvent represents multiple events.
 type MultiEvent []Event
 
@@ -0,0 +1,141 @@
+// Package inputtest provides utilities to test applications that read
+// terminal input using the input package, without a terminal.
+package inputtest
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/input"
+)
+
+// Driver is an input driver that reads the input sent by the test instead
+// of a terminal. The input goes through the same parsing as terminal input,
+// so applications see the same events they would see in a terminal.
+//
+//	drv, _ := inputtest.New()
+//	defer drv.Close()
+//
+//	drv.Send(input.KeyPressEvent{Rune: 'q'})
+//	events, _ := drv.ReadEvents()
+type Driver struct {
+	*input.Driver
+
+	r *reader
+}
+
+// New returns a new test driver. The options are passed to [input.New], and
+// the terminal defaults to "dumb" so that the results don't depend on the
+// Terminfo database.
+func New(opts ...input.DriverOption) (*Driver, error) {
+	r := newReader()
+	opts = append([]input.DriverOption{input.WithTerm("dumb")}, opts...)
+	drv, err := input.New(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Driver{Driver: drv, r: r}, nil
+}
+
+// Send sends the sequences of the given events as a single chunk of input.
+// See [Encode] for how events are encoded.
+func (d *Driver) Send(events ...input.Event) error {
+	s, err := Encode(input.MultiEvent(events))
+	if err != nil {
+		return err
+	}
+	d.r.send(0, s)
+	return nil
+}
+
+// SendString sends the given raw input as a single chunk.
+func (d *Driver) SendString(s string) {
+	d.r.send(0, s)
+}
+
+// SendChunks sends the given raw input as separate chunks, with delay
+// between them. The driver receives the chunks in separate reads, which is
+// useful to test sequences split across reads.
+//
+//	// An up arrow split after the ESC byte.
+//	drv.SendChunks(10*time.Millisecond, "\x1b", "[A")
+func (d *Driver) SendChunks(delay time.Duration, chunks ...string) {
+	for i, c := range chunks {
+		if i == 0 {
+			d.r.send(0, c)
+		} else {
+			d.r.send(delay, c)
+		}
+	}
+}
+
+// Close closes the driver and its input.
+func (d *Driver) Close() error {
+	d.r.close()
+	return d.Driver.Close()
+}
+
+// chunk is a chunk of input delivered in a single read.
+type chunk struct {
+	data  string
+	delay time.Duration // delay before delivering the chunk
+}
+
+// reader is an io.Reader that delivers each chunk in a separate read, and
+// blocks until there is input or it's closed.
+type reader struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	chunks []chunk
+	closed bool
+}
+
+func newReader() *reader {
+	r := &reader{}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *reader) send(delay time.Duration, s string) {
+	if s == "" {
+		return
+	}
+	r.mu.Lock()
+	r.chunks = append(r.chunks, chunk{data: s, delay: delay})
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+func (r *reader) close() {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// Read implements io.Reader.
+func (r *reader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for len(r.chunks) == 0 && !r.closed {
+		r.cond.Wait()
+	}
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	if delay := r.chunks[0].delay; delay > 0 {
+		r.chunks[0].delay = 0
+		r.mu.Unlock()
+		time.Sleep(delay)
+		r.mu.Lock()
+	}
+
+	n := copy(p, r.chunks[0].data)
+	if r.chunks[0].data = r.chunks[0].data[n:]; r.chunks[0].data == "" {
+		r.chunks = r.chunks[1:]
+	}
+	return n, nil
+}
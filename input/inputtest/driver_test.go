@@ -0,0 +1,143 @@
+package inputtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/input"
+)
+
+func TestSend(t *testing.T) {
+	events := []input.Event{
+		input.KeyPressEvent{Rune: 'a'},
+		input.KeyPressEvent{Rune: 'x', Mod: input.ModCtrl},
+		input.KeyPressEvent{Rune: 'b', Mod: input.ModAlt},
+		input.KeyPressEvent{Rune: 'A', AltRune: 'a', Mod: input.ModShift},
+		input.KeyPressEvent{Rune: 'é'},
+		input.KeyPressEvent{Sym: input.KeyUp},
+		input.KeyPressEvent{Sym: input.KeyF5, Mod: input.ModCtrl | input.ModShift},
+		input.KeyPressEvent{Sym: input.KeyEnter},
+		input.KeyPressEvent{Sym: input.KeyEscape},
+		input.KeyPressEvent{Sym: input.KeyMediaPlayPause},
+		input.KeyPressEvent{Sym: input.KeyRaiseVol, Mod: input.ModShift},
+		input.KeyPressEvent{Sym: input.KeySpace, Rune: ' '},
+		input.KeyPressEvent{Sym: input.KeySpace, Rune: ' ', Mod: input.ModCtrl},
+		input.KeyReleaseEvent{Rune: 'q'},
+		input.KeyReleaseEvent{Sym: input.KeyLeft, Mod: input.ModAlt},
+		input.MouseClickEvent{X: 3, Y: 4, Button: input.MouseLeft},
+		input.MouseMotionEvent{X: 5, Y: 4, Button: input.MouseLeft},
+		input.MouseReleaseEvent{X: 5, Y: 4, Button: input.MouseLeft},
+		input.MouseWheelEvent{X: 1, Y: 1, Button: input.MouseWheelDown, Delta: -input.WheelDelta},
+		input.MouseWheelEvent{X: 2, Y: 7, Button: input.MouseWheelRight, Mod: input.ModCtrl, Delta: 45},
+		input.PasteStartEvent{},
+		input.PasteEvent("hello\nworld"),
+		input.PasteEndEvent{},
+		input.FocusEvent{},
+		input.WindowSizeEvent{Width: 80, Height: 24},
+	}
+
+	drv, err := New()
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	// Pastes are reported with their start and end events.
+	var send []input.Event
+	for _, e := range events {
+		switch e.(type) {
+		case input.PasteStartEvent, input.PasteEndEvent:
+			continue
+		}
+		send = append(send, e)
+	}
+	if err := drv.Send(send...); err != nil {
+		t.Fatalf("could not send events: %v", err)
+	}
+
+	var got []input.Event
+	for len(got) < len(events) {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, evs...)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("expected %d events, got %d: %#v", len(events), len(got), got)
+	}
+	for i := range events {
+		if !reflect.DeepEqual(got[i], events[i]) {
+			t.Errorf("%d: expected %#v, got %#v", i, events[i], got[i])
+		}
+	}
+}
+
+func TestSendUnsupported(t *testing.T) {
+	drv, err := New()
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if err := drv.Send(input.CursorPositionEvent{}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSendChunks(t *testing.T) {
+	drv, err := New(input.WithEscTimeout(50 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	// The driver waits for the rest of the sequence.
+	drv.SendChunks(10*time.Millisecond, "\x1b", "[A")
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := []input.Event{input.KeyPressEvent{Sym: input.KeyUp}}; !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+
+	// Unless it takes too long.
+	drv.SendChunks(100*time.Millisecond, "\x1b", "a")
+	var got []input.Event
+	for len(got) < 2 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, evs...)
+	}
+	expect := []input.Event{input.KeyPressEvent{Sym: input.KeyEscape}, input.KeyPressEvent{Rune: 'a'}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	drv, err := New()
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		drv.ReadEvents() // nolint: errcheck
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := drv.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ReadEvents didn't return after Close")
+	}
+}
@@ -0,0 +1,269 @@
+package inputtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/input"
+)
+
+// Encode returns the sequence a terminal would send for the given event.
+//
+// Keys are encoded using their legacy encoding when possible, and the Kitty
+// keyboard protocol otherwise, e.g. for key releases. Mouse events use the
+// SGR mouse encoding, with Kitty's high-resolution scrolling for wheel
+// deltas other than a notch, pastes use bracketed-paste, and window sizes use
+// in-band resize reports.
+func Encode(e input.Event) (string, error) {
+	switch e := e.(type) {
+	case input.KeyPressEvent:
+		k := input.Key(e)
+		event := 1
+		if k.IsRepeat {
+			event = 2
+		}
+		return encodeKey(k, event)
+	case input.KeyReleaseEvent:
+		return encodeKey(input.Key(e), 3)
+	case input.MouseClickEvent:
+		return encodeMouse(input.Mouse(e), false, false)
+	case input.MouseReleaseEvent:
+		return encodeMouse(input.Mouse(e), true, false)
+	case input.MouseWheelEvent:
+		return encodeMouse(input.Mouse(e), false, false)
+	case input.MouseMotionEvent:
+		return encodeMouse(input.Mouse(e), false, true)
+	case input.PasteEvent:
+		return "\x1b[200~" + string(e) + "\x1b[201~", nil
+	case input.FocusEvent:
+		return "\x1b[I", nil
+	case input.BlurEvent:
+		return "\x1b[O", nil
+	case input.WindowSizeEvent:
+		return fmt.Sprintf("\x1b[48;%d;%d;%d;%dt", e.Height, e.Width, e.PixelHeight, e.PixelWidth), nil
+	case input.MultiEvent:
+		var sb strings.Builder
+		for _, ev := range e {
+			s, err := Encode(ev)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+		}
+		return sb.String(), nil
+	}
+	return "", fmt.Errorf("inputtest: can't encode %T", e)
+}
+
+// Kitty keyboard protocol modifiers.
+const (
+	kittyShift = 1 << iota
+	kittyAlt
+	kittyCtrl
+	kittySuper
+	kittyHyper
+	kittyMeta
+)
+
+// kittyMod returns the Kitty keyboard protocol modifiers parameter of the
+// given modifiers and event type.
+func kittyMod(m input.KeyMod, event int) string {
+	var mod int
+	if m.HasShift() {
+		mod |= kittyShift
+	}
+	if m.HasAlt() {
+		mod |= kittyAlt
+	}
+	if m.HasCtrl() {
+		mod |= kittyCtrl
+	}
+	if m.HasSuper() {
+		mod |= kittySuper
+	}
+	if m.HasHyper() {
+		mod |= kittyHyper
+	}
+	if m.HasMeta() {
+		mod |= kittyMeta
+	}
+	s := strconv.Itoa(mod + 1)
+	if event > 1 {
+		s += ":" + strconv.Itoa(event)
+	}
+	return s
+}
+
+// Legacy CSI key sequences. The letter keys use CSI 1 ; mod <letter>, and
+// the others use CSI <number> ; mod ~.
+var (
+	csiLetterKeys = map[input.KeySym]byte{
+		input.KeyUp:    'A',
+		input.KeyDown:  'B',
+		input.KeyRight: 'C',
+		input.KeyLeft:  'D',
+		input.KeyBegin: 'E',
+		input.KeyEnd:   'F',
+		input.KeyHome:  'H',
+		input.KeyF1:    'P',
+		input.KeyF2:    'Q',
+		input.KeyF3:    'R',
+		input.KeyF4:    'S',
+	}
+	csiTildeKeys = map[input.KeySym]int{
+		input.KeyInsert: 2,
+		input.KeyDelete: 3,
+		input.KeyPgUp:   5,
+		input.KeyPgDown: 6,
+		input.KeyF5:     15,
+		input.KeyF6:     17,
+		input.KeyF7:     18,
+		input.KeyF8:     19,
+		input.KeyF9:     20,
+		input.KeyF10:    21,
+		input.KeyF11:    23,
+		input.KeyF12:    24,
+	}
+	csiUKeys = map[input.KeySym]rune{
+		input.KeyEscape:    27,
+		input.KeyEnter:     13,
+		input.KeyTab:       9,
+		input.KeyBackspace: 127,
+	}
+	// kittyFuncKeys are keys that only the Kitty keyboard protocol reports.
+	kittyFuncKeys = map[input.KeySym]rune{
+		input.KeyMenu:             57363,
+		input.KeyMediaPlay:        57428,
+		input.KeyMediaPause:       57429,
+		input.KeyMediaPlayPause:   57430,
+		input.KeyMediaReverse:     57431,
+		input.KeyMediaStop:        57432,
+		input.KeyMediaFastForward: 57433,
+		input.KeyMediaRewind:      57434,
+		input.KeyMediaNext:        57435,
+		input.KeyMediaPrev:        57436,
+		input.KeyMediaRecord:      57437,
+		input.KeyLowerVol:         57438,
+		input.KeyRaiseVol:         57439,
+		input.KeyMute:             57440,
+	}
+)
+
+func encodeKey(k input.Key, event int) (string, error) {
+	legacy := event == 1
+	if k.Sym != input.KeyNone && k.Sym != input.KeySpace {
+		if c, ok := csiLetterKeys[k.Sym]; ok {
+			if legacy && k.Mod == 0 {
+				return "\x1b[" + string(c), nil
+			}
+			return "\x1b[1;" + kittyMod(k.Mod, event) + string(c), nil
+		}
+		if n, ok := csiTildeKeys[k.Sym]; ok {
+			s := "\x1b[" + strconv.Itoa(n)
+			if !legacy || k.Mod != 0 {
+				s += ";" + kittyMod(k.Mod, event)
+			}
+			return s + "~", nil
+		}
+		if r, ok := csiUKeys[k.Sym]; ok {
+			if legacy && k.Mod == 0 && k.Sym != input.KeyEscape {
+				// A lone ESC is ambiguous, use CSI u for it.
+				return string(r), nil
+			}
+			return kittyKey(r, 0, k.Mod, event), nil
+		}
+		if r, ok := kittyFuncKeys[k.Sym]; ok {
+			return kittyKey(r, 0, k.Mod, event), nil
+		}
+		return "", fmt.Errorf("inputtest: can't encode key %v", k)
+	}
+
+	r := k.Rune
+	if k.Sym == input.KeySpace {
+		r = ' '
+	}
+	if r == 0 {
+		return "", fmt.Errorf("inputtest: can't encode key %v", k)
+	}
+
+	if legacy {
+		switch k.Mod {
+		case 0:
+			return string(r), nil
+		case input.ModAlt:
+			return "\x1b" + string(r), nil
+		case input.ModCtrl:
+			if r == ' ' {
+				return "\x00", nil
+			}
+			if r >= 'a' && r <= 'z' {
+				return string(r - 'a' + 1), nil
+			}
+		}
+	}
+
+	if k.AltRune != 0 && k.AltRune != r {
+		return kittyKey(k.AltRune, r, k.Mod, event), nil
+	}
+	return kittyKey(r, 0, k.Mod, event), nil
+}
+
+// kittyKey returns a Kitty keyboard protocol key sequence.
+//
+//	CSI code [: shifted] ; mods [: event] u
+func kittyKey(code, shifted rune, mod input.KeyMod, event int) string {
+	s := "\x1b[" + strconv.Itoa(int(code))
+	if shifted != 0 {
+		s += ":" + strconv.Itoa(int(shifted))
+	}
+	if mod != 0 || event > 1 {
+		s += ";" + kittyMod(mod, event)
+	}
+	return s + "u"
+}
+
+func encodeMouse(m input.Mouse, release, motion bool) (string, error) {
+	var b int
+	switch m.Button {
+	case input.MouseNone:
+		if !motion {
+			return "", fmt.Errorf("inputtest: can't encode mouse event without a button")
+		}
+		b = 3
+	case input.MouseLeft, input.MouseMiddle, input.MouseRight:
+		b = int(m.Button - input.MouseLeft)
+	case input.MouseWheelUp, input.MouseWheelDown, input.MouseWheelLeft, input.MouseWheelRight:
+		b = 64 + int(m.Button-input.MouseWheelUp)
+	case input.MouseBackward, input.MouseForward, input.MouseExtra1, input.MouseExtra2:
+		b = 128 + int(m.Button-input.MouseBackward)
+	default:
+		return "", fmt.Errorf("inputtest: can't encode mouse button %d", m.Button)
+	}
+	if m.Mod.HasShift() {
+		b |= 4
+	}
+	if m.Mod.HasAlt() {
+		b |= 8
+	}
+	if m.Mod.HasCtrl() {
+		b |= 16
+	}
+	if motion {
+		b |= 32
+	}
+
+	btn := strconv.Itoa(b)
+	if d := m.Delta; d != 0 && d != input.WheelDelta && d != -input.WheelDelta {
+		if d < 0 {
+			d = -d
+		}
+		btn += ":" + strconv.Itoa(d)
+	}
+
+	cmd := 'M'
+	if release {
+		cmd = 'm'
+	}
+	return fmt.Sprintf("\x1b[<%s;%d;%d%c", btn, m.X+1, m.Y+1, cmd), nil
+}
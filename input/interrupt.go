@@ -0,0 +1,46 @@
+package input
+
+// InterruptMode is how the driver handles ctrl+c, see [WithInterrupt].
+type InterruptMode int
+
+const (
+	// InterruptDefault leaves ctrl+c to the terminal settings. In raw mode,
+	// see [WithRawMode], ctrl+c arrives as a key, otherwise the terminal
+	// interrupts the program.
+	InterruptDefault InterruptMode = iota
+
+	// InterruptKey makes sure ctrl+c arrives as a key press, instead of
+	// interrupting the program. The driver turns off the terminal signal
+	// keys, even without raw mode, and turns them back on when it's closed
+	// or paused.
+	InterruptKey
+
+	// InterruptSignal makes ctrl+c interrupt the program, even in raw mode.
+	// The driver drops the ctrl+c key press, and sends the program an
+	// interrupt signal instead, SIGINT on Unix, and a ctrl+c console event on
+	// Windows. Use [os/signal] to handle it.
+	InterruptSignal
+)
+
+// isInterruptKey reports whether e is the ctrl+c key press.
+func isInterruptKey(e Event) bool {
+	k, ok := e.(KeyPressEvent)
+	return ok && k.Sym == KeyNone && k.Rune == 'c' && k.Mod == ModCtrl
+}
+
+// raiseInterrupts drops the ctrl+c key presses from events, and raises an
+// interrupt for each of them.
+func (d *Driver) raiseInterrupts(events []Event) []Event {
+	n := 0
+	for _, e := range events {
+		if !isInterruptKey(e) {
+			events[n] = e
+			n++
+			continue
+		}
+		if err := raiseInterrupt(); err != nil {
+			d.logf("input: interrupt: %v", err)
+		}
+	}
+	return events[:n]
+}
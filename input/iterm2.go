@@ -0,0 +1,75 @@
+package input
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+)
+
+// ITerm2CellSizeEvent is the size of a cell in points, and the number of
+// pixels per point. It's the response to [ansi.RequestITerm2CellSize].
+type ITerm2CellSizeEvent struct {
+	Width, Height float64
+	Scale         float64
+}
+
+// ITerm2UserVarEvent is an iTerm2 user variable set with
+// [ansi.ITerm2SetUserVar].
+type ITerm2UserVarEvent struct {
+	Name, Value string
+}
+
+// ITerm2CopyToClipboardEvent starts a copy to the clipboard, see
+// [ansi.ITerm2CopyToClipboard]. The text up to the [ITerm2EndCopyEvent] is
+// copied.
+type ITerm2CopyToClipboardEvent struct {
+	// Clipboard is the clipboard name, empty for the general clipboard.
+	Clipboard string
+}
+
+// ITerm2EndCopyEvent ends a copy to the clipboard, see [ansi.ITerm2EndCopy].
+type ITerm2EndCopyEvent struct{}
+
+// parseITerm2 parses the data of an OSC 1337 sequence. It returns nil for
+// messages it doesn't know.
+func parseITerm2(data []byte) Event {
+	key, value := data, []byte(nil)
+	if i := bytes.IndexByte(data, '='); i >= 0 {
+		key, value = data[:i], data[i+1:]
+	}
+
+	switch string(key) {
+	case "ReportCellSize":
+		// The height comes first, and older versions don't report the
+		// scale.
+		fields := bytes.Split(value, []byte{';'})
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil
+		}
+		var sizes [3]float64
+		sizes[2] = 1
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(string(f), 64)
+			if err != nil {
+				return nil
+			}
+			sizes[i] = v
+		}
+		return ITerm2CellSizeEvent{Height: sizes[0], Width: sizes[1], Scale: sizes[2]}
+	case "SetUserVar":
+		i := bytes.IndexByte(value, '=')
+		if i < 0 {
+			return nil
+		}
+		v, err := base64.StdEncoding.DecodeString(string(value[i+1:]))
+		if err != nil {
+			return nil
+		}
+		return ITerm2UserVarEvent{Name: string(value[:i]), Value: string(v)}
+	case "CopyToClipboard":
+		return ITerm2CopyToClipboardEvent{Clipboard: string(value)}
+	case "EndCopy":
+		return ITerm2EndCopyEvent{}
+	}
+	return nil
+}
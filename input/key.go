@@ -1,5 +1,11 @@
 package input
 
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
 // KeySym is a keyboard symbol.
 type KeySym int
 
@@ -175,6 +181,21 @@ const (
 	KeyRightMeta
 	KeyIsoLevel3Shift
 	KeyIsoLevel5Shift
+
+	// Browser and application launch keys found on multimedia keyboards.
+	// These are the XF86Back, XF86Forward, and similar X11 keys.
+
+	KeyBrowserBack
+	KeyBrowserForward
+	KeyBrowserRefresh
+	KeyBrowserStop
+	KeyBrowserSearch
+	KeyBrowserFavorites
+	KeyBrowserHome
+	KeyLaunchMail
+	KeyLaunchMedia
+	KeyLaunchApp1
+	KeyLaunchApp2
 )
 
 // Key represents a key event.
@@ -217,6 +238,14 @@ type Key struct {
 	// This is only available with the Kitty Keyboard Protocol or the Windows
 	// Console API.
 	IsRepeat bool
+
+	// Text is the text generated by the key press, if any. For example,
+	// shift+a produces "A". Composed characters and input methods may
+	// produce more than one rune.
+	//
+	// This is only available with the Kitty Keyboard Protocol when
+	// [ansi.KittyReportAssociatedKeys] is enabled.
+	Text string
 }
 
 // KeyPressEvent represents a key press event.
@@ -250,8 +279,9 @@ func (k KeyReleaseEvent) String() string {
 //   - hyper
 //   - super
 //
-// For example, you'll always see "ctrl+shift+alt+a" and never
-// "shift+ctrl+alt+a".
+// For example, you'll always see "ctrl+alt+shift+a" and never
+// "shift+ctrl+alt+a". Use [ParseKey] to parse this representation back into
+// a key.
 func (k Key) String() string {
 	var s string
 	if k.Mod.HasCtrl() && k.Sym != KeyLeftCtrl && k.Sym != KeyRightCtrl {
@@ -296,6 +326,71 @@ func (k Key) String() string {
 	return s
 }
 
+// keyModNames maps modifier names, as printed by [Key.String], to their
+// modifier keys.
+var keyModNames = map[string]KeyMod{
+	"ctrl":  ModCtrl,
+	"alt":   ModAlt,
+	"shift": ModShift,
+	"meta":  ModMeta,
+	"hyper": ModHyper,
+	"super": ModSuper,
+}
+
+// keySymNames maps key symbol names, as printed by [KeySym.String], to their
+// key symbols.
+var keySymNames = func() map[string]KeySym {
+	m := make(map[string]KeySym, len(keySymString))
+	for k, v := range keySymString {
+		m[v] = k
+	}
+	return m
+}()
+
+// ParseKey parses a key from its string representation as returned by
+// [Key.String], such as "ctrl+x", "ctrl+shift+f5", "alt+é", or "ctrl++".
+// It's useful to read key bindings from configuration files.
+//
+// Modifiers can appear in any order, while the key itself is either a key
+// symbol name, like "enter" or "pgup", or a single character. Characters
+// are case sensitive, so "A" and "shift+a" are different keys.
+func ParseKey(s string) (Key, error) {
+	var k Key
+	if s == "" {
+		return k, fmt.Errorf("parse key: empty string")
+	}
+
+	name := s
+	if len(s) > 1 {
+		// Don't split on a trailing "+" since that's the key itself.
+		if i := strings.LastIndexByte(s[:len(s)-1], '+'); i >= 0 {
+			name = s[i+1:]
+			for _, mod := range strings.Split(s[:i], "+") {
+				m, ok := keyModNames[mod]
+				if !ok {
+					return Key{}, fmt.Errorf("parse key %q: unknown modifier %q", s, mod)
+				}
+				k.Mod |= m
+			}
+		}
+	}
+
+	if sym, ok := keySymNames[name]; ok {
+		k.Sym = sym
+		if sym == KeySpace {
+			k.Rune = ' '
+		}
+		return k, nil
+	}
+
+	r, w := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError || w != len(name) {
+		return Key{}, fmt.Errorf("parse key %q: unknown key %q", s, name)
+	}
+	k.Rune = r
+	return k, nil
+}
+
 // String implements fmt.Stringer and prints the string representation of a of
 // a Symbol key.
 func (k KeySym) String() string {
@@ -456,4 +551,15 @@ var keySymString = map[KeySym]string{
 	KeyRightMeta:        "rightmeta",
 	KeyIsoLevel3Shift:   "isolevel3shift",
 	KeyIsoLevel5Shift:   "isolevel5shift",
+	KeyBrowserBack:      "browserback",
+	KeyBrowserForward:   "browserforward",
+	KeyBrowserRefresh:   "browserrefresh",
+	KeyBrowserStop:      "browserstop",
+	KeyBrowserSearch:    "browsersearch",
+	KeyBrowserFavorites: "browserfavorites",
+	KeyBrowserHome:      "browserhome",
+	KeyLaunchMail:       "launchmail",
+	KeyLaunchMedia:      "launchmedia",
+	KeyLaunchApp1:       "launchapp1",
+	KeyLaunchApp2:       "launchapp2",
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"image/color"
 	"io"
 	"math/rand"
 	"reflect"
@@ -19,7 +20,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 )
 
-var sequences = buildKeysTable(FlagTerminfo, "dumb")
+var sequences = buildKeysTable(FlagTerminfo, "dumb", nil)
 
 func TestKeyString(t *testing.T) {
 	t.Run("alt+space", func(t *testing.T) {
@@ -58,6 +59,55 @@ func TestKeyTypeString(t *testing.T) {
 	})
 }
 
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		in     string
+		expect Key
+	}{
+		{"a", Key{Rune: 'a'}},
+		{"A", Key{Rune: 'A'}},
+		{"ctrl+x", Key{Rune: 'x', Mod: ModCtrl}},
+		{"ctrl+shift+f5", Key{Sym: KeyF5, Mod: ModCtrl | ModShift}},
+		{"shift+ctrl+f5", Key{Sym: KeyF5, Mod: ModCtrl | ModShift}},
+		{"alt+é", Key{Rune: 'é', Mod: ModAlt}},
+		{"ctrl+space", Key{Sym: KeySpace, Rune: ' ', Mod: ModCtrl}},
+		{"+", Key{Rune: '+'}},
+		{"ctrl++", Key{Rune: '+', Mod: ModCtrl}},
+		{"super+hyper+meta+enter", Key{Sym: KeyEnter, Mod: ModSuper | ModHyper | ModMeta}},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			k, err := ParseKey(c.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(k, c.expect) {
+				t.Errorf("expected %#v, got %#v", c.expect, k)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "ctrl+", "foo+a", "ctrl+xy", "++"} {
+		if _, err := ParseKey(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestParseKeyRoundTrip(t *testing.T) {
+	for _, key := range sequences {
+		s := key.String()
+		k, err := ParseKey(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", s, err)
+			continue
+		}
+		if got := k.String(); got != s {
+			t.Errorf("expected %q, got %q", s, got)
+		}
+	}
+}
+
 type seqTest struct {
 	seq  []byte
 	msgs []Event
@@ -114,11 +164,167 @@ func TestParseSequence(t *testing.T) {
 				BlurEvent{},
 			},
 		},
+		// Cursor position reports.
+		seqTest{
+			[]byte("\x1b[10;20R"),
+			[]Event{
+				CursorPositionEvent{Row: 10, Column: 20},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[?1;5;1R"),
+			[]Event{
+				CursorPositionEvent{Row: 1, Column: 5, Page: 1},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[?3;7R"),
+			[]Event{
+				CursorPositionEvent{Row: 3, Column: 7},
+			},
+		},
+		// OSC colors.
+		seqTest{
+			[]byte("\x1b]17;rgb:1111/2222/3333\x1b\\"),
+			[]Event{
+				HighlightBackgroundColorEvent{color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}},
+			},
+		},
+		seqTest{
+			[]byte("\x1b]19;#aabbcc\x07"),
+			[]Event{
+				HighlightForegroundColorEvent{color.RGBA{R: 0xaa, G: 0xbb, B: 0xcc, A: 0xff}},
+			},
+		},
+		seqTest{
+			[]byte("\x1b]11;?\x07"),
+			[]Event{
+				MissingColorEvent(11),
+			},
+		},
+		seqTest{
+			[]byte("\x1b]10;\x07"),
+			[]Event{
+				MissingColorEvent(10),
+			},
+		},
+		seqTest{
+			[]byte("\x1b]12;rgb:12/34\x07"),
+			[]Event{
+				MissingColorEvent(12),
+			},
+		},
+		seqTest{
+			[]byte("\x1b]110\x07"),
+			[]Event{
+				ColorResetEvent(10),
+			},
+		},
+		seqTest{
+			[]byte("\x1b]119\x1b\\"),
+			[]Event{
+				ColorResetEvent(19),
+			},
+		},
+		// Semantic prompt.
+		seqTest{
+			[]byte("\x1b]133;A\x07\x1b]133;B\x1b\\\x1b]133;C\x07"),
+			[]Event{
+				PromptStartEvent{},
+				CommandStartEvent{},
+				CommandExecutedEvent{},
+			},
+		},
+		seqTest{
+			[]byte("\x1b]133;D;2;aid=1\x07\x1b]133;D\x07\x1b]133;A;k=s\x07"),
+			[]Event{
+				CommandEndEvent{ExitCode: 2},
+				CommandEndEvent{ExitCode: -1},
+				PromptStartEvent{},
+			},
+		},
+		// iTerm2.
+		seqTest{
+			[]byte("\x1b]1337;ReportCellSize=17.0;8.5;2.0\x07\x1b]1337;ReportCellSize=16;8\x1b\\"),
+			[]Event{
+				ITerm2CellSizeEvent{Width: 8.5, Height: 17, Scale: 2},
+				ITerm2CellSizeEvent{Width: 8, Height: 16, Scale: 1},
+			},
+		},
+		seqTest{
+			[]byte("\x1b]1337;SetUserVar=foo=YmFy\x07\x1b]1337;CopyToClipboard=\x07a\x1b]1337;EndCopy\x07"),
+			[]Event{
+				ITerm2UserVarEvent{Name: "foo", Value: "bar"},
+				ITerm2CopyToClipboardEvent{},
+				KeyPressEvent{Rune: 'a'},
+				ITerm2EndCopyEvent{},
+			},
+		},
+		seqTest{
+			[]byte("\x1b]1337;File=inline=1:AAAA\x07"),
+			[]Event{
+				UnknownOscEvent("\x1b]1337;File=inline=1:AAAA\x07"),
+			},
+		},
+		// DECRPSS.
+		seqTest{
+			[]byte("\x1bP1$r0;1m\x1b\\\x1bP1$r2 q\x1b\\\x1bP0$r\x1b\\"),
+			[]Event{
+				SettingReportEvent{Valid: true, Setting: "0;1m"},
+				SettingReportEvent{Valid: true, Setting: "2 q"},
+				SettingReportEvent{},
+			},
+		},
+		// Kitty graphics.
+		seqTest{
+			[]byte("\x1b_Gi=31;OK\x1b\\\x1b_Gi=31,I=2,p=7;ENOENT:file not found\x1b\\\x9fGI=3;OK\x9c"),
+			[]Event{
+				GraphicsReplyEvent{ImageID: 31, Message: "OK"},
+				GraphicsReplyEvent{ImageID: 31, ImageNumber: 2, PlacementID: 7, Message: "ENOENT:file not found"},
+				GraphicsReplyEvent{ImageNumber: 3, Message: "OK"},
+			},
+		},
+		seqTest{
+			[]byte("\x1b_Ga=q\x1b\\"),
+			[]Event{
+				UnknownApcEvent("\x1b_Ga=q\x1b\\"),
+			},
+		},
+		// Window operation reports.
+		seqTest{
+			[]byte("\x1b[4;480;640t\x1b[8;24;80t\x1b[6;20;10t"),
+			[]Event{
+				PixelSizeEvent{Width: 640, Height: 480},
+				TextAreaSizeEvent{Width: 80, Height: 24},
+				CellSizeEvent{Width: 10, Height: 20},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[1t\x1b[2t\x1b[3;1t"),
+			[]Event{
+				WindowStateEvent{},
+				WindowStateEvent{Minimized: true},
+				UnknownCsiEvent("\x1b[3;1t"),
+			},
+		},
+		// In-band resize.
+		seqTest{
+			[]byte("\x1b[48;24;80;480;640t"),
+			[]Event{
+				WindowSizeEvent{Width: 80, Height: 24, PixelWidth: 640, PixelHeight: 480},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[48;24;80t"),
+			[]Event{
+				WindowSizeEvent{Width: 80, Height: 24},
+			},
+		},
 		// Mouse event.
 		seqTest{
 			[]byte{'\x1b', '[', 'M', byte(32) + 0b0100_0000, byte(65), byte(49)},
 			[]Event{
-				MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp},
+				MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp, Delta: WheelDelta},
 			},
 		},
 		// SGR Mouse event.
@@ -193,6 +399,31 @@ func TestParseSequence(t *testing.T) {
 				KeyPressEvent{Rune: ' ', Sym: KeySpace, Mod: ModCtrl | ModAlt},
 			},
 		},
+		// XTerm modifyOtherKeys.
+		seqTest{
+			[]byte("\x1b[27;6;65~"),
+			[]Event{
+				KeyPressEvent{Rune: 'A', AltRune: 'a', Mod: ModCtrl | ModShift},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[27;5;44~"),
+			[]Event{
+				KeyPressEvent{Rune: ',', Mod: ModCtrl},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[27;5;32~"),
+			[]Event{
+				KeyPressEvent{Rune: ' ', Sym: KeySpace, Mod: ModCtrl},
+			},
+		},
+		seqTest{
+			[]byte("\x1b[27;3;13~"),
+			[]Event{
+				KeyPressEvent{Sym: KeyEnter, Mod: ModAlt},
+			},
+		},
 		// C1 control characters.
 		seqTest{
 			[]byte{'\x80'},
@@ -338,7 +569,7 @@ func TestReadInput(t *testing.T) {
 			"wheel up",
 			[]byte{'\x1b', '[', 'M', byte(32) + 0b0100_0000, byte(65), byte(49)},
 			[]Event{
-				MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp},
+				MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp, Delta: WheelDelta},
 			},
 		},
 		{
@@ -359,6 +590,21 @@ func TestReadInput(t *testing.T) {
 				KeyPressEvent{Sym: KeyTab, Mod: ModShift},
 			},
 		},
+		{
+			"shift+home a",
+			[]byte("\x1b[7$a"),
+			[]Event{
+				KeyPressEvent{Sym: KeyHome, Mod: ModShift},
+				KeyPressEvent{Rune: 'a'},
+			},
+		},
+		{
+			"alt+shift+delete",
+			[]byte("\x1b\x1b[3$"),
+			[]Event{
+				KeyPressEvent{Sym: KeyDelete, Mod: ModShift | ModAlt},
+			},
+		},
 		{
 			"enter",
 			[]byte{'\r'},
@@ -514,6 +760,61 @@ func TestReadInput(t *testing.T) {
 	}
 }
 
+func TestReadInputTermFamily(t *testing.T) {
+	fkeys := "\x1b[25~\x1b[25^\x1b[34~\x1b\x1b[28~\x1b[23~"
+	cases := []struct {
+		term   string
+		in     string
+		expect []Event
+	}{
+		{"xterm-256color", fkeys, []Event{
+			KeyPressEvent{Sym: KeyF13},
+			KeyPressEvent{Sym: KeyF13, Mod: ModCtrl},
+			KeyPressEvent{Sym: KeyF20},
+			KeyPressEvent{Sym: KeyF15, Mod: ModAlt},
+			KeyPressEvent{Sym: KeyF11},
+		}},
+		{"rxvt-unicode-256color", fkeys, []Event{
+			KeyPressEvent{Sym: KeyF3, Mod: ModShift},
+			KeyPressEvent{Sym: KeyF3, Mod: ModShift | ModCtrl},
+			KeyPressEvent{Sym: KeyF10, Mod: ModShift},
+			KeyPressEvent{Sym: KeyF5, Mod: ModShift | ModAlt},
+			KeyPressEvent{Sym: KeyF11},
+		}},
+		{"linux", fkeys, []Event{
+			KeyPressEvent{Sym: KeyF1, Mod: ModShift},
+			KeyPressEvent{Sym: KeyF13, Mod: ModCtrl},
+			KeyPressEvent{Sym: KeyF8, Mod: ModShift},
+			KeyPressEvent{Sym: KeyF3, Mod: ModShift | ModAlt},
+			KeyPressEvent{Sym: KeyF11},
+		}},
+		{"linux", "\x1b[[A\x1b[[Ea\x1b[1~\x1b[4~", []Event{
+			KeyPressEvent{Sym: KeyF1},
+			KeyPressEvent{Sym: KeyF5},
+			KeyPressEvent{Rune: 'a'},
+			KeyPressEvent{Sym: KeyHome},
+			KeyPressEvent{Sym: KeyEnd},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.term, func(t *testing.T) {
+			drv, err := New(strings.NewReader(c.in), WithTerm(c.term), WithoutTerminfo())
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			defer drv.Close()
+
+			events, err := drv.ReadEvents()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(events, c.expect) {
+				t.Errorf("expected %v, got %v", c.expect, events)
+			}
+		})
+	}
+}
+
 func testReadInputs(t *testing.T, input io.Reader) []Event {
 	// We'll check that the input reader finishes at the end
 	// without error.
@@ -686,3 +987,23 @@ func BenchmarkDetectSequenceMap(b *testing.B) {
 		}
 	}
 }
+
+func TestKeySymValues(t *testing.T) {
+	// The values of the key symbols don't change, new ones are added at
+	// the end.
+	cases := []struct {
+		sym  KeySym
+		want int
+	}{
+		{KeyNone, 0},
+		{KeyMute, 130},
+		{KeyLeftShift, 131},
+		{KeyIsoLevel5Shift, 144},
+		{KeyBrowserBack, 145},
+	}
+	for _, c := range cases {
+		if int(c.sym) != c.want {
+			t.Errorf("%s: expected %d, got %d", c.sym, c.want, int(c.sym))
+		}
+	}
+}
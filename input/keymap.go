@@ -0,0 +1,298 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultChordTimeout is the default maximum time between the keys of a
+// chord.
+const DefaultChordTimeout = time.Second
+
+// KeyMatcher reports whether a key matches a binding. Use it with
+// [Keymap.BindMatcher] for bindings that can't be described with a single
+// key, like "any digit".
+type KeyMatcher func(k Key) bool
+
+// LegacyKeyAliases are keys that legacy terminals can't tell apart. They
+// send the same bytes for both keys of each pair, and the driver reports
+// one or the other depending on its flags. Add them to a keymap using
+// [Keymap.Alias] so that binding either key works in every terminal.
+var LegacyKeyAliases = map[string]string{
+	"tab":        "ctrl+i",
+	"enter":      "ctrl+m",
+	"esc":        "ctrl+[",
+	"backspace":  "ctrl+h",
+	"ctrl+space": "ctrl+@",
+}
+
+// PendingChordEvent represents the keys typed so far of an incomplete chord.
+// Use it to show which prefix is active.
+type PendingChordEvent []Key
+
+// String implements fmt.Stringer.
+func (e PendingChordEvent) String() string {
+	keys := make([]string, len(e))
+	for i, k := range e {
+		keys[i] = k.String()
+	}
+	return strings.Join(keys, " ")
+}
+
+// Keymap maps keys and chords to named actions.
+//
+// Keys are described using the notation of [Key.String] and [ParseKey], like
+// "ctrl+x" or "alt+shift+up", and are normalized before matching. Lock
+// modifiers are ignored, and shifted letters match regardless of how the
+// terminal reports them, so "shift+a", "A", and a Kitty report of shift+a
+// are the same key.
+//
+// Chords are space separated keys, like "ctrl+x ctrl+s" or "g g", that must
+// be typed within [Keymap.ChordTimeout] of each other.
+//
+// Escape prefixed keys are reported by the driver with the alt modifier, so
+// "alt+x" matches ESC x. When the escape key arrives on its own, and it isn't
+// bound to anything, the keymap treats it as an alt prefix for the next key.
+//
+// The zero value is not usable, use [NewKeymap] to create a keymap.
+type Keymap struct {
+	// ChordTimeout is the maximum time between the keys of a chord. Zero
+	// means [DefaultChordTimeout] and a negative value disables it.
+	ChordTimeout time.Duration
+
+	chords   map[string]string
+	prefixes map[string]int
+	matchers []keyMatcherBinding
+	aliases  map[Key][]Key
+	alts     int // number of bindings using the alt modifier
+
+	pending   []Key
+	pendingAt time.Time
+	escPrefix bool // the pending key is a lone escape used as alt
+}
+
+type keyMatcherBinding struct {
+	action  string
+	matcher KeyMatcher
+}
+
+// NewKeymap returns a new empty keymap.
+func NewKeymap() *Keymap {
+	return &Keymap{
+		chords:   make(map[string]string),
+		prefixes: make(map[string]int),
+		aliases:  make(map[Key][]Key),
+	}
+}
+
+// Bind binds the given keys or chords to an action. A key bound more than
+// once triggers the last action bound to it.
+func (m *Keymap) Bind(action string, keys ...string) error {
+	for _, s := range keys {
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			return fmt.Errorf("bind %q: empty key", action)
+		}
+		chord := make([]Key, len(fields))
+		for i, f := range fields {
+			k, err := ParseKey(f)
+			if err != nil {
+				return fmt.Errorf("bind %q: %w", action, err)
+			}
+			chord[i] = normalizeKey(k)
+		}
+
+		id := chordString(chord)
+		if _, ok := m.chords[id]; !ok {
+			for i := 1; i < len(chord); i++ {
+				m.prefixes[chordString(chord[:i])]++
+			}
+			if chord[len(chord)-1].Mod.HasAlt() {
+				m.alts++
+			}
+		}
+		m.chords[id] = action
+	}
+	return nil
+}
+
+// BindMatcher binds the keys matched by the given matcher to an action.
+// Matchers are tried in order, after the bound keys, and receive the
+// normalized key. They don't take part in chords.
+func (m *Keymap) BindMatcher(action string, matcher KeyMatcher) {
+	m.matchers = append(m.matchers, keyMatcherBinding{action, matcher})
+}
+
+// Alias makes two keys equivalent. A key event matches a binding of either
+// key. See [LegacyKeyAliases].
+func (m *Keymap) Alias(key, alias string) error {
+	k, err := ParseKey(key)
+	if err != nil {
+		return fmt.Errorf("alias: %w", err)
+	}
+	a, err := ParseKey(alias)
+	if err != nil {
+		return fmt.Errorf("alias: %w", err)
+	}
+	k, a = normalizeKey(k), normalizeKey(a)
+	m.aliases[k] = append(m.aliases[k], a)
+	m.aliases[a] = append(m.aliases[a], k)
+	return nil
+}
+
+// Match returns the action bound to the key of the given event. Only key
+// press events match, other events always return false.
+//
+// A key that starts or continues a chord returns false, and the keys typed
+// so far are available using [Keymap.Pending]. A key that doesn't continue
+// the pending chord cancels it and is matched on its own.
+func (m *Keymap) Match(e Event) (action string, ok bool) {
+	k, ok := e.(KeyPressEvent)
+	if !ok {
+		return "", false
+	}
+
+	if len(m.pending) > 0 && m.expired() {
+		m.reset()
+	}
+	if len(m.pending) > 0 {
+		if action, ok, done := m.step(Key(k)); done {
+			return action, ok
+		}
+		m.reset()
+	}
+	if action, ok, done := m.step(Key(k)); done {
+		return action, ok
+	}
+
+	keys := candidateKeys(Key(k))
+	for _, b := range m.matchers {
+		for _, k := range keys {
+			if b.matcher(k) {
+				return b.action, true
+			}
+		}
+	}
+
+	if len(keys) == 1 && keys[0] == (Key{Sym: KeyEscape}) && m.alts > 0 {
+		m.pending = append(m.pending, keys[0])
+		m.pendingAt = time.Now()
+		m.escPrefix = true
+	}
+
+	return "", false
+}
+
+// Pending returns the keys typed so far of an incomplete chord, or nil if
+// there is none or it has timed out.
+func (m *Keymap) Pending() PendingChordEvent {
+	if len(m.pending) == 0 || m.expired() {
+		return nil
+	}
+	return append(PendingChordEvent(nil), m.pending...)
+}
+
+// Flush cancels the pending chord and returns the action bound to the keys
+// typed so far, if any. Call it when the chord times out to trigger a
+// binding that is also the prefix of a chord, like "g" when "g g" is bound.
+func (m *Keymap) Flush() (action string, ok bool) {
+	if len(m.pending) > 0 && !m.escPrefix {
+		action, ok = m.chords[chordString(m.pending)]
+	}
+	m.reset()
+	return action, ok
+}
+
+// step matches the key as the next key of the pending chord. It reports
+// whether the key was consumed, either completing or extending a chord.
+func (m *Keymap) step(k Key) (action string, ok bool, done bool) {
+	keys := candidateKeys(k)
+	if m.escPrefix {
+		for i := range keys {
+			keys[i].Mod |= ModAlt
+		}
+		m.reset()
+	}
+	for _, k := range keys {
+		keys = append(keys, m.aliases[k]...)
+	}
+
+	for _, k := range keys {
+		chord := append(m.pending[:len(m.pending):len(m.pending)], k)
+		id := chordString(chord)
+		if m.prefixes[id] > 0 {
+			m.pending = chord
+			m.pendingAt = time.Now()
+			return "", false, true
+		}
+		if action, ok := m.chords[id]; ok {
+			m.reset()
+			return action, true, true
+		}
+	}
+	return "", false, false
+}
+
+func (m *Keymap) expired() bool {
+	timeout := m.ChordTimeout
+	if timeout == 0 {
+		timeout = DefaultChordTimeout
+	}
+	return timeout > 0 && time.Since(m.pendingAt) > timeout
+}
+
+func (m *Keymap) reset() {
+	m.pending = nil
+	m.escPrefix = false
+}
+
+// chordString returns the string used to look up a chord.
+func chordString(keys []Key) string {
+	return PendingChordEvent(keys).String()
+}
+
+// candidateKeys returns the normalized forms of a key. Keys reported with a
+// base layout key, like Kitty on non-US layouts, can match both the
+// produced character and the PC-101 key.
+func candidateKeys(k Key) []Key {
+	keys := []Key{normalizeKey(k)}
+	if k.baseRune != 0 && k.Sym == KeyNone {
+		b := k
+		b.Rune, b.AltRune, b.baseRune = k.baseRune, 0, 0
+		if nb := normalizeKey(b); nb != keys[0] {
+			keys = append(keys, nb)
+		}
+	}
+	return keys
+}
+
+// normalizeKey returns the canonical form of a key used for matching.
+func normalizeKey(k Key) Key {
+	n := Key{
+		Sym: k.Sym,
+		Mod: k.Mod &^ (ModCapsLock | ModNumLock | ModScrollLock),
+	}
+	if n.Sym == KeyNone && k.Rune == ' ' {
+		n.Sym = KeySpace
+	}
+	if n.Sym != KeyNone {
+		return n
+	}
+
+	r := k.Rune
+	if k.AltRune != 0 && k.Mod.HasShift() && !unicode.IsLetter(r) {
+		// A shifted symbol, like shift+/ reported as "?", is the symbol
+		// itself.
+		n.Mod &^= ModShift
+	} else if k.AltRune != 0 && r == 0 {
+		r = k.AltRune
+	}
+	if unicode.IsUpper(r) {
+		r = unicode.ToLower(r)
+		n.Mod |= ModShift
+	}
+	n.Rune = r
+	return n
+}
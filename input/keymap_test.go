@@ -0,0 +1,151 @@
+package input
+
+import (
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestKeymapMatch(t *testing.T) {
+	m := NewKeymap()
+	if err := m.Bind("save", "ctrl+s"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("upper", "shift+a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("help", "?"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("back", "alt+left", "alt+b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("complete", "tab"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("leader", "space"); err != nil {
+		t.Fatal(err)
+	}
+	m.BindMatcher("digit", func(k Key) bool {
+		return k.Mod == 0 && unicode.IsDigit(k.Rune)
+	})
+	for k, a := range LegacyKeyAliases {
+		if err := m.Alias(k, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		event  Event
+		action string
+	}{
+		{"ctrl+s", KeyPressEvent{Rune: 's', Mod: ModCtrl}, "save"},
+		{"ctrl+s with caps lock", KeyPressEvent{Rune: 's', Mod: ModCtrl | ModCapsLock}, "save"},
+		{"legacy shift+a", KeyPressEvent{Rune: 'A'}, "upper"},
+		{"kitty shift+a", KeyPressEvent{Rune: 'A', AltRune: 'a', Mod: ModShift}, "upper"},
+		{"legacy ?", KeyPressEvent{Rune: '?'}, "help"},
+		{"kitty shift+/", KeyPressEvent{Rune: '?', AltRune: '/', Mod: ModShift}, "help"},
+		{"alt+left", KeyPressEvent{Sym: KeyLeft, Mod: ModAlt}, "back"},
+		{"esc prefixed b", KeyPressEvent{Rune: 'b', Mod: ModAlt}, "back"},
+		{"tab", KeyPressEvent{Sym: KeyTab}, "complete"},
+		{"ctrl+i alias", KeyPressEvent{Rune: 'i', Mod: ModCtrl}, "complete"},
+		{"space", KeyPressEvent{Sym: KeySpace, Rune: ' '}, "leader"},
+		{"digit", KeyPressEvent{Rune: '7'}, "digit"},
+		{"unbound", KeyPressEvent{Rune: 'z'}, ""},
+		{"release", KeyReleaseEvent{Rune: 's', Mod: ModCtrl}, ""},
+		{"not a key", FocusEvent{}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			action, ok := m.Match(c.event)
+			if ok != (c.action != "") || action != c.action {
+				t.Errorf("expected action %q, got %q (%v)", c.action, action, ok)
+			}
+		})
+	}
+}
+
+func TestKeymapBindError(t *testing.T) {
+	m := NewKeymap()
+	if err := m.Bind("bad", "ctrl+nope"); err == nil {
+		t.Error("expected an error")
+	}
+	if err := m.Alias("tab", "nope+i"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestKeymapChords(t *testing.T) {
+	m := NewKeymap()
+	if err := m.Bind("save", "ctrl+x ctrl+s"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("top", "g g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("goto", "g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("word", "alt+f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Bind("quit", "q"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrlX := KeyPressEvent{Rune: 'x', Mod: ModCtrl}
+	if _, ok := m.Match(ctrlX); ok {
+		t.Fatal("expected ctrl+x to be pending")
+	}
+	if p := m.Pending(); p.String() != "ctrl+x" {
+		t.Errorf("expected pending ctrl+x, got %q", p)
+	}
+	if action, ok := m.Match(KeyPressEvent{Rune: 's', Mod: ModCtrl}); !ok || action != "save" {
+		t.Errorf("expected save, got %q", action)
+	}
+	if p := m.Pending(); p != nil {
+		t.Errorf("expected no pending chord, got %q", p)
+	}
+
+	// A key that doesn't continue the chord is matched on its own.
+	m.Match(ctrlX)
+	if action, ok := m.Match(KeyPressEvent{Rune: 'q'}); !ok || action != "quit" {
+		t.Errorf("expected quit, got %q", action)
+	}
+
+	// A binding that is also a prefix waits for the chord.
+	m.Match(KeyPressEvent{Rune: 'g'})
+	if action, ok := m.Match(KeyPressEvent{Rune: 'g'}); !ok || action != "top" {
+		t.Errorf("expected top, got %q", action)
+	}
+	m.Match(KeyPressEvent{Rune: 'g'})
+	if action, ok := m.Flush(); !ok || action != "goto" {
+		t.Errorf("expected goto, got %q", action)
+	}
+
+	// A lone escape is an alt prefix.
+	if _, ok := m.Match(KeyPressEvent{Sym: KeyEscape}); ok {
+		t.Fatal("expected esc to be pending")
+	}
+	if action, ok := m.Match(KeyPressEvent{Rune: 'f'}); !ok || action != "word" {
+		t.Errorf("expected word, got %q", action)
+	}
+}
+
+func TestKeymapChordTimeout(t *testing.T) {
+	m := NewKeymap()
+	m.ChordTimeout = 10 * time.Millisecond
+	if err := m.Bind("save", "ctrl+x ctrl+s"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Match(KeyPressEvent{Rune: 'x', Mod: ModCtrl})
+	time.Sleep(20 * time.Millisecond)
+	if p := m.Pending(); p != nil {
+		t.Errorf("expected the chord to time out, got %q", p)
+	}
+	if action, ok := m.Match(KeyPressEvent{Rune: 's', Mod: ModCtrl}); ok {
+		t.Errorf("expected no action, got %q", action)
+	}
+}
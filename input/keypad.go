@@ -0,0 +1,61 @@
+package input
+
+// keypadRunes maps the keypad keys to the characters printed on them.
+var keypadRunes = map[KeySym]rune{
+	KeyKpEqual:    '=',
+	KeyKpMultiply: '*',
+	KeyKpPlus:     '+',
+	KeyKpComma:    ',',
+	KeyKpSep:      ',',
+	KeyKpMinus:    '-',
+	KeyKpDecimal:  '.',
+	KeyKpDivide:   '/',
+	KeyKp0:        '0',
+	KeyKp1:        '1',
+	KeyKp2:        '2',
+	KeyKp3:        '3',
+	KeyKp4:        '4',
+	KeyKp5:        '5',
+	KeyKp6:        '6',
+	KeyKp7:        '7',
+	KeyKp8:        '8',
+	KeyKp9:        '9',
+}
+
+// keypadSyms maps the keypad keys to their main keyboard equivalents.
+var keypadSyms = map[KeySym]KeySym{
+	KeyKpEnter:  KeyEnter,
+	KeyKpUp:     KeyUp,
+	KeyKpDown:   KeyDown,
+	KeyKpLeft:   KeyLeft,
+	KeyKpRight:  KeyRight,
+	KeyKpPgUp:   KeyPgUp,
+	KeyKpPgDown: KeyPgDown,
+	KeyKpHome:   KeyHome,
+	KeyKpEnd:    KeyEnd,
+	KeyKpInsert: KeyInsert,
+	KeyKpDelete: KeyDelete,
+	KeyKpBegin:  KeyBegin,
+}
+
+// foldKeypad returns the main keyboard equivalent of a keypad key. Other
+// keys are returned as is. See [FlagFoldKeypad].
+func foldKeypad(k Key) Key {
+	if r, ok := keypadRunes[k.Sym]; ok {
+		k.Sym, k.Rune = KeyNone, r
+	} else if sym, ok := keypadSyms[k.Sym]; ok {
+		k.Sym = sym
+	}
+	return k
+}
+
+// foldKeypadEvent folds the keypad keys of key events.
+func foldKeypadEvent(e Event) Event {
+	switch e := e.(type) {
+	case KeyPressEvent:
+		return KeyPressEvent(foldKeypad(Key(e)))
+	case KeyReleaseEvent:
+		return KeyReleaseEvent(foldKeypad(Key(e)))
+	}
+	return e
+}
@@ -0,0 +1,91 @@
+package input
+
+import "sort"
+
+// KeySource is where the definition of a key sequence comes from.
+type KeySource int
+
+// Key sequence sources.
+const (
+	// KeySourceDefault is the built-in VT100/VT200 and URxvt sequences.
+	KeySourceDefault KeySource = iota
+	// KeySourceXTerm is the XTerm modified key sequences, like CSI 1;5 A.
+	KeySourceXTerm
+	// KeySourceTerminfo is the Terminfo database of the terminal.
+	KeySourceTerminfo
+	// KeySourceUser is the sequences registered using
+	// [EventParser.RegisterSequence].
+	KeySourceUser
+)
+
+var keySourceNames = map[KeySource]string{
+	KeySourceDefault:  "default",
+	KeySourceXTerm:    "xterm",
+	KeySourceTerminfo: "terminfo",
+	KeySourceUser:     "user",
+}
+
+// String implements fmt.Stringer.
+func (s KeySource) String() string {
+	return keySourceNames[s]
+}
+
+// KeySequence is a sequence known to the driver along with the event it
+// produces and where its definition comes from.
+type KeySequence struct {
+	// Seq is the raw sequence.
+	Seq string
+
+	// Event is the event reported for the sequence, a [KeyPressEvent] for
+	// built-in sequences.
+	Event Event
+
+	// Source is where the definition of the sequence comes from.
+	Source KeySource
+}
+
+// Sequences returns the sequences registered using
+// [EventParser.RegisterSequence], sorted by sequence.
+func (p *EventParser) Sequences() []KeySequence {
+	seqs := make([]KeySequence, 0, len(p.seqs))
+	for seq, e := range p.seqs {
+		seqs = append(seqs, KeySequence{Seq: seq, Event: e, Source: KeySourceUser})
+	}
+	sortKeySequences(seqs)
+	return seqs
+}
+
+// Sequences returns the key sequences the driver knows about, sorted by
+// sequence. This includes the built-in sequences, the Terminfo sequences
+// when [FlagTerminfo] is set, and the sequences registered on the parser.
+// Use it to show which sequence maps to which key on a terminal.
+func (d *Driver) Sequences() []KeySequence {
+	seqs := d.parser.Sequences()
+	d.keys.walk(func(seq string, k Key) {
+		if _, ok := d.parser.seqs[seq]; ok {
+			// Registered sequences take precedence.
+			return
+		}
+		seqs = append(seqs, KeySequence{Seq: seq, Event: KeyPressEvent(k), Source: d.sources[seq]})
+	})
+	sortKeySequences(seqs)
+	return seqs
+}
+
+// LookupSequence returns the definition of the given key sequence, if the
+// driver knows about it.
+func (d *Driver) LookupSequence(seq string) (KeySequence, bool) {
+	if e, ok := d.parser.seqs[seq]; ok {
+		return KeySequence{Seq: seq, Event: e, Source: KeySourceUser}, true
+	}
+	if k, ok := d.keys.lookup(seq); ok {
+		return KeySequence{Seq: seq, Event: KeyPressEvent(k), Source: d.sources[seq]}, true
+	}
+	return KeySequence{}, false
+}
+
+func sortKeySequences(seqs []KeySequence) {
+	sort.Slice(seqs, func(i, j int) bool {
+		return seqs[i].Seq < seqs[j].Seq
+	})
+}
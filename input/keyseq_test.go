@@ -0,0 +1,141 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDriverSequences(t *testing.T) {
+	p := &EventParser{}
+	p.RegisterSequence("\x1b[99~", KeyPressEvent{Sym: KeyF1, Mod: ModCtrl})
+	p.RegisterSequence("\x1b[A", KeyPressEvent{Sym: KeyDown})
+
+	drv, err := New(strings.NewReader(""), WithTerm("xterm"),
+		WithTerminfoSource(EmbeddedTerminfo()), WithParser(p))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	cases := []struct {
+		seq    string
+		event  Event
+		source KeySource
+	}{
+		{"\x1b[a", KeyPressEvent{Sym: KeyUp, Mod: ModShift}, KeySourceDefault},
+		{"\x1b[1;9C", KeyPressEvent{Sym: KeyRight, Mod: ModMeta}, KeySourceXTerm},
+		{"\x1bOA", KeyPressEvent{Sym: KeyUp}, KeySourceTerminfo},
+		{"\x1b[99~", KeyPressEvent{Sym: KeyF1, Mod: ModCtrl}, KeySourceUser},
+		{"\x1b[A", KeyPressEvent{Sym: KeyDown}, KeySourceUser},
+	}
+	for _, c := range cases {
+		ks, ok := drv.LookupSequence(c.seq)
+		if !ok {
+			t.Errorf("expected %q to be known", c.seq)
+			continue
+		}
+		if ks.Event != c.event || ks.Source != c.source {
+			t.Errorf("%q: expected %v from %v, got %v from %v", c.seq, c.event, c.source, ks.Event, ks.Source)
+		}
+	}
+	if _, ok := drv.LookupSequence("\x1b[999~"); ok {
+		t.Errorf("expected unknown sequence not to be found")
+	}
+
+	seqs := drv.Sequences()
+	if !sort.SliceIsSorted(seqs, func(i, j int) bool { return seqs[i].Seq < seqs[j].Seq }) {
+		t.Errorf("expected sorted sequences")
+	}
+	found := map[string]KeySequence{}
+	for _, ks := range seqs {
+		if _, ok := found[ks.Seq]; ok {
+			t.Errorf("duplicate sequence %q", ks.Seq)
+		}
+		found[ks.Seq] = ks
+	}
+	for _, c := range cases {
+		if found[c.seq].Source != c.source {
+			t.Errorf("%q: expected source %v, got %v", c.seq, c.source, found[c.seq].Source)
+		}
+	}
+}
+
+// customEvent is an event reported by a registered sequence or handler.
+type customEvent string
+
+func TestDriverRegisteredSequencePrecedence(t *testing.T) {
+	p := &EventParser{}
+	p.RegisterSequence("\x1b[A", customEvent("up"))
+	p.RegisterCsiHandler('B', func(csi *ansi.CsiSequence) Event {
+		return customEvent("down")
+	})
+
+	drv, err := New(strings.NewReader("\x1b[A\x1b[B\x1b[C\x1bOA"), WithTerm("xterm"),
+		WithTerminfoSource(EmbeddedTerminfo()), WithParser(p), WithEscTimeout(0))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{
+		customEvent("up"),
+		customEvent("down"),
+		KeyPressEvent{Sym: KeyRight},
+		KeyPressEvent{Sym: KeyUp},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+}
+
+func TestDriverSharedParser(t *testing.T) {
+	// Drivers can share a parser, parsing doesn't change it.
+	p := &EventParser{}
+	p.RegisterSequence("\x1b[A", customEvent("up"))
+
+	errc := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			drv, err := New(strings.NewReader(strings.Repeat("\x1b[A\x1b[C", 20)),
+				WithTerm("xterm"), WithParser(p), WithEscTimeout(0))
+			if err != nil {
+				errc <- err
+				return
+			}
+			defer drv.Close()
+			var n int
+			for {
+				events, err := drv.ReadEvents()
+				n += len(events)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					errc <- err
+					return
+				}
+			}
+			if n != 40 {
+				errc <- fmt.Errorf("expected 40 events, got %d", n)
+				return
+			}
+			errc <- nil
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package input
+
+import "bytes"
+
+// keyTrie is a byte trie of key sequences. It finds the key sequence at the
+// start of a buffer in place, without building strings or allocating.
+type keyTrie struct {
+	key Key
+	ok  bool // ok reports whether a sequence ends at this node.
+
+	// labels are the bytes leading to the children, in the same order.
+	labels   []byte
+	children []*keyTrie
+}
+
+// newKeyTrie returns a trie of the sequences in table.
+func newKeyTrie(table map[string]Key) *keyTrie {
+	t := new(keyTrie)
+	for seq, k := range table {
+		t.insert(seq, k)
+	}
+	return t
+}
+
+// insert adds a key sequence to the trie.
+func (t *keyTrie) insert(seq string, k Key) {
+	n := t
+	for i := 0; i < len(seq); i++ {
+		c := n.child(seq[i])
+		if c == nil {
+			c = new(keyTrie)
+			n.labels = append(n.labels, seq[i])
+			n.children = append(n.children, c)
+		}
+		n = c
+	}
+	n.key, n.ok = k, true
+}
+
+// child returns the child node for the given byte, or nil.
+func (t *keyTrie) child(b byte) *keyTrie {
+	if i := bytes.IndexByte(t.labels, b); i >= 0 {
+		return t.children[i]
+	}
+	return nil
+}
+
+// match returns the longest key sequence b starts with, and its length.
+func (t *keyTrie) match(b []byte) (n int, k Key, ok bool) {
+	node := t
+	for i := 0; i < len(b); i++ {
+		node = node.child(b[i])
+		if node == nil {
+			break
+		}
+		if node.ok {
+			n, k, ok = i+1, node.key, true
+		}
+	}
+	return n, k, ok
+}
+
+// lookup returns the key of the given sequence.
+func (t *keyTrie) lookup(seq string) (Key, bool) {
+	node := t
+	for i := 0; i < len(seq) && node != nil; i++ {
+		node = node.child(seq[i])
+	}
+	if node == nil || !node.ok {
+		return Key{}, false
+	}
+	return node.key, true
+}
+
+// walk calls fn for each sequence in the trie.
+func (t *keyTrie) walk(fn func(seq string, k Key)) {
+	t.walkPrefix(nil, fn)
+}
+
+func (t *keyTrie) walkPrefix(prefix []byte, fn func(seq string, k Key)) {
+	if t.ok {
+		fn(string(prefix), t.key)
+	}
+	for i, c := range t.children {
+		c.walkPrefix(append(prefix, t.labels[i]), fn)
+	}
+}
@@ -0,0 +1,108 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyTrieMatch(t *testing.T) {
+	trie := newKeyTrie(map[string]Key{
+		"\x1b":      {Sym: KeyEscape},
+		"\x1b[A":    {Sym: KeyUp},
+		"\x1b[1;5":  {Sym: KeyF1}, // a prefix of the next one
+		"\x1b[1;5A": {Sym: KeyUp, Mod: ModCtrl},
+	})
+
+	cases := []struct {
+		in  string
+		n   int
+		key Key
+		ok  bool
+	}{
+		{"\x1b[A\x1b[B", 3, Key{Sym: KeyUp}, true},
+		{"\x1b[1;5Ax", 6, Key{Sym: KeyUp, Mod: ModCtrl}, true},
+		{"\x1b[1;5B", 5, Key{Sym: KeyF1}, true},
+		{"\x1b[B", 1, Key{Sym: KeyEscape}, true},
+		{"a", 0, Key{}, false},
+		{"", 0, Key{}, false},
+	}
+	for _, c := range cases {
+		n, k, ok := trie.match([]byte(c.in))
+		if n != c.n || k != c.key || ok != c.ok {
+			t.Errorf("%q: expected %d %v %v, got %d %v %v", c.in, c.n, c.key, c.ok, n, k, ok)
+		}
+	}
+
+	if k, ok := trie.lookup("\x1b[1;5A"); !ok || k != (Key{Sym: KeyUp, Mod: ModCtrl}) {
+		t.Errorf("expected lookup to find ctrl+up, got %v %v", k, ok)
+	}
+	if _, ok := trie.lookup("\x1b[1;"); ok {
+		t.Errorf("expected lookup of a prefix to fail")
+	}
+
+	var n int
+	trie.walk(func(string, Key) { n++ })
+	if n != 4 {
+		t.Errorf("expected to walk 4 sequences, got %d", n)
+	}
+}
+
+func TestKeyTrieMatchAllocs(t *testing.T) {
+	trie := newKeyTrie(buildKeysTable(0, "", nil))
+	b := []byte("\x1b[1;5A\x1b[1;5B")
+	allocs := testing.AllocsPerRun(100, func() {
+		trie.match(b)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestDriverKeySequencesInOneRead(t *testing.T) {
+	// The Linux console F1 and F2 keys are Terminfo only sequences that the
+	// parser stops short of.
+	drv, err := New(strings.NewReader("\x1b[[A\x1b[[B"), WithTerm("linux"),
+		WithTerminfoSource(EmbeddedTerminfo()))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{KeyPressEvent{Sym: KeyF1}, KeyPressEvent{Sym: KeyF2}}
+	if len(events) != len(expect) {
+		t.Fatalf("expected %v, got %v", expect, events)
+	}
+	for i := range expect {
+		if events[i] != expect[i] {
+			t.Errorf("expected %v, got %v", expect[i], events[i])
+		}
+	}
+}
+
+func BenchmarkKeyLookup(b *testing.B) {
+	table := buildKeysTable(0, "", nil)
+	trie := newKeyTrie(table)
+	buf := []byte("\x1b[1;5A\x1b[1;5B")
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// The map needs the exact sequence, try each prefix.
+			for n := len(buf); n > 0; n-- {
+				if _, ok := table[string(buf[:n])]; ok {
+					break
+				}
+			}
+		}
+	})
+	b.Run("trie", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			trie.match(buf)
+		}
+	})
+}
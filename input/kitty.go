@@ -8,8 +8,44 @@ import (
 )
 
 // KittyKeyboardEvent represents Kitty keyboard progressive enhancement flags.
+// This is reported by the terminal in response to
+// [ansi.RequestKittyKeyboard] and can be used to negotiate which
+// enhancements are available.
 type KittyKeyboardEvent int
 
+// Contains reports whether all the given flags are set. The flags are a
+// bitmask of the ansi.Kitty* progressive enhancement flags.
+func (e KittyKeyboardEvent) Contains(flags int) bool {
+	return int(e)&flags == flags
+}
+
+// String implements fmt.Stringer.
+func (e KittyKeyboardEvent) String() string {
+	names := []struct {
+		flag int
+		name string
+	}{
+		{ansi.KittyDisambiguateEscapeCodes, "disambiguate"},
+		{ansi.KittyReportEventTypes, "events"},
+		{ansi.KittyReportAlternateKeys, "alternates"},
+		{ansi.KittyReportAllKeysAsEscapeCodes, "allkeys"},
+		{ansi.KittyReportAssociatedKeys, "text"},
+	}
+	var s string
+	for _, n := range names {
+		if e.Contains(n.flag) {
+			if s != "" {
+				s += "|"
+			}
+			s += n.name
+		}
+	}
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
 // IsDisambiguateEscapeCodes returns true if the DisambiguateEscapeCodes flag is set.
 func (e KittyKeyboardEvent) IsDisambiguateEscapeCodes() bool {
 	return e&ansi.KittyDisambiguateEscapeCodes != 0
@@ -196,6 +232,35 @@ func fromKittyMod(mod int) KeyMod {
 	return m
 }
 
+// Kitty key event types as reported in the modifiers sub-parameter.
+const (
+	kittyEventPress   = 1
+	kittyEventRepeat  = 2
+	kittyEventRelease = 3
+)
+
+// kittyEventType returns the event type of a key event sequence. The event
+// type is the second sub-parameter of the modifiers parameter at index i. It
+// defaults to a press event when not present.
+func kittyEventType(csi *ansi.CsiSequence, i int) int {
+	if params := csi.Subparams(i); len(params) > 1 && params[1] > 0 {
+		return params[1]
+	}
+	return kittyEventPress
+}
+
+// kittyKeyEvent returns a key press, repeat, or release event based on the
+// given Kitty event type.
+func kittyKeyEvent(key Key, eventType int) Event {
+	switch eventType {
+	case kittyEventRelease:
+		return KeyReleaseEvent(key)
+	case kittyEventRepeat:
+		key.IsRepeat = true
+	}
+	return KeyPressEvent(key)
+}
+
 // parseKittyKeyboard parses a Kitty Keyboard Protocol sequence.
 //
 // In `CSI u`, this is parsed as:
@@ -210,7 +275,6 @@ func fromKittyMod(mod int) KeyMod {
 //
 // See https://sw.kovidgoyal.net/kitty/keyboard-protocol/
 func parseKittyKeyboard(csi *ansi.CsiSequence) Event {
-	var isRelease bool
 	key := Key{}
 
 	if params := csi.Subparams(0); len(params) > 0 {
@@ -258,24 +322,18 @@ func parseKittyKeyboard(csi *ansi.CsiSequence) Event {
 		if mod > 1 {
 			key.Mod = fromKittyMod(mod - 1)
 		}
-		if len(params) > 1 {
-			switch params[1] {
-			case 2:
-				key.IsRepeat = true
-			case 3:
-				isRelease = true
+	}
+	if params := csi.Subparams(2); len(params) > 0 {
+		// Associated text is a colon separated list of codepoints.
+		var buf [16]byte
+		text := buf[:0]
+		for _, p := range params {
+			if r := rune(p); r > 0 && utf8.ValidRune(r) && unicode.IsPrint(r) {
+				text = utf8.AppendRune(text, r)
 			}
 		}
+		key.Text = string(text)
 	}
-	// TODO: Associated keys are not support yet.
-	// if params := csi.Subparams(2); len(params) > 0 {
-	// 	r := rune(params[0])
-	// 	if unicode.IsPrint(r) {
-	// 		key.AltRune = r
-	// 	}
-	// }
-	if isRelease {
-		return KeyReleaseEvent(key)
-	}
-	return KeyPressEvent(key)
+
+	return kittyKeyEvent(key, kittyEventType(csi, 1))
 }
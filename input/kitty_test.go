@@ -0,0 +1,88 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseKittyKeyboardFlags(t *testing.T) {
+	cases := []struct {
+		seq    string
+		expect KittyKeyboardEvent
+		str    string
+	}{
+		{"\x1b[?u", 0, "none"},
+		{"\x1b[?0u", 0, "none"},
+		{"\x1b[?1u", KittyKeyboardEvent(ansi.KittyDisambiguateEscapeCodes), "disambiguate"},
+		{"\x1b[?31u", KittyKeyboardEvent(ansi.KittyAllFlags), "disambiguate|events|alternates|allkeys|text"},
+	}
+	for _, c := range cases {
+		t.Run(c.seq, func(t *testing.T) {
+			n, e := ParseSequence([]byte(c.seq))
+			if n != len(c.seq) {
+				t.Errorf("expected %d bytes, got %d", len(c.seq), n)
+			}
+			ev, ok := e.(KittyKeyboardEvent)
+			if !ok {
+				t.Fatalf("expected KittyKeyboardEvent, got %T", e)
+			}
+			if ev != c.expect {
+				t.Errorf("expected %d, got %d", c.expect, ev)
+			}
+			if ev.String() != c.str {
+				t.Errorf("expected %q, got %q", c.str, ev.String())
+			}
+		})
+	}
+}
+
+func TestKittyKeyboardEventContains(t *testing.T) {
+	e := KittyKeyboardEvent(ansi.KittyDisambiguateEscapeCodes | ansi.KittyReportEventTypes)
+	if !e.Contains(ansi.KittyDisambiguateEscapeCodes | ansi.KittyReportEventTypes) {
+		t.Error("expected flags to be set")
+	}
+	if e.Contains(ansi.KittyReportAlternateKeys) {
+		t.Error("expected alternate keys flag to be unset")
+	}
+}
+
+func TestParseKittyKeyEvents(t *testing.T) {
+	cases := []struct {
+		seq    string
+		expect Event
+	}{
+		{"\x1b[97u", KeyPressEvent{Rune: 'a'}},
+		{"\x1b[97;1:1u", KeyPressEvent{Rune: 'a'}},
+		{"\x1b[97;1:2u", KeyPressEvent{Rune: 'a', IsRepeat: true}},
+		{"\x1b[97;1:3u", KeyReleaseEvent{Rune: 'a'}},
+		{"\x1b[97:65;2;65u", KeyPressEvent{Rune: 'A', AltRune: 'a', Mod: ModShift, Text: "A"}},
+		{"\x1b[1089::99;1;1089u", KeyPressEvent{Rune: 'с', baseRune: 'c', Text: "с"}},
+		{"\x1b[101;1;101:769u", KeyPressEvent{Rune: 'e', Text: "é"}},
+		{"\x1b[57399;1;48u", KeyPressEvent{Sym: KeyKp0, Text: "0"}},
+		{"\x1b[57428u", KeyPressEvent{Sym: KeyMediaPlay}},
+		{"\x1b[57430u", KeyPressEvent{Sym: KeyMediaPlayPause}},
+		{"\x1b[57439;1:2u", KeyPressEvent{Sym: KeyRaiseVol, IsRepeat: true}},
+		{"\x1b[57438;1:3u", KeyReleaseEvent{Sym: KeyLowerVol}},
+		{"\x1b[57440u", KeyPressEvent{Sym: KeyMute}},
+		{"\x1b[57363;5u", KeyPressEvent{Sym: KeyMenu, Mod: ModCtrl}},
+		{"\x1b[57441;2:3u", KeyReleaseEvent{Sym: KeyLeftShift, Mod: ModShift}},
+		{"\x1b[1;5:3A", KeyReleaseEvent{Sym: KeyUp, Mod: ModCtrl}},
+		{"\x1b[1;1:2D", KeyPressEvent{Sym: KeyLeft, IsRepeat: true}},
+		{"\x1b[3;1:3~", KeyReleaseEvent{Sym: KeyDelete}},
+		{"\x1b[15;3:2~", KeyPressEvent{Sym: KeyF5, Mod: ModAlt, IsRepeat: true}},
+		{"\x1b[57427~", KeyPressEvent{Sym: KeyKpBegin}},
+		{"\x1b[57427;5:3~", KeyReleaseEvent{Sym: KeyKpBegin, Mod: ModCtrl}},
+	}
+	for _, c := range cases {
+		t.Run(c.seq, func(t *testing.T) {
+			n, e := ParseSequence([]byte(c.seq))
+			if n != len(c.seq) {
+				t.Errorf("expected %d bytes, got %d", len(c.seq), n)
+			}
+			if e != c.expect {
+				t.Errorf("expected %#v, got %#v", c.expect, e)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package input
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements of the input the driver reads, so that
+// long-running programs can keep an eye on it, e.g. to expose them with
+// [expvar] or Prometheus. Set it with [WithMetrics], or the Metrics field of
+// an [EventParser]. The methods are called from the goroutine reading events
+// and must not block. See [Stats] for a ready to use implementation.
+type Metrics interface {
+	// BytesRead is called with the number of bytes read from the terminal.
+	BytesRead(n int)
+
+	// EventParsed is called for each event parsed from the input.
+	EventParsed(e Event)
+
+	// UnknownSequence is called for each sequence the parser doesn't
+	// recognize. It must not keep seq, which is only valid during the call.
+	UnknownSequence(seq []byte)
+
+	// ParseTime is called with the time spent parsing the input.
+	ParseTime(d time.Duration)
+}
+
+// isUnknownEvent reports whether e is an unknown sequence.
+func isUnknownEvent(e Event) bool {
+	switch e.(type) {
+	case UnknownEvent, UnknownCsiEvent, UnknownSs3Event, UnknownOscEvent,
+		UnknownDcsEvent, UnknownApcEvent:
+		return true
+	}
+	return false
+}
+
+// Stats is a [Metrics] that counts the input it receives. It's safe for
+// concurrent use.
+//
+//	var stats input.Stats
+//	drv, err := input.New(os.Stdin, input.WithMetrics(&stats))
+//	expvar.Publish("input", expvar.Func(func() interface{} {
+//		return stats.Snapshot()
+//	}))
+type Stats struct {
+	mu sync.Mutex
+	s  StatsSnapshot
+}
+
+// StatsSnapshot is a copy of the counters of a [Stats].
+type StatsSnapshot struct {
+	// Events is the number of events parsed by type, like "KeyPressEvent".
+	Events map[string]uint64
+
+	// Unknown is the number of unknown sequences.
+	Unknown uint64
+
+	// Bytes is the number of bytes read.
+	Bytes uint64
+
+	// ParseTime is the total time spent parsing.
+	ParseTime time.Duration
+}
+
+var _ Metrics = &Stats{}
+
+// BytesRead implements [Metrics].
+func (s *Stats) BytesRead(n int) {
+	s.mu.Lock()
+	s.s.Bytes += uint64(n)
+	s.mu.Unlock()
+}
+
+// EventParsed implements [Metrics].
+func (s *Stats) EventParsed(e Event) {
+	name := eventTypeName(e)
+	s.mu.Lock()
+	if s.s.Events == nil {
+		s.s.Events = make(map[string]uint64)
+	}
+	s.s.Events[name]++
+	s.mu.Unlock()
+}
+
+// UnknownSequence implements [Metrics].
+func (s *Stats) UnknownSequence([]byte) {
+	s.mu.Lock()
+	s.s.Unknown++
+	s.mu.Unlock()
+}
+
+// ParseTime implements [Metrics].
+func (s *Stats) ParseTime(d time.Duration) {
+	s.mu.Lock()
+	s.s.ParseTime += d
+	s.mu.Unlock()
+}
+
+// Snapshot returns a copy of the counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.s
+	snap.Events = make(map[string]uint64, len(s.s.Events))
+	for k, v := range s.s.Events {
+		snap.Events[k] = v
+	}
+	return snap
+}
+
+// Reset sets the counters back to zero.
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	s.s = StatsSnapshot{}
+	s.mu.Unlock()
+}
+
+// eventTypeName returns the name of the type of e, like "KeyPressEvent".
+func eventTypeName(e Event) string {
+	if name := reflect.TypeOf(e).Name(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%T", e)
+}
@@ -0,0 +1,59 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDriverMetrics(t *testing.T) {
+	var stats Stats
+	const in = "\x1b[Aab\x1b[1;2;3y"
+	drv, err := New(strings.NewReader(in), WithTerm("dumb"), WithMetrics(&stats))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if _, err := drv.ReadEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := stats.Snapshot()
+	expect := map[string]uint64{"KeyPressEvent": 3, "UnknownCsiEvent": 1}
+	if !reflect.DeepEqual(s.Events, expect) {
+		t.Errorf("expected events %v, got %v", expect, s.Events)
+	}
+	if s.Unknown != 1 {
+		t.Errorf("expected 1 unknown sequence, got %d", s.Unknown)
+	}
+	if s.Bytes != uint64(len(in)) {
+		t.Errorf("expected %d bytes, got %d", len(in), s.Bytes)
+	}
+
+	stats.Reset()
+	if s := stats.Snapshot(); len(s.Events) != 0 || s.Bytes != 0 {
+		t.Errorf("expected the stats to be reset, got %+v", s)
+	}
+}
+
+func TestParserMetrics(t *testing.T) {
+	var stats Stats
+	p := EventParser{Metrics: &stats}
+	buf := []byte("\x1ba\x1b[1;2;3y")
+	for len(buf) > 0 {
+		n, _ := p.Parse(buf)
+		buf = buf[n:]
+	}
+
+	s := stats.Snapshot()
+	expect := map[string]uint64{"KeyPressEvent": 1, "UnknownCsiEvent": 1}
+	if !reflect.DeepEqual(s.Events, expect) {
+		t.Errorf("expected events %v, got %v", expect, s.Events)
+	}
+	if s.Unknown != 1 {
+		t.Errorf("expected 1 unknown sequence, got %d", s.Unknown)
+	}
+	if s.Bytes != 0 {
+		t.Errorf("expected no bytes read, got %d", s.Bytes)
+	}
+}
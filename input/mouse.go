@@ -2,6 +2,7 @@ package input
 
 import (
 	"regexp"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/x/ansi"
 )
@@ -41,6 +42,20 @@ const (
 	MouseExtra2
 )
 
+// WheelDelta is the [Mouse] Delta of a single wheel notch.
+const WheelDelta = 120
+
+// wheelDelta returns the delta of a single notch of the given wheel button.
+func wheelDelta(btn MouseButton) int {
+	switch btn {
+	case MouseWheelUp, MouseWheelRight:
+		return WheelDelta
+	case MouseWheelDown, MouseWheelLeft:
+		return -WheelDelta
+	}
+	return 0
+}
+
 var mouseButtons = map[MouseButton]string{
 	MouseNone:       "none",
 	MouseLeft:       "left",
@@ -61,6 +76,22 @@ type Mouse struct {
 	X, Y   int
 	Button MouseButton
 	Mod    KeyMod
+
+	// Delta is the signed distance scrolled by wheel events, in 1/120ths of
+	// a wheel notch, see [WheelDelta]. It's positive when scrolling up or
+	// right. Terminals report one notch per event, unless they support
+	// Kitty's high-resolution scrolling, and the Windows Console reports the
+	// fractional deltas of high resolution devices.
+	Delta int
+
+	// PixelX and PixelY are the pixel coordinates of SGR-Pixels mouse
+	// reports, and are zero otherwise. See [EventParser.MousePixels].
+	PixelX, PixelY int
+
+	// ClickCount is the number of consecutive clicks of the same button,
+	// e.g. 2 for a double click. Terminals don't report clicks, so this is
+	// only set when click tracking is enabled, see [ClickTracker].
+	ClickCount int
 }
 
 // String implements fmt.Stringer.
@@ -121,6 +152,34 @@ func (e MouseMotionEvent) String() string {
 	return m.String() + "motion"
 }
 
+// withMouse calls fn with the mouse of the given mouse event and returns the
+// updated event. Other events are returned as is.
+func withMouse(e Event, fn func(m *Mouse)) Event {
+	switch ev := e.(type) {
+	case MouseClickEvent:
+		m := Mouse(ev)
+		fn(&m)
+		return MouseClickEvent(m)
+	case MouseReleaseEvent:
+		m := Mouse(ev)
+		fn(&m)
+		return MouseReleaseEvent(m)
+	case MouseWheelEvent:
+		m := Mouse(ev)
+		fn(&m)
+		return MouseWheelEvent(m)
+	case MouseMotionEvent:
+		m := Mouse(ev)
+		fn(&m)
+		return MouseMotionEvent(m)
+	case MouseDragEvent:
+		m := Mouse(ev)
+		fn(&m)
+		return MouseDragEvent(m)
+	}
+	return e
+}
+
 var mouseSGRRegex = regexp.MustCompile(`(\d+);(\d+);(\d+)([Mm])`)
 
 // Parse SGR-encoded mouse events; SGR extended mouse events. SGR mouse events
@@ -135,12 +194,18 @@ var mouseSGRRegex = regexp.MustCompile(`(\d+);(\d+);(\d+)([Mm])`)
 //	Cy is the y-coordinate of the mouse
 //	M is for button press, m is for button release
 //
+// With Kitty's high-resolution scrolling, wheel reports carry the distance
+// scrolled, in 1/120ths of a notch, as a sub-parameter of the button code:
+//
+//	ESC [ < Cb : Cd ; Cx ; Cy M
+//
 // https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Extended-coordinates
 func parseSGRMouseEvent(csi *ansi.CsiSequence) Event {
-	x := csi.Param(1)
-	y := csi.Param(2)
+	b := csi.Subparams(0)
+	x := csi.Subparams(1)[0]
+	y := csi.Subparams(2)[0]
 	release := csi.Command() == 'm'
-	mod, btn, _, isMotion := parseMouseButton(csi.Param(0))
+	mod, btn, _, isMotion := parseMouseButton(b[0])
 
 	// (1,1) is the upper left. We subtract 1 to normalize it to (0,0).
 	x--
@@ -151,6 +216,15 @@ func parseSGRMouseEvent(csi *ansi.CsiSequence) Event {
 	// Wheel buttons don't have release events
 	// Motion can be reported as a release event in some terminals (Windows Terminal)
 	if isWheel(m.Button) {
+		m.Delta = wheelDelta(m.Button)
+		if len(b) > 1 && b[1] > 0 {
+			// The button gives the direction of high-resolution deltas.
+			if m.Delta < 0 {
+				m.Delta = -b[1]
+			} else {
+				m.Delta = b[1]
+			}
+		}
 		return MouseWheelEvent(m)
 	} else if !isMotion && release {
 		return MouseReleaseEvent(m)
@@ -173,7 +247,46 @@ const x10MouseByteOffset = 32
 // See: http://www.xfree86.org/current/ctlseqs.html#Mouse%20Tracking
 func parseX10MouseEvent(buf []byte) Event {
 	v := buf[3:6]
-	b := int(v[0])
+	return x10MouseEvent(int(v[0]), int(v[1]), int(v[2]))
+}
+
+// Parse UTF-8 extended X10 mouse events. These are X10 mouse events where
+// each value is encoded as a UTF-8 character, which raises the coordinate
+// limit to 2015. The buffer starts after the ESC [ M prefix.
+//
+// It returns the number of bytes consumed, or zero if the buffer doesn't
+// contain a complete report.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+func parseUtf8MouseEvent(buf []byte) (int, Event) {
+	var v [3]int
+	var n int
+	for i := range v {
+		r, w := utf8.DecodeRune(buf[n:])
+		if r == utf8.RuneError && w <= 1 {
+			return 0, nil
+		}
+		v[i] = int(r)
+		n += w
+	}
+	return n, x10MouseEvent(v[0], v[1], v[2])
+}
+
+// Parse URXVT mouse events. These are X10 mouse events where the values
+// are decimal parameters, and the coordinates don't have the X10 offset.
+//
+//	ESC [ Cb ; Cx ; Cy M
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Mouse-Tracking
+func parseURXVTMouseEvent(csi *ansi.CsiSequence) Event {
+	return x10MouseEvent(csi.Param(0),
+		csi.Param(1)+x10MouseByteOffset,
+		csi.Param(2)+x10MouseByteOffset)
+}
+
+// x10MouseEvent returns the mouse event of X10 encoded values, where all
+// values are offset by 32, and the coordinates start at (1,1).
+func x10MouseEvent(b, x, y int) Event {
 	if b >= x10MouseByteOffset {
 		// XXX: b < 32 should be impossible, but we're being defensive.
 		b -= x10MouseByteOffset
@@ -182,11 +295,12 @@ func parseX10MouseEvent(buf []byte) Event {
 	mod, btn, isRelease, isMotion := parseMouseButton(b)
 
 	// (1,1) is the upper left. We subtract 1 to normalize it to (0,0).
-	x := int(v[1]) - x10MouseByteOffset - 1
-	y := int(v[2]) - x10MouseByteOffset - 1
+	x -= x10MouseByteOffset + 1
+	y -= x10MouseByteOffset + 1
 
 	m := Mouse{X: x, Y: y, Button: btn, Mod: mod}
 	if isWheel(m.Button) {
+		m.Delta = wheelDelta(m.Button)
 		return MouseWheelEvent(m)
 	} else if isMotion {
 		return MouseMotionEvent(m)
@@ -193,22 +193,22 @@ func TestParseX10MouseDownEvent(t *testing.T) {
 		{
 			name:     "wheel up",
 			buf:      encode(0b0100_0000, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp, Delta: WheelDelta},
 		},
 		{
 			name:     "wheel down",
 			buf:      encode(0b0100_0001, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "wheel left",
 			buf:      encode(0b0100_0010, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelLeft},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelLeft, Delta: -WheelDelta},
 		},
 		{
 			name:     "wheel right",
 			buf:      encode(0b0100_0011, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelRight},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelRight, Delta: WheelDelta},
 		},
 		{
 			name:     "release",
@@ -269,17 +269,17 @@ func TestParseX10MouseDownEvent(t *testing.T) {
 		{
 			name:     "ctrl+wheel up",
 			buf:      encode(0b0101_0000, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModCtrl, Button: MouseWheelUp},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModCtrl, Button: MouseWheelUp, Delta: WheelDelta},
 		},
 		{
 			name:     "alt+wheel down",
 			buf:      encode(0b0100_1001, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "ctrl+alt+wheel down",
 			buf:      encode(0b0101_1001, 32, 16),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModCtrl, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModCtrl, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		// Overflow position.
 		{
@@ -382,22 +382,22 @@ func TestParseSGRMouseEvent(t *testing.T) {
 		{
 			name:     "wheel up",
 			buf:      encode(64, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelUp, Delta: WheelDelta},
 		},
 		{
 			name:     "wheel down",
 			buf:      encode(65, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "wheel left",
 			buf:      encode(66, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelLeft},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelLeft, Delta: -WheelDelta},
 		},
 		{
 			name:     "wheel right",
 			buf:      encode(67, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelRight},
+			expected: MouseWheelEvent{X: 32, Y: 16, Button: MouseWheelRight, Delta: WheelDelta},
 		},
 		{
 			name:     "backward",
@@ -438,22 +438,22 @@ func TestParseSGRMouseEvent(t *testing.T) {
 		{
 			name:     "alt+wheel",
 			buf:      encode(73, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "ctrl+wheel",
 			buf:      encode(81, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModCtrl, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModCtrl, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "ctrl+alt+wheel",
 			buf:      encode(89, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModCtrl, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModCtrl, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 		{
 			name:     "ctrl+alt+shift+wheel",
 			buf:      encode(93, 32, 16, false),
-			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModShift | ModCtrl, Button: MouseWheelDown},
+			expected: MouseWheelEvent{X: 32, Y: 16, Mod: ModAlt | ModShift | ModCtrl, Button: MouseWheelDown, Delta: -WheelDelta},
 		},
 	}
 
@@ -471,3 +471,131 @@ func TestParseSGRMouseEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSGRPixelsMouseEvent(t *testing.T) {
+	p := EventParser{MousePixels: true}
+
+	// Without a cell size, only the pixel coordinates are known.
+	_, e := p.Parse([]byte("\x1b[<0;101;41M"))
+	if expect := (MouseClickEvent{PixelX: 100, PixelY: 40, Button: MouseLeft}); e != expect {
+		t.Errorf("expected %#v, got %#v", expect, e)
+	}
+
+	// Parsing a cell size report updates the parser.
+	_, e = p.Parse([]byte("\x1b[6;20;10t"))
+	if expect := (CellSizeEvent{Width: 10, Height: 20}); e != expect {
+		t.Errorf("expected %#v, got %#v", expect, e)
+	}
+
+	_, e = p.Parse([]byte("\x1b[<35;101;41m"))
+	if expect := (MouseMotionEvent{X: 10, Y: 2, PixelX: 100, PixelY: 40}); e != expect {
+		t.Errorf("expected %#v, got %#v", expect, e)
+	}
+
+	_, e = p.Parse([]byte("\x1b[<64;25;25M"))
+	if expect := (MouseWheelEvent{X: 2, Y: 1, PixelX: 24, PixelY: 24, Button: MouseWheelUp, Delta: WheelDelta}); e != expect {
+		t.Errorf("expected %#v, got %#v", expect, e)
+	}
+}
+
+func TestParseURXVTMouseEvent(t *testing.T) {
+	cases := []struct {
+		seq    string
+		expect Event
+	}{
+		{"\x1b[32;1;1M", MouseClickEvent{X: 0, Y: 0, Button: MouseLeft}},
+		{"\x1b[34;300;120M", MouseClickEvent{X: 299, Y: 119, Button: MouseRight}},
+		{"\x1b[35;5;5M", MouseReleaseEvent{X: 4, Y: 4, Button: MouseNone}},
+		{"\x1b[96;10;10M", MouseWheelEvent{X: 9, Y: 9, Button: MouseWheelUp, Delta: WheelDelta}},
+		{"\x1b[64;2;3M", MouseMotionEvent{X: 1, Y: 2, Button: MouseLeft}},
+	}
+	for _, c := range cases {
+		n, e := ParseSequence([]byte(c.seq))
+		if n != len(c.seq) {
+			t.Errorf("%q: expected %d bytes, got %d", c.seq, len(c.seq), n)
+		}
+		if e != c.expect {
+			t.Errorf("%q: expected %#v, got %#v", c.seq, c.expect, e)
+		}
+	}
+}
+
+func TestParseUtf8MouseEvent(t *testing.T) {
+	p := EventParser{MouseUtf8: true}
+
+	// Coordinates beyond the X10 limit are encoded as 2 byte characters.
+	seq := "\x1b[M " + string(rune(300+33)) + string(rune(120+33))
+	n, e := p.Parse([]byte(seq))
+	if n != len(seq) {
+		t.Errorf("expected %d bytes, got %d", len(seq), n)
+	}
+	if expect := (MouseClickEvent{X: 300, Y: 120, Button: MouseLeft}); e != expect {
+		t.Errorf("expected %#v, got %#v", expect, e)
+	}
+
+	// Small values are the same as X10.
+	n, e = p.Parse([]byte("\x1b[M#!!"))
+	if expect := (MouseReleaseEvent{X: 0, Y: 0}); n != 6 || e != expect {
+		t.Errorf("expected %#v, got %d %#v", expect, n, e)
+	}
+
+	// Incomplete reports are unknown.
+	if _, e := p.Parse([]byte("\x1b[M \xc4")); e != UnknownCsiEvent("\x1b[M") {
+		t.Errorf("expected unknown event, got %#v", e)
+	}
+}
+
+func TestWheelDelta(t *testing.T) {
+	cases := []struct {
+		seq    string
+		button MouseButton
+		delta  int
+	}{
+		{"\x1b[<64;1;1M", MouseWheelUp, WheelDelta},
+		{"\x1b[<65;1;1M", MouseWheelDown, -WheelDelta},
+		{"\x1b[<66;1;1M", MouseWheelLeft, -WheelDelta},
+		{"\x1b[<67;1;1M", MouseWheelRight, WheelDelta},
+		{"\x1b[Mb!!", MouseWheelLeft, -WheelDelta},
+		{"\x1b[Mc!!", MouseWheelRight, WheelDelta},
+		// High-resolution scrolling.
+		{"\x1b[<64:30;1;1M", MouseWheelUp, 30},
+		{"\x1b[<65:240;1;1M", MouseWheelDown, -240},
+		{"\x1b[<66:1;1;1M", MouseWheelLeft, -1},
+		{"\x1b[<67:60;1;1M", MouseWheelRight, 60},
+		{"\x1b[<64:0;1;1M", MouseWheelUp, WheelDelta},
+		{"\x1b[<65:;1;1M", MouseWheelDown, -WheelDelta},
+	}
+	for _, c := range cases {
+		_, e := ParseSequence([]byte(c.seq))
+		w, ok := e.(MouseWheelEvent)
+		if !ok {
+			t.Errorf("%q: expected a wheel event, got %#v", c.seq, e)
+			continue
+		}
+		if w.Button != c.button || w.Delta != c.delta {
+			t.Errorf("%q: expected %v with delta %d, got %v with delta %d", c.seq, c.button, c.delta, w.Button, w.Delta)
+		}
+	}
+}
+
+func TestParseHighResolutionWheel(t *testing.T) {
+	cases := []struct {
+		seq    string
+		expect Event
+	}{
+		{"\x1b[<80:60;5;7M", MouseWheelEvent{X: 4, Y: 6, Mod: ModCtrl, Button: MouseWheelUp, Delta: 60}},
+		{"\x1b[<65:15;10;20M", MouseWheelEvent{X: 9, Y: 19, Button: MouseWheelDown, Delta: -15}},
+		// Sub-parameters of other buttons are ignored.
+		{"\x1b[<0:5;3;4M", MouseClickEvent{X: 2, Y: 3, Button: MouseLeft}},
+		{"\x1b[<64:30;1M", UnknownCsiEvent("\x1b[<64:30;1M")},
+	}
+	for _, c := range cases {
+		n, e := ParseSequence([]byte(c.seq))
+		if n != len(c.seq) {
+			t.Errorf("%q: expected %d bytes, got %d", c.seq, len(c.seq), n)
+		}
+		if e != c.expect {
+			t.Errorf("%q: expected %#v, got %#v", c.seq, c.expect, e)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package input
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// Logger represents a logger used by the driver to report unusual input,
+// like unknown sequences.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// driverOptions holds the options used to create a driver.
+type driverOptions struct {
+	term       string
+	flags      int
+	logger     Logger
+	escTimeout time.Duration
+	maxReadBuf int
+	maxSeq     int
+	parser     *EventParser
+	terminfo   TerminfoSource
+	clicks     *ClickTracker
+	drags      bool
+	coalesce   bool
+
+	pasteStream bool
+	maxPaste    int
+	rawPaste    bool
+
+	pasteSanitize int
+
+	tap     io.Writer
+	out     io.Writer
+	decoder transform.Transformer
+
+	releaseDelay time.Duration
+
+	filters []EventFilter
+
+	raw         bool
+	interrupt   InterruptMode
+	flowControl FlowControlMode
+	suspend     bool
+
+	metrics Metrics
+}
+
+// DriverOption is a functional option that configures a driver created
+// using [New].
+type DriverOption func(o *driverOptions)
+
+// WithTerm sets the terminal name used to look up key sequences in the
+// Terminfo database. It defaults to the $TERM environment variable.
+func WithTerm(term string) DriverOption {
+	return func(o *driverOptions) {
+		o.term = term
+	}
+}
+
+// WithFlags adds the given flags to the driver flags. See the Flag*
+// constants.
+func WithFlags(flags ...int) DriverOption {
+	return func(o *driverOptions) {
+		for _, f := range flags {
+			o.flags |= f
+		}
+	}
+}
+
+// WithoutTerminfo disables looking up key sequences in the Terminfo
+// database.
+func WithoutTerminfo() DriverOption {
+	return func(o *driverOptions) {
+		o.flags &^= FlagTerminfo
+	}
+}
+
+// WithTerminfoSource sets the source used to look up Terminfo entries. It
+// defaults to [DefaultTerminfo].
+func WithTerminfoSource(src TerminfoSource) DriverOption {
+	return func(o *driverOptions) {
+		o.terminfo = src
+	}
+}
+
+// WithClickTracker enables counting mouse clicks using the given tracker.
+// The driver sets the ClickCount of mouse click and release events.
+func WithClickTracker(t *ClickTracker) DriverOption {
+	return func(o *driverOptions) {
+		o.clicks = t
+	}
+}
+
+// WithSynthesizedKeyReleases makes the driver report a key release event
+// delay after each key press, for terminals that only report key presses.
+// Repeated presses of a held key are reported as repeats, and push its
+// release back. This lets code written against press and release events
+// work in legacy terminals. A zero delay means [DefaultKeyReleaseDelay].
+//
+// Synthesis stops as soon as the terminal reports a key release itself,
+// e.g. when the Kitty keyboard protocol is enabled with
+// [ansi.KittyReportEventTypes].
+func WithSynthesizedKeyReleases(delay time.Duration) DriverOption {
+	return func(o *driverOptions) {
+		if delay <= 0 {
+			delay = DefaultKeyReleaseDelay
+		}
+		o.releaseDelay = delay
+	}
+}
+
+// WithDragTracking enables tracking the mouse button held down across
+// motion events. The driver reports motion with a button held down as
+// [MouseDragEvent]s. See [DragTracker].
+func WithDragTracking() DriverOption {
+	return func(o *driverOptions) {
+		o.drags = true
+	}
+}
+
+// WithMotionCoalescing enables coalescing consecutive mouse motion events
+// read at once into the latest one. See [CoalesceMotion].
+func WithMotionCoalescing() DriverOption {
+	return func(o *driverOptions) {
+		o.coalesce = true
+	}
+}
+
+// WithPasteStreaming makes the driver deliver bracketed pastes as they
+// arrive. Instead of a [PasteEvent], the driver reports the raw pasted bytes
+// in [PasteChunkEvent]s between the [PasteStartEvent] and [PasteEndEvent].
+func WithPasteStreaming() DriverOption {
+	return func(o *driverOptions) {
+		o.pasteStream = true
+	}
+}
+
+// WithMaxPasteSize limits the size of bracketed pastes to n bytes. The rest
+// of the pasted data is dropped.
+func WithMaxPasteSize(n int) DriverOption {
+	return func(o *driverOptions) {
+		o.maxPaste = n
+	}
+}
+
+// WithRawPaste makes the driver report the pasted bytes as is in
+// [PasteEvent]s. By default, invalid UTF-8 is dropped.
+func WithRawPaste() DriverOption {
+	return func(o *driverOptions) {
+		o.rawPaste = true
+	}
+}
+
+// WithPasteSanitizer makes the driver sanitize bracketed pastes before
+// delivering them. The flags select the sanitization, see [SanitizePaste]
+// and the Paste* flags.
+//
+//	drv, err := input.New(os.Stdin, input.WithPasteSanitizer(input.PasteSanitizeAll))
+func WithPasteSanitizer(flags int) DriverOption {
+	return func(o *driverOptions) {
+		o.pasteSanitize = flags
+	}
+}
+
+// WithInputTap makes the driver write every chunk of raw input it reads
+// from the terminal to w, before parsing it. Use it to debug unexpected
+// events, or to record the input. Write errors are logged and otherwise
+// ignored.
+//
+// On Windows, console input records are not raw input and are not tapped.
+func WithInputTap(w io.Writer) DriverOption {
+	return func(o *driverOptions) {
+		o.tap = w
+	}
+}
+
+// WithDecoder makes the driver convert the input to UTF-8 using the given
+// decoder before parsing it. Use it with terminals that send input in a
+// legacy encoding, like CP437 or Latin-1. Escape sequences are ASCII and are
+// left as is by these encodings. The raw input, before decoding, is what
+// [WithInputTap] receives.
+//
+//	drv, err := input.New(port, input.WithDecoder(charmap.CodePage437.NewDecoder()))
+//
+// On Windows, console input records are already Unicode and are not
+// decoded.
+func WithDecoder(t transform.Transformer) DriverOption {
+	return func(o *driverOptions) {
+		o.decoder = t
+	}
+}
+
+// WithFilter adds a filter that can drop, transform, or expand the events
+// before the driver delivers them. It can be used more than once, and the
+// filters run in the order they were added, each one getting the events
+// returned by the previous one. Filters run after the built-in processing,
+// like click tracking and motion coalescing.
+func WithFilter(f EventFilter) DriverOption {
+	return func(o *driverOptions) {
+		o.filters = append(o.filters, f)
+	}
+}
+
+// WithOutput sets the terminal output, that the driver writes queries to,
+// like the ones sent by [Driver.Detect]. It's usually [os.Stdout].
+func WithOutput(w io.Writer) DriverOption {
+	return func(o *driverOptions) {
+		o.out = w
+	}
+}
+
+// WithRawMode puts the terminal the driver reads from into raw mode, so that
+// input arrives as typed, without line editing, echo, or signal keys. The
+// previous terminal state is restored when the driver is closed. It has no
+// effect when the reader isn't a terminal.
+//
+//	drv, err := input.New(os.Stdin, input.WithRawMode())
+//	if err != nil {
+//		return err
+//	}
+//	defer drv.Close()
+//
+// See [term.MakeRaw] to manage the terminal state yourself.
+func WithRawMode() DriverOption {
+	return func(o *driverOptions) {
+		o.raw = true
+	}
+}
+
+// WithInterrupt sets how the driver handles ctrl+c, see [InterruptMode].
+//
+//	// Handle ctrl+c as a key, even without raw mode.
+//	drv, err := input.New(os.Stdin, input.WithInterrupt(input.InterruptKey))
+func WithInterrupt(mode InterruptMode) DriverOption {
+	return func(o *driverOptions) {
+		o.interrupt = mode
+	}
+}
+
+// WithFlowControl sets how the driver handles the ctrl+s and ctrl+q flow
+// control keys, see [FlowControlMode].
+func WithFlowControl(mode FlowControlMode) DriverOption {
+	return func(o *driverOptions) {
+		o.flowControl = mode
+	}
+}
+
+// WithSuspendEvents makes the driver report ctrl+z as a [SuspendEvent], so
+// that the program can suspend itself with [Driver.Suspend].
+func WithSuspendEvents() DriverOption {
+	return func(o *driverOptions) {
+		o.suspend = true
+	}
+}
+
+// WithMetrics makes the driver report measurements of the input it reads,
+// like the number of events parsed by type and the unknown sequences, to m.
+// See [Stats].
+func WithMetrics(m Metrics) DriverOption {
+	return func(o *driverOptions) {
+		o.metrics = m
+	}
+}
+
+// WithLogger sets the logger used to report unusual input.
+func WithLogger(l Logger) DriverOption {
+	return func(o *driverOptions) {
+		o.logger = l
+	}
+}
+
+// WithEscTimeout sets how long the driver waits for the rest of an escape
+// sequence that arrives split across reads. See [Driver.SetEscTimeout].
+func WithEscTimeout(d time.Duration) DriverOption {
+	return func(o *driverOptions) {
+		o.escTimeout = d
+	}
+}
+
+// WithMaxReadBufferSize sets the maximum size of the read buffer. The buffer
+// starts at 256 bytes and doubles whenever a read fills it, up to n bytes.
+// Use a small size in memory constrained environments. It defaults to
+// [DefaultMaxReadBufferSize].
+func WithMaxReadBufferSize(n int) DriverOption {
+	return func(o *driverOptions) {
+		o.maxReadBuf = n
+	}
+}
+
+// WithMaxSequenceSize sets the maximum size of an incomplete sequence, like
+// a long OSC 52 reply, that the driver keeps while waiting for the rest of
+// it. Longer sequences are reported as unknown events. It defaults to
+// [DefaultMaxSequenceSize].
+func WithMaxSequenceSize(n int) DriverOption {
+	return func(o *driverOptions) {
+		o.maxSeq = n
+	}
+}
+
+// WithParser sets the parser used to decode input sequences. Use this to
+// register custom sequences or to unwrap multiplexer passthrough sequences.
+// When set, the parser flags are used as is, and flags that affect parsing
+// passed to the driver are ignored.
+func WithParser(p *EventParser) DriverOption {
+	return func(o *driverOptions) {
+		o.parser = p
+	}
+}
+
+// New returns a new ANSI input driver configured using the given options.
+// By default, the driver uses the $TERM environment variable and the
+// Terminfo database to look up key sequences.
+//
+//	drv, err := input.New(os.Stdin,
+//		input.WithFlags(input.FlagWindowSize),
+//		input.WithEscTimeout(25*time.Millisecond),
+//	)
+func New(r io.Reader, opts ...DriverOption) (*Driver, error) {
+	o := driverOptions{
+		term:       os.Getenv("TERM"),
+		flags:      FlagTerminfo,
+		escTimeout: DefaultEscTimeout,
+		maxReadBuf: DefaultMaxReadBufferSize,
+		maxSeq:     DefaultMaxSequenceSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newDriver(r, o)
+}
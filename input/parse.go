@@ -1,8 +1,10 @@
 package input
 
 import (
+	"bytes"
 	"encoding/base64"
-	"strings"
+	"image/color"
+	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/x/ansi"
@@ -81,6 +83,25 @@ const (
 	// Key definitions come from Terminfo, this flag is only useful when
 	// FlagTerminfo is not set.
 	FlagFKeys
+
+	// When this flag is set, the driver will report window size changes as
+	// [WindowSizeEvent]s along with the rest of the input events. The
+	// current size is reported right away.
+	//
+	// On Unix, the driver listens for SIGWINCH signals and queries the
+	// terminal size. This requires the driver input to be a terminal file.
+	// On Windows, window buffer size records are always reported when
+	// reading from the console.
+	FlagWindowSize
+
+	// When this flag is set, the driver will report keypad keys as their main
+	// keyboard equivalents, e.g. the keypad Enter key as Enter and the keypad
+	// 1 key as the character "1".
+	//
+	// Terminals in Keypad Application Mode (DECKPAM), and terminals using the
+	// Kitty keyboard protocol, report keypad keys distinctly. This flag
+	// allows the driver to treat them as the keys most applications expect.
+	FlagFoldKeypad
 )
 
 var flags int
@@ -91,16 +112,149 @@ func SetFlags(f int) {
 	flags = f
 }
 
+// EventParser represents a decoder for terminal input sequences. The zero
+// value is ready to use.
+type EventParser struct {
+	// Flags control the behavior of the parser. See the Flag* constants.
+	Flags int
+
+	// UnwrapPassthrough makes the parser detect tmux (DCS tmux ; ... ST) and
+	// GNU Screen (DCS ESC ... ST) passthrough sequences and parse the wrapped
+	// sequences instead. This makes events work the same way inside
+	// multiplexers.
+	UnwrapPassthrough bool
+
+	// MouseUtf8 makes the parser decode X10 mouse reports as UTF-8 extended
+	// reports. Set it when [ansi.EnableMouseUtf8Ext] is enabled.
+	MouseUtf8 bool
+
+	// MousePixels makes the parser treat SGR mouse reports as SGR-Pixels
+	// reports, which use pixel coordinates. Set it when
+	// [ansi.EnableMouseSgrPixelsExt] is enabled.
+	MousePixels bool
+
+	// CellWidth and CellHeight are the size of a cell in pixels. They're used
+	// to derive the cell coordinates of SGR-Pixels mouse reports. The parser
+	// updates them whenever it parses a [CellSizeEvent], i.e. the response to
+	// a CSI 16 t request.
+	CellWidth, CellHeight int
+
+	// Metrics, when set, receives the events parsed by [EventParser.Parse],
+	// the unknown sequences, and the time spent parsing. A [Driver] reports
+	// to the metrics set with [WithMetrics] instead.
+	Metrics Metrics
+
+	// seqs maps custom sequences to their events.
+	seqs map[string]Event
+
+	// csiHandlers maps CSI final bytes to custom handlers.
+	csiHandlers map[byte]func(*ansi.CsiSequence) Event
+}
+
+// registeredEvent wraps the events of registered sequences and CSI handlers
+// until they're returned by parseEvent, which reports them as custom.
+type registeredEvent struct {
+	Event
+}
+
+// RegisterSequence teaches the parser a custom sequence. Whenever the input
+// starts with seq, the parser reports the given event. Registered sequences
+// take precedence over the built-in ones, and the longest match wins.
+//
+// This must not be called concurrently with Parse.
+func (p *EventParser) RegisterSequence(seq string, event Event) {
+	if seq == "" {
+		return
+	}
+	if p.seqs == nil {
+		p.seqs = make(map[string]Event)
+	}
+	p.seqs[seq] = event
+}
+
+// RegisterCsiHandler registers a handler for CSI sequences with the given
+// final byte. The handler gets called with the parsed sequence, including
+// its marker and intermediate bytes, and takes precedence over the built-in
+// handling. If the handler returns nil, the sequence is handled as usual.
+//
+// The sequence passed to the handler is only valid during the call. Use
+// [ansi.CsiSequence.Clone] to keep it around.
+//
+// This must not be called concurrently with Parse.
+func (p *EventParser) RegisterCsiHandler(final byte, fn func(csi *ansi.CsiSequence) Event) {
+	if p.csiHandlers == nil {
+		p.csiHandlers = make(map[byte]func(*ansi.CsiSequence) Event)
+	}
+	p.csiHandlers[final] = fn
+}
+
+// parseRegistered returns the longest registered sequence buf starts with.
+func (p *EventParser) parseRegistered(buf []byte) (n int, e Event) {
+	for seq, ev := range p.seqs {
+		if len(seq) > n && len(seq) <= len(buf) && string(buf[:len(seq)]) == seq {
+			n, e = len(seq), ev
+		}
+	}
+	return n, e
+}
+
 // ParseSequence finds the first recognized event sequence and returns it along
 // with its length.
 //
 // It will return zero and nil no sequence is recognized or when the buffer is
 // empty. If a sequence is not supported, an UnknownEvent is returned.
+//
+// ParseSequence uses the flags set by [SetFlags]. Use an [EventParser] for
+// more control over the parser.
 func ParseSequence(buf []byte) (n int, e Event) {
+	p := EventParser{Flags: flags}
+	return p.Parse(buf)
+}
+
+// Parse finds the first recognized event sequence and returns it along with
+// its length. See [ParseSequence].
+func (p *EventParser) Parse(buf []byte) (n int, e Event) {
+	if p.Metrics == nil {
+		n, e, _ = p.parseEvent(buf)
+		return n, e
+	}
+
+	start := time.Now()
+	n, e, _ = p.parseEvent(buf)
+	p.Metrics.ParseTime(time.Since(start))
+	if e != nil {
+		p.Metrics.EventParsed(e)
+		if isUnknownEvent(e) {
+			p.Metrics.UnknownSequence(buf[:n])
+		}
+	}
+	return n, e
+}
+
+// parseEvent is like Parse, without reporting metrics. custom reports
+// whether the event comes from a registered sequence or CSI handler.
+func (p *EventParser) parseEvent(buf []byte) (n int, e Event, custom bool) {
+	n, e = p.parse(buf)
+	if c, ok := e.(registeredEvent); ok {
+		e, custom = c.Event, true
+	}
+	if p.Flags&FlagFoldKeypad != 0 {
+		e = foldKeypadEvent(e)
+	}
+	return n, e, custom
+}
+
+func (p *EventParser) parse(buf []byte) (n int, e Event) {
 	if len(buf) == 0 {
 		return 0, nil
 	}
 
+	if len(p.seqs) > 0 {
+		if n, e := p.parseRegistered(buf); n > 0 {
+			return n, registeredEvent{e}
+		}
+	}
+
 	switch b := buf[0]; b {
 	case ansi.ESC:
 		if len(buf) == 1 {
@@ -112,15 +266,15 @@ func ParseSequence(buf []byte) (n int, e Event) {
 		case 'O': // Esc-prefixed SS3
 			return parseSs3(buf)
 		case 'P': // Esc-prefixed DCS
-			return parseDcs(buf)
+			return p.parseDcs(buf)
 		case '[': // Esc-prefixed CSI
-			return parseCsi(buf)
+			return p.parseCsi(buf)
 		case ']': // Esc-prefixed OSC
 			return parseOsc(buf)
 		case '_': // Esc-prefixed APC
 			return parseApc(buf)
 		default:
-			n, e := ParseSequence(buf[1:])
+			n, e, _ := p.parseEvent(buf[1:])
 			if k, ok := e.(KeyPressEvent); ok && !k.Mod.HasAlt() {
 				k.Mod |= ModAlt
 				return n + 1, k
@@ -133,27 +287,27 @@ func ParseSequence(buf []byte) (n int, e Event) {
 	case ansi.SS3:
 		return parseSs3(buf)
 	case ansi.DCS:
-		return parseDcs(buf)
+		return p.parseDcs(buf)
 	case ansi.CSI:
-		return parseCsi(buf)
+		return p.parseCsi(buf)
 	case ansi.OSC:
 		return parseOsc(buf)
 	case ansi.APC:
 		return parseApc(buf)
 	default:
 		if b <= ansi.US || b == ansi.DEL || b == ansi.SP {
-			return 1, parseControl(b)
+			return 1, p.parseControl(b)
 		} else if b >= ansi.PAD && b <= ansi.APC {
 			// C1 control code
 			// UTF-8 never starts with a C1 control code
 			// Encode these as Ctrl+Alt+<code - 0x40>
 			return 1, KeyPressEvent{Rune: rune(b) - 0x40, Mod: ModCtrl | ModAlt}
 		}
-		return parseUtf8(buf)
+		return p.parseUtf8(buf)
 	}
 }
 
-func parseCsi(b []byte) (int, Event) {
+func (p *EventParser) parseCsi(b []byte) (int, Event) {
 	if len(b) == 2 && b[0] == ansi.ESC {
 		// short cut if this is an alt+[ key
 		return 2, KeyPressEvent{Rune: rune(b[1]), Mod: ModAlt}
@@ -212,19 +366,27 @@ func parseCsi(b []byte) (int, Event) {
 	// Set the intermediate byte
 	csi.Cmd |= int(intermed) << parser.IntermedShift
 
+	// Special case for URxvt keys
+	// CSI <number> $ is an invalid sequence, but URxvt uses it for shift
+	// modified keys. It has no final byte, whatever comes after the $ is the
+	// next key, even when it looks like a final byte, like in "CSI 7 $ a".
+	if intermed == '$' && b[i-1] == '$' && csi.Marker() == 0 && paramsLen == 1 {
+		seq := make([]byte, i)
+		copy(seq, b[:i-1])
+		seq[i-1] = '~'
+		_, ev := p.parseCsi(seq)
+		if c, ok := ev.(registeredEvent); ok {
+			ev = c.Event
+		}
+		if k, ok := ev.(KeyPressEvent); ok {
+			k.Mod |= ModShift
+			return i, k
+		}
+	}
+
 	// Scan final byte in the range 0x40-0x7E
 	if i >= len(b) || b[i] < 0x40 || b[i] > 0x7E {
-		// Special case for URxvt keys
-		// CSI <number> $ is an invalid sequence, but URxvt uses it for
-		// shift modified keys.
-		if b[i-1] == '$' {
-			n, ev := parseCsi(append(b[:i-1], '~'))
-			if k, ok := ev.(KeyPressEvent); ok {
-				k.Mod |= ModShift
-				return n, k
-			}
-		}
-		return i, UnknownEvent(b[:i-1])
+		return i, UnknownEvent(b[:i])
 	}
 
 	// Add the final byte
@@ -233,6 +395,15 @@ func parseCsi(b []byte) (int, Event) {
 
 	csi.Params = params[:paramsLen]
 	marker, cmd := csi.Marker(), csi.Command()
+
+	if h, ok := p.csiHandlers[byte(cmd)]; ok {
+		// Give the handler its own copy so that the parameters can stay on
+		// the stack otherwise.
+		hcsi := ansi.CsiSequence{Cmd: csi.Cmd, Params: append([]int(nil), csi.Params...)}
+		if e := h(&hcsi); e != nil {
+			return i, registeredEvent{e}
+		}
+	}
 	switch marker {
 	case '?':
 		switch cmd {
@@ -250,25 +421,36 @@ func parseCsi(b []byte) (int, Event) {
 			return i, parsePrimaryDevAttrs(&csi)
 		case 'u':
 			// Kitty keyboard flags
+			// A missing parameter means no flags are enabled.
 			if param := csi.Param(0); param != -1 {
 				return i, KittyKeyboardEvent(param)
 			}
+			return i, KittyKeyboardEvent(0)
 		case 'R':
-			// This report may return a third parameter representing the page
-			// number, but we don't really need it.
+			// Extended cursor position report (DECXCPR)
+			// CSI ? Pl ; Pc ; Pp R
 			if paramsLen >= 2 {
-				return i, CursorPositionEvent{Row: csi.Param(0), Column: csi.Param(1)}
+				e := CursorPositionEvent{Row: csi.Param(0), Column: csi.Param(1)}
+				if paramsLen > 2 && csi.Param(2) > 0 {
+					e.Page = csi.Param(2)
+				}
+				return i, e
 			}
 		}
 		return i, UnknownCsiEvent(b[:i])
 	case '<':
 		switch cmd {
 		case 'm', 'M':
-			// Handle SGR mouse
-			if paramsLen != 3 {
+			// Handle SGR mouse, the button may have a wheel delta
+			// sub-parameter.
+			if csi.Len() != 3 {
 				return i, UnknownCsiEvent(b[:i])
 			}
-			return i, parseSGRMouseEvent(&csi)
+			e := parseSGRMouseEvent(&csi)
+			if p.MousePixels {
+				e = withMouse(e, p.pixelMouse)
+			}
+			return i, e
 		default:
 			return i, UnknownCsiEvent(b[:i])
 		}
@@ -290,9 +472,14 @@ func parseCsi(b []byte) (int, Event) {
 	}
 
 	switch cmd := csi.Command(); cmd {
-	case 'I':
-		return i, FocusEvent{}
-	case 'O':
+	case 'I', 'O':
+		// Focus reports don't take any parameters.
+		if paramsLen != 0 {
+			return i, UnknownCsiEvent(b[:i])
+		}
+		if cmd == 'I' {
+			return i, FocusEvent{}
+		}
 		return i, BlurEvent{}
 	case 'R':
 		// Cursor position report OR modified F3
@@ -334,12 +521,24 @@ func parseCsi(b []byte) (int, Event) {
 		}
 		if paramsLen > 1 && csi.Param(0) == 1 {
 			// CSI 1 ; <modifiers> A
-			if paramsLen > 1 {
-				k.Mod |= KeyMod(csi.Param(1) - 1)
-			}
+			// CSI 1 ; <modifiers> : <event-type> A
+			k.Mod |= KeyMod(csi.Param(1) - 1)
+			return i, kittyKeyEvent(Key(k), kittyEventType(&csi, 1))
 		}
 		return i, k
 	case 'M':
+		if paramsLen == 3 {
+			// Handle URXVT mouse
+			// CSI Cb ; Cx ; Cy M
+			return i, parseURXVTMouseEvent(&csi)
+		}
+		if p.MouseUtf8 {
+			// Handle UTF-8 extended X10 mouse
+			if n, e := parseUtf8MouseEvent(b[i:]); n > 0 {
+				return i + n, e
+			}
+			return i, UnknownCsiEvent(b[:i])
+		}
 		// Handle X10 mouse
 		if i+3 > len(b) {
 			return i, UnknownCsiEvent(b[:i])
@@ -351,6 +550,45 @@ func parseCsi(b []byte) (int, Event) {
 			return i, UnknownCsiEvent(b[:i])
 		}
 		return i, ReportModeEvent{Mode: csi.Param(0), Value: csi.Param(1)}
+	case 't':
+		// Window operation (XTWINOPS) reports
+		switch csi.Param(0) {
+		case 1, 2:
+			// Window state report
+			// CSI 1 t or CSI 2 t
+			if paramsLen == 1 {
+				return i, WindowStateEvent{Minimized: csi.Param(0) == 2}
+			}
+			return i, UnknownCsiEvent(b[:i])
+		}
+		if paramsLen < 3 {
+			return i, UnknownCsiEvent(b[:i])
+		}
+		switch csi.Param(0) {
+		case 4:
+			// Text area or window size report in pixels
+			// CSI 4 ; height ; width t
+			return i, PixelSizeEvent{Height: csi.Param(1), Width: csi.Param(2)}
+		case 8:
+			// Text area size report in cells
+			// CSI 8 ; height ; width t
+			return i, TextAreaSizeEvent{Height: csi.Param(1), Width: csi.Param(2)}
+		case 6:
+			// Cell size report
+			// CSI 6 ; height ; width t
+			e := CellSizeEvent{Height: csi.Param(1), Width: csi.Param(2)}
+			p.CellWidth, p.CellHeight = e.Width, e.Height
+			return i, e
+		case 48:
+			// In-band resize report
+			// CSI 48 ; height ; width ; height_pixels ; width_pixels t
+			e := WindowSizeEvent{Height: csi.Param(1), Width: csi.Param(2)}
+			if paramsLen == 5 {
+				e.PixelHeight, e.PixelWidth = csi.Param(3), csi.Param(4)
+			}
+			return i, e
+		}
+		return i, UnknownCsiEvent(b[:i])
 	case 'u':
 		// Kitty keyboard protocol & CSI u (fixterms)
 		if paramsLen == 0 {
@@ -359,26 +597,32 @@ func parseCsi(b []byte) (int, Event) {
 		return i, parseKittyKeyboard(&csi)
 	case '_':
 		// Win32 Input Mode
-		if paramsLen != 6 {
+		// CSI Vk ; Sc ; Uc ; Kd ; Cs ; Rc _
+		//
+		// Any of the parameters may be omitted, in which case they default to
+		// 0, except for Rc which defaults to 1.
+		if paramsLen == 0 || paramsLen > 6 {
 			return i, UnknownCsiEvent(b[:i])
 		}
 
-		rc := uint16(csi.Param(5))
-		if rc == 0 {
-			rc = 1
+		param := func(n, def int) int {
+			if p := csi.Param(n); p >= 0 {
+				return p
+			}
+			return def
 		}
 
 		event := parseWin32InputKeyEvent(
-			coninput.VirtualKeyCode(csi.Param(0)),  // Vk wVirtualKeyCode
-			coninput.VirtualKeyCode(csi.Param(1)),  // Sc wVirtualScanCode
-			rune(csi.Param(2)),                     // Uc UnicodeChar
-			csi.Param(3) == 1,                      // Kd bKeyDown
-			coninput.ControlKeyState(csi.Param(4)), // Cs dwControlKeyState
-			rc,                                     // Rc wRepeatCount
+			coninput.VirtualKeyCode(param(0, 0)),  // Vk wVirtualKeyCode
+			coninput.VirtualKeyCode(param(1, 0)),  // Sc wVirtualScanCode
+			rune(param(2, 0)),                     // Uc UnicodeChar
+			param(3, 0) == 1,                      // Kd bKeyDown
+			coninput.ControlKeyState(param(4, 0)), // Cs dwControlKeyState
+			uint16(param(5, 1)),                   // Rc wRepeatCount
 		)
 
 		if event == nil {
-			return i, UnknownCsiEvent(b[:])
+			return i, UnknownCsiEvent(b[:i])
 		}
 
 		return i, event
@@ -404,6 +648,17 @@ func parseCsi(b []byte) (int, Event) {
 				// bracketed-paste end
 				return i, PasteEndEvent{}
 			}
+
+			// Kitty functional keys that don't have a legacy encoding,
+			// like the keypad begin key, use their Private Use Area
+			// codepoint with the ~ terminator.
+			if sym, ok := kittyKeyMap[param]; ok && param >= 57344 {
+				k := Key{Sym: sym}
+				if mod := csi.Param(1); mod > 1 {
+					k.Mod = fromKittyMod(mod - 1)
+				}
+				return i, kittyKeyEvent(k, kittyEventType(&csi, 1))
+			}
 		}
 
 		switch param {
@@ -417,7 +672,7 @@ func parseCsi(b []byte) (int, Event) {
 			var k KeyPressEvent
 			switch param {
 			case 1:
-				if flags&FlagFind != 0 {
+				if p.Flags&FlagFind != 0 {
 					k = KeyPressEvent{Sym: KeyFind}
 				} else {
 					k = KeyPressEvent{Sym: KeyHome}
@@ -427,7 +682,7 @@ func parseCsi(b []byte) (int, Event) {
 			case 3:
 				k = KeyPressEvent{Sym: KeyDelete}
 			case 4:
-				if flags&FlagSelect != 0 {
+				if p.Flags&FlagSelect != 0 {
 					k = KeyPressEvent{Sym: KeySelect}
 				} else {
 					k = KeyPressEvent{Sym: KeyEnd}
@@ -452,11 +707,6 @@ func parseCsi(b []byte) (int, Event) {
 				k = KeyPressEvent{Sym: KeyF17 + KeySym(param-31)}
 			}
 
-			// modifiers
-			if paramsLen > 1 {
-				k.Mod |= KeyMod(csi.Param(1) - 1)
-			}
-
 			// Handle URxvt weird keys
 			switch cmd {
 			case '^':
@@ -465,6 +715,14 @@ func parseCsi(b []byte) (int, Event) {
 				k.Mod |= ModCtrl | ModShift
 			}
 
+			// modifiers
+			if paramsLen > 1 {
+				// CSI <number> ; <modifiers> ~
+				// CSI <number> ; <modifiers> : <event-type> ~
+				k.Mod |= KeyMod(csi.Param(1) - 1)
+				return i, kittyKeyEvent(Key(k), kittyEventType(&csi, 1))
+			}
+
 			return i, k
 		}
 	}
@@ -577,8 +835,9 @@ func parseOsc(b []byte) (int, Event) {
 		}
 	}
 
-	if i >= len(b) {
-		return i, UnknownEvent(b[:i])
+	if i >= len(b) || (b[i] == ansi.ESC && i+1 == len(b)) {
+		// The sequence is incomplete, possibly with a split ST.
+		return len(b), UnknownEvent(b)
 	}
 
 	end = i // end of the sequence data
@@ -589,29 +848,61 @@ func parseOsc(b []byte) (int, Event) {
 		i++
 	}
 
+	switch cmd {
+	case 110, 111, 112, 117, 119:
+		// Color reset notifications don't have any data.
+		return i, ColorResetEvent(cmd - 100)
+	case 10, 11, 12, 17, 19:
+		// Color reports without a valid color specification, like an
+		// empty reply, are reported apart, so that the color of the color
+		// events is never nil.
+		var c color.Color
+		if end > start {
+			c = xParseColor(string(b[start:end]))
+		}
+		switch {
+		case c == nil:
+			return i, MissingColorEvent(cmd)
+		case cmd == 10:
+			return i, ForegroundColorEvent{c}
+		case cmd == 11:
+			return i, BackgroundColorEvent{c}
+		case cmd == 12:
+			return i, CursorColorEvent{c}
+		case cmd == 17:
+			return i, HighlightBackgroundColorEvent{c}
+		default:
+			return i, HighlightForegroundColorEvent{c}
+		}
+	}
+
 	if end <= start {
 		return i, UnknownOscEvent(b[:i])
 	}
 
-	data := string(b[start:end])
+	data := b[start:end]
 	switch cmd {
-	case 10:
-		return i, ForegroundColorEvent{xParseColor(data)}
-	case 11:
-		return i, BackgroundColorEvent{xParseColor(data)}
-	case 12:
-		return i, CursorColorEvent{xParseColor(data)}
 	case 52:
-		parts := strings.Split(data, ";")
-		if len(parts) == 0 {
-			return i, ClipboardEvent("")
+		// The payload is the last field, after the selection.
+		if j := bytes.LastIndexByte(data, ';'); j >= 0 {
+			data = data[j+1:]
 		}
-		b64 := parts[len(parts)-1]
-		bts, err := base64.StdEncoding.DecodeString(b64)
+		bts := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+		n, err := base64.StdEncoding.Decode(bts, data)
 		if err != nil {
 			return i, ClipboardEvent("")
 		}
-		return i, ClipboardEvent(bts)
+		return i, ClipboardEvent(bts[:n])
+	case 133:
+		if e := parseSemanticPrompt(data); e != nil {
+			return i, e
+		}
+		return i, UnknownOscEvent(b[:i])
+	case 1337:
+		if e := parseITerm2(data); e != nil {
+			return i, e
+		}
+		return i, UnknownOscEvent(b[:i])
 	default:
 		return i, UnknownOscEvent(b[:i])
 	}
@@ -663,12 +954,28 @@ func parseStTerminated(intro8, intro7 byte) func([]byte) (int, Event) {
 	}
 }
 
-func parseDcs(b []byte) (int, Event) {
+// pixelMouse moves the coordinates of an SGR-Pixels mouse report to the
+// pixel fields and derives the cell coordinates from the cell size, if known.
+func (p *EventParser) pixelMouse(m *Mouse) {
+	m.PixelX, m.PixelY = m.X, m.Y
+	m.X, m.Y = 0, 0
+	if p.CellWidth > 0 && p.CellHeight > 0 {
+		m.X, m.Y = m.PixelX/p.CellWidth, m.PixelY/p.CellHeight
+	}
+}
+
+func (p *EventParser) parseDcs(b []byte) (int, Event) {
 	if len(b) == 2 && b[0] == ansi.ESC {
 		// short cut if this is an alt+P key
 		return 2, KeyPressEvent{Rune: rune(b[1]), Mod: ModAlt}
 	}
 
+	if p.UnwrapPassthrough {
+		if n, e := p.parsePassthrough(b); n > 0 {
+			return n, e
+		}
+	}
+
 	var params [16]int
 	var paramsLen int
 	var dcs ansi.DcsSequence
@@ -755,6 +1062,12 @@ func parseDcs(b []byte) (int, Event) {
 	switch cmd := dcs.Command(); cmd {
 	case 'r':
 		switch dcs.Intermediate() {
+		case '$':
+			// DECRPSS responses
+			switch param := dcs.Param(0); param {
+			case 0, 1:
+				return i, SettingReportEvent{Valid: param == 1, Setting: string(b[start:end])}
+			}
 		case '+':
 			// XTGETTCAP responses
 			switch param := dcs.Param(0); param {
@@ -771,11 +1084,98 @@ func parseDcs(b []byte) (int, Event) {
 				return i, tc
 			}
 		}
+	case '|':
+		if dcs.Marker() == '>' && dcs.Intermediate() == 0 {
+			// XTVERSION response
+			return i, TerminalVersionEvent(b[start:end])
+		}
 	}
 
 	return i, UnknownDcsEvent(b[:i])
 }
 
+// parsePassthrough unwraps tmux and GNU Screen passthrough sequences and
+// parses the wrapped sequences. It returns zero if b is not a passthrough
+// sequence.
+//
+//	DCS tmux ; <escaped-data> ST
+//	DCS <data> ST
+//
+// Where <escaped-data> has all ESC characters doubled. A GNU Screen
+// passthrough is a DCS sequence that wraps an escape sequence. The wrapped
+// sequence can't contain a ST since it would terminate the passthrough.
+func (p *EventParser) parsePassthrough(b []byte) (int, Event) {
+	var i int
+	if b[i] == ansi.DCS {
+		i++
+	} else if len(b) > 1 && b[i] == ansi.ESC && b[i+1] == 'P' {
+		i += 2
+	} else {
+		return 0, nil
+	}
+
+	const tmuxPrefix = "tmux;"
+	isTmux := bytes.HasPrefix(b[i:], []byte(tmuxPrefix))
+	if isTmux {
+		i += len(tmuxPrefix)
+	} else if i+1 >= len(b) || b[i] != ansi.ESC || b[i+1] == '\\' {
+		// Not a GNU Screen passthrough either.
+		return 0, nil
+	}
+
+	start, end := i, -1
+	for ; i < len(b)-1; i++ {
+		if b[i] != ansi.ESC {
+			continue
+		}
+		if b[i+1] == '\\' {
+			// End of the passthrough sequence.
+			end = i
+			break
+		}
+		if isTmux && b[i+1] == ansi.ESC {
+			// Skip the escaped ESC.
+			i++
+		}
+	}
+
+	if end < 0 {
+		// Incomplete sequence.
+		return len(b), UnknownDcsEvent(b)
+	}
+
+	n := end + 2
+	data := b[start:end]
+	if isTmux {
+		data = bytes.ReplaceAll(data, []byte{ansi.ESC, ansi.ESC}, []byte{ansi.ESC})
+	}
+
+	var events []Event
+	for len(data) > 0 {
+		w, e, _ := p.parseEvent(data)
+		if w == 0 {
+			break
+		}
+		switch e := e.(type) {
+		case nil:
+		case MultiEvent:
+			events = append(events, e...)
+		default:
+			events = append(events, e)
+		}
+		data = data[w:]
+	}
+
+	switch len(events) {
+	case 0:
+		return n, UnknownDcsEvent(b[:n])
+	case 1:
+		return n, events[0]
+	default:
+		return n, MultiEvent(events)
+	}
+}
+
 func parseApc(b []byte) (int, Event) {
 	if len(b) == 2 && b[0] == ansi.ESC {
 		// short cut if this is an alt+_ key
@@ -783,46 +1183,74 @@ func parseApc(b []byte) (int, Event) {
 	}
 
 	// APC sequences are introduced by APC (0x9f) or ESC _ (0x1b 0x5f)
-	return parseStTerminated(ansi.APC, '_')(b)
+	n, e := parseStTerminated(ansi.APC, '_')(b)
+	seq, ok := e.(UnknownApcEvent)
+	if !ok {
+		return n, e
+	}
+
+	// Kitty graphics replies, the data is between the introducer and the
+	// string terminator.
+	data := []byte(seq)
+	switch {
+	case bytes.HasSuffix(data, []byte{ansi.ESC, '\\'}):
+		data = data[:len(data)-2]
+	case len(data) > 0 && data[len(data)-1] == ansi.ST:
+		data = data[:len(data)-1]
+	default:
+		// The sequence is incomplete.
+		return n, e
+	}
+	if data[0] == ansi.ESC {
+		data = data[2:]
+	} else {
+		data = data[1:]
+	}
+	if len(data) > 0 && data[0] == 'G' {
+		if r := parseGraphicsReply(data[1:]); r != nil {
+			return n, r
+		}
+	}
+	return n, e
 }
 
-func parseUtf8(b []byte) (int, Event) {
+func (p *EventParser) parseUtf8(b []byte) (int, Event) {
 	r, rw := utf8.DecodeRune(b)
 	if r <= ansi.US || r == ansi.DEL || r == ansi.SP {
 		// Control codes get handled by parseControl
-		return 1, parseControl(byte(r))
+		return 1, p.parseControl(byte(r))
 	} else if r == utf8.RuneError {
 		return 1, UnknownEvent(b[0])
 	}
 	return rw, KeyPressEvent{Rune: r}
 }
 
-func parseControl(b byte) Event {
+func (p *EventParser) parseControl(b byte) Event {
 	switch b {
 	case ansi.NUL:
-		if flags&FlagCtrlAt != 0 {
+		if p.Flags&FlagCtrlAt != 0 {
 			return KeyPressEvent{Rune: '@', Mod: ModCtrl}
 		}
 		return KeyPressEvent{Rune: ' ', Sym: KeySpace, Mod: ModCtrl}
 	case ansi.BS:
 		return KeyPressEvent{Rune: 'h', Mod: ModCtrl}
 	case ansi.HT:
-		if flags&FlagCtrlI != 0 {
+		if p.Flags&FlagCtrlI != 0 {
 			return KeyPressEvent{Rune: 'i', Mod: ModCtrl}
 		}
 		return KeyPressEvent{Sym: KeyTab}
 	case ansi.CR:
-		if flags&FlagCtrlM != 0 {
+		if p.Flags&FlagCtrlM != 0 {
 			return KeyPressEvent{Rune: 'm', Mod: ModCtrl}
 		}
 		return KeyPressEvent{Sym: KeyEnter}
 	case ansi.ESC:
-		if flags&FlagCtrlOpenBracket != 0 {
+		if p.Flags&FlagCtrlOpenBracket != 0 {
 			return KeyPressEvent{Rune: '[', Mod: ModCtrl}
 		}
 		return KeyPressEvent{Sym: KeyEscape}
 	case ansi.DEL:
-		if flags&FlagBackspace != 0 {
+		if p.Flags&FlagBackspace != 0 {
 			return KeyPressEvent{Sym: KeyDelete}
 		}
 		return KeyPressEvent{Sym: KeyBackspace}
@@ -1,8 +1,13 @@
 package input
 
 import (
+	"encoding/base64"
 	"image/color"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestParseSequence_Events(t *testing.T) {
@@ -38,3 +43,161 @@ func BenchmarkParseSequence(b *testing.B) {
 		ParseSequence(input)
 	}
 }
+
+func BenchmarkParseSequenceKinds(b *testing.B) {
+	cases := []struct {
+		name string
+		seq  string
+	}{
+		{"csi key", "\x1b[1;5A"},
+		{"csi mouse", "\x1b[<0;120;40M"},
+		{"csi kitty", "\x1b[97:65;2;65u"},
+		{"csi unknown", "\x1b[" + strings.Repeat("1;", 15) + "Y"},
+		{"ss3", "\x1bO5P"},
+		{"osc color", "\x1b]11;rgb:1212/1212/1212\x07"},
+		{"osc52", "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 64*1024))) + "\x07"},
+		{"dcs", "\x1bP>|" + strings.Repeat("x", 4096) + "\x1b\\"},
+	}
+	for _, c := range cases {
+		input := []byte(c.seq)
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				ParseSequence(input)
+			}
+		})
+	}
+}
+
+func TestEventParserPassthrough(t *testing.T) {
+	cases := []struct {
+		name  string
+		seq   string
+		event Event
+	}{
+		{
+			name:  "tmux",
+			seq:   ansi.TmuxPassthrough("\x1b]11;rgb:1212/1212/1212\x07"),
+			event: BackgroundColorEvent{color.RGBA{R: 0x12, G: 0x12, B: 0x12, A: 0xff}},
+		},
+		{
+			name:  "tmux st terminated",
+			seq:   ansi.TmuxPassthrough("\x1b]11;rgb:1212/1212/1212\x1b\\"),
+			event: BackgroundColorEvent{color.RGBA{R: 0x12, G: 0x12, B: 0x12, A: 0xff}},
+		},
+		{
+			name: "tmux multiple",
+			seq:  ansi.TmuxPassthrough("\x1b[I\x1b[?1;2c"),
+			event: MultiEvent{
+				FocusEvent{},
+				PrimaryDeviceAttributesEvent{1, 2},
+			},
+		},
+		{
+			name:  "screen",
+			seq:   ansi.ScreenPassthrough("\x1b[?2004;1$y", 0),
+			event: ReportModeEvent{Mode: 2004, Value: 1},
+		},
+		{
+			name:  "regular dcs",
+			seq:   "\x1bP1+r5463\x1b\\",
+			event: TermcapEvent{Values: map[string]string{"Tc": ""}, IsValid: true},
+		},
+		{
+			name:  "incomplete",
+			seq:   "\x1bPtmux;\x1b\x1b[I",
+			event: UnknownDcsEvent("\x1bPtmux;\x1b\x1b[I"),
+		},
+	}
+
+	p := EventParser{UnwrapPassthrough: true}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n, e := p.Parse([]byte(tc.seq))
+			if n != len(tc.seq) {
+				t.Errorf("expected %d bytes consumed, got %d", len(tc.seq), n)
+			}
+			if !reflect.DeepEqual(e, tc.event) {
+				t.Errorf("expected %#v, got %#v", tc.event, e)
+			}
+		})
+	}
+}
+
+type testCustomEvent string
+
+func TestEventParserRegister(t *testing.T) {
+	var p EventParser
+	p.RegisterSequence("\x1b[99~", testCustomEvent("short"))
+	p.RegisterSequence("\x1b[99~x", testCustomEvent("long"))
+	p.RegisterCsiHandler('z', func(csi *ansi.CsiSequence) Event {
+		if csi.Marker() != '>' {
+			return nil
+		}
+		return testCustomEvent("z" + string(rune('0'+csi.Param(0))))
+	})
+	p.RegisterCsiHandler('A', func(*ansi.CsiSequence) Event { return nil })
+
+	cases := []struct {
+		seq   string
+		n     int
+		event Event
+	}{
+		{"\x1b[99~", 5, testCustomEvent("short")},
+		{"\x1b[99~xy", 6, testCustomEvent("long")},
+		{"\x1b[>3z", 5, testCustomEvent("z3")},
+		{"\x1b[3z", 4, UnknownCsiEvent("\x1b[3z")},
+		{"\x1b[A", 3, KeyPressEvent{Sym: KeyUp}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.seq, func(t *testing.T) {
+			n, e := p.Parse([]byte(tc.seq))
+			if n != tc.n {
+				t.Errorf("expected %d bytes consumed, got %d", tc.n, n)
+			}
+			if !reflect.DeepEqual(e, tc.event) {
+				t.Errorf("expected %#v, got %#v", tc.event, e)
+			}
+		})
+	}
+}
+
+func TestEventParserKeypad(t *testing.T) {
+	cases := []struct {
+		seq    string
+		keypad Event
+		folded Event
+	}{
+		{"\x1bOM", KeyPressEvent{Sym: KeyKpEnter}, KeyPressEvent{Sym: KeyEnter}},
+		{"\x1bOq", KeyPressEvent{Sym: KeyKp1}, KeyPressEvent{Rune: '1'}},
+		{"\x1bOk", KeyPressEvent{Sym: KeyKpPlus}, KeyPressEvent{Rune: '+'}},
+		{"\x1bO5n", KeyPressEvent{Sym: KeyKpDecimal, Mod: ModCtrl}, KeyPressEvent{Rune: '.', Mod: ModCtrl}},
+		{"\x1b\x1bOo", KeyPressEvent{Sym: KeyKpDivide, Mod: ModAlt}, KeyPressEvent{Rune: '/', Mod: ModAlt}},
+		{"\x1b[57414u", KeyPressEvent{Sym: KeyKpEnter}, KeyPressEvent{Sym: KeyEnter}},
+		{"\x1b[57419;1:3u", KeyReleaseEvent{Sym: KeyKpUp}, KeyReleaseEvent{Sym: KeyUp}},
+		{"\x1bOA", KeyPressEvent{Sym: KeyUp}, KeyPressEvent{Sym: KeyUp}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.seq, func(t *testing.T) {
+			var p EventParser
+			_, e := p.Parse([]byte(tc.seq))
+			if !reflect.DeepEqual(e, tc.keypad) {
+				t.Errorf("expected %#v, got %#v", tc.keypad, e)
+			}
+
+			p.Flags = FlagFoldKeypad
+			_, e = p.Parse([]byte(tc.seq))
+			if !reflect.DeepEqual(e, tc.folded) {
+				t.Errorf("expected folded %#v, got %#v", tc.folded, e)
+			}
+
+			table := buildKeysTable(FlagFoldKeypad, "", nil)
+			if k, ok := table[tc.seq]; ok {
+				if e, ok := tc.folded.(KeyPressEvent); ok && Key(e) != k {
+					t.Errorf("expected table key %#v, got %#v", e, k)
+				}
+			}
+		})
+	}
+}
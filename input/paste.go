@@ -1,12 +1,91 @@
 package input
 
+import "github.com/charmbracelet/x/ansi"
+
 // PasteEvent is an event that is emitted when a terminal receives pasted text
 // using bracketed-paste.
 type PasteEvent string
 
+// PasteChunkEvent is an event that is emitted when a terminal receives a
+// chunk of pasted text using bracketed-paste and paste streaming is enabled.
+// It holds the raw pasted bytes, which may split UTF-8 characters across
+// chunks.
+type PasteChunkEvent string
+
 // PasteStartEvent is an event that is emitted when a terminal enters
 // bracketed-paste mode.
 type PasteStartEvent struct{}
 
 // PasteEvent is an event that is emitted when a terminal receives pasted text.
 type PasteEndEvent struct{}
+
+// Paste sanitization flags. See [SanitizePaste].
+const (
+	// PasteStripControls removes C0 and C1 control characters, and DEL,
+	// except for tab and newline.
+	PasteStripControls = 1 << iota
+
+	// PasteStripEsc removes ESC characters. This neutralizes escape
+	// sequences embedded in the pasted text, which could otherwise end the
+	// paste early and inject input. It's implied by [PasteStripControls].
+	PasteStripEsc
+
+	// PasteNormalizeNewlines converts CRLF and CR line endings to LF.
+	PasteNormalizeNewlines
+
+	// PasteSanitizeAll enables all paste sanitization.
+	PasteSanitizeAll = PasteStripControls | PasteStripEsc | PasteNormalizeNewlines
+)
+
+// SanitizePaste returns the pasted text sanitized according to the given
+// flags. See the Paste* flags.
+func SanitizePaste(s string, flags int) string {
+	ps := pasteSanitizer{flags: flags}
+	return string(ps.sanitize([]byte(s)))
+}
+
+// pasteSanitizer sanitizes pasted data. It keeps track of line endings split
+// across chunks.
+type pasteSanitizer struct {
+	flags int
+	cr    bool // the last chunk ended with a CR
+}
+
+// sanitize sanitizes the given data in place and returns it.
+func (s *pasteSanitizer) sanitize(b []byte) []byte {
+	out := b[:0]
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		cr := s.cr
+		s.cr = false
+
+		if s.flags&PasteNormalizeNewlines != 0 {
+			switch c {
+			case '\r':
+				c = '\n'
+				s.cr = true
+			case '\n':
+				if cr {
+					// CRLF, the CR was already converted.
+					continue
+				}
+			}
+		}
+
+		switch {
+		case c == ansi.ESC && s.flags&(PasteStripEsc|PasteStripControls) != 0:
+			continue
+		case s.flags&PasteStripControls != 0:
+			if (c < ansi.SP && c != '\t' && c != '\n') || c == ansi.DEL {
+				continue
+			}
+			// C1 controls are encoded as 0xC2 0x80-0x9F.
+			if c == 0xc2 && i+1 < len(b) && b[i+1] >= 0x80 && b[i+1] <= 0x9f {
+				i++
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
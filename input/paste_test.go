@@ -0,0 +1,58 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSanitizePaste(t *testing.T) {
+	cases := []struct {
+		name   string
+		in     string
+		flags  int
+		expect string
+	}{
+		{"none", "a\x1b[31m\r\nb", 0, "a\x1b[31m\r\nb"},
+		{"strip esc", "a\x1b[31mb\x07", PasteStripEsc, "a[31mb\x07"},
+		{"strip controls", "a\tb\x00c\x07\x1b\x7fd\n\u0085\u009be", PasteStripControls, "a\tbcd\ne"},
+		{"keep unicode", "héllo 世界", PasteStripControls, "héllo 世界"},
+		{"normalize newlines", "a\r\nb\rc\nd\r\r\n", PasteNormalizeNewlines, "a\nb\nc\nd\n\n"},
+		{"all", "rm -rf /\r\x1b[201~\x1b[200~echo\r\n", PasteSanitizeAll, "rm -rf /\n[201~[200~echo\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := SanitizePaste(c.in, c.flags); got != c.expect {
+				t.Errorf("expected %q, got %q", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestPasteSanitizerChunks(t *testing.T) {
+	ps := pasteSanitizer{flags: PasteNormalizeNewlines}
+	var got []string
+	for _, chunk := range []string{"a\r", "\nb\r", "c"} {
+		got = append(got, string(ps.sanitize([]byte(chunk))))
+	}
+	if expect := []string{"a\n", "b\n", "c"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestDriverPasteSanitizer(t *testing.T) {
+	input := "\x1b[200~ls\r\x1b\x07\x1b[201~"
+	drv, err := New(strings.NewReader(input), WithTerm("dumb"), WithPasteSanitizer(PasteSanitizeAll))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 || events[1] != PasteEvent("ls\n") {
+		t.Errorf("expected sanitized paste, got %q", events)
+	}
+}
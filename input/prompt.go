@@ -0,0 +1,52 @@
+package input
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PromptStartEvent is reported when the shell marks the start of its prompt
+// with [ansi.PromptStart].
+type PromptStartEvent struct{}
+
+// CommandStartEvent is reported when the shell marks the end of its prompt,
+// and the start of the command typed by the user, with [ansi.CommandStart].
+type CommandStartEvent struct{}
+
+// CommandExecutedEvent is reported when the shell marks the start of the
+// output of a command with [ansi.CommandExecuted].
+type CommandExecutedEvent struct{}
+
+// CommandEndEvent is reported when the shell marks the end of the output of
+// a command with [ansi.CommandEnd].
+type CommandEndEvent struct {
+	// ExitCode is the exit code of the command, or -1 when the shell didn't
+	// report it, e.g. when the command was canceled.
+	ExitCode int
+}
+
+// parseSemanticPrompt parses the data of an OSC 133 semantic prompt
+// sequence. The options after the mark, like the prompt kind, are ignored.
+func parseSemanticPrompt(data []byte) Event {
+	fields := bytes.Split(data, []byte{';'})
+	if len(fields[0]) != 1 {
+		return nil
+	}
+	switch fields[0][0] {
+	case 'A':
+		return PromptStartEvent{}
+	case 'B':
+		return CommandStartEvent{}
+	case 'C':
+		return CommandExecutedEvent{}
+	case 'D':
+		e := CommandEndEvent{ExitCode: -1}
+		if len(fields) > 1 {
+			if code, err := strconv.Atoi(string(fields[1])); err == nil {
+				e.ExitCode = code
+			}
+		}
+		return e
+	}
+	return nil
+}
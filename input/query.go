@@ -0,0 +1,80 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNoOutput is returned when the driver has no output to write queries
+// to. See [WithOutput].
+var ErrNoOutput = errors.New("no output to write queries to")
+
+// Query writes the query seq to the driver output, see [WithOutput], and
+// waits for the reply, the first event match reports true for. Other events
+// read in the meantime are kept and delivered by the next reads. For
+// example, to get the cursor position:
+//
+//	e, err := drv.Query(ansi.RequestCursorPosition, func(e input.Event) bool {
+//		_, ok := e.(input.CursorPositionEvent)
+//		return ok
+//	}, time.Second)
+//
+// When the timeout expires, Query returns [os.ErrDeadlineExceeded]. The
+// terminal might not support the query, and its reply might still arrive,
+// and be delivered, later.
+//
+// Like [Driver.ReadEvents], Query must not be called once the event pump is
+// started.
+func (d *Driver) Query(seq string, match func(Event) bool, timeout time.Duration) (Event, error) {
+	var reply Event
+	err := d.query(seq, timeout, func(e Event) (bool, bool) {
+		if match(e) {
+			reply = e
+			return true, true
+		}
+		return false, false
+	})
+	return reply, err
+}
+
+// query writes seq to the driver output and reads events until reply
+// reports it's done, or the timeout expires. reply reports whether the
+// event is a reply, and whether it's the last one. Events that aren't
+// replies are queued.
+func (d *Driver) query(seq string, timeout time.Duration, reply func(Event) (ok, done bool)) error {
+	if d.out == nil {
+		return ErrNoOutput
+	}
+	if _, err := io.WriteString(d.out, seq); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var events []Event
+	for {
+		var err error
+		events, err = d.readEventsContext(ctx, events[:0])
+		for i, e := range events {
+			ok, done := reply(e)
+			if !ok {
+				d.queued = append(d.queued, e)
+				continue
+			}
+			if done {
+				d.queued = append(d.queued, events[i+1:]...)
+				return nil
+			}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return os.ErrDeadlineExceeded
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
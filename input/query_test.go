@@ -0,0 +1,95 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func isCursorPosition(e Event) bool {
+	_, ok := e.(CursorPositionEvent)
+	return ok
+}
+
+func TestDriverQuery(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithOutput(&replier{pw, "a\x1b[3;5Rb"}))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	e, err := drv.Query(ansi.RequestCursorPosition, isCursorPosition, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (CursorPositionEvent{Row: 3, Column: 5}); e != want {
+		t.Errorf("expected %v, got %v", want, e)
+	}
+
+	// The keys read while waiting are delivered in order.
+	var events []Event
+	for len(events) < 2 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, evs...)
+	}
+	want := []Event{KeyPressEvent{Rune: 'a'}, KeyPressEvent{Rune: 'b'}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("expected %v, got %v", want, events)
+	}
+}
+
+func TestDriverQueryTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	out := &replier{pw, "a"}
+	drv, err := New(pr, WithTerm("dumb"), WithOutput(out))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	e, err := drv.Query(ansi.RequestCursorPosition, isCursorPosition, 50*time.Millisecond)
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got %v", err)
+	}
+	if e != nil {
+		t.Errorf("expected no reply, got %v", e)
+	}
+
+	// A late reply is delivered like any other event, after the events
+	// queued by the query.
+	go io.WriteString(pw, "\x1b[2;1R") // nolint: errcheck
+	var events []Event
+	for len(events) < 2 {
+		evs, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, evs...)
+	}
+	want := []Event{KeyPressEvent{Rune: 'a'}, CursorPositionEvent{Row: 2, Column: 1}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("expected %v, got %v", want, events)
+	}
+}
+
+func TestDriverQueryNoOutput(t *testing.T) {
+	drv, err := NewDriver(strings.NewReader(""), "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	if _, err := drv.Query(ansi.RequestCursorPosition, isCursorPosition, time.Second); !errors.Is(err, ErrNoOutput) {
+		t.Errorf("expected ErrNoOutput, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package input
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// setupTerminal changes the mode of the terminal r reads from as asked by
+// the driver options, like [WithRawMode], and records its previous state.
+// It does nothing if r isn't a terminal, or if no change is needed.
+func (d *Driver) setupTerminal(r io.Reader) error {
+	if !d.raw && d.interrupt != InterruptKey && d.flowControl == FlowControlDefault {
+		return nil
+	}
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return nil
+	}
+
+	fd := f.Fd()
+	state, err := term.GetState(fd)
+	if err != nil {
+		return fmt.Errorf("get terminal mode: %w", err)
+	}
+	d.rawFd, d.rawState = fd, state
+
+	if d.raw {
+		if _, err := term.MakeRaw(fd); err != nil {
+			d.restoreMode() // nolint: errcheck
+			return fmt.Errorf("set raw mode: %w", err)
+		}
+	}
+	if d.interrupt == InterruptKey {
+		if err := disableSignalKeys(fd); err != nil {
+			d.restoreMode() // nolint: errcheck
+			return fmt.Errorf("disable signal keys: %w", err)
+		}
+	}
+	if d.flowControl != FlowControlDefault {
+		if err := setFlowControl(fd, d.flowControl == FlowControlTerminal); err != nil {
+			d.restoreMode() // nolint: errcheck
+			return fmt.Errorf("set flow control: %w", err)
+		}
+	}
+	return nil
+}
+
+// restoreMode restores the terminal state changed by setupTerminal, if any.
+func (d *Driver) restoreMode() error {
+	if d.rawState == nil {
+		return nil
+	}
+	state := d.rawState
+	d.rawState = nil
+	if err := term.Restore(d.rawFd, state); err != nil {
+		return fmt.Errorf("restore terminal mode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+//go:build linux
+// +build linux
+
+package input
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestDriverRawMode(t *testing.T) {
+	_, slave := openPty(t)
+	lflag := func() uint32 {
+		tio, err := unix.IoctlGetTermios(int(slave.Fd()), unix.TCGETS)
+		if err != nil {
+			t.Fatalf("could not get termios: %v", err)
+		}
+		return tio.Lflag
+	}
+
+	before := lflag()
+	if before&unix.ISIG == 0 {
+		t.Fatal("expected signal keys to be enabled")
+	}
+
+	drv, err := New(slave, WithTerm("dumb"), WithRawMode())
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	if lflag()&unix.ISIG != 0 {
+		t.Error("expected raw mode to disable signal keys")
+	}
+
+	if err := drv.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := lflag(); after != before {
+		t.Errorf("expected the terminal mode to be restored, got %#o, want %#o", after, before)
+	}
+}
+
+func TestDriverInterruptKey(t *testing.T) {
+	_, slave := openPty(t)
+	lflag := func() uint32 {
+		tio, err := unix.IoctlGetTermios(int(slave.Fd()), unix.TCGETS)
+		if err != nil {
+			t.Fatalf("could not get termios: %v", err)
+		}
+		return tio.Lflag
+	}
+
+	before := lflag()
+	drv, err := New(slave, WithTerm("dumb"), WithInterrupt(InterruptKey))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	after := lflag()
+	if after&unix.ISIG != 0 {
+		t.Error("expected signal keys to be disabled")
+	}
+	if after|unix.ISIG != before {
+		t.Errorf("expected only signal keys to change, got %#o, want %#o", after, before&^unix.ISIG)
+	}
+
+	if err := drv.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := lflag(); after != before {
+		t.Errorf("expected the terminal mode to be restored, got %#o, want %#o", after, before)
+	}
+}
+
+func TestDriverInterruptSignal(t *testing.T) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt)
+	defer signal.Stop(sigc)
+
+	drv, err := New(strings.NewReader("\x03a"), WithTerm("dumb"), WithInterrupt(InterruptSignal))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	events, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := []Event{KeyPressEvent{Rune: 'a'}}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %v, got %v", expect, events)
+	}
+	select {
+	case <-sigc:
+	case <-time.After(time.Second):
+		t.Error("expected an interrupt signal")
+	}
+}
+
+func TestDriverFlowControl(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []DriverOption
+		ixon bool
+	}{
+		{"key", []DriverOption{WithFlowControl(FlowControlKey)}, false},
+		{"terminal", []DriverOption{WithFlowControl(FlowControlTerminal)}, true},
+		{"raw", []DriverOption{WithRawMode()}, false},
+		{"raw terminal", []DriverOption{WithRawMode(), WithFlowControl(FlowControlTerminal)}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, slave := openPty(t)
+			iflag := func() uint32 {
+				tio, err := unix.IoctlGetTermios(int(slave.Fd()), unix.TCGETS)
+				if err != nil {
+					t.Fatalf("could not get termios: %v", err)
+				}
+				return tio.Iflag
+			}
+
+			before := iflag()
+			drv, err := New(slave, append([]DriverOption{WithTerm("dumb")}, tc.opts...)...)
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			if ixon := iflag()&unix.IXON != 0; ixon != tc.ixon {
+				t.Errorf("expected IXON to be %v, got %v", tc.ixon, ixon)
+			}
+
+			if err := drv.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if after := iflag(); after != before {
+				t.Errorf("expected the terminal mode to be restored, got %#o, want %#o", after, before)
+			}
+		})
+	}
+}
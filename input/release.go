@@ -0,0 +1,100 @@
+package input
+
+import (
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultKeyReleaseDelay is the default time after a key press at which a
+// synthesized key release is reported.
+const DefaultKeyReleaseDelay = 100 * time.Millisecond
+
+// keyReleaser synthesizes key release events for terminals that only report
+// key presses. See [WithSynthesizedKeyReleases].
+type keyReleaser struct {
+	delay time.Duration
+
+	// native reports whether the terminal reports key releases itself, in
+	// which case no releases are synthesized.
+	native bool
+
+	held []heldKey // held are the pressed keys, in press order.
+}
+
+// heldKey is a pressed key waiting to be released.
+type heldKey struct {
+	key Key
+	at  time.Time // at is when the key gets released.
+}
+
+// track returns the events with the synthesized key releases due at now
+// prepended, and records the key presses among them. Repeated presses of a
+// held key are reported as repeats, and push its release back.
+func (r *keyReleaser) track(events []Event, now time.Time) []Event {
+	out := r.expire(nil, now)
+	for _, e := range events {
+		switch e := e.(type) {
+		case KeyReleaseEvent:
+			r.native, r.held = true, nil
+		case KittyKeyboardEvent:
+			if e.Contains(ansi.KittyReportEventTypes) {
+				r.native, r.held = true, nil
+			}
+		case KeyPressEvent:
+			if r.native {
+				break
+			}
+			out = append(out, r.press(Key(e), now))
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// press records a key press and returns its event.
+func (r *keyReleaser) press(k Key, now time.Time) Event {
+	id := releaseKey(k)
+	for i, h := range r.held {
+		if h.key == id {
+			r.held[i].at = now.Add(r.delay)
+			k.IsRepeat = true
+			return KeyPressEvent(k)
+		}
+	}
+	r.held = append(r.held, heldKey{key: id, at: now.Add(r.delay)})
+	return KeyPressEvent(k)
+}
+
+// expire appends the release events due at now to events.
+func (r *keyReleaser) expire(events []Event, now time.Time) []Event {
+	held := r.held[:0]
+	for _, h := range r.held {
+		if now.Before(h.at) {
+			held = append(held, h)
+			continue
+		}
+		events = append(events, KeyReleaseEvent(h.key))
+	}
+	r.held = held
+	return events
+}
+
+// next returns when the next release is due, if any.
+func (r *keyReleaser) next() (at time.Time, ok bool) {
+	for _, h := range r.held {
+		if !ok || h.at.Before(at) {
+			at, ok = h.at, true
+		}
+	}
+	return at, ok
+}
+
+// releaseKey returns the key a release is reported for. Release events don't
+// carry text, and aren't repeats.
+func releaseKey(k Key) Key {
+	k.IsRepeat = false
+	k.Text = ""
+	return k
+}
@@ -0,0 +1,74 @@
+package input
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyReleaser(t *testing.T) {
+	r := keyReleaser{delay: 100 * time.Millisecond}
+	start := time.Now()
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	steps := []struct {
+		at     int
+		events []Event
+		expect []Event
+	}{
+		{0, []Event{KeyPressEvent{Rune: 'a', Text: "a"}}, []Event{KeyPressEvent{Rune: 'a', Text: "a"}}},
+		{50, []Event{KeyPressEvent{Rune: 'b'}}, []Event{KeyPressEvent{Rune: 'b'}}},
+		// A repeated press pushes the release back.
+		{80, []Event{KeyPressEvent{Rune: 'a', Text: "a"}}, []Event{KeyPressEvent{Rune: 'a', Text: "a", IsRepeat: true}}},
+		{150, nil, []Event{KeyReleaseEvent{Rune: 'b'}}},
+		{200, []Event{MouseClickEvent{}}, []Event{KeyReleaseEvent{Rune: 'a'}, MouseClickEvent{}}},
+		{210, nil, nil},
+	}
+	for i, s := range steps {
+		got := r.track(s.events, at(s.at))
+		if !reflect.DeepEqual(got, s.expect) {
+			t.Errorf("step %d: expected %#v, got %#v", i, s.expect, got)
+		}
+	}
+	if _, ok := r.next(); ok {
+		t.Errorf("expected no pending release")
+	}
+
+	// The terminal reports releases itself.
+	r.track([]Event{KeyPressEvent{Rune: 'c'}}, at(300))
+	got := r.track([]Event{KeyReleaseEvent{Rune: 'c'}, KeyPressEvent{Rune: 'd'}}, at(310))
+	expect := []Event{KeyReleaseEvent{Rune: 'c'}, KeyPressEvent{Rune: 'd'}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %#v, got %#v", expect, got)
+	}
+	if got := r.track(nil, at(1000)); len(got) != 0 {
+		t.Errorf("expected no synthesized releases, got %#v", got)
+	}
+}
+
+func TestDriverSynthesizedKeyReleases(t *testing.T) {
+	pr, pw := io.Pipe()
+	drv, err := New(pr, WithTerm("dumb"), WithSynthesizedKeyReleases(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+
+	go pw.Write([]byte("x")) // nolint: errcheck
+
+	var events []Event
+	for len(events) < 2 {
+		e, err := drv.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, e...)
+	}
+	expect := []Event{KeyPressEvent{Rune: 'x'}, KeyReleaseEvent{Rune: 'x'}}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("expected %#v, got %#v", expect, events)
+	}
+}
@@ -0,0 +1,72 @@
+package input
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SettingReportEvent is the reply of the terminal to a DECRQSS request, see
+// [ansi.RequestSetting]. It reports the current setting of a control
+// function.
+//
+//	DCS 1 $ r 0;1m ST
+//
+// See: https://vt100.net/docs/vt510-rm/DECRPSS.html
+type SettingReportEvent struct {
+	// Valid reports whether the terminal recognized the request.
+	Valid bool
+
+	// Setting is the control function with its current parameters, like
+	// "0;1m" for SGR, or "2 q" for DECSCUSR. It's empty when the request
+	// isn't valid.
+	Setting string
+}
+
+// Function returns the intermediate and final bytes of the control function,
+// like "m" for SGR, or " q" for DECSCUSR.
+func (e SettingReportEvent) Function() string {
+	i := len(e.Setting)
+	if i == 0 {
+		return ""
+	}
+	// The final byte, and the intermediate bytes before it.
+	for i--; i > 0 && e.Setting[i-1] >= 0x20 && e.Setting[i-1] <= 0x2F; i-- {
+	}
+	return e.Setting[i:]
+}
+
+// Params returns the parameters of the control function, like [0 1] for
+// "0;1m". Missing parameters are reported as -1. Sub-parameters, separated
+// by colons, are reported as separate parameters.
+func (e SettingReportEvent) Params() []int {
+	s := strings.TrimSuffix(e.Setting, e.Function())
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(strings.ReplaceAll(s, ":", ";"), ";")
+	params := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			v = -1
+		}
+		params[i] = v
+	}
+	return params
+}
+
+// CursorStyle returns the cursor shape, and whether it blinks, reported in
+// reply to [ansi.RequestCursorStyle]. It returns false when the event isn't
+// a valid cursor style report.
+func (e SettingReportEvent) CursorStyle() (shape ansi.CursorShape, blink bool, ok bool) {
+	if !e.Valid || e.Function() != " q" {
+		return 0, false, false
+	}
+	style := 0
+	if params := e.Params(); len(params) > 0 && params[0] > 0 {
+		style = params[0]
+	}
+	return ansi.ParseCursorStyle(style)
+}
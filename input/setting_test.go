@@ -0,0 +1,54 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSettingReportEvent(t *testing.T) {
+	cases := []struct {
+		setting string
+		fn      string
+		params  []int
+	}{
+		{"0;1m", "m", []int{0, 1}},
+		{"0;38:2::1:2:3m", "m", []int{0, 38, 2, -1, 1, 2, 3}},
+		{"2 q", " q", []int{2}},
+		{"1;24r", "r", []int{1, 24}},
+		{"65;1\"p", "\"p", []int{65, 1}},
+		{"m", "m", nil},
+		{"", "", nil},
+	}
+	for _, tc := range cases {
+		e := SettingReportEvent{Valid: true, Setting: tc.setting}
+		if fn := e.Function(); fn != tc.fn {
+			t.Errorf("%q: expected function %q, got %q", tc.setting, tc.fn, fn)
+		}
+		if params := e.Params(); !reflect.DeepEqual(params, tc.params) {
+			t.Errorf("%q: expected params %v, got %v", tc.setting, tc.params, params)
+		}
+	}
+}
+
+func TestSettingReportEventCursorStyle(t *testing.T) {
+	cases := []struct {
+		e     SettingReportEvent
+		shape ansi.CursorShape
+		blink bool
+		ok    bool
+	}{
+		{SettingReportEvent{Valid: true, Setting: "2 q"}, ansi.CursorBlock, false, true},
+		{SettingReportEvent{Valid: true, Setting: "5 q"}, ansi.CursorBar, true, true},
+		{SettingReportEvent{Valid: true, Setting: " q"}, ansi.CursorBlock, true, true},
+		{SettingReportEvent{Valid: true, Setting: "0;1m"}, 0, false, false},
+		{SettingReportEvent{}, 0, false, false},
+	}
+	for _, tc := range cases {
+		shape, blink, ok := tc.e.CursorStyle()
+		if shape != tc.shape || blink != tc.blink || ok != tc.ok {
+			t.Errorf("%q: expected %v %v %v, got %v %v %v", tc.e.Setting, tc.shape, tc.blink, tc.ok, shape, blink, ok)
+		}
+	}
+}
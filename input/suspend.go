@@ -0,0 +1,167 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/muesli/cancelreader"
+)
+
+// ErrSuspendNotSupported is returned by [Driver.Suspend] on platforms
+// without job control, like Windows.
+var ErrSuspendNotSupported = errors.New("suspend not supported on this platform")
+
+// SuspendEvent is reported instead of ctrl+z when [WithSuspendEvents] is
+// used. In raw mode, the terminal doesn't suspend the program on ctrl+z, it
+// sends it as input. Use [Driver.Suspend] to suspend the program like the
+// shell would.
+type SuspendEvent struct{}
+
+// isSuspendKey reports whether e is the ctrl+z key press.
+func isSuspendKey(e Event) bool {
+	k, ok := e.(KeyPressEvent)
+	return ok && k.Sym == KeyNone && k.Rune == 'z' && k.Mod == ModCtrl
+}
+
+// SetModes sets the sequences that enable and disable the terminal modes the
+// program uses, like mouse tracking, bracketed paste, and the Kitty keyboard
+// protocol. [Driver.Pause] writes disable to the terminal output, see
+// [WithOutput], and [Driver.Resume] writes enable. Set them again whenever
+// the program changes modes.
+//
+//	drv.SetModes(
+//		ansi.EnableMouseTracking(opts)+ansi.EnableBracketedPaste,
+//		ansi.DisableMouseTracking(opts, ansi.MouseOptions{})+ansi.DisableBracketedPaste,
+//	)
+func (d *Driver) SetModes(enable, disable string) {
+	d.mu.Lock()
+	d.enableModes, d.disableModes = enable, disable
+	d.mu.Unlock()
+}
+
+// Pause releases the terminal, so that another program, like an editor or
+// the shell, can use it. The driver stops reading input, disables the
+// terminal modes set with [Driver.SetModes], and restores the terminal mode
+// changed by [WithRawMode], [WithInterrupt], and [WithFlowControl]. Reads in
+// progress and new reads wait until the driver is resumed with
+// [Driver.Resume]. Pausing a paused driver has no effect.
+//
+// Input that arrives while the driver is paused is left for the other
+// program. Readers that can't be polled or interrupted, like in-memory
+// buffers, might still consume the input that arrives right after pausing.
+func (d *Driver) Pause() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.resumed != nil {
+		return nil
+	}
+
+	d.resumed = make(chan struct{})
+	d.rd.Cancel()
+	if !d.rdBusy {
+		d.rd.Close() // nolint: errcheck
+	}
+	// A reader in use is closed by releaseReader once the read is done.
+	d.rd = nil
+
+	err := d.writeModes(d.disableModes)
+	if rerr := d.restoreMode(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// Resume takes the terminal back after [Driver.Pause]. It puts the terminal
+// back into the mode set by [WithRawMode], [WithInterrupt], and
+// [WithFlowControl], enables the terminal modes set with [Driver.SetModes]
+// again, and resumes reading input. Resuming a driver that isn't paused has
+// no effect.
+func (d *Driver) Resume() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.resumed == nil {
+		return nil
+	}
+
+	if err := d.setupTerminal(d.input); err != nil {
+		return err
+	}
+	rd, err := newCancelreader(d.input)
+	if err != nil {
+		d.restoreMode() // nolint: errcheck
+		return err
+	}
+
+	d.rd = rd
+	close(d.resumed)
+	d.resumed = nil
+	return d.writeModes(d.enableModes)
+}
+
+// writeModes writes the terminal modes sequence seq to the output.
+func (d *Driver) writeModes(seq string) error {
+	if seq == "" || d.out == nil {
+		return nil
+	}
+	if _, err := io.WriteString(d.out, seq); err != nil {
+		return fmt.Errorf("set terminal modes: %w", err)
+	}
+	return nil
+}
+
+// readInput reads from the underlying reader into buf. When the driver is
+// paused, the read waits for it to resume and reads from the new reader.
+func (d *Driver) readInput(buf []byte) (int, error) {
+	for {
+		rd, err := d.acquireReader(context.Background())
+		if err != nil {
+			return 0, err
+		}
+		n, err := rd.Read(buf)
+		if d.releaseReader(rd) && n == 0 && errors.Is(err, cancelreader.ErrCanceled) {
+			continue
+		}
+		return n, err
+	}
+}
+
+// acquireReader returns the underlying reader to read from, once the driver
+// isn't paused. The reader must be released with releaseReader after
+// reading.
+func (d *Driver) acquireReader(ctx context.Context) (cancelreader.CancelReader, error) {
+	for {
+		d.mu.Lock()
+		rd, resumed := d.rd, d.resumed
+		d.rdBusy = resumed == nil
+		d.mu.Unlock()
+		if resumed == nil {
+			return rd, nil
+		}
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-d.done:
+			return nil, cancelreader.ErrCanceled
+		}
+	}
+}
+
+// releaseReader releases the reader rd returned by acquireReader. It
+// reports whether the driver was paused while reading, in which case a
+// canceled read should be tried again once it's resumed.
+func (d *Driver) releaseReader(rd cancelreader.CancelReader) bool {
+	d.mu.Lock()
+	d.rdBusy = false
+	paused := rd != d.rd
+	d.mu.Unlock()
+
+	if paused {
+		// Pause left the reader for us to close.
+		rd.Close() // nolint: errcheck
+	}
+	return paused
+}
@@ -0,0 +1,77 @@
+//go:build linux
+// +build linux
+
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestDriverPauseResume(t *testing.T) {
+	master, slave := openPty(t)
+	isig := func() bool {
+		tio, err := unix.IoctlGetTermios(int(slave.Fd()), unix.TCGETS)
+		if err != nil {
+			t.Fatalf("could not get termios: %v", err)
+		}
+		return tio.Lflag&unix.ISIG != 0
+	}
+
+	var out strings.Builder
+	drv, err := New(slave, WithTerm("dumb"), WithRawMode(), WithOutput(&out))
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer drv.Close()
+	drv.SetModes("\x1b[?1000h", "\x1b[?1000l")
+
+	events := make(chan []Event, 1)
+	go func() {
+		e, _ := drv.ReadEvents()
+		events <- e
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := drv.Pause(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isig() {
+		t.Error("expected pause to restore the terminal mode")
+	}
+	if out.String() != "\x1b[?1000l" {
+		t.Errorf("expected the modes to be disabled, got %q", out.String())
+	}
+
+	// Input that arrives while paused isn't read.
+	master.WriteString("a") // nolint: errcheck
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected events while paused: %v", e)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	out.Reset()
+	if err := drv.Resume(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isig() {
+		t.Error("expected resume to put the terminal into raw mode")
+	}
+	if out.String() != "\x1b[?1000h" {
+		t.Errorf("expected the modes to be enabled, got %q", out.String())
+	}
+
+	select {
+	case e := <-events:
+		if expect := []Event{KeyPressEvent{Rune: 'a'}}; !reflect.DeepEqual(e, expect) {
+			t.Errorf("expected %v, got %v", expect, e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the read to resume")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package input
+
+// Suspend suspends the program, like the terminal does when ctrl+z is
+// pressed outside of raw mode. Job control isn't supported on this
+// platform, and Suspend returns [ErrSuspendNotSupported].
+func (d *Driver) Suspend() error {
+	return ErrSuspendNotSupported
+}
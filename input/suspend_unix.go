@@ -0,0 +1,42 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package input
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Suspend suspends the program, like the terminal does when ctrl+z is
+// pressed outside of raw mode. It pauses the driver, see [Driver.Pause],
+// stops the process group with SIGTSTP, and resumes the driver once the
+// program is continued, usually by the shell fg command.
+//
+//	for _, e := range events {
+//		if _, ok := e.(input.SuspendEvent); ok {
+//			if err := drv.Suspend(); err != nil {
+//				return err
+//			}
+//			redraw()
+//		}
+//	}
+func (d *Driver) Suspend() error {
+	if err := d.Pause(); err != nil {
+		return err
+	}
+
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+	if err := unix.Kill(0, unix.SIGTSTP); err != nil {
+		d.Resume() // nolint: errcheck
+		return err
+	}
+	<-cont
+
+	return d.Resume()
+}
@@ -2,11 +2,21 @@ package input
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/x/ansi"
 )
 
-func buildKeysTable(flags int, term string) map[string]Key {
+func buildKeysTable(flags int, term string, src TerminfoSource) map[string]Key {
+	table, _ := buildKeysTableSources(flags, term, src)
+	return table
+}
+
+// buildKeysTableSources builds the key sequences table along with the
+// sources of the sequences that don't come from the VT100/VT200 defaults.
+func buildKeysTableSources(flags int, term string, src TerminfoSource) (map[string]Key, map[string]KeySource) {
+	sources := map[string]KeySource{}
+
 	nul := Key{Rune: ' ', Sym: KeySpace, Mod: ModCtrl} // ctrl+@ or ctrl+space
 	if flags&FlagCtrlAt != 0 {
 		nul = Key{Rune: '@', Mod: ModCtrl}
@@ -270,6 +280,37 @@ func buildKeysTable(flags int, term string) map[string]Key {
 	table["\x1b[33@"] = Key{Sym: KeyF19, Mod: ModShift | ModCtrl}
 	table["\x1b[34@"] = Key{Sym: KeyF20, Mod: ModShift | ModCtrl}
 
+	// URxvt only has 20 function keys, shift + F3-F10 generate F13-F20 and
+	// ctrl + shift + F3-F10 generate ctrl + F13-F20. When we know it's URxvt,
+	// report them as the keys that were pressed, unless F13-F20 are asked
+	// for.
+	family := termFamilyOf(term)
+	if flags&FlagFKeys == 0 && family == termFamilyRxvt {
+		for i, n := range []string{"25", "26", "28", "29", "31", "32", "33", "34"} {
+			key := Key{Sym: KeyF3 + KeySym(i), Mod: ModShift}
+			table["\x1b["+n+"~"] = key
+			key.Mod |= ModCtrl
+			table["\x1b["+n+"^"] = key
+		}
+	}
+
+	// Linux console keys
+	// The console sends F1-F5 as CSI [ A-E, and shift + F1-F8 as F13-F20.
+	// Other modifiers aren't reported, except alt, which prefixes keys with
+	// ESC like everywhere else.
+	//
+	// See https://man7.org/linux/man-pages/man4/console_codes.4.html
+	if family == termFamilyLinux {
+		for i, c := range "ABCDE" {
+			table["\x1b[["+string(c)] = Key{Sym: KeyF1 + KeySym(i)}
+		}
+		if flags&FlagFKeys == 0 {
+			for i, n := range []string{"25", "26", "28", "29", "31", "32", "33", "34"} {
+				table["\x1b["+n+"~"] = Key{Sym: KeyF1 + KeySym(i), Mod: ModShift}
+			}
+		}
+	}
+
 	// Register Alt + <key> combinations
 	// XXX: this must come after URxvt but before XTerm keys to register URxvt
 	// keys with alt modifier
@@ -350,6 +391,7 @@ func buildKeysTable(flags int, term string) map[string]Key {
 			key := v
 			key.Mod = m
 			table[seq] = key
+			sources[seq] = KeySourceXTerm
 		}
 		// SS3 <modifier> <func>
 		for k, v := range ss3FuncKeys {
@@ -357,6 +399,7 @@ func buildKeysTable(flags int, term string) map[string]Key {
 			key := v
 			key.Mod = m
 			table[seq] = key
+			sources[seq] = KeySourceXTerm
 		}
 		//  CSI <number> ; <modifier> ~
 		for k, v := range csiTildeKeys {
@@ -364,6 +407,7 @@ func buildKeysTable(flags int, term string) map[string]Key {
 			key := v
 			key.Mod = m
 			table[seq] = key
+			sources[seq] = KeySourceXTerm
 		}
 		// CSI 27 ; <modifier> ; <code> ~
 		for k, v := range modifyOtherKeys {
@@ -372,17 +416,47 @@ func buildKeysTable(flags int, term string) map[string]Key {
 			key := v
 			key.Mod = m
 			table[seq] = key
+			sources[seq] = KeySourceXTerm
 		}
 	}
 
 	// Register terminfo keys
 	// XXX: this might override keys already registered in table
 	if flags&FlagTerminfo != 0 {
-		titable := buildTerminfoKeys(flags, term)
+		titable := buildTerminfoKeys(flags, term, src)
 		for seq, key := range titable {
 			table[seq] = key
+			sources[seq] = KeySourceTerminfo
 		}
 	}
 
-	return table
+	if flags&FlagFoldKeypad != 0 {
+		for seq, key := range table {
+			table[seq] = foldKeypad(key)
+		}
+	}
+
+	return table, sources
+}
+
+// termFamily is a family of terminals that encode keys the same way.
+type termFamily int
+
+// Terminal families.
+const (
+	termFamilyOther termFamily = iota
+	termFamilyRxvt
+	termFamilyLinux
+)
+
+// termFamilyOf returns the family of the terminal with the given name, as
+// in the TERM environment variable.
+func termFamilyOf(term string) termFamily {
+	switch {
+	case strings.HasPrefix(term, "rxvt"):
+		return termFamilyRxvt
+	case term == "linux" || strings.HasPrefix(term, "linux-"):
+		return termFamilyLinux
+	}
+	return termFamilyOther
 }
@@ -1,39 +1,159 @@
 package input
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/xo/terminfo"
 )
 
-func buildTerminfoKeys(flags int, term string) map[string]Key {
-	table := make(map[string]Key)
-	ti, _ := terminfo.Load(term)
-	if ti == nil {
-		return table
+// ErrTerminfoNotFound is returned by a [TerminfoSource] when it doesn't have
+// an entry for the requested terminal.
+var ErrTerminfoNotFound = errors.New("terminfo entry not found")
+
+// TerminfoSource is a source of Terminfo entries.
+type TerminfoSource interface {
+	// StringCaps returns the string capabilities of the given terminal
+	// keyed by their short names, e.g. "kcuu1". It returns
+	// [ErrTerminfoNotFound] if the terminal is unknown.
+	StringCaps(term string) (map[string]string, error)
+}
+
+// TerminfoSourceFunc is a function that implements [TerminfoSource].
+type TerminfoSourceFunc func(term string) (map[string]string, error)
+
+// StringCaps implements [TerminfoSource].
+func (f TerminfoSourceFunc) StringCaps(term string) (map[string]string, error) {
+	return f(term)
+}
+
+// SystemTerminfo returns a [TerminfoSource] that reads compiled entries from
+// the system Terminfo database.
+//
+// It follows terminfo(5) and searches $TERMINFO, $HOME/.terminfo,
+// $TERMINFO_DIRS, and the default system directories, in that order. An
+// empty entry in $TERMINFO_DIRS stands for the default system directories.
+func SystemTerminfo() TerminfoSource {
+	return TerminfoSourceFunc(loadSystemTerminfo)
+}
+
+// terminfoDirs are the default system Terminfo directories.
+var terminfoDirs = []string{"/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo"}
+
+func loadSystemTerminfo(term string) (map[string]string, error) {
+	if term == "" {
+		return nil, ErrTerminfoNotFound
 	}
 
-	tiTable := defaultTerminfoKeys(flags)
+	var dirs []string
+	if dir := os.Getenv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	if env := os.Getenv("TERMINFO_DIRS"); env != "" {
+		for _, dir := range filepath.SplitList(env) {
+			if dir == "" {
+				dirs = append(dirs, terminfoDirs...)
+			} else {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	dirs = append(dirs, terminfoDirs...)
 
-	// Default keys
-	for name, seq := range ti.StringCapsShort() {
-		if !strings.HasPrefix(name, "k") || len(seq) == 0 {
-			continue
+	for _, dir := range dirs {
+		ti, err := terminfo.Open(dir, term)
+		if err == nil {
+			caps := make(map[string]string)
+			for name, seq := range ti.StringCapsShort() {
+				caps[name] = string(seq)
+			}
+			for name, seq := range ti.ExtStringCapsShort() {
+				caps[name] = string(seq)
+			}
+			return caps, nil
+		}
+		if err != terminfo.ErrFileNotFound && !os.IsNotExist(err) {
+			return nil, err
 		}
+	}
 
-		if k, ok := tiTable[name]; ok {
-			table[string(seq)] = k
+	return nil, ErrTerminfoNotFound
+}
+
+// EmbeddedTerminfo returns a [TerminfoSource] backed by a minimal built-in
+// database. It covers the key capabilities of xterm, tmux, screen, and the
+// Linux console, and their 256 color variants. Use it when the system
+// database might not be available, like in containers and static binaries.
+// Each call to StringCaps returns a new map.
+func EmbeddedTerminfo() TerminfoSource {
+	return TerminfoSourceFunc(func(term string) (map[string]string, error) {
+		entry, ok := embeddedTerminfo[term]
+		if !ok {
+			return nil, ErrTerminfoNotFound
+		}
+		caps := make(map[string]string, len(entry))
+		for name, seq := range entry {
+			caps[name] = seq
 		}
+		return caps, nil
+	})
+}
+
+// ChainTerminfo returns a [TerminfoSource] that tries each of the given
+// sources in order and returns the first entry found.
+func ChainTerminfo(sources ...TerminfoSource) TerminfoSource {
+	return TerminfoSourceFunc(func(term string) (map[string]string, error) {
+		for _, src := range sources {
+			caps, err := src.StringCaps(term)
+			if err == nil {
+				return caps, nil
+			}
+			if !errors.Is(err, ErrTerminfoNotFound) {
+				return nil, err
+			}
+		}
+		return nil, ErrTerminfoNotFound
+	})
+}
+
+// DefaultTerminfo returns the default [TerminfoSource]. It reads the system
+// database and falls back to the embedded one.
+func DefaultTerminfo() TerminfoSource {
+	return ChainTerminfo(SystemTerminfo(), EmbeddedTerminfo())
+}
+
+func buildTerminfoKeys(flags int, term string, src TerminfoSource) map[string]Key {
+	table := make(map[string]Key)
+	if src == nil {
+		src = DefaultTerminfo()
 	}
 
-	// Extended keys
-	for name, seq := range ti.ExtStringCapsShort() {
+	caps, _ := src.StringCaps(term)
+	if caps == nil {
+		return table
+	}
+
+	tiTable := defaultTerminfoKeys(flags)
+	if flags&FlagFKeys == 0 && termFamilyOf(term) == termFamilyRxvt {
+		// URxvt F13-F20 are shift + F3-F10, not shift + F1-F8 like in
+		// XTerm, see buildKeysTableSources.
+		for i := 0; i < 8; i++ {
+			tiTable["kf"+strconv.Itoa(13+i)] = Key{Sym: KeyF3 + KeySym(i), Mod: ModShift}
+		}
+	}
+	for name, seq := range caps {
 		if !strings.HasPrefix(name, "k") || len(seq) == 0 {
 			continue
 		}
 
 		if k, ok := tiTable[name]; ok {
-			table[string(seq)] = k
+			table[seq] = k
 		}
 	}
 
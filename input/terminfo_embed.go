@@ -0,0 +1,356 @@
+package input
+
+// embeddedTerminfo is a minimal terminfo database that holds the key
+// capabilities of common terminals. It is used when the system terminfo
+// database is not available.
+//
+// The capabilities were extracted from the ncurses terminfo database using
+// infocmp -1 -x, and only include the keys we know about, see
+// [defaultTerminfoKeys].
+var embeddedTerminfo = map[string]map[string]string{
+	"xterm":           xtermTerminfoKeys,
+	"xterm-256color":  xtermTerminfoKeys,
+	"tmux":            tmuxTerminfoKeys,
+	"tmux-256color":   tmuxTerminfoKeys,
+	"screen":          screenTerminfoKeys,
+	"screen-256color": screenTerminfoKeys,
+	"linux":           linuxTerminfoKeys,
+}
+
+var xtermTerminfoKeys = map[string]string{
+	"kDC":   "\x1b[3;2~",
+	"kDC3":  "\x1b[3;3~",
+	"kDC4":  "\x1b[3;4~",
+	"kDC5":  "\x1b[3;5~",
+	"kDC6":  "\x1b[3;6~",
+	"kDC7":  "\x1b[3;7~",
+	"kDN":   "\x1b[1;2B",
+	"kDN3":  "\x1b[1;3B",
+	"kDN4":  "\x1b[1;4B",
+	"kDN5":  "\x1b[1;5B",
+	"kDN6":  "\x1b[1;6B",
+	"kDN7":  "\x1b[1;7B",
+	"kEND":  "\x1b[1;2F",
+	"kEND3": "\x1b[1;3F",
+	"kEND4": "\x1b[1;4F",
+	"kEND5": "\x1b[1;5F",
+	"kEND6": "\x1b[1;6F",
+	"kEND7": "\x1b[1;7F",
+	"kHOM":  "\x1b[1;2H",
+	"kHOM3": "\x1b[1;3H",
+	"kHOM4": "\x1b[1;4H",
+	"kHOM5": "\x1b[1;5H",
+	"kHOM6": "\x1b[1;6H",
+	"kHOM7": "\x1b[1;7H",
+	"kIC":   "\x1b[2;2~",
+	"kIC3":  "\x1b[2;3~",
+	"kIC4":  "\x1b[2;4~",
+	"kIC5":  "\x1b[2;5~",
+	"kIC6":  "\x1b[2;6~",
+	"kIC7":  "\x1b[2;7~",
+	"kLFT":  "\x1b[1;2D",
+	"kLFT3": "\x1b[1;3D",
+	"kLFT4": "\x1b[1;4D",
+	"kLFT5": "\x1b[1;5D",
+	"kLFT6": "\x1b[1;6D",
+	"kLFT7": "\x1b[1;7D",
+	"kNXT":  "\x1b[6;2~",
+	"kNXT3": "\x1b[6;3~",
+	"kNXT4": "\x1b[6;4~",
+	"kNXT5": "\x1b[6;5~",
+	"kNXT6": "\x1b[6;6~",
+	"kNXT7": "\x1b[6;7~",
+	"kPRV":  "\x1b[5;2~",
+	"kPRV3": "\x1b[5;3~",
+	"kPRV4": "\x1b[5;4~",
+	"kPRV5": "\x1b[5;5~",
+	"kPRV6": "\x1b[5;6~",
+	"kPRV7": "\x1b[5;7~",
+	"kRIT":  "\x1b[1;2C",
+	"kRIT3": "\x1b[1;3C",
+	"kRIT4": "\x1b[1;4C",
+	"kRIT5": "\x1b[1;5C",
+	"kRIT6": "\x1b[1;6C",
+	"kRIT7": "\x1b[1;7C",
+	"kUP":   "\x1b[1;2A",
+	"kUP3":  "\x1b[1;3A",
+	"kUP4":  "\x1b[1;4A",
+	"kUP5":  "\x1b[1;5A",
+	"kUP6":  "\x1b[1;6A",
+	"kUP7":  "\x1b[1;7A",
+	"kbs":   "\x7f",
+	"kcbt":  "\x1b[Z",
+	"kcub1": "\x1bOD",
+	"kcud1": "\x1bOB",
+	"kcuf1": "\x1bOC",
+	"kcuu1": "\x1bOA",
+	"kdch1": "\x1b[3~",
+	"kend":  "\x1bOF",
+	"kf1":   "\x1bOP",
+	"kf2":   "\x1bOQ",
+	"kf3":   "\x1bOR",
+	"kf4":   "\x1bOS",
+	"kf5":   "\x1b[15~",
+	"kf6":   "\x1b[17~",
+	"kf7":   "\x1b[18~",
+	"kf8":   "\x1b[19~",
+	"kf9":   "\x1b[20~",
+	"kf10":  "\x1b[21~",
+	"kf11":  "\x1b[23~",
+	"kf12":  "\x1b[24~",
+	"kf13":  "\x1b[1;2P",
+	"kf14":  "\x1b[1;2Q",
+	"kf15":  "\x1b[1;2R",
+	"kf16":  "\x1b[1;2S",
+	"kf17":  "\x1b[15;2~",
+	"kf18":  "\x1b[17;2~",
+	"kf19":  "\x1b[18;2~",
+	"kf20":  "\x1b[19;2~",
+	"kf21":  "\x1b[20;2~",
+	"kf22":  "\x1b[21;2~",
+	"kf23":  "\x1b[23;2~",
+	"kf24":  "\x1b[24;2~",
+	"kf25":  "\x1b[1;5P",
+	"kf26":  "\x1b[1;5Q",
+	"kf27":  "\x1b[1;5R",
+	"kf28":  "\x1b[1;5S",
+	"kf29":  "\x1b[15;5~",
+	"kf30":  "\x1b[17;5~",
+	"kf31":  "\x1b[18;5~",
+	"kf32":  "\x1b[19;5~",
+	"kf33":  "\x1b[20;5~",
+	"kf34":  "\x1b[21;5~",
+	"kf35":  "\x1b[23;5~",
+	"kf36":  "\x1b[24;5~",
+	"kf37":  "\x1b[1;6P",
+	"kf38":  "\x1b[1;6Q",
+	"kf39":  "\x1b[1;6R",
+	"kf40":  "\x1b[1;6S",
+	"kf41":  "\x1b[15;6~",
+	"kf42":  "\x1b[17;6~",
+	"kf43":  "\x1b[18;6~",
+	"kf44":  "\x1b[19;6~",
+	"kf45":  "\x1b[20;6~",
+	"kf46":  "\x1b[21;6~",
+	"kf47":  "\x1b[23;6~",
+	"kf48":  "\x1b[24;6~",
+	"kf49":  "\x1b[1;3P",
+	"kf50":  "\x1b[1;3Q",
+	"kf51":  "\x1b[1;3R",
+	"kf52":  "\x1b[1;3S",
+	"kf53":  "\x1b[15;3~",
+	"kf54":  "\x1b[17;3~",
+	"kf55":  "\x1b[18;3~",
+	"kf56":  "\x1b[19;3~",
+	"kf57":  "\x1b[20;3~",
+	"kf58":  "\x1b[21;3~",
+	"kf59":  "\x1b[23;3~",
+	"kf60":  "\x1b[24;3~",
+	"kf61":  "\x1b[1;4P",
+	"kf62":  "\x1b[1;4Q",
+	"kf63":  "\x1b[1;4R",
+	"khome": "\x1bOH",
+	"kich1": "\x1b[2~",
+	"knp":   "\x1b[6~",
+	"kpp":   "\x1b[5~",
+}
+
+var tmuxTerminfoKeys = map[string]string{
+	"kDC":   "\x1b[3;2~",
+	"kDC3":  "\x1b[3;3~",
+	"kDC4":  "\x1b[3;4~",
+	"kDC5":  "\x1b[3;5~",
+	"kDC6":  "\x1b[3;6~",
+	"kDC7":  "\x1b[3;7~",
+	"kDN":   "\x1b[1;2B",
+	"kDN3":  "\x1b[1;3B",
+	"kDN4":  "\x1b[1;4B",
+	"kDN5":  "\x1b[1;5B",
+	"kDN6":  "\x1b[1;6B",
+	"kDN7":  "\x1b[1;7B",
+	"kEND":  "\x1b[1;2F",
+	"kEND3": "\x1b[1;3F",
+	"kEND4": "\x1b[1;4F",
+	"kEND5": "\x1b[1;5F",
+	"kEND6": "\x1b[1;6F",
+	"kEND7": "\x1b[1;7F",
+	"kHOM":  "\x1b[1;2H",
+	"kHOM3": "\x1b[1;3H",
+	"kHOM4": "\x1b[1;4H",
+	"kHOM5": "\x1b[1;5H",
+	"kHOM6": "\x1b[1;6H",
+	"kHOM7": "\x1b[1;7H",
+	"kIC":   "\x1b[2;2~",
+	"kIC3":  "\x1b[2;3~",
+	"kIC4":  "\x1b[2;4~",
+	"kIC5":  "\x1b[2;5~",
+	"kIC6":  "\x1b[2;6~",
+	"kIC7":  "\x1b[2;7~",
+	"kLFT":  "\x1b[1;2D",
+	"kLFT3": "\x1b[1;3D",
+	"kLFT4": "\x1b[1;4D",
+	"kLFT5": "\x1b[1;5D",
+	"kLFT6": "\x1b[1;6D",
+	"kLFT7": "\x1b[1;7D",
+	"kNXT":  "\x1b[6;2~",
+	"kNXT3": "\x1b[6;3~",
+	"kNXT4": "\x1b[6;4~",
+	"kNXT5": "\x1b[6;5~",
+	"kNXT6": "\x1b[6;6~",
+	"kNXT7": "\x1b[6;7~",
+	"kPRV":  "\x1b[5;2~",
+	"kPRV3": "\x1b[5;3~",
+	"kPRV4": "\x1b[5;4~",
+	"kPRV5": "\x1b[5;5~",
+	"kPRV6": "\x1b[5;6~",
+	"kPRV7": "\x1b[5;7~",
+	"kRIT":  "\x1b[1;2C",
+	"kRIT3": "\x1b[1;3C",
+	"kRIT4": "\x1b[1;4C",
+	"kRIT5": "\x1b[1;5C",
+	"kRIT6": "\x1b[1;6C",
+	"kRIT7": "\x1b[1;7C",
+	"kUP":   "\x1b[1;2A",
+	"kUP3":  "\x1b[1;3A",
+	"kUP4":  "\x1b[1;4A",
+	"kUP5":  "\x1b[1;5A",
+	"kUP6":  "\x1b[1;6A",
+	"kUP7":  "\x1b[1;7A",
+	"kbs":   "\x7f",
+	"kcbt":  "\x1b[Z",
+	"kcub1": "\x1bOD",
+	"kcud1": "\x1bOB",
+	"kcuf1": "\x1bOC",
+	"kcuu1": "\x1bOA",
+	"kdch1": "\x1b[3~",
+	"kend":  "\x1b[4~",
+	"kf1":   "\x1bOP",
+	"kf2":   "\x1bOQ",
+	"kf3":   "\x1bOR",
+	"kf4":   "\x1bOS",
+	"kf5":   "\x1b[15~",
+	"kf6":   "\x1b[17~",
+	"kf7":   "\x1b[18~",
+	"kf8":   "\x1b[19~",
+	"kf9":   "\x1b[20~",
+	"kf10":  "\x1b[21~",
+	"kf11":  "\x1b[23~",
+	"kf12":  "\x1b[24~",
+	"kf13":  "\x1b[1;2P",
+	"kf14":  "\x1b[1;2Q",
+	"kf15":  "\x1b[1;2R",
+	"kf16":  "\x1b[1;2S",
+	"kf17":  "\x1b[15;2~",
+	"kf18":  "\x1b[17;2~",
+	"kf19":  "\x1b[18;2~",
+	"kf20":  "\x1b[19;2~",
+	"kf21":  "\x1b[20;2~",
+	"kf22":  "\x1b[21;2~",
+	"kf23":  "\x1b[23;2~",
+	"kf24":  "\x1b[24;2~",
+	"kf25":  "\x1b[1;5P",
+	"kf26":  "\x1b[1;5Q",
+	"kf27":  "\x1b[1;5R",
+	"kf28":  "\x1b[1;5S",
+	"kf29":  "\x1b[15;5~",
+	"kf30":  "\x1b[17;5~",
+	"kf31":  "\x1b[18;5~",
+	"kf32":  "\x1b[19;5~",
+	"kf33":  "\x1b[20;5~",
+	"kf34":  "\x1b[21;5~",
+	"kf35":  "\x1b[23;5~",
+	"kf36":  "\x1b[24;5~",
+	"kf37":  "\x1b[1;6P",
+	"kf38":  "\x1b[1;6Q",
+	"kf39":  "\x1b[1;6R",
+	"kf40":  "\x1b[1;6S",
+	"kf41":  "\x1b[15;6~",
+	"kf42":  "\x1b[17;6~",
+	"kf43":  "\x1b[18;6~",
+	"kf44":  "\x1b[19;6~",
+	"kf45":  "\x1b[20;6~",
+	"kf46":  "\x1b[21;6~",
+	"kf47":  "\x1b[23;6~",
+	"kf48":  "\x1b[24;6~",
+	"kf49":  "\x1b[1;3P",
+	"kf50":  "\x1b[1;3Q",
+	"kf51":  "\x1b[1;3R",
+	"kf52":  "\x1b[1;3S",
+	"kf53":  "\x1b[15;3~",
+	"kf54":  "\x1b[17;3~",
+	"kf55":  "\x1b[18;3~",
+	"kf56":  "\x1b[19;3~",
+	"kf57":  "\x1b[20;3~",
+	"kf58":  "\x1b[21;3~",
+	"kf59":  "\x1b[23;3~",
+	"kf60":  "\x1b[24;3~",
+	"kf61":  "\x1b[1;4P",
+	"kf62":  "\x1b[1;4Q",
+	"kf63":  "\x1b[1;4R",
+	"khome": "\x1b[1~",
+	"kich1": "\x1b[2~",
+	"knp":   "\x1b[6~",
+	"kpp":   "\x1b[5~",
+}
+
+var screenTerminfoKeys = map[string]string{
+	"kbs":   "\x7f",
+	"kcbt":  "\x1b[Z",
+	"kcub1": "\x1bOD",
+	"kcud1": "\x1bOB",
+	"kcuf1": "\x1bOC",
+	"kcuu1": "\x1bOA",
+	"kdch1": "\x1b[3~",
+	"kend":  "\x1b[4~",
+	"kf1":   "\x1bOP",
+	"kf2":   "\x1bOQ",
+	"kf3":   "\x1bOR",
+	"kf4":   "\x1bOS",
+	"kf5":   "\x1b[15~",
+	"kf6":   "\x1b[17~",
+	"kf7":   "\x1b[18~",
+	"kf8":   "\x1b[19~",
+	"kf9":   "\x1b[20~",
+	"kf10":  "\x1b[21~",
+	"kf11":  "\x1b[23~",
+	"kf12":  "\x1b[24~",
+	"khome": "\x1b[1~",
+	"kich1": "\x1b[2~",
+	"knp":   "\x1b[6~",
+	"kpp":   "\x1b[5~",
+}
+
+var linuxTerminfoKeys = map[string]string{
+	"kbs":   "\x7f",
+	"kcbt":  "\x1b\x09",
+	"kcub1": "\x1b[D",
+	"kcud1": "\x1b[B",
+	"kcuf1": "\x1b[C",
+	"kcuu1": "\x1b[A",
+	"kdch1": "\x1b[3~",
+	"kend":  "\x1b[4~",
+	"kf1":   "\x1b[[A",
+	"kf2":   "\x1b[[B",
+	"kf3":   "\x1b[[C",
+	"kf4":   "\x1b[[D",
+	"kf5":   "\x1b[[E",
+	"kf6":   "\x1b[17~",
+	"kf7":   "\x1b[18~",
+	"kf8":   "\x1b[19~",
+	"kf9":   "\x1b[20~",
+	"kf10":  "\x1b[21~",
+	"kf11":  "\x1b[23~",
+	"kf12":  "\x1b[24~",
+	"kf13":  "\x1b[25~",
+	"kf14":  "\x1b[26~",
+	"kf15":  "\x1b[28~",
+	"kf16":  "\x1b[29~",
+	"kf17":  "\x1b[31~",
+	"kf18":  "\x1b[32~",
+	"kf19":  "\x1b[33~",
+	"kf20":  "\x1b[34~",
+	"khome": "\x1b[1~",
+	"kich1": "\x1b[2~",
+	"knp":   "\x1b[6~",
+	"kpp":   "\x1b[5~",
+}
@@ -0,0 +1,122 @@
+package input
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedTerminfo(t *testing.T) {
+	src := EmbeddedTerminfo()
+	for _, term := range []string{"xterm", "xterm-256color", "tmux", "tmux-256color", "screen", "screen-256color", "linux"} {
+		caps, err := src.StringCaps(term)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", term, err)
+		}
+		if caps["kcuu1"] == "" {
+			t.Errorf("%s: expected kcuu1 capability", term)
+		}
+	}
+
+	if _, err := src.StringCaps("unknown"); !errors.Is(err, ErrTerminfoNotFound) {
+		t.Errorf("expected ErrTerminfoNotFound, got %v", err)
+	}
+}
+
+func TestEmbeddedTerminfoCopy(t *testing.T) {
+	src := EmbeddedTerminfo()
+	caps, err := src.StringCaps("xterm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kcuu1 := caps["kcuu1"]
+	caps["kcuu1"] = "changed"
+	delete(caps, "kcud1")
+
+	caps, err = src.StringCaps("xterm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if caps["kcuu1"] != kcuu1 || caps["kcud1"] == "" {
+		t.Errorf("changes to the returned capabilities leaked into the database: %q", caps)
+	}
+}
+
+func TestChainTerminfo(t *testing.T) {
+	empty := TerminfoSourceFunc(func(string) (map[string]string, error) {
+		return nil, ErrTerminfoNotFound
+	})
+	src := ChainTerminfo(empty, EmbeddedTerminfo())
+	caps, err := src.StringCaps("linux")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if caps["kf1"] != "\x1b[[A" {
+		t.Errorf("expected linux kf1, got %q", caps["kf1"])
+	}
+
+	broken := errors.New("broken")
+	src = ChainTerminfo(TerminfoSourceFunc(func(string) (map[string]string, error) {
+		return nil, broken
+	}), EmbeddedTerminfo())
+	if _, err := src.StringCaps("linux"); err != broken {
+		t.Errorf("expected error to be returned, got %v", err)
+	}
+}
+
+func TestSystemTerminfoDirs(t *testing.T) {
+	var entry []byte
+	for _, dir := range terminfoDirs {
+		if b, err := os.ReadFile(filepath.Join(dir, "x", "xterm")); err == nil {
+			entry = b
+			break
+		}
+	}
+	if entry == nil {
+		t.Skip("no system xterm terminfo entry")
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "c"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c", "custom-term"), entry, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TERMINFO", "")
+	t.Setenv("TERMINFO_DIRS", dir)
+	caps, err := SystemTerminfo().StringCaps("custom-term")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if caps["kcuu1"] == "" {
+		t.Errorf("expected kcuu1 capability")
+	}
+
+	t.Setenv("TERMINFO_DIRS", "")
+	t.Setenv("TERMINFO", dir)
+	if _, err := SystemTerminfo().StringCaps("custom-term"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildTerminfoKeysSource(t *testing.T) {
+	src := TerminfoSourceFunc(func(term string) (map[string]string, error) {
+		if term != "custom" {
+			return nil, ErrTerminfoNotFound
+		}
+		return map[string]string{"kf1": "\x1bOP", "kcuu1": "\x1b[A", "bel": "\a"}, nil
+	})
+	table := buildTerminfoKeys(0, "custom", src)
+	if len(table) != 2 {
+		t.Errorf("expected 2 keys, got %d", len(table))
+	}
+	if table["\x1bOP"] != (Key{Sym: KeyF1}) {
+		t.Errorf("expected f1 key, got %v", table["\x1bOP"])
+	}
+	if table := buildTerminfoKeys(0, "other", src); len(table) != 0 {
+		t.Errorf("expected no keys, got %d", len(table))
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package input
+
+import "errors"
+
+// disableSignalKeys is not supported on this platform.
+func disableSignalKeys(uintptr) error {
+	return nil
+}
+
+// setFlowControl is not supported on this platform.
+func setFlowControl(uintptr, bool) error {
+	return nil
+}
+
+// raiseInterrupt is not supported on this platform.
+func raiseInterrupt() error {
+	return errors.New("interrupt not supported on this platform")
+}
@@ -0,0 +1,42 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package input
+
+import "golang.org/x/sys/unix"
+
+// updateTermios changes the terminal settings of fd with fn.
+func updateTermios(fd uintptr, fn func(*unix.Termios)) error {
+	t, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+	if err != nil {
+		return err
+	}
+	fn(t)
+	return unix.IoctlSetTermios(int(fd), ioctlWriteTermios, t)
+}
+
+// disableSignalKeys turns off the signal keys of the terminal fd, like
+// ctrl+c, so that they arrive as input.
+func disableSignalKeys(fd uintptr) error {
+	return updateTermios(fd, func(t *unix.Termios) {
+		t.Lflag &^= unix.ISIG
+	})
+}
+
+// setFlowControl turns the XON/XOFF flow control of the terminal fd on or
+// off. When it's off, ctrl+s and ctrl+q arrive as input.
+func setFlowControl(fd uintptr, enabled bool) error {
+	return updateTermios(fd, func(t *unix.Termios) {
+		if enabled {
+			t.Iflag |= unix.IXON
+		} else {
+			t.Iflag &^= unix.IXON
+		}
+	})
+}
+
+// raiseInterrupt sends SIGINT to the process, like the terminal does when
+// ctrl+c is pressed.
+func raiseInterrupt() error {
+	return unix.Kill(unix.Getpid(), unix.SIGINT)
+}
@@ -0,0 +1,11 @@
+//go:build linux || solaris
+// +build linux solaris
+
+package input
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlReadTermios  = unix.TCGETS
+	ioctlWriteTermios = unix.TCSETS
+)
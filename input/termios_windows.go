@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package input
+
+import "golang.org/x/sys/windows"
+
+// disableSignalKeys turns off the signal keys of the terminal fd, like
+// ctrl+c, so that they arrive as input. The console input reader already
+// turns off ctrl+c processing.
+func disableSignalKeys(uintptr) error {
+	return nil
+}
+
+// setFlowControl turns the XON/XOFF flow control of the terminal fd on or
+// off. The console doesn't have flow control, ctrl+s and ctrl+q always
+// arrive as input.
+func setFlowControl(uintptr, bool) error {
+	return nil
+}
+
+// raiseInterrupt sends a ctrl+c event to the processes attached to the
+// console, like the console does when ctrl+c is pressed.
+func raiseInterrupt() error {
+	return windows.GenerateConsoleCtrlEvent(windows.CTRL_C_EVENT, 0)
+}
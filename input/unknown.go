@@ -0,0 +1,315 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SequenceKind is the kind of an escape sequence.
+type SequenceKind int
+
+// Sequence kinds.
+const (
+	// SequenceOther is an escape sequence, or bytes, of no particular kind.
+	SequenceOther SequenceKind = iota
+	SequenceCsi
+	SequenceSs3
+	SequenceOsc
+	SequenceDcs
+	SequenceApc
+)
+
+var sequenceKindNames = map[SequenceKind]string{
+	SequenceOther: "ESC",
+	SequenceCsi:   "CSI",
+	SequenceSs3:   "SS3",
+	SequenceOsc:   "OSC",
+	SequenceDcs:   "DCS",
+	SequenceApc:   "APC",
+}
+
+// String implements fmt.Stringer.
+func (k SequenceKind) String() string {
+	return sequenceKindNames[k]
+}
+
+// UnknownSequence describes an input sequence the parser didn't recognize.
+// Use it to report unknown sequences in a readable way.
+//
+//	if e, ok := ev.(input.UnknownCsiEvent); ok {
+//		log.Printf("unknown sequence: %s", e.Sequence())
+//	}
+type UnknownSequence struct {
+	// Kind is the kind of the sequence.
+	Kind SequenceKind
+
+	// Raw is the sequence as received.
+	Raw string
+
+	// Marker is the private marker of CSI and DCS sequences, like '?', or
+	// zero.
+	Marker byte
+
+	// Intermediates are the intermediate bytes of CSI and DCS sequences, in
+	// order, like "$", or empty.
+	Intermediates string
+
+	// Final is the final byte of CSI, SS3, and DCS sequences.
+	Final byte
+
+	// Params are the parameters of CSI and DCS sequences, or the modifier
+	// of SS3 sequences. Each parameter holds its sub-parameters, if any.
+	// Missing parameters are -1.
+	Params [][]int
+
+	// Cmd is the command number of OSC sequences, or -1.
+	Cmd int
+
+	// Data is the payload of OSC, DCS, and APC sequences.
+	Data string
+
+	// Name is the name of the sequence when known, like "DECRPM".
+	Name string
+
+	// Incomplete reports whether the sequence is missing its final byte or
+	// string terminator.
+	Incomplete bool
+}
+
+// Summary returns a short description of the sequence for logs, like
+// "unrecognized DECRQSS", or "unknown CSI sequence" when the sequence
+// has no name.
+func (s UnknownSequence) Summary() string {
+	if s.Name == "" {
+		return "unknown " + s.Kind.String() + " sequence"
+	}
+	return "unrecognized " + s.Name
+}
+
+// String returns a readable representation of the sequence, like
+// `CSI ? 1;2 $ y (DECRPM) "\x1b[?1;2$y"`.
+func (s UnknownSequence) String() string {
+	var b strings.Builder
+	b.WriteString(s.Kind.String())
+	switch s.Kind {
+	case SequenceOsc:
+		if s.Cmd >= 0 {
+			b.WriteString(" " + strconv.Itoa(s.Cmd))
+		}
+	case SequenceCsi, SequenceSs3, SequenceDcs:
+		if s.Marker != 0 {
+			b.WriteString(" " + string(s.Marker))
+		}
+		if len(s.Params) > 0 {
+			b.WriteString(" " + paramsString(s.Params))
+		}
+		for i := 0; i < len(s.Intermediates); i++ {
+			b.WriteString(" " + string(s.Intermediates[i]))
+		}
+		if s.Final != 0 {
+			b.WriteString(" " + string(s.Final))
+		}
+	}
+	if s.Name != "" {
+		b.WriteString(" (" + s.Name + ")")
+	}
+	if s.Incomplete {
+		b.WriteString(" incomplete")
+	}
+	fmt.Fprintf(&b, " %q", s.Raw)
+	return b.String()
+}
+
+// paramsString formats parameters the way they appear in a sequence.
+func paramsString(params [][]int) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		sub := make([]string, len(p))
+		for j, v := range p {
+			if v >= 0 {
+				sub[j] = strconv.Itoa(v)
+			}
+		}
+		parts[i] = strings.Join(sub, ":")
+	}
+	return strings.Join(parts, ";")
+}
+
+// Sequence returns the description of the sequence.
+func (e UnknownEvent) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// Sequence returns the description of the sequence.
+func (e UnknownCsiEvent) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// Sequence returns the description of the sequence.
+func (e UnknownSs3Event) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// Sequence returns the description of the sequence.
+func (e UnknownOscEvent) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// Sequence returns the description of the sequence.
+func (e UnknownDcsEvent) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// Sequence returns the description of the sequence.
+func (e UnknownApcEvent) Sequence() UnknownSequence { return ParseUnknownSequence(string(e)) }
+
+// ParseUnknownSequence describes the raw sequence s. It's meant for
+// diagnostics and accepts any input, including incomplete sequences.
+func ParseUnknownSequence(s string) UnknownSequence {
+	seq := UnknownSequence{Raw: s, Cmd: -1}
+
+	var i int
+	switch {
+	case strings.HasPrefix(s, "\x1b["), strings.HasPrefix(s, "\x9b"):
+		seq.Kind = SequenceCsi
+	case strings.HasPrefix(s, "\x1bO"), strings.HasPrefix(s, "\x8f"):
+		seq.Kind = SequenceSs3
+	case strings.HasPrefix(s, "\x1b]"), strings.HasPrefix(s, "\x9d"):
+		seq.Kind = SequenceOsc
+	case strings.HasPrefix(s, "\x1bP"), strings.HasPrefix(s, "\x90"):
+		seq.Kind = SequenceDcs
+	case strings.HasPrefix(s, "\x1b_"), strings.HasPrefix(s, "\x9f"):
+		seq.Kind = SequenceApc
+	default:
+		return seq
+	}
+	if s[0] == ansi.ESC {
+		i = 2
+	} else {
+		i = 1
+	}
+
+	switch seq.Kind {
+	case SequenceCsi, SequenceDcs:
+		i = seq.parseControl(s, i)
+		if seq.Kind == SequenceDcs && !seq.Incomplete {
+			seq.Data, seq.Incomplete = stringData(s[i:])
+		}
+	case SequenceSs3:
+		var mod []int
+		for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+			if len(mod) == 0 {
+				mod = []int{0}
+			}
+			mod[0] = mod[0]*10 + int(s[i]-'0')
+		}
+		if mod != nil {
+			seq.Params = [][]int{mod}
+		}
+		if i < len(s) {
+			seq.Final = s[i]
+		} else {
+			seq.Incomplete = true
+		}
+	case SequenceOsc:
+		j := i
+		for ; j < len(s) && s[j] >= '0' && s[j] <= '9'; j++ {
+		}
+		if j > i && (j == len(s) || s[j] == ';' || s[j] == ansi.BEL || s[j] == ansi.ESC || s[j] == ansi.ST) {
+			seq.Cmd, _ = strconv.Atoi(s[i:j])
+			if j < len(s) && s[j] == ';' {
+				j++
+			}
+			i = j
+		}
+		seq.Data, seq.Incomplete = stringData(s[i:])
+	case SequenceApc:
+		seq.Data, seq.Incomplete = stringData(s[i:])
+	}
+
+	seq.Name = unknownSequenceName(seq)
+	return seq
+}
+
+// parseControl parses the marker, parameters, intermediate, and final bytes
+// of CSI and DCS sequences starting at i. It returns the index after the
+// final byte.
+func (s *UnknownSequence) parseControl(b string, i int) int {
+	if i < len(b) && b[i] >= '<' && b[i] <= '?' {
+		s.Marker = b[i]
+		i++
+	}
+
+	start := i
+	for ; i < len(b) && b[i] >= 0x30 && b[i] <= 0x3F; i++ {
+	}
+	if i > start {
+		for _, p := range strings.Split(b[start:i], ";") {
+			var param []int
+			for _, sub := range strings.Split(p, ":") {
+				v, err := strconv.Atoi(sub)
+				if err != nil {
+					v = -1
+				}
+				param = append(param, v)
+			}
+			s.Params = append(s.Params, param)
+		}
+	}
+
+	start = i
+	for ; i < len(b) && b[i] >= 0x20 && b[i] <= 0x2F; i++ {
+	}
+	s.Intermediates = b[start:i]
+	if i < len(b) && b[i] >= 0x40 && b[i] <= 0x7E {
+		s.Final = b[i]
+		return i + 1
+	}
+	s.Incomplete = true
+	return i
+}
+
+// stringData returns the data of a string sequence, without its terminator,
+// and whether the terminator is missing.
+func stringData(s string) (string, bool) {
+	switch {
+	case strings.HasSuffix(s, "\x1b\\"):
+		return s[:len(s)-2], false
+	case strings.HasSuffix(s, "\a"), strings.HasSuffix(s, "\x9c"):
+		return s[:len(s)-1], false
+	}
+	return s, true
+}
+
+// inputCsiNames maps CSI sequences only terminals send, by command, to
+// their names. They take precedence over [ansi.CsiNames], which names the
+// sequences terminals receive, like SCORC for CSI u.
+var inputCsiNames = map[ansi.Cmd]string{
+	ansi.NewCmd(0, 0, 'u'): "Kitty keyboard",
+	ansi.NewCmd(0, 0, '~'): "function key",
+	ansi.NewCmd(0, 0, 'M'): "X10 mouse",
+	ansi.NewCmd(0, 0, '_'): "win32-input-mode",
+}
+
+// unknownSequenceName returns the name of a sequence, if known.
+func unknownSequenceName(s UnknownSequence) string {
+	var intermed byte
+	switch len(s.Intermediates) {
+	case 0:
+	case 1:
+		intermed = s.Intermediates[0]
+	default:
+		// Named sequences have at most one intermediate byte.
+		return ""
+	}
+	cmd := ansi.NewCmd(s.Marker, intermed, s.Final)
+	switch s.Kind {
+	case SequenceCsi:
+		if name, ok := inputCsiNames[cmd]; ok {
+			return name
+		}
+		return ansi.CsiNames[cmd]
+	case SequenceDcs:
+		return ansi.DcsNames[cmd]
+	case SequenceOsc:
+		return ansi.OscNames[s.Cmd]
+	case SequenceApc:
+		if strings.HasPrefix(s.Data, "G") {
+			return "Kitty graphics"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUnknownSequence(t *testing.T) {
+	cases := []struct {
+		name   string
+		seq    string
+		expect UnknownSequence
+		str    string
+	}{
+		{
+			name: "csi",
+			seq:  "\x1b[?1;2:3$y",
+			expect: UnknownSequence{
+				Kind: SequenceCsi, Raw: "\x1b[?1;2:3$y", Marker: '?',
+				Intermediates: "$", Final: 'y', Params: [][]int{{1}, {2, 3}},
+				Cmd: -1, Name: "DECRPM",
+			},
+			str: `CSI ? 1;2:3 $ y (DECRPM) "\x1b[?1;2:3$y"`,
+		},
+		{
+			name: "csi missing params",
+			seq:  "\x9b;5Y",
+			expect: UnknownSequence{
+				Kind: SequenceCsi, Raw: "\x9b;5Y", Final: 'Y',
+				Params: [][]int{{-1}, {5}}, Cmd: -1,
+			},
+			str: `CSI ;5 Y "\x9b;5Y"`,
+		},
+		{
+			name: "incomplete csi",
+			seq:  "\x1b[12;",
+			expect: UnknownSequence{
+				Kind: SequenceCsi, Raw: "\x1b[12;", Params: [][]int{{12}, {-1}},
+				Cmd: -1, Incomplete: true,
+			},
+			str: `CSI 12; incomplete "\x1b[12;"`,
+		},
+		{
+			name: "csi intermediates",
+			seq:  "\x1b[1!\"p",
+			expect: UnknownSequence{
+				Kind: SequenceCsi, Raw: "\x1b[1!\"p", Intermediates: "!\"",
+				Final: 'p', Params: [][]int{{1}}, Cmd: -1,
+			},
+			str: `CSI 1 ! " p "\x1b[1!\"p"`,
+		},
+		{
+			name: "incomplete csi intermediates",
+			seq:  "\x1b[!\"",
+			expect: UnknownSequence{
+				Kind: SequenceCsi, Raw: "\x1b[!\"", Intermediates: "!\"",
+				Cmd: -1, Incomplete: true,
+			},
+			str: `CSI ! " incomplete "\x1b[!\""`,
+		},
+		{
+			name: "ss3",
+			seq:  "\x1bO5z",
+			expect: UnknownSequence{
+				Kind: SequenceSs3, Raw: "\x1bO5z", Final: 'z', Params: [][]int{{5}}, Cmd: -1,
+			},
+			str: `SS3 5 z "\x1bO5z"`,
+		},
+		{
+			name: "osc",
+			seq:  "\x1b]11;rgb:0000/0000/0000\x07",
+			expect: UnknownSequence{
+				Kind: SequenceOsc, Raw: "\x1b]11;rgb:0000/0000/0000\x07", Cmd: 11,
+				Data: "rgb:0000/0000/0000", Name: "background color",
+			},
+			str: `OSC 11 (background color) "\x1b]11;rgb:0000/0000/0000\a"`,
+		},
+		{
+			name: "dcs",
+			seq:  "\x1bP1$r0m\x1b\\",
+			expect: UnknownSequence{
+				Kind: SequenceDcs, Raw: "\x1bP1$r0m\x1b\\", Intermediates: "$", Final: 'r',
+				Params: [][]int{{1}}, Cmd: -1, Data: "0m", Name: "DECRQSS reply",
+			},
+			str: `DCS 1 $ r (DECRQSS reply) "\x1bP1$r0m\x1b\\"`,
+		},
+		{
+			name: "apc",
+			seq:  "\x1b_Gi=1;OK\x1b\\",
+			expect: UnknownSequence{
+				Kind: SequenceApc, Raw: "\x1b_Gi=1;OK\x1b\\", Cmd: -1,
+				Data: "Gi=1;OK", Name: "Kitty graphics",
+			},
+			str: `APC (Kitty graphics) "\x1b_Gi=1;OK\x1b\\"`,
+		},
+		{
+			name:   "other",
+			seq:    "\x1b#8",
+			expect: UnknownSequence{Raw: "\x1b#8", Cmd: -1},
+			str:    `ESC "\x1b#8"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := ParseUnknownSequence(c.seq)
+			if !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("expected %#v, got %#v", c.expect, got)
+			}
+			if got.String() != c.str {
+				t.Errorf("expected %s, got %s", c.str, got)
+			}
+		})
+	}
+}
+
+func TestUnknownEventSequence(t *testing.T) {
+	_, e := ParseSequence([]byte("\x1b[?1;2;3;4;5;6;7;8Y"))
+	u, ok := e.(UnknownCsiEvent)
+	if !ok {
+		t.Fatalf("expected an unknown CSI event, got %#v", e)
+	}
+	seq := u.Sequence()
+	if seq.Kind != SequenceCsi || seq.Marker != '?' || seq.Final != 'Y' || len(seq.Params) != 8 {
+		t.Errorf("unexpected sequence %#v", seq)
+	}
+}
+
+func TestUnknownSequenceSummary(t *testing.T) {
+	cases := []struct {
+		seq    string
+		expect string
+	}{
+		{"\x1bP1$r0m\x1b\\", "unrecognized DECRQSS reply"},
+		{"\x1b[?2004;1$y", "unrecognized DECRPM"},
+		{"\x1b[5u", "unrecognized Kitty keyboard"},
+		{"\x1b[1;2Y", "unknown CSI sequence"},
+		{"\x1b[!p", "unrecognized DECSTR"},
+		{"\x1b[ !p", "unknown CSI sequence"},
+	}
+	for _, c := range cases {
+		if got := ParseUnknownSequence(c.seq).Summary(); got != c.expect {
+			t.Errorf("%q: expected %q, got %q", c.seq, c.expect, got)
+		}
+	}
+}
@@ -6,13 +6,31 @@ import (
 	"github.com/erikgeiser/coninput"
 )
 
-func parseWin32InputKeyEvent(vkc coninput.VirtualKeyCode, _ coninput.VirtualKeyCode, r rune, keyDown bool, cks coninput.ControlKeyState, repeatCount uint16) Event {
+// Scan codes used to tell the left and right shift keys apart since Windows
+// reports both as VK_SHIFT.
+const (
+	scLeftShift  = 0x2a
+	scRightShift = 0x36
+)
+
+// parseWin32InputKeyEvent converts a Windows KEY_EVENT_RECORD, either read
+// from the console or decoded from a win32-input-mode sequence, into a key
+// event. A repeat count greater than one yields a [MultiEvent] with one
+// repeated key event per count.
+func parseWin32InputKeyEvent(vkc coninput.VirtualKeyCode, sc coninput.VirtualKeyCode, r rune, keyDown bool, cks coninput.ControlKeyState, repeatCount uint16) Event {
 	var key Key
 	isCtrl := cks.Contains(coninput.LEFT_CTRL_PRESSED | coninput.RIGHT_CTRL_PRESSED)
 	switch vkc {
 	case coninput.VK_SHIFT:
-		// We currently ignore these keys when they are pressed alone.
-		return nil
+		switch sc {
+		case scLeftShift:
+			key = Key{Sym: KeyLeftShift}
+		case scRightShift:
+			key = Key{Sym: KeyRightShift}
+		default:
+			// We can't tell which shift key this is, ignore it.
+			return nil
+		}
 	case coninput.VK_MENU:
 		if cks.Contains(coninput.LEFT_ALT_PRESSED) {
 			key = Key{Sym: KeyLeftAlt}
@@ -69,13 +87,17 @@ func parseWin32InputKeyEvent(vkc coninput.VirtualKeyCode, _ coninput.VirtualKeyC
 		key.AltRune = unicode.ToLower(key.Rune)
 	}
 
-	var e Event = KeyPressEvent(key)
+	if repeatCount == 0 {
+		repeatCount = 1
+	}
+
 	key.IsRepeat = repeatCount > 1
+	var e Event = KeyPressEvent(key)
 	if !keyDown {
 		e = KeyReleaseEvent(key)
 	}
 
-	if repeatCount <= 1 {
+	if repeatCount == 1 {
 		return e
 	}
 
@@ -88,75 +110,93 @@ func parseWin32InputKeyEvent(vkc coninput.VirtualKeyCode, _ coninput.VirtualKeyC
 }
 
 var vkKeyEvent = map[coninput.VirtualKeyCode]Key{
-	coninput.VK_RETURN:    {Sym: KeyEnter},
-	coninput.VK_BACK:      {Sym: KeyBackspace},
-	coninput.VK_TAB:       {Sym: KeyTab},
-	coninput.VK_ESCAPE:    {Sym: KeyEscape},
-	coninput.VK_SPACE:     {Sym: KeySpace, Rune: ' '},
-	coninput.VK_UP:        {Sym: KeyUp},
-	coninput.VK_DOWN:      {Sym: KeyDown},
-	coninput.VK_RIGHT:     {Sym: KeyRight},
-	coninput.VK_LEFT:      {Sym: KeyLeft},
-	coninput.VK_HOME:      {Sym: KeyHome},
-	coninput.VK_END:       {Sym: KeyEnd},
-	coninput.VK_PRIOR:     {Sym: KeyPgUp},
-	coninput.VK_NEXT:      {Sym: KeyPgDown},
-	coninput.VK_DELETE:    {Sym: KeyDelete},
-	coninput.VK_SELECT:    {Sym: KeySelect},
-	coninput.VK_SNAPSHOT:  {Sym: KeyPrintScreen},
-	coninput.VK_INSERT:    {Sym: KeyInsert},
-	coninput.VK_LWIN:      {Sym: KeyLeftSuper},
-	coninput.VK_RWIN:      {Sym: KeyRightSuper},
-	coninput.VK_APPS:      {Sym: KeyMenu},
-	coninput.VK_NUMPAD0:   {Sym: KeyKp0},
-	coninput.VK_NUMPAD1:   {Sym: KeyKp1},
-	coninput.VK_NUMPAD2:   {Sym: KeyKp2},
-	coninput.VK_NUMPAD3:   {Sym: KeyKp3},
-	coninput.VK_NUMPAD4:   {Sym: KeyKp4},
-	coninput.VK_NUMPAD5:   {Sym: KeyKp5},
-	coninput.VK_NUMPAD6:   {Sym: KeyKp6},
-	coninput.VK_NUMPAD7:   {Sym: KeyKp7},
-	coninput.VK_NUMPAD8:   {Sym: KeyKp8},
-	coninput.VK_NUMPAD9:   {Sym: KeyKp9},
-	coninput.VK_MULTIPLY:  {Sym: KeyKpMultiply},
-	coninput.VK_ADD:       {Sym: KeyKpPlus},
-	coninput.VK_SEPARATOR: {Sym: KeyKpComma},
-	coninput.VK_SUBTRACT:  {Sym: KeyKpMinus},
-	coninput.VK_DECIMAL:   {Sym: KeyKpDecimal},
-	coninput.VK_DIVIDE:    {Sym: KeyKpDivide},
-	coninput.VK_F1:        {Sym: KeyF1},
-	coninput.VK_F2:        {Sym: KeyF2},
-	coninput.VK_F3:        {Sym: KeyF3},
-	coninput.VK_F4:        {Sym: KeyF4},
-	coninput.VK_F5:        {Sym: KeyF5},
-	coninput.VK_F6:        {Sym: KeyF6},
-	coninput.VK_F7:        {Sym: KeyF7},
-	coninput.VK_F8:        {Sym: KeyF8},
-	coninput.VK_F9:        {Sym: KeyF9},
-	coninput.VK_F10:       {Sym: KeyF10},
-	coninput.VK_F11:       {Sym: KeyF11},
-	coninput.VK_F12:       {Sym: KeyF12},
-	coninput.VK_F13:       {Sym: KeyF13},
-	coninput.VK_F14:       {Sym: KeyF14},
-	coninput.VK_F15:       {Sym: KeyF15},
-	coninput.VK_F16:       {Sym: KeyF16},
-	coninput.VK_F17:       {Sym: KeyF17},
-	coninput.VK_F18:       {Sym: KeyF18},
-	coninput.VK_F19:       {Sym: KeyF19},
-	coninput.VK_F20:       {Sym: KeyF20},
-	coninput.VK_F21:       {Sym: KeyF21},
-	coninput.VK_F22:       {Sym: KeyF22},
-	coninput.VK_F23:       {Sym: KeyF23},
-	coninput.VK_F24:       {Sym: KeyF24},
-	coninput.VK_NUMLOCK:   {Sym: KeyNumLock},
-	coninput.VK_SCROLL:    {Sym: KeyScrollLock},
-	coninput.VK_LSHIFT:    {Sym: KeyLeftShift},
-	coninput.VK_RSHIFT:    {Sym: KeyRightShift},
-	coninput.VK_LCONTROL:  {Sym: KeyLeftCtrl},
-	coninput.VK_RCONTROL:  {Sym: KeyRightCtrl},
-	coninput.VK_LMENU:     {Sym: KeyLeftAlt},
-	coninput.VK_RMENU:     {Sym: KeyRightAlt},
-	coninput.VK_OEM_4:     {Rune: '['},
+	coninput.VK_RETURN:              {Sym: KeyEnter},
+	coninput.VK_BACK:                {Sym: KeyBackspace},
+	coninput.VK_TAB:                 {Sym: KeyTab},
+	coninput.VK_ESCAPE:              {Sym: KeyEscape},
+	coninput.VK_SPACE:               {Sym: KeySpace, Rune: ' '},
+	coninput.VK_UP:                  {Sym: KeyUp},
+	coninput.VK_DOWN:                {Sym: KeyDown},
+	coninput.VK_RIGHT:               {Sym: KeyRight},
+	coninput.VK_LEFT:                {Sym: KeyLeft},
+	coninput.VK_HOME:                {Sym: KeyHome},
+	coninput.VK_END:                 {Sym: KeyEnd},
+	coninput.VK_PRIOR:               {Sym: KeyPgUp},
+	coninput.VK_NEXT:                {Sym: KeyPgDown},
+	coninput.VK_DELETE:              {Sym: KeyDelete},
+	coninput.VK_SELECT:              {Sym: KeySelect},
+	coninput.VK_SNAPSHOT:            {Sym: KeyPrintScreen},
+	coninput.VK_INSERT:              {Sym: KeyInsert},
+	coninput.VK_LWIN:                {Sym: KeyLeftSuper},
+	coninput.VK_RWIN:                {Sym: KeyRightSuper},
+	coninput.VK_APPS:                {Sym: KeyMenu},
+	coninput.VK_NUMPAD0:             {Sym: KeyKp0},
+	coninput.VK_NUMPAD1:             {Sym: KeyKp1},
+	coninput.VK_NUMPAD2:             {Sym: KeyKp2},
+	coninput.VK_NUMPAD3:             {Sym: KeyKp3},
+	coninput.VK_NUMPAD4:             {Sym: KeyKp4},
+	coninput.VK_NUMPAD5:             {Sym: KeyKp5},
+	coninput.VK_NUMPAD6:             {Sym: KeyKp6},
+	coninput.VK_NUMPAD7:             {Sym: KeyKp7},
+	coninput.VK_NUMPAD8:             {Sym: KeyKp8},
+	coninput.VK_NUMPAD9:             {Sym: KeyKp9},
+	coninput.VK_MULTIPLY:            {Sym: KeyKpMultiply},
+	coninput.VK_ADD:                 {Sym: KeyKpPlus},
+	coninput.VK_SEPARATOR:           {Sym: KeyKpComma},
+	coninput.VK_SUBTRACT:            {Sym: KeyKpMinus},
+	coninput.VK_DECIMAL:             {Sym: KeyKpDecimal},
+	coninput.VK_DIVIDE:              {Sym: KeyKpDivide},
+	coninput.VK_F1:                  {Sym: KeyF1},
+	coninput.VK_F2:                  {Sym: KeyF2},
+	coninput.VK_F3:                  {Sym: KeyF3},
+	coninput.VK_F4:                  {Sym: KeyF4},
+	coninput.VK_F5:                  {Sym: KeyF5},
+	coninput.VK_F6:                  {Sym: KeyF6},
+	coninput.VK_F7:                  {Sym: KeyF7},
+	coninput.VK_F8:                  {Sym: KeyF8},
+	coninput.VK_F9:                  {Sym: KeyF9},
+	coninput.VK_F10:                 {Sym: KeyF10},
+	coninput.VK_F11:                 {Sym: KeyF11},
+	coninput.VK_F12:                 {Sym: KeyF12},
+	coninput.VK_F13:                 {Sym: KeyF13},
+	coninput.VK_F14:                 {Sym: KeyF14},
+	coninput.VK_F15:                 {Sym: KeyF15},
+	coninput.VK_F16:                 {Sym: KeyF16},
+	coninput.VK_F17:                 {Sym: KeyF17},
+	coninput.VK_F18:                 {Sym: KeyF18},
+	coninput.VK_F19:                 {Sym: KeyF19},
+	coninput.VK_F20:                 {Sym: KeyF20},
+	coninput.VK_F21:                 {Sym: KeyF21},
+	coninput.VK_F22:                 {Sym: KeyF22},
+	coninput.VK_F23:                 {Sym: KeyF23},
+	coninput.VK_F24:                 {Sym: KeyF24},
+	coninput.VK_NUMLOCK:             {Sym: KeyNumLock},
+	coninput.VK_SCROLL:              {Sym: KeyScrollLock},
+	coninput.VK_LSHIFT:              {Sym: KeyLeftShift},
+	coninput.VK_RSHIFT:              {Sym: KeyRightShift},
+	coninput.VK_LCONTROL:            {Sym: KeyLeftCtrl},
+	coninput.VK_RCONTROL:            {Sym: KeyRightCtrl},
+	coninput.VK_LMENU:               {Sym: KeyLeftAlt},
+	coninput.VK_RMENU:               {Sym: KeyRightAlt},
+	coninput.VK_BROWSER_BACK:        {Sym: KeyBrowserBack},
+	coninput.VK_BROWSER_FORWARD:     {Sym: KeyBrowserForward},
+	coninput.VK_BROWSER_REFRESH:     {Sym: KeyBrowserRefresh},
+	coninput.VK_BROWSER_STOP:        {Sym: KeyBrowserStop},
+	coninput.VK_BROWSER_SEARCH:      {Sym: KeyBrowserSearch},
+	coninput.VK_BROWSER_FAVORITES:   {Sym: KeyBrowserFavorites},
+	coninput.VK_BROWSER_HOME:        {Sym: KeyBrowserHome},
+	coninput.VK_VOLUME_MUTE:         {Sym: KeyMute},
+	coninput.VK_VOLUME_DOWN:         {Sym: KeyLowerVol},
+	coninput.VK_VOLUME_UP:           {Sym: KeyRaiseVol},
+	coninput.VK_MEDIA_NEXT_TRACK:    {Sym: KeyMediaNext},
+	coninput.VK_MEDIA_PREV_TRACK:    {Sym: KeyMediaPrev},
+	coninput.VK_MEDIA_STOP:          {Sym: KeyMediaStop},
+	coninput.VK_MEDIA_PLAY_PAUSE:    {Sym: KeyMediaPlayPause},
+	coninput.VK_LAUNCH_MAIL:         {Sym: KeyLaunchMail},
+	coninput.VK_LAUNCH_MEDIA_SELECT: {Sym: KeyLaunchMedia},
+	coninput.VK_LAUNCH_APP1:         {Sym: KeyLaunchApp1},
+	coninput.VK_LAUNCH_APP2:         {Sym: KeyLaunchApp2},
+	coninput.VK_OEM_4:               {Rune: '['},
 	// TODO: add more keys
 }
 
@@ -217,9 +257,13 @@ func vkCtrlRune(k Key, r rune, kc coninput.VirtualKeyCode) Key {
 	case '\x1a':
 		k.Rune = 'z'
 	case '\x1b':
-		k.Rune = ']'
+		k.Rune = '['
 	case '\x1c':
 		k.Rune = '\\'
+	case '\x1d':
+		k.Rune = ']'
+	case '\x1e':
+		k.Rune = '^'
 	case '\x1f':
 		k.Rune = '_'
 	}
@@ -231,9 +275,9 @@ func vkCtrlRune(k Key, r rune, kc coninput.VirtualKeyCode) Key {
 
 	// https://learn.microsoft.com/en-us/windows/win32/inputdev/virtual-key-codes
 	if k.Rune == 0 &&
-		(kc >= 0x30 && kc <= 0x39) ||
-		(kc >= 0x41 && kc <= 0x5a) {
-		k.Rune = rune(kc)
+		((kc >= 0x30 && kc <= 0x39) ||
+			(kc >= 0x41 && kc <= 0x5a)) {
+		k.Rune = unicode.ToLower(rune(kc))
 	}
 
 	return k
@@ -0,0 +1,95 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWin32InputMode(t *testing.T) {
+	cases := []struct {
+		name  string
+		seq   string
+		event Event
+	}{
+		{
+			name:  "press a",
+			seq:   "\x1b[65;30;97;1;0;1_",
+			event: KeyPressEvent{Rune: 'a', AltRune: 'a'},
+		},
+		{
+			name:  "release a",
+			seq:   "\x1b[65;30;97;0;0;1_",
+			event: KeyReleaseEvent{Rune: 'a', AltRune: 'a'},
+		},
+		{
+			name:  "shift A",
+			seq:   "\x1b[65;30;65;1;16;1_",
+			event: KeyPressEvent{Rune: 'A', AltRune: 'A', Mod: ModShift},
+		},
+		{
+			name:  "ctrl a",
+			seq:   "\x1b[65;30;1;1;8_",
+			event: KeyPressEvent{Rune: 'a', AltRune: 'a', Mod: ModCtrl},
+		},
+		{
+			name:  "ctrl ]",
+			seq:   "\x1b[221;27;29;1;8_",
+			event: KeyPressEvent{Rune: ']', AltRune: ']', Mod: ModCtrl},
+		},
+		{
+			name:  "left shift",
+			seq:   "\x1b[16;42;0;1;16_",
+			event: KeyPressEvent{Sym: KeyLeftShift, Mod: ModShift},
+		},
+		{
+			name:  "right shift",
+			seq:   "\x1b[16;54;0;1;16_",
+			event: KeyPressEvent{Sym: KeyRightShift, Mod: ModShift},
+		},
+		{
+			name:  "volume up",
+			seq:   "\x1b[175;48;0;1;0;1_",
+			event: KeyPressEvent{Sym: KeyRaiseVol},
+		},
+		{
+			name:  "browser back",
+			seq:   "\x1b[166;106;0;1;0;1_",
+			event: KeyPressEvent{Sym: KeyBrowserBack},
+		},
+		{
+			name:  "omitted params",
+			seq:   "\x1b[13;;13;1_",
+			event: KeyPressEvent{Sym: KeyEnter},
+		},
+		{
+			name: "repeat",
+			seq:  "\x1b[66;48;98;1;0;2_",
+			event: MultiEvent{
+				KeyPressEvent{Rune: 'b', AltRune: 'b', IsRepeat: true},
+				KeyPressEvent{Rune: 'b', AltRune: 'b', IsRepeat: true},
+			},
+		},
+		{
+			name:  "unknown shift",
+			seq:   "\x1b[16;0;0;1;16_",
+			event: UnknownCsiEvent("\x1b[16;0;0;1;16_"),
+		},
+		{
+			name:  "too many params",
+			seq:   "\x1b[1;2;3;4;5;6;7_",
+			event: UnknownCsiEvent("\x1b[1;2;3;4;5;6;7_"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n, e := ParseSequence([]byte(tc.seq))
+			if n != len(tc.seq) {
+				t.Errorf("expected %d bytes consumed, got %d", len(tc.seq), n)
+			}
+			if !reflect.DeepEqual(e, tc.event) {
+				t.Errorf("expected %#v, got %#v", tc.event, e)
+			}
+		})
+	}
+}
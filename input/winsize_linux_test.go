@@ -0,0 +1,101 @@
+//go:build linux
+// +build linux
+
+package input
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPty opens a new pseudo-terminal pair.
+func openPty(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	m, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("could not open pty: %v", err)
+	}
+	fd := int(m.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		m.Close()
+		t.Skipf("could not unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		m.Close()
+		t.Skipf("could not get pty number: %v", err)
+	}
+	s, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		m.Close()
+		t.Skipf("could not open pty slave: %v", err)
+	}
+	// Put the terminal in non-canonical mode so that input is available
+	// without a line terminator.
+	tio, err := unix.IoctlGetTermios(int(s.Fd()), unix.TCGETS)
+	if err == nil {
+		tio.Lflag &^= unix.ICANON | unix.ECHO
+		err = unix.IoctlSetTermios(int(s.Fd()), unix.TCSETS, tio)
+	}
+	if err != nil {
+		s.Close()
+		m.Close()
+		t.Skipf("could not configure pty: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		m.Close()
+	})
+	return m, s
+}
+
+func TestDriverWindowSize(t *testing.T) {
+	master, slave := openPty(t)
+	setSize := func(w, h int) {
+		ws := &unix.Winsize{Col: uint16(w), Row: uint16(h)}
+		if err := unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, ws); err != nil {
+			t.Fatalf("could not set window size: %v", err)
+		}
+	}
+
+	setSize(80, 24)
+	d, err := NewDriver(slave, "dumb", FlagWindowSize)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	defer d.Close()
+
+	expectEvents := func(want ...Event) {
+		t.Helper()
+		got, err := d.ReadEvents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("expected %v, got %v", want[i], got[i])
+			}
+		}
+	}
+
+	// The initial size is reported first.
+	expectEvents(WindowSizeEvent{Width: 80, Height: 24})
+
+	setSize(120, 40)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGWINCH); err != nil {
+		t.Fatalf("could not send signal: %v", err)
+	}
+	expectEvents(WindowSizeEvent{Width: 120, Height: 40})
+
+	if _, err := master.Write([]byte("a")); err != nil {
+		t.Fatalf("could not write input: %v", err)
+	}
+	expectEvents(KeyPressEvent{Rune: 'a'})
+}
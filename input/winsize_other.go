@@ -0,0 +1,18 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package input
+
+import "io"
+
+// winsizeNotifier is not supported on this platform.
+type winsizeNotifier struct{}
+
+func newWinsizeNotifier(io.Reader) (*winsizeNotifier, error) {
+	return nil, nil
+}
+
+func (*winsizeNotifier) events() <-chan Event { return nil }
+
+// Close implements io.Closer.
+func (*winsizeNotifier) Close() error { return nil }
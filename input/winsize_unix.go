@@ -0,0 +1,98 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package input
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// winsizeNotifier listens for SIGWINCH signals and reports the new window
+// size of the terminal.
+type winsizeNotifier struct {
+	fd   int            // the terminal file descriptor
+	sig  chan os.Signal // SIGWINCH notifications
+	evs  chan Event     // window size events
+	done chan struct{}  // closed when the notifier is canceled
+	once sync.Once
+}
+
+// newWinsizeNotifier returns a new window size notifier for the given
+// terminal. It returns nil if r is not a file.
+func newWinsizeNotifier(r io.Reader) (*winsizeNotifier, error) {
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok {
+		return nil, nil
+	}
+
+	n := &winsizeNotifier{
+		fd:   int(f.Fd()),
+		sig:  make(chan os.Signal, 1),
+		evs:  make(chan Event, 1),
+		done: make(chan struct{}),
+	}
+
+	signal.Notify(n.sig, syscall.SIGWINCH)
+
+	// Report the initial window size.
+	n.notify()
+
+	go n.loop()
+
+	return n, nil
+}
+
+func (n *winsizeNotifier) loop() {
+	for {
+		select {
+		case <-n.sig:
+			n.notify()
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// notify queries the window size and reports it. A size that hasn't been
+// picked up yet gets replaced by the new one.
+func (n *winsizeNotifier) notify() {
+	ws, err := unix.IoctlGetWinsize(n.fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return
+	}
+
+	select {
+	case <-n.evs:
+	default:
+	}
+
+	n.evs <- WindowSizeEvent{
+		Width:       int(ws.Col),
+		Height:      int(ws.Row),
+		PixelWidth:  int(ws.Xpixel),
+		PixelHeight: int(ws.Ypixel),
+	}
+}
+
+// events returns the channel window size events are sent to.
+func (n *winsizeNotifier) events() <-chan Event {
+	if n == nil {
+		return nil
+	}
+	return n.evs
+}
+
+// Close stops listening for signals.
+func (n *winsizeNotifier) Close() error {
+	n.once.Do(func() {
+		signal.Stop(n.sig)
+		close(n.done)
+	})
+	return nil
+}
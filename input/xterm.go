@@ -1,13 +1,27 @@
 package input
 
 import (
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/charmbracelet/x/ansi"
 )
 
+// parseXTermModifyOtherKeys parses an XTerm modifyOtherKeys key sequence.
+//
+//	CSI 27 ; <modifier> ; <code> ~
+//
+// The code is the Unicode codepoint of the key after applying shift, so
+// ctrl+shift+a is reported as "A" with the shift and ctrl modifiers.
 func parseXTermModifyOtherKeys(csi *ansi.CsiSequence) Event {
-	// XTerm modify other keys starts with ESC [ 27 ; <modifier> ; <code> ~
-	mod := KeyMod(csi.Param(1) - 1)
+	var mod KeyMod
+	if m := csi.Param(1); m > 1 {
+		mod = KeyMod(m - 1)
+	}
 	r := rune(csi.Param(2))
+	if r < 0 || !utf8.ValidRune(r) {
+		r = utf8.RuneError
+	}
 
 	switch r {
 	case ansi.BS:
@@ -18,15 +32,21 @@ func parseXTermModifyOtherKeys(csi *ansi.CsiSequence) Event {
 		return KeyPressEvent{Mod: mod, Sym: KeyEnter}
 	case ansi.ESC:
 		return KeyPressEvent{Mod: mod, Sym: KeyEscape}
+	case ansi.SP:
+		return KeyPressEvent{Mod: mod, Sym: KeySpace, Rune: ' '}
 	case ansi.DEL:
 		return KeyPressEvent{Mod: mod, Sym: KeyBackspace}
 	}
 
 	// CSI 27 ; <modifier> ; <code> ~ keys defined in XTerm modifyOtherKeys
-	return KeyPressEvent{
-		Mod:  mod,
-		Rune: r,
+	k := KeyPressEvent{Mod: mod, Rune: r}
+	if mod.HasShift() && unicode.IsUpper(r) {
+		// Keep the unshifted key around so that ctrl+shift+a matches the
+		// same key as the one reported by the Kitty protocol.
+		k.AltRune = unicode.ToLower(r)
 	}
+
+	return k
 }
 
 // ModifyOtherKeysEvent represents a modifyOtherKeys event.
@@ -38,3 +58,10 @@ func parseXTermModifyOtherKeys(csi *ansi.CsiSequence) Event {
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Functions-using-CSI-_-ordered-by-the-final-character_s_
 // See: https://invisible-island.net/xterm/manpage/xterm.html#VT100-Widget-Resources:modifyOtherKeys
 type ModifyOtherKeysEvent uint8
+
+// TerminalVersionEvent is the name and version of the terminal, like
+// "kitty(0.31.0)" or "XTerm(388)". It is reported in response to
+// [ansi.RequestXTVersion].
+//
+//	DCS > | text ST
+type TerminalVersionEvent string
@@ -0,0 +1,11 @@
+module github.com/charmbracelet/x/pty
+
+go 1.18
+
+require (
+	github.com/charmbracelet/x/conpty v0.1.0
+	github.com/creack/pty v1.1.23
+	golang.org/x/sys v0.26.0
+)
+
+require github.com/charmbracelet/x/errors v0.0.0-20240508181413-e8d8b6e2de86 // indirect
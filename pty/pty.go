@@ -0,0 +1,126 @@
+// Package pty provides a cross-platform pseudo-terminal. It uses openpty on
+// Unix systems and ConPTY on Windows.
+package pty
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// ErrUnsupported is returned when pseudo-terminals aren't supported on the
+// current platform.
+var ErrUnsupported = errors.New("pty: unsupported platform")
+
+// Default size of a new PTY.
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// Pty is a pseudo-terminal. Reading from it returns the output of the
+// command running on it, and writing to it sends input to that command.
+type Pty interface {
+	io.ReadWriteCloser
+
+	// Fd returns the file descriptor, or handle on Windows, of the PTY.
+	Fd() uintptr
+
+	// Name returns the name of the PTY.
+	Name() string
+
+	// Resize resizes the PTY.
+	Resize(width, height int) error
+
+	// Size returns the size of the PTY.
+	Size() (width, height int, err error)
+
+	// Start starts a command on the PTY. The command's standard input,
+	// output, and error are connected to the PTY unless they're already set.
+	// On Windows, cmd.Wait doesn't work with ConPTY processes, use Wait
+	// instead.
+	Start(cmd *exec.Cmd) error
+}
+
+// Options are the options of a new PTY.
+type Options struct {
+	// Width and Height are the initial size of the PTY.
+	Width, Height int
+
+	// Flags are the ConPTY creation flags. They're ignored on Unix.
+	Flags int
+}
+
+// Option is an option of a new PTY.
+type Option func(*Options)
+
+// WithSize sets the initial size of the PTY.
+func WithSize(width, height int) Option {
+	return func(o *Options) {
+		o.Width = width
+		o.Height = height
+	}
+}
+
+// WithFlags sets the ConPTY creation flags. They're ignored on Unix.
+func WithFlags(flags int) Option {
+	return func(o *Options) {
+		o.Flags = flags
+	}
+}
+
+// New creates a new PTY. It's DefaultWidth by DefaultHeight cells unless
+// WithSize is given.
+//
+//	p, err := pty.New(pty.WithSize(120, 40))
+//	if err != nil {
+//		// handle error
+//	}
+//	defer p.Close() // Make sure to close the PTY when done.
+//
+//	cmd := exec.Command("top")
+//	if err := p.Start(cmd); err != nil {
+//		// handle error
+//	}
+func New(opts ...Option) (Pty, error) {
+	return newPty(newOptions(opts))
+}
+
+func newOptions(opts []Option) Options {
+	o := Options{Width: DefaultWidth, Height: DefaultHeight}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Wait waits for a command started on a PTY to exit, or kills it when ctx is
+// done. It exists because cmd.Wait doesn't work with ConPTY on Windows.
+func Wait(ctx context.Context, cmd *exec.Cmd) (err error) {
+	if cmd.Process == nil {
+		return errors.New("process not started")
+	}
+
+	type result struct {
+		*os.ProcessState
+		error
+	}
+
+	donec := make(chan result, 1)
+	go func() {
+		state, err := cmd.Process.Wait()
+		donec <- result{state, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = cmd.Process.Kill()
+	case r := <-donec:
+		cmd.ProcessState = r.ProcessState
+		err = r.error
+	}
+
+	return
+}
@@ -0,0 +1,23 @@
+package pty
+
+import "testing"
+
+func TestOptions(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []Option
+		want Options
+	}{
+		{"default", nil, Options{Width: DefaultWidth, Height: DefaultHeight}},
+		{"size", []Option{WithSize(120, 40)}, Options{Width: 120, Height: 40}},
+		{"flags", []Option{WithFlags(1)}, Options{Width: DefaultWidth, Height: DefaultHeight, Flags: 1}},
+		{"last wins", []Option{WithSize(1, 2), WithSize(3, 4)}, Options{Width: 3, Height: 4}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := newOptions(c.opts); got != c.want {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !windows
+// +build !windows
+
+package pty
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/creack/pty"
+)
+
+// unixPty is a PTY opened with openpty.
+type unixPty struct {
+	master, slave *os.File
+}
+
+func newPty(o Options) (Pty, error) {
+	master, slave, err := pty.Open()
+	if errors.Is(err, pty.ErrUnsupported) {
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &unixPty{master: master, slave: slave}
+	if err := p.Resize(o.Width, o.Height); err != nil {
+		p.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Close implements Pty.
+func (p *unixPty) Close() error {
+	err := p.master.Close()
+	if serr := p.slave.Close(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// Fd implements Pty.
+func (p *unixPty) Fd() uintptr {
+	return p.master.Fd()
+}
+
+// Name implements Pty.
+func (p *unixPty) Name() string {
+	return p.master.Name()
+}
+
+// Read implements Pty.
+func (p *unixPty) Read(b []byte) (int, error) {
+	return p.master.Read(b)
+}
+
+// Write implements Pty.
+func (p *unixPty) Write(b []byte) (int, error) {
+	return p.master.Write(b)
+}
+
+// Resize implements Pty.
+func (p *unixPty) Resize(width, height int) error {
+	return pty.Setsize(p.master, &pty.Winsize{
+		Rows: uint16(height),
+		Cols: uint16(width),
+	})
+}
+
+// Size implements Pty.
+func (p *unixPty) Size() (width, height int, err error) {
+	height, width, err = pty.Getsize(p.master)
+	return
+}
+
+// Start implements Pty.
+func (p *unixPty) Start(cmd *exec.Cmd) error {
+	if cmd.Stdin == nil {
+		cmd.Stdin = p.slave
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = p.slave
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = p.slave
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+// +build !windows
+
+package pty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func newTestPty(t *testing.T, opts ...Option) Pty {
+	t.Helper()
+	p, err := New(opts...)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() }) //nolint:errcheck
+	return p
+}
+
+func TestResize(t *testing.T) {
+	p := newTestPty(t, WithSize(120, 40))
+	if w, h, err := p.Size(); err != nil || w != 120 || h != 40 {
+		t.Fatalf("expected 120x40, got %dx%d (%v)", w, h, err)
+	}
+	if err := p.Resize(40, 10); err != nil {
+		t.Fatal(err)
+	}
+	if w, h, err := p.Size(); err != nil || w != 40 || h != 10 {
+		t.Fatalf("expected 40x10, got %dx%d (%v)", w, h, err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	p := newTestPty(t, WithSize(30, 5))
+	cmd := exec.Command("sh", "-c", "stty size; read line; echo got $line")
+	if err := p.Start(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(p, "hello\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := Wait(ctx, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// The slave stays open, so read what's buffered without waiting for
+	// EOF.
+	var out bytes.Buffer
+	buf := make([]byte, 1024)
+	for !bytes.Contains(out.Bytes(), []byte("got hello")) {
+		n, err := p.Read(buf)
+		if err != nil {
+			t.Fatalf("%v after %q", err, out.String())
+		}
+		out.Write(buf[:n])
+	}
+	if !bytes.Contains(out.Bytes(), []byte("5 30")) {
+		t.Errorf("expected the command to see a 30x5 terminal, got %q", out.String())
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+// +build windows
+
+package pty
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/charmbracelet/x/conpty"
+	"golang.org/x/sys/windows"
+)
+
+// conPty is a Windows pseudo console.
+type conPty struct {
+	*conpty.ConPty
+}
+
+func newPty(o Options) (Pty, error) {
+	c, err := conpty.New(o.Width, o.Height, o.Flags)
+	if err != nil {
+		return nil, err
+	}
+	return &conPty{c}, nil
+}
+
+// Name implements Pty.
+func (*conPty) Name() string {
+	return "windows-pty"
+}
+
+// Start implements Pty.
+func (p *conPty) Start(cmd *exec.Cmd) error {
+	pid, proc, err := p.ConPty.Spawn(cmd.Path, cmd.Args, &syscall.ProcAttr{
+		Dir: cmd.Dir,
+		Env: cmd.Env,
+		Sys: cmd.SysProcAttr,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Process, err = os.FindProcess(pid)
+	if err != nil {
+		// Everything else relies on cmd.Process, so don't leave the process
+		// running without it.
+		if tErr := windows.TerminateProcess(windows.Handle(proc), 1); tErr != nil {
+			return fmt.Errorf("failed to terminate process after process not found: %w", tErr)
+		}
+		return fmt.Errorf("failed to find process after starting: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+module github.com/charmbracelet/x/vt
+
+go 1.18
+
+require (
+	github.com/charmbracelet/x/ansi v0.3.2
+	github.com/charmbracelet/x/cellbuf v0.0.13
+	github.com/charmbracelet/x/wcwidth v0.0.0-20241011142426-46044092ad91
+)
+
+require (
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/text v0.19.0 // indirect
+)
@@ -0,0 +1,250 @@
+package vt
+
+import (
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/charmbracelet/x/wcwidth"
+)
+
+// handle applies a parsed sequence to the terminal.
+func (t *Terminal) handle(seq ansi.Sequence) {
+	switch seq := seq.(type) {
+	case ansi.Rune:
+		t.print(rune(seq))
+	case ansi.ControlCode:
+		t.execute(byte(seq))
+	case ansi.EscSequence:
+		t.handleEsc(seq)
+	case ansi.CsiSequence:
+		t.handleCsi(seq)
+	case ansi.OscSequence:
+		t.handleOsc(seq)
+	}
+}
+
+// print writes a character at the cursor position.
+func (t *Terminal) print(r rune) {
+	if t.cur.charsets[t.cur.gl] == ansi.DecSpecialGraphicsCharset {
+		r = ansi.DecSpecialGraphicsRune(r)
+	}
+	w := wcwidth.RuneWidth(r)
+	if w <= 0 {
+		// Zero-width characters combine with the last one printed.
+		if c, err := t.scr.At(t.lastX, t.lastY); err == nil && c.Width > 0 {
+			c.Content += string(r)
+			t.scr.Set(t.lastX, t.lastY, c)
+		}
+		return
+	}
+
+	width := t.Width()
+	if w > width {
+		return
+	}
+	if t.cur.wrap || t.cur.x+w > width {
+		if t.nowrap {
+			t.cur.x = width - w
+		} else {
+			t.cur.x = 0
+			t.linefeed()
+		}
+	}
+	t.cur.wrap = false
+
+	cellbuf.SetCell(t.scr, t.cur.x, t.cur.y, cellbuf.Cell{
+		Content: string(r),
+		Width:   w,
+		Style:   t.cur.pen,
+		Link:    t.cur.link,
+	})
+	t.lastX, t.lastY = t.cur.x, t.cur.y
+
+	t.cur.x += w
+	if t.cur.x >= width {
+		t.cur.x = width - 1
+		t.cur.wrap = !t.nowrap
+	}
+}
+
+// execute handles a control character.
+func (t *Terminal) execute(c byte) {
+	switch c {
+	case ansi.BS:
+		t.moveTo(t.cur.x-1, t.cur.y)
+	case ansi.HT:
+		t.moveTo((t.cur.x/8+1)*8, t.cur.y)
+	case ansi.LF, ansi.VT, ansi.FF:
+		t.linefeed()
+	case ansi.CR:
+		t.moveTo(0, t.cur.y)
+	case ansi.IND:
+		t.linefeed()
+	case ansi.NEL:
+		t.moveTo(0, t.cur.y)
+		t.linefeed()
+	case ansi.RI:
+		t.reverseIndex()
+	case ansi.SO:
+		t.cur.gl = 1
+	case ansi.SI:
+		t.cur.gl = 0
+	}
+}
+
+// handleEsc handles an escape sequence.
+func (t *Terminal) handleEsc(seq ansi.EscSequence) {
+	switch i := seq.Intermediate(); i {
+	case 0:
+	case '(', ')', '*', '+': // SCS
+		t.cur.charsets[i-'('] = byte(seq.Command())
+		return
+	default:
+		return
+	}
+	switch seq.Command() {
+	case '7': // DECSC
+		t.saveCursor()
+	case '8': // DECRC
+		t.restoreCursor()
+	case 'D': // IND
+		t.execute(ansi.IND)
+	case 'E': // NEL
+		t.execute(ansi.NEL)
+	case 'M': // RI
+		t.execute(ansi.RI)
+	case 'c': // RIS
+		t.Reset()
+	}
+}
+
+// handleCsi handles a control sequence.
+func (t *Terminal) handleCsi(seq ansi.CsiSequence) {
+	// n returns the ith parameter, or def when missing or zero.
+	n := func(i, def int) int {
+		if p := seq.Param(i); p > 0 {
+			return p
+		}
+		return def
+	}
+
+	if seq.Intermediate() != 0 {
+		return
+	}
+	switch seq.Marker() {
+	case '?':
+		switch seq.Command() {
+		case 'h': // DECSET
+			t.setModes(seq, true)
+		case 'l': // DECRST
+			t.setModes(seq, false)
+		}
+		return
+	case 0:
+	default:
+		return
+	}
+
+	x, y := t.cur.x, t.cur.y
+	switch seq.Command() {
+	case 'A': // CUU
+		t.moveTo(x, t.clampUp(y-n(0, 1)))
+	case 'B', 'e': // CUD, VPR
+		t.moveTo(x, t.clampDown(y+n(0, 1)))
+	case 'C', 'a': // CUF, HPR
+		t.moveTo(x+n(0, 1), y)
+	case 'D': // CUB
+		t.moveTo(x-n(0, 1), y)
+	case 'E': // CNL
+		t.moveTo(0, t.clampDown(y+n(0, 1)))
+	case 'F': // CPL
+		t.moveTo(0, t.clampUp(y-n(0, 1)))
+	case 'G', '`': // CHA, HPA
+		t.moveTo(n(0, 1)-1, y)
+	case 'H', 'f': // CUP, HVP
+		t.moveTo(n(1, 1)-1, n(0, 1)-1)
+	case 'd': // VPA
+		t.moveTo(x, n(0, 1)-1)
+	case 'J': // ED
+		t.eraseDisplay(seq.Param(0))
+	case 'K': // EL
+		t.eraseLine(seq.Param(0))
+	case 'X': // ECH
+		t.eraseCells(x, x+n(0, 1), y)
+	case '@': // ICH
+		t.insertCells(n(0, 1))
+	case 'P': // DCH
+		t.deleteCells(n(0, 1))
+	case 'L': // IL
+		t.insertLines(n(0, 1))
+	case 'M': // DL
+		t.deleteLines(n(0, 1))
+	case 'S': // SU
+		t.scrollUp(t.top, t.bottom, n(0, 1))
+	case 'T': // SD
+		t.scrollDown(t.top, t.bottom, n(0, 1))
+	case 'r': // DECSTBM
+		top, bottom := n(0, 1)-1, n(1, t.Height())
+		if top < bottom-1 && bottom <= t.Height() {
+			t.top, t.bottom = top, bottom
+			t.moveTo(0, 0)
+		}
+	case 'm': // SGR
+		cellbuf.ReadStyle(seq.Params, &t.cur.pen)
+	case 's': // SCOSC
+		t.saveCursor()
+	case 'u': // SCORC
+		t.restoreCursor()
+	}
+}
+
+// setModes sets or resets the DEC private modes of a DECSET or DECRST
+// sequence.
+func (t *Terminal) setModes(seq ansi.CsiSequence, set bool) {
+	for i := 0; i < seq.Len(); i++ {
+		switch seq.Param(i) {
+		case 7: // DECAWM
+			t.nowrap = !set
+			if !set {
+				t.cur.wrap = false
+			}
+		case 25: // DECTCEM
+			t.hidden = !set
+		case 47, 1047: // Alternate screen
+			t.setAltScreen(set)
+		case 1048: // Save cursor
+			if set {
+				t.saveCursor()
+			} else {
+				t.restoreCursor()
+			}
+		case 1049: // Alternate screen, saving the cursor
+			if set {
+				t.saveCursor()
+				t.setAltScreen(true)
+				t.eraseDisplay(2)
+			} else {
+				t.setAltScreen(false)
+				t.restoreCursor()
+			}
+		}
+	}
+}
+
+// handleOsc handles an operating system command.
+func (t *Terminal) handleOsc(seq ansi.OscSequence) {
+	// The data starts with the command and a semicolon.
+	var data []byte
+	for i, b := range seq.Data {
+		if b == ';' {
+			data = seq.Data[i+1:]
+			break
+		}
+	}
+
+	switch seq.Command() {
+	case 0, 2: // Window title
+		t.title = string(data)
+	case 8: // Hyperlink
+		cellbuf.ReadLink(seq.Data, &t.cur.link)
+	}
+}
@@ -0,0 +1,243 @@
+package vt
+
+import (
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// blank returns the cell used to erase the screen. Erased cells take the
+// background color of the pen.
+func (t *Terminal) blank() cellbuf.Cell {
+	return cellbuf.Cell{
+		Content: " ",
+		Width:   1,
+		Style:   cellbuf.Style{Bg: t.cur.pen.Bg},
+	}
+}
+
+// moveTo moves the cursor to the given position, clamped to the screen.
+func (t *Terminal) moveTo(x, y int) {
+	t.cur.x = clamp(x, 0, t.Width()-1)
+	t.cur.y = clamp(y, 0, t.Height()-1)
+	t.cur.wrap = false
+}
+
+// clampUp clamps a row the cursor moves up to, which stops at the top
+// margin when the cursor is in the scroll region.
+func (t *Terminal) clampUp(y int) int {
+	if t.cur.y >= t.top && y < t.top {
+		return t.top
+	}
+	return y
+}
+
+// clampDown clamps a row the cursor moves down to, which stops at the
+// bottom margin when the cursor is in the scroll region.
+func (t *Terminal) clampDown(y int) int {
+	if t.cur.y < t.bottom && y >= t.bottom {
+		return t.bottom - 1
+	}
+	return y
+}
+
+// linefeed moves the cursor down, scrolling the region up when it is on the
+// bottom margin.
+func (t *Terminal) linefeed() {
+	switch {
+	case t.cur.y == t.bottom-1:
+		t.scrollUp(t.top, t.bottom, 1)
+	case t.cur.y < t.Height()-1:
+		t.cur.y++
+	}
+	t.cur.wrap = false
+}
+
+// reverseIndex moves the cursor up, scrolling the region down when it is on
+// the top margin.
+func (t *Terminal) reverseIndex() {
+	switch {
+	case t.cur.y == t.top:
+		t.scrollDown(t.top, t.bottom, 1)
+	case t.cur.y > 0:
+		t.cur.y--
+	}
+	t.cur.wrap = false
+}
+
+// saveCursor saves the cursor of the active screen.
+func (t *Terminal) saveCursor() {
+	t.saved[t.screenIndex()] = t.cur
+}
+
+// restoreCursor restores the saved cursor of the active screen.
+func (t *Terminal) restoreCursor() {
+	t.cur = t.saved[t.screenIndex()]
+	t.moveTo(t.cur.x, t.cur.y)
+}
+
+func (t *Terminal) screenIndex() int {
+	if t.IsAltScreen() {
+		return 1
+	}
+	return 0
+}
+
+// setAltScreen switches between the main and the alternate screens.
+func (t *Terminal) setAltScreen(alt bool) {
+	if alt {
+		t.scr = t.alt
+	} else {
+		t.scr = t.main
+	}
+	t.lastX, t.lastY = -1, -1
+}
+
+// eraseDisplay erases the display (ED). Mode 0 erases below the cursor, 1
+// above it, and 2 and 3 the entire screen.
+func (t *Terminal) eraseDisplay(mode int) {
+	x, y := t.cur.x, t.cur.y
+	switch mode {
+	case -1, 0:
+		t.eraseCells(x, t.Width(), y)
+		t.eraseLines(y+1, t.Height())
+	case 1:
+		t.eraseLines(0, y)
+		t.eraseCells(0, x+1, y)
+	case 2, 3:
+		t.eraseLines(0, t.Height())
+	}
+}
+
+// eraseLine erases the line (EL). Mode 0 erases right of the cursor, 1 left
+// of it, and 2 the entire line.
+func (t *Terminal) eraseLine(mode int) {
+	x, y := t.cur.x, t.cur.y
+	switch mode {
+	case -1, 0:
+		t.eraseCells(x, t.Width(), y)
+	case 1:
+		t.eraseCells(0, x+1, y)
+	case 2:
+		t.eraseCells(0, t.Width(), y)
+	}
+}
+
+// eraseCells erases the cells from x0 to x1, excluded, of the yth line.
+func (t *Terminal) eraseCells(x0, x1, y int) {
+	x1 = clamp(x1, 0, t.Width())
+	if x0 < x1 {
+		cellbuf.FillRect(t.scr, t.blank(), x0, y, x1-x0, 1)
+	}
+	t.cur.wrap = false
+}
+
+// eraseLines erases the lines from y0 to y1, excluded.
+func (t *Terminal) eraseLines(y0, y1 int) {
+	if y0 < y1 {
+		cellbuf.FillRect(t.scr, t.blank(), 0, y0, t.Width(), y1-y0)
+	}
+}
+
+// insertCells inserts n blank cells at the cursor, shifting the rest of the
+// line right (ICH).
+func (t *Terminal) insertCells(n int) {
+	x, y, width := t.cur.x, t.cur.y, t.Width()
+	for i := width - 1; i >= x+n; i-- {
+		c, _ := t.scr.At(i-n, y)
+		t.scr.Set(i, y, c)
+	}
+	t.eraseCells(x, x+n, y)
+	t.repairLine(y)
+}
+
+// deleteCells deletes n cells at the cursor, shifting the rest of the line
+// left (DCH).
+func (t *Terminal) deleteCells(n int) {
+	x, y, width := t.cur.x, t.cur.y, t.Width()
+	for i := x; i < width-n; i++ {
+		c, _ := t.scr.At(i+n, y)
+		t.scr.Set(i, y, c)
+	}
+	t.eraseCells(clamp(width-n, x, width), width, y)
+	t.repairLine(y)
+}
+
+// repairLine replaces the halves of wide cells split by shifting the cells
+// of the yth line with blank cells.
+func (t *Terminal) repairLine(y int) {
+	width := t.Width()
+	for x := 0; x < width; x++ {
+		c, _ := t.scr.At(x, y)
+		switch {
+		case c.Width == 0:
+			// A placeholder without its wide cell.
+			t.scr.Set(x, y, t.blank())
+		case c.Width > 1:
+			whole := x+c.Width <= width
+			for i := x + 1; whole && i < x+c.Width; i++ {
+				p, _ := t.scr.At(i, y)
+				whole = p.Width == 0
+			}
+			if !whole {
+				t.scr.Set(x, y, t.blank())
+				continue
+			}
+			x += c.Width - 1
+		}
+	}
+}
+
+// insertLines inserts n blank lines at the cursor, shifting the lines below
+// down to the bottom margin (IL). It does nothing outside the scroll region.
+func (t *Terminal) insertLines(n int) {
+	if t.cur.y < t.top || t.cur.y >= t.bottom {
+		return
+	}
+	t.scrollDown(t.cur.y, t.bottom, n)
+	t.moveTo(0, t.cur.y)
+}
+
+// deleteLines deletes n lines at the cursor, shifting the lines below up
+// from the bottom margin (DL). It does nothing outside the scroll region.
+func (t *Terminal) deleteLines(n int) {
+	if t.cur.y < t.top || t.cur.y >= t.bottom {
+		return
+	}
+	t.scrollUp(t.cur.y, t.bottom, n)
+	t.moveTo(0, t.cur.y)
+}
+
+// scrollUp scrolls the lines from top to bottom, excluded, up by n lines.
+func (t *Terminal) scrollUp(top, bottom, n int) {
+	n = clamp(n, 0, bottom-top)
+	t.copyLines(top, top+n, bottom-top-n)
+	t.eraseLines(bottom-n, bottom)
+}
+
+// scrollDown scrolls the lines from top to bottom, excluded, down by n
+// lines.
+func (t *Terminal) scrollDown(top, bottom, n int) {
+	n = clamp(n, 0, bottom-top)
+	t.copyLines(top+n, top, bottom-top-n)
+	t.eraseLines(top, top+n)
+}
+
+// copyLines copies n lines starting at src to dst.
+func (t *Terminal) copyLines(dst, src, n int) {
+	width := t.Width()
+	copyLine := func(i int) {
+		for x := 0; x < width; x++ {
+			c, _ := t.scr.At(x, src+i)
+			t.scr.Set(x, dst+i, c)
+		}
+	}
+	if dst < src {
+		for i := 0; i < n; i++ {
+			copyLine(i)
+		}
+	} else {
+		for i := n - 1; i >= 0; i-- {
+			copyLine(i)
+		}
+	}
+	t.lastX, t.lastY = -1, -1
+}
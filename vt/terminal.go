@@ -0,0 +1,191 @@
+package vt
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// Terminal is an in-memory virtual terminal. It parses the output written to
+// it, like a terminal emulator would, and keeps the resulting screen in a
+// cell buffer. Use it to test what the user would see without a PTY.
+//
+//	t := vt.NewTerminal(80, 24)
+//	io.WriteString(t, "\x1b[1mhello\x1b[m world")
+//	fmt.Println(t.String())
+//
+// It supports cursor movement, SGR styles, hyperlinks, erasing, insertion
+// and deletion, scroll regions, autowrap, the alternate screen, and the DEC
+// special graphics character set.
+type Terminal struct {
+	main, alt *cellbuf.Buffer
+	scr       *cellbuf.Buffer // scr is the active screen.
+
+	parser   *ansi.Parser
+	dispatch ansi.ParserDispatcher
+
+	cur   cursor
+	saved [2]cursor // saved cursors of the main and alternate screens.
+
+	top, bottom int // top, bottom is the scroll region, bottom excluded.
+
+	// lastX, lastY is the last cell printed, that zero-width characters
+	// combine with, or -1.
+	lastX, lastY int
+
+	nowrap bool // nowrap disables autowrap (DECAWM).
+	hidden bool // hidden hides the cursor (DECTCEM).
+	title  string
+}
+
+// cursor is the cursor position and pen.
+type cursor struct {
+	x, y int
+	pen  cellbuf.Style
+	link cellbuf.Link
+
+	// wrap reports whether the cursor is past the last column, waiting for
+	// the next character to wrap.
+	wrap bool
+
+	// charsets are the G0 to G3 character sets designated with SCS, zero
+	// for ASCII, and gl is the one in use, G0 or G1.
+	charsets [4]byte
+	gl       int
+}
+
+// NewTerminal returns a new terminal with the given size.
+func NewTerminal(width, height int) *Terminal {
+	t := &Terminal{
+		parser: ansi.NewParser(32, 4096),
+	}
+	t.dispatch = t.handle
+	t.Resize(width, height)
+	return t
+}
+
+// Write parses the output in p and applies it to the screen. It never fails.
+func (t *Terminal) Write(p []byte) (int, error) {
+	for _, b := range p {
+		// Sequences split across writes are expected.
+		t.parser.Advance(t.dispatch, b, true)
+	}
+	return len(p), nil
+}
+
+// WriteString is like [Terminal.Write] but for strings.
+func (t *Terminal) WriteString(s string) (int, error) {
+	for i := 0; i < len(s); i++ {
+		t.parser.Advance(t.dispatch, s[i], true)
+	}
+	return len(s), nil
+}
+
+// Width returns the width of the terminal.
+func (t *Terminal) Width() int {
+	return t.scr.Width()
+}
+
+// Height returns the height of the terminal.
+func (t *Terminal) Height() int {
+	return t.scr.Height()
+}
+
+// Resize resizes the terminal. The content is kept from the top-left
+// corner, and the scroll region is reset.
+func (t *Terminal) Resize(width, height int) {
+	alt := t.scr != nil && t.IsAltScreen()
+	t.main = resizeBuffer(t.main, width, height)
+	t.alt = resizeBuffer(t.alt, width, height)
+	t.scr = t.main
+	if alt {
+		t.scr = t.alt
+	}
+	t.top, t.bottom = 0, height
+	t.cur.x, t.cur.y = clamp(t.cur.x, 0, width-1), clamp(t.cur.y, 0, height-1)
+	t.cur.wrap = false
+	t.lastX, t.lastY = -1, -1
+}
+
+// Screen returns the active screen buffer.
+func (t *Terminal) Screen() *cellbuf.Buffer {
+	return t.scr
+}
+
+// Cell returns the cell at the given position of the active screen.
+func (t *Terminal) Cell(x, y int) cellbuf.Cell {
+	c, _ := t.scr.At(x, y)
+	return c
+}
+
+// Cursor returns the cursor position.
+func (t *Terminal) Cursor() (x, y int) {
+	return t.cur.x, t.cur.y
+}
+
+// CursorVisible reports whether the cursor is visible.
+func (t *Terminal) CursorVisible() bool {
+	return !t.hidden
+}
+
+// IsAltScreen reports whether the alternate screen is active.
+func (t *Terminal) IsAltScreen() bool {
+	return t.scr == t.alt
+}
+
+// Title returns the window title set with OSC 0 or OSC 2.
+func (t *Terminal) Title() string {
+	return t.title
+}
+
+// String returns the text of the active screen, one line per row, without
+// trailing spaces.
+func (t *Terminal) String() string {
+	var b strings.Builder
+	for y := 0; y < t.Height(); y++ {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(t.line(y))
+	}
+	return b.String()
+}
+
+// line returns the text of the yth line without trailing spaces.
+func (t *Terminal) line(y int) string {
+	var b strings.Builder
+	for x := 0; x < t.Width(); x++ {
+		b.WriteString(t.Cell(x, y).Content)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Reset resets the terminal to its initial state (RIS).
+func (t *Terminal) Reset() {
+	w, h := t.Width(), t.Height()
+	*t = Terminal{parser: t.parser, dispatch: t.dispatch}
+	t.parser.Reset()
+	t.Resize(w, h)
+}
+
+// resizeBuffer returns a buffer of the given size with the content of b, if
+// any, kept from the top-left corner.
+func resizeBuffer(b *cellbuf.Buffer, width, height int) *cellbuf.Buffer {
+	nb := &cellbuf.Buffer{}
+	nb.Resize(width, height)
+	if b != nil {
+		cellbuf.CopyRect(nb, 0, 0, b, 0, 0, width, height)
+	}
+	return nb
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,214 @@
+package vt
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+func TestTerminal(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		screen []string
+		x, y   int
+	}{
+		{"text", "hello", []string{"hello", "", ""}, 5, 0},
+		{"crlf", "foo\r\nbar", []string{"foo", "bar", ""}, 3, 1},
+		{"lf", "foo\nbar", []string{"foo", "   bar", ""}, 6, 1},
+		{"backspace", "abc\bd", []string{"abd", "", ""}, 3, 0},
+		{"tab", "a\tb", []string{"a       b", "", ""}, 9, 0},
+		{"dec graphics", "\x1b(0lqk\x1b(Bq", []string{"┌─┐q", "", ""}, 4, 0},
+		{"dec graphics shift", "\x1b)0a\x0eqx\x0fq", []string{"a─│q", "", ""}, 4, 0},
+		{"autowrap", "0123456789ab", []string{"0123456789", "ab", ""}, 2, 1},
+		{"pending wrap", "0123456789", []string{"0123456789", "", ""}, 9, 0},
+		{"pending wrap cr", "0123456789\rx", []string{"x123456789", "", ""}, 1, 0},
+		{"no autowrap", "\x1b[?7l0123456789ab", []string{"012345678b", "", ""}, 9, 0},
+		{"scroll", "a\r\nb\r\nc\r\nd", []string{"b", "c", "d"}, 1, 2},
+		{"cup", "\x1b[2;3Hx", []string{"", "  x", ""}, 3, 1},
+		{"cup default", "abc\x1b[Hx", []string{"xbc", "", ""}, 1, 0},
+		{"cup clamped", "\x1b[99;99Hx", []string{"", "", "         x"}, 9, 2},
+		{"relative moves", "\x1b[2B\x1b[3Cx\x1b[A\x1b[2Dy", []string{"", "  y", "   x"}, 3, 1},
+		{"cha vpa", "\x1b[5G\x1b[3dx", []string{"", "", "    x"}, 5, 2},
+		{"el right", "abcdef\x1b[3G\x1b[K", []string{"ab", "", ""}, 2, 0},
+		{"el left", "abcdef\x1b[3G\x1b[1K", []string{"   def", "", ""}, 2, 0},
+		{"el all", "abcdef\x1b[2K", []string{"", "", ""}, 6, 0},
+		{"ed below", "aaa\r\nbbb\r\nccc\x1b[2;2H\x1b[J", []string{"aaa", "b", ""}, 1, 1},
+		{"ed above", "aaa\r\nbbb\r\nccc\x1b[2;2H\x1b[1J", []string{"", "  b", "ccc"}, 1, 1},
+		{"ed all", "aaa\r\nbbb\x1b[2J", []string{"", "", ""}, 3, 1},
+		{"ech", "abcdef\x1b[2G\x1b[2X", []string{"a  def", "", ""}, 1, 0},
+		{"ich", "abcdef\x1b[2G\x1b[2@", []string{"a  bcdef", "", ""}, 1, 0},
+		{"dch", "abcdef\x1b[2G\x1b[2P", []string{"adef", "", ""}, 1, 0},
+		{"il", "a\r\nb\r\nc\x1b[2H\x1b[L", []string{"a", "", "b"}, 0, 1},
+		{"dl", "a\r\nb\r\nc\x1b[1H\x1b[M", []string{"b", "c", ""}, 0, 0},
+		{"su", "a\r\nb\r\nc\x1b[S", []string{"b", "c", ""}, 1, 2},
+		{"sd", "a\r\nb\r\nc\x1b[T", []string{"", "a", "b"}, 1, 2},
+		{"scroll region", "a\r\nb\r\nc\x1b[1;2r\x1b[2Hx\n", []string{"x", "", "c"}, 1, 1},
+		{"reverse index", "a\r\nb\x1b[H\x1bM", []string{"", "a", "b"}, 0, 0},
+		{"save restore", "\x1b[2;2H\x1b7\x1b[Hx\x1b8y", []string{"x", " y", ""}, 2, 1},
+		{"wide", "a世b", []string{"a世b", "", ""}, 4, 0},
+		{"wide wrap", "012345678世", []string{"012345678", "世", ""}, 2, 1},
+		{"wide overwritten", "世\x1b[2Gx", []string{" x", "", ""}, 2, 0},
+		{"combining", "éx", []string{"éx", "", ""}, 2, 0},
+		{"split utf8", "\xe4\xb8\x96", []string{"世", "", ""}, 2, 0},
+		{"sgr ignored in text", "\x1b[1;31mred\x1b[m", []string{"red", "", ""}, 3, 0},
+		{"osc ignored in text", "\x1b]2;title\x07ok", []string{"ok", "", ""}, 2, 0},
+		{"alt screen", "main\x1b[?1049halt", []string{"    alt", "", ""}, 7, 0},
+		{"alt screen exit", "main\x1b[?1049halt\x1b[?1049l", []string{"main", "", ""}, 4, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := NewTerminal(10, 3)
+			term.WriteString(c.input) //nolint:errcheck
+			want := strings.Join(c.screen, "\n")
+			if got := term.String(); got != want {
+				t.Errorf("screen:\n%q\nwant:\n%q", got, want)
+			}
+			if x, y := term.Cursor(); x != c.x || y != c.y {
+				t.Errorf("cursor at %d,%d, want %d,%d", x, y, c.x, c.y)
+			}
+		})
+	}
+}
+
+func TestTerminalSplitWrites(t *testing.T) {
+	const input = "\x1b[1;31mred\x1b[m \x1b]8;;https://charm.sh\x1b\\link\x1b]8;;\x1b\\ 世界\x1b[2;1H\x1b[4:3mcurly"
+	want := NewTerminal(20, 2)
+	want.WriteString(input) //nolint:errcheck
+
+	for i := 0; i <= len(input); i++ {
+		term := NewTerminal(20, 2)
+		term.WriteString(input[:i])   //nolint:errcheck
+		term.Write([]byte(input[i:])) //nolint:errcheck
+		if !cellbuf.Equal(term.Screen(), want.Screen()) {
+			t.Fatalf("split at %d: screen differs:\n%s\nwant:\n%s", i, term, want)
+		}
+	}
+}
+
+func TestTerminalStyles(t *testing.T) {
+	term := NewTerminal(20, 2)
+	term.WriteString("\x1b[1;31ma\x1b[22;4:3;38;5;200mb\x1b[mc\x1b]8;id=1;https://charm.sh\x07d\x1b]8;;\x07e") //nolint:errcheck
+
+	var bold, curly cellbuf.Style
+	bold.Bold(true).Foreground(ansi.Red)
+	curly.UnderlineStyle(cellbuf.CurlyUnderline).Foreground(ansi.ExtendedColor(200))
+
+	cases := []struct {
+		x     int
+		style cellbuf.Style
+		link  cellbuf.Link
+	}{
+		{0, bold, cellbuf.Link{}},
+		{1, curly, cellbuf.Link{}},
+		{2, cellbuf.Style{}, cellbuf.Link{}},
+		{3, cellbuf.Style{}, cellbuf.Link{URL: "https://charm.sh", URLID: "id=1"}},
+		{4, cellbuf.Style{}, cellbuf.Link{}},
+	}
+	for _, c := range cases {
+		cell := term.Cell(c.x, 0)
+		if !cell.Style.Equal(c.style) {
+			t.Errorf("cell %d: style %q, want %q", c.x, cell.Style.Sequence(), c.style.Sequence())
+		}
+		if cell.Link != c.link {
+			t.Errorf("cell %d: link %+v, want %+v", c.x, cell.Link, c.link)
+		}
+	}
+}
+
+func TestTerminalBackgroundErase(t *testing.T) {
+	term := NewTerminal(4, 1)
+	term.WriteString("\x1b[44m\x1b[K") //nolint:errcheck
+	for x := 0; x < 4; x++ {
+		if bg := term.Cell(x, 0).Style.Bg; bg != ansi.Blue {
+			t.Errorf("cell %d: background %v, want blue", x, bg)
+		}
+	}
+}
+
+func TestTerminalModes(t *testing.T) {
+	term := NewTerminal(10, 3)
+	term.WriteString("\x1b]0;my title\x07\x1b[?25l\x1b[?1049h") //nolint:errcheck
+	if term.Title() != "my title" {
+		t.Errorf("title %q", term.Title())
+	}
+	if term.CursorVisible() {
+		t.Error("cursor visible")
+	}
+	if !term.IsAltScreen() {
+		t.Error("not on the alternate screen")
+	}
+
+	term.WriteString("\x1bc") //nolint:errcheck
+	if !term.CursorVisible() || term.IsAltScreen() || term.Title() != "" {
+		t.Error("reset didn't reset the modes")
+	}
+}
+
+func TestTerminalResize(t *testing.T) {
+	term := NewTerminal(10, 3)
+	term.WriteString("hello\r\nworld\r\nfoo") //nolint:errcheck
+	term.Resize(3, 2)
+	if got, want := term.String(), "hel\nwor"; got != want {
+		t.Errorf("screen %q, want %q", got, want)
+	}
+	if x, y := term.Cursor(); x != 2 || y != 1 {
+		t.Errorf("cursor at %d,%d", x, y)
+	}
+}
+
+func TestTerminalRender(t *testing.T) {
+	src := NewTerminal(12, 3)
+	src.WriteString("\x1b[1mbold\x1b[m plain\r\n\x1b[4:3;31mcurly 世界\x1b[m\r\n\x1b]8;;https://charm.sh\x07link\x1b]8;;\x07") //nolint:errcheck
+
+	// Drawing the screen on another terminal gives the same screen.
+	dst := NewTerminal(12, 3)
+	dst.WriteString(cellbuf.RenderScreen(src.Screen())) //nolint:errcheck
+	if !cellbuf.Equal(src.Screen(), dst.Screen()) {
+		t.Errorf("screens differ:\n%s\nwant:\n%s", dst, src)
+	}
+}
+
+func TestTerminalRenderer(t *testing.T) {
+	var out bytes.Buffer
+	r := cellbuf.NewRenderer(&out)
+	term := NewTerminal(12, 6)
+
+	// Random frames, mostly made of small changes to the previous one.
+	rng := rand.New(rand.NewSource(1))
+	contents := []string{"a", "b", "世"}
+	styles := []cellbuf.Style{{}, {Attrs: cellbuf.BoldAttr}, {Bg: ansi.Blue}}
+	frame := &cellbuf.Buffer{}
+	frame.Resize(12, 6)
+	for i := 0; i < 500; i++ {
+		if i%50 == 0 {
+			cellbuf.Fill(frame, cellbuf.Cell{Content: " ", Width: 1})
+		}
+		for j := 0; j < 1+rng.Intn(8); j++ {
+			content := contents[rng.Intn(len(contents))]
+			cellbuf.SetCell(frame, rng.Intn(12), rng.Intn(6), cellbuf.Cell{
+				Content: content,
+				Width:   ansi.StringWidth(content),
+				Style:   styles[rng.Intn(len(styles))],
+			})
+		}
+		if rng.Intn(10) == 0 {
+			y := rng.Intn(6)
+			cellbuf.FillRect(frame, cellbuf.Cell{Content: " ", Width: 1}, rng.Intn(12), y, 12, 6-y)
+		}
+
+		out.Reset()
+		r.SyncOutput = i%2 == 1
+		if err := r.Render(frame); err != nil {
+			t.Fatal(err)
+		}
+		term.Write(out.Bytes()) //nolint:errcheck
+		if !cellbuf.Equal(term.Screen(), frame) {
+			t.Fatalf("frame %d: screens differ after %q:\n%s", i, out.String(), term)
+		}
+	}
+}